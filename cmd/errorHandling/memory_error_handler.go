@@ -47,6 +47,13 @@ func (h *MemoryErrorHandler) GetErrorCount() uint {
 	return h.storage.GetErrorCount()
 }
 
+/*GetErrorMessage returns the message of the memory error numbered `number`, counting from 0
+ */
+func (h *MemoryErrorHandler) GetErrorMessage(number uint8) (string, error) {
+	_, message, err := h.storage.GetError(number)
+	return message, err
+}
+
 /*ClearErrors clears the errors -- the error count will go to 0, and HasErrors will go back to false
  */
 func (h *MemoryErrorHandler) ClearErrors() {
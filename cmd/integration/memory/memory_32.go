@@ -1,7 +1,6 @@
 package memory
 
 import (
-	Adapters "github.com/chenhowa/computer/cmd/integration/memory/adapters"
 	Memory "github.com/chenhowa/computer/lib/memory"
 )
 
@@ -21,12 +20,13 @@ type errorSink interface {
 }
 
 /*MakeMemory32 is a constructor for Memory32. It accepts an errorSink that
-will be responsible for handling any errors that occur while fetching or writing memory*/
+will be responsible for handling any panics that occur while reading or writing memory.
+Accesses that are outside of memory are not panics; they return errors instead*/
 func MakeMemory32(maxAddress uint16, sink errorSink) Memory32 {
 	memory := Memory32{}
 	errorSink := sink
 
-	basicMemory := Adapters.MakeBasicMemory16Adapted(maxAddress)
+	basicMemory := Memory.MakeBasicMemory(maxAddress)
 
 	panicMemory := Memory.MakePanicMemory32(&basicMemory, errorSink)
 	memory.memory = &panicMemory
@@ -34,14 +34,19 @@ func MakeMemory32(maxAddress uint16, sink errorSink) Memory32 {
 	return memory
 }
 
-/*Get fetches a 32-bit value from the `address` in memory*/
-func (m *Memory32) Get(address uint32) uint32 {
-	return m.memory.Get(address)
+/*Read returns the `width` bytes starting at `address` in memory, or an error
+if they cannot all be read*/
+func (m *Memory32) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	return m.memory.Read(address, width)
 }
 
-/*Set writes the first `numBits` of a 32-bit `val` to memory at `address`,
-and returns the number of bits successfully written*/
-func (m *Memory32) Set(address uint32, val uint32, numBits uint) Memory.NumberOfBitsWritten {
-	result := m.memory.Set(address, val, numBits)
-	return result
+/*Write writes the lowest `width` bytes of `val` to memory at `address`, or returns an error,
+and writes nothing, if they cannot all be written*/
+func (m *Memory32) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	return m.memory.Write(address, width, val)
+}
+
+/*GetAddressSpaceSize returns the first address that is not valid in memory*/
+func (m *Memory32) GetAddressSpaceSize() uint {
+	return m.memory.GetAddressSpaceSize()
 }
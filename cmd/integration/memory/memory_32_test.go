@@ -1,9 +1,11 @@
 package memory
 
 import (
+	"errors"
 	"math"
 	"testing"
 
+	LibMemory "github.com/chenhowa/computer/lib/memory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -26,8 +28,10 @@ func (suite *Memory32Suite) SetupTest() {
 	suite.sink = &sink
 }
 
-func (suite *Memory32Suite) AssertMemoryAtAddressIs(address uint32, val uint32) {
-	assert.Equal(suite.T(), val, suite.memory.Get(address))
+func (suite *Memory32Suite) AssertMemoryAtAddressIs(address uint32, width LibMemory.AccessWidth, val uint64) {
+	result, err := suite.memory.Read(address, width)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), val, result)
 }
 
 func (suite *Memory32Suite) AssertNoMemoryErrors() {
@@ -42,29 +46,30 @@ func (h *MockErrorHandler) Handle(message interface{}) {
 	h.Called(message)
 }
 
-func (suite *Memory32Suite) TestGet() {
-	suite.AssertMemoryAtAddressIs(0, 0)
-	suite.memory.Set(0, math.MaxUint16, 16)
+func (suite *Memory32Suite) TestRead() {
+	suite.AssertMemoryAtAddressIs(0, LibMemory.Word, 0)
+	assert.Nil(suite.T(), suite.memory.Write(0, LibMemory.HalfWord, math.MaxUint16))
 	suite.AssertNoMemoryErrors()
-	suite.AssertMemoryAtAddressIs(0, math.MaxUint16)
-	suite.AssertMemoryAtAddressIs(1, math.MaxUint8)
+	suite.AssertMemoryAtAddressIs(0, LibMemory.Word, math.MaxUint16)
+	suite.AssertMemoryAtAddressIs(1, LibMemory.Byte, math.MaxUint8)
 }
 
-func (suite *Memory32Suite) TestGet_Fail() {
-	suite.sink.On("Handle", mock.Anything)
-	suite.memory.Get(21)
-	suite.sink.AssertCalled(suite.T(), "Handle", mock.Anything)
+func (suite *Memory32Suite) TestRead_Fail() {
+	_, err := suite.memory.Read(19, LibMemory.Word)
+	assert.True(suite.T(), errors.Is(err, LibMemory.ErrAddressOutOfRange))
+	suite.AssertNoMemoryErrors()
 }
 
-func (suite *Memory32Suite) TestSet() {
-	suite.AssertMemoryAtAddressIs(20, 0)
-	suite.memory.Set(20, math.MaxUint16, 16)
+func (suite *Memory32Suite) TestWrite() {
+	suite.AssertMemoryAtAddressIs(20, LibMemory.Byte, 0)
+	assert.Nil(suite.T(), suite.memory.Write(20, LibMemory.Byte, math.MaxUint16))
 	suite.AssertNoMemoryErrors()
-	suite.AssertMemoryAtAddressIs(20, math.MaxUint8)
+	suite.AssertMemoryAtAddressIs(20, LibMemory.Byte, math.MaxUint8)
 }
 
-func (suite *Memory32Suite) TestSet_Fail() {
-	suite.sink.On("Handle", mock.Anything)
-	suite.memory.Set(21, math.MaxUint16, 16)
-	suite.sink.AssertCalled(suite.T(), "Handle", mock.Anything)
+func (suite *Memory32Suite) TestWrite_Fail() {
+	err := suite.memory.Write(20, LibMemory.HalfWord, math.MaxUint16)
+	assert.True(suite.T(), errors.Is(err, LibMemory.ErrAddressOutOfRange))
+	suite.AssertNoMemoryErrors()
+	suite.AssertMemoryAtAddressIs(20, LibMemory.Byte, 0)
 }
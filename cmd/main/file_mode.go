@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+
+	ErrorHandling "github.com/chenhowa/computer/cmd/errorHandling"
+	Computer "github.com/chenhowa/computer/lib"
+	Assembler "github.com/chenhowa/computer/lib/assembly"
+	CodeGeneration "github.com/chenhowa/computer/lib/assembly/codeGeneration"
+	Parser "github.com/chenhowa/computer/lib/assembly/parser"
+	Tokenizer "github.com/chenhowa/computer/lib/assembly/tokenizer"
+	Instruction "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	Clocks "github.com/chenhowa/computer/lib/clocks"
+	Delay "github.com/chenhowa/computer/lib/clocks/delay"
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	LibMemory "github.com/chenhowa/computer/lib/memory"
+	Loaders "github.com/chenhowa/computer/lib/programLoaders"
+)
+
+/*These constants are the exit codes of the application*/
+const (
+	exitSuccess = iota
+	exitUsageFailure
+	exitReadFailure
+	exitAssemblyFailure
+	exitExecutionFailure
+)
+
+/*maxSteps is the largest number of instructions that a program may execute before it is
+considered to be stuck in an infinite loop*/
+const maxSteps = 1 << 20
+
+/*maxErrorNumber is the number of memory errors, minus one, that are stored before the
+memory error handler gives up*/
+const maxErrorNumber = 0
+
+/*noProgramEnd is passed to runProgram for programs that only stop by halting. No instruction
+can be at this address, as instructions are always at even addresses*/
+const noProgramEnd = math.MaxUint32
+
+/*runFileMode assembles the program in the file at `path`, loads it at address 0, and runs it.
+If the file is an ELF executable, it is loaded and run from its entry point instead, until it halts.
+The machine runs on the platform that `options` describe. Its final state is written to `stdout`,
+and any errors to `stderr`. It returns the exit code of the application*/
+func runFileMode(path string, options platformOptions, stdout io.Writer, stderr io.Writer) int {
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitReadFailure
+	}
+
+	handler := ErrorHandling.MakeMemoryErrorHandler(maxErrorNumber)
+	clock := Clocks.MakeClock(&Delay.NoDelay{})
+	platform, err := makePlatform(options, &clock, &handler)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsageFailure
+	}
+	machine := Computer.MakeMachine(&platform.memory, 0, &clock)
+	platform.attach(&machine)
+
+	programEnd := uint32(noProgramEnd)
+	if bytes.HasPrefix(source, []byte(elf.ELFMAG)) {
+		loader := Loaders.MakeElfLoader(&platform.memory, &machine)
+		_, err = loader.Load(bytes.NewReader(source))
+	} else {
+		programEnd, err = assembleProgram(string(source), &platform.memory, &handler)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitAssemblyFailure
+	}
+
+	errRun := runProgram(&machine, &handler, programEnd)
+
+	dumpState(stdout, &machine, &platform)
+	if errRun != nil {
+		fmt.Fprintln(stderr, errRun)
+		return exitExecutionFailure
+	}
+
+	return exitSuccess
+}
+
+/*assembleProgram assembles the whole of `source`, and loads it into `memory` at address 0. It returns
+the address just past the last instruction*/
+func assembleProgram(source string, memory LibMemory.Writer, handler *ErrorHandling.MemoryErrorHandler) (uint32, error) {
+	instructions, err := assemble(source)
+	if err != nil {
+		return 0, err
+	}
+
+	return loadProgram(instructions, memory, handler)
+}
+
+/*assemble assembles the whole of `source`, which is assumed to be loaded at address 0*/
+func assemble(source string) ([]uint32, error) {
+	tokenizer := Tokenizer.MakeAdaptedRiscVTokenizer(&Tokenizer.RiscVTokenizer{})
+	parser := Parser.MakeRiscVParser()
+	adaptedParser := Parser.MakeAdaptedRiscVParser(&parser)
+	gen := CodeGeneration.MakeRiscVCodeGenerator(0)
+	assembler := Assembler.MakeRiscVAssembler(&tokenizer, &adaptedParser, &gen)
+
+	return assembler.Assemble(source)
+}
+
+/*loadProgram loads the assembled `instructions` one after the other, starting at address 0, and returns
+the address just past the last of them. Compressed instructions only take up 2 bytes*/
+func loadProgram(instructions []uint32, memory LibMemory.Writer, handler *ErrorHandling.MemoryErrorHandler) (uint32, error) {
+	address := uint32(0)
+	for _, instruction := range instructions {
+		width := LibMemory.Word
+		if Instruction.IsCompressed(instruction) {
+			width = LibMemory.HalfWord
+		}
+		if err := memory.Write(address, width, uint64(instruction)); err != nil {
+			return 0, fmt.Errorf("loadProgram: %d instructions do not fit in memory: %v", len(instructions), err)
+		}
+
+		address += uint32(width)
+	}
+
+	return address, memoryError(handler)
+}
+
+/*runProgram runs the `machine` until it halts, or until its program counter reaches `programEnd`,
+which is the address just past the last instruction of the program*/
+func runProgram(machine *Computer.Machine, handler *ErrorHandling.MemoryErrorHandler, programEnd uint32) error {
+	for steps := 0; !machine.IsHalted() && machine.GetProgramCounter() != programEnd; steps++ {
+		if steps == maxSteps {
+			return fmt.Errorf("runProgram: program did not finish within %d instructions", maxSteps)
+		}
+
+		address := machine.GetProgramCounter()
+		if err := machine.Step(); err != nil {
+			return err
+		}
+		if err := memoryError(handler); err != nil {
+			machine.Halt()
+			return fmt.Errorf("runProgram: instruction at address %d failed: %v", address, err)
+		}
+		if err := unhandledTrap(machine, address); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*unhandledTrap halts the `machine` and returns an error if the instruction at `address` trapped while
+the program had no trap handler. Assembled programs are loaded at address 0, and nothing else is, so a
+trap vector of 0 means that no handler was installed, and the trap would only start the program over*/
+func unhandledTrap(machine *Computer.Machine, address uint32) error {
+	exception, trapped := machine.GetLastTrap()
+	if !trapped {
+		return nil
+	}
+
+	vector := CsrManagers.Mtvec
+	if machine.GetPrivilege() == CsrManagers.Supervisor {
+		vector = CsrManagers.Stvec // the trap was delegated
+	}
+	if machine.GetCsr(vector) != 0 {
+		return nil
+	}
+
+	machine.Halt()
+	return fmt.Errorf("instruction at address %d raised an exception without a trap handler: %v", address, exception)
+}
+
+func memoryError(handler *ErrorHandling.MemoryErrorHandler) error {
+	if !handler.HasErrors() {
+		return nil
+	}
+
+	message, err := handler.GetErrorMessage(0)
+	if err != nil {
+		return errors.New("memory access failed")
+	}
+	return errors.New(message)
+}
+
+/*dumpState writes the final state of the `machine` and the RAM of its `platform` to `out`, in the format that
+is described by the documentation of main. Only the pages of RAM that have been written to can hold rows that
+are not all 0, and the devices are left out, as reading them could change them*/
+func dumpState(out io.Writer, machine *Computer.Machine, platform *platform) {
+	fmt.Fprintf(out, "pc 0x%08x\n", machine.GetProgramCounter())
+	for reg := uint(0); reg < 32; reg++ {
+		fmt.Fprintf(out, "x%d 0x%08x\n", reg, machine.GetRegister(reg))
+	}
+
+	fmt.Fprintln(out, "memory")
+	for _, ram := range platform.ram {
+		for _, page := range ram.memory.GetPageAddresses() {
+			dumpPage(out, ram, page)
+		}
+	}
+}
+
+/*dumpPage writes the rows of the page of `ram` at `page` that are not all 0 to `out`*/
+func dumpPage(out io.Writer, ram ramRegion, page uint32) {
+	const rowSize = 16
+	for rowAddress := page; rowAddress < page+LibMemory.SparsePageSize; rowAddress += rowSize {
+		row := [rowSize]uint8{}
+		isZero := true
+		for i := range row {
+			b, _ := ram.memory.Read(rowAddress+uint32(i), LibMemory.Byte) // the whole page is in memory
+			row[i] = uint8(b)
+			isZero = isZero && row[i] == 0
+		}
+
+		if isZero {
+			continue
+		}
+
+		fmt.Fprintf(out, "0x%08x", ram.base+rowAddress)
+		for _, b := range row {
+			fmt.Fprintf(out, " %02x", b)
+		}
+		fmt.Fprintln(out)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	Devices "github.com/chenhowa/computer/lib/devices"
+	ElfFixtures "github.com/chenhowa/computer/lib/programLoaders/elfFixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FileModeSuite struct {
+	suite.Suite
+	dir     string
+	stdout  *bytes.Buffer
+	stderr  *bytes.Buffer
+	options platformOptions
+}
+
+func TestFileModeSuite(t *testing.T) {
+	suite.Run(t, new(FileModeSuite))
+}
+
+func (suite *FileModeSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "file_mode")
+	suite.Require().Nil(err)
+	suite.dir = dir
+	suite.stdout = &bytes.Buffer{}
+	suite.stderr = &bytes.Buffer{}
+	suite.options = platformOptions{uartBase: Devices.UartBase, uartInput: strings.NewReader(""), uartOutput: suite.stdout}
+}
+
+func (suite *FileModeSuite) TearDownTest() {
+	os.RemoveAll(suite.dir)
+}
+
+func (suite *FileModeSuite) run(program string) int {
+	path := suite.dir + "/program.s"
+	suite.Require().Nil(ioutil.WriteFile(path, []byte(program), 0644))
+	return runFileMode(path, suite.options, suite.stdout, suite.stderr)
+}
+
+func (suite *FileModeSuite) TestRunsProgram() {
+	assert := assert.New(suite.T())
+	code := suite.run("ADDI x1 x0 5\nADDI x2 x0 7\nADD x3 x1 x2\nSW x3 64(x0)\n")
+
+	assert.Equal(exitSuccess, code)
+	assert.Equal("", suite.stderr.String())
+
+	lines := strings.Split(suite.stdout.String(), "\n")
+	assert.Equal("pc 0x00000010", lines[0])
+	assert.Equal("x0 0x00000000", lines[1])
+	assert.Equal("x3 0x0000000c", lines[4])
+	assert.Equal("memory", lines[33])
+	assert.Equal("0x00000040 0c 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00", lines[35])
+	assert.Equal("", lines[36])
+}
+
+func (suite *FileModeSuite) TestHaltsOnBreakpoint() {
+	assert := assert.New(suite.T())
+	code := suite.run("Loop:\nADDI x1 x1 1\nBEQ x1 x1 Skip\nJ Loop\nSkip: EBREAK\nADDI x2 x0 1")
+
+	assert.Equal(exitSuccess, code)
+	assert.Contains(suite.stdout.String(), "pc 0x00000010\nx0 0x00000000\nx1 0x00000001\nx2 0x00000000\n")
+}
+
+func (suite *FileModeSuite) TestRunsCompressedProgram() {
+	assert := assert.New(suite.T())
+	code := suite.run("C.LI x8 3\nLoop: C.ADDI x8 -1\nADDI x9 x9 100\nC.BNEZ x8 Loop\nC.MV x10 x9\n")
+
+	assert.Equal(exitSuccess, code)
+	assert.Equal("", suite.stderr.String())
+	assert.Contains(suite.stdout.String(), "pc 0x0000000c\n")
+	assert.Contains(suite.stdout.String(), "x10 0x0000012c\n")
+}
+
+func (suite *FileModeSuite) TestAssemblyFailure() {
+	assert := assert.New(suite.T())
+	code := suite.run("ADDI x1 x0")
+
+	assert.Equal(exitAssemblyFailure, code)
+	assert.Equal("", suite.stdout.String())
+	assert.Contains(suite.stderr.String(), "line 1")
+}
+
+func (suite *FileModeSuite) TestExecutionFailure() {
+	assert := assert.New(suite.T())
+	code := suite.run("LUI x1 32768\nLW x2 0(x1)") // nothing is mapped at 0x08000000
+
+	assert.Equal(exitExecutionFailure, code)
+	assert.True(strings.HasPrefix(suite.stdout.String(), "pc "))
+	assert.Contains(suite.stderr.String(), "address 4")
+}
+
+func (suite *FileModeSuite) TestInfiniteLoop() {
+	assert := assert.New(suite.T())
+	code := suite.run("Loop: J Loop")
+
+	assert.Equal(exitExecutionFailure, code)
+	assert.Contains(suite.stderr.String(), "did not finish")
+}
+
+func (suite *FileModeSuite) TestMissingFile() {
+	assert.Equal(suite.T(), exitReadFailure, runFileMode(suite.dir+"/missing.s", suite.options, suite.stdout, suite.stderr))
+}
+
+func (suite *FileModeSuite) TestRunsElfExecutable() {
+	assert := assert.New(suite.T())
+	code := suite.run(string(ElfFixtures.Build(ElfFixtures.Options{Address: 0x100})))
+
+	assert.Equal(exitSuccess, code)
+	assert.Equal("", suite.stderr.String())
+	assert.Contains(suite.stdout.String(), "pc 0x00000108\n")
+	assert.Contains(suite.stdout.String(), "x10 0x00000005\n")
+	assert.Contains(suite.stdout.String(), "0x00000100 13 05 50 00 73 00 10 00 00 00 00 00 00 00 00 00\n")
+}
+
+func (suite *FileModeSuite) TestMapsThePlic() {
+	assert := assert.New(suite.T())
+	code := suite.run("LUI x1 49152\nADDI x2 x0 3\nSW x2 4(x1)\nLW x3 4(x1)\n") // the priority of source 1
+
+	assert.Equal(exitSuccess, code)
+	assert.Equal("", suite.stderr.String())
+	assert.Contains(suite.stdout.String(), "x3 0x00000003\n")
+}
+
+func (suite *FileModeSuite) TestRunsElfExecutableLinkedAtRamBase() {
+	assert := assert.New(suite.T())
+	code := suite.run(string(ElfFixtures.Build(ElfFixtures.Options{Address: 0x80000000})))
+
+	assert.Equal(exitSuccess, code)
+	assert.Equal("", suite.stderr.String())
+	assert.Contains(suite.stdout.String(), "pc 0x80000008\n")
+	assert.Contains(suite.stdout.String(), "x10 0x00000005\n")
+	assert.Contains(suite.stdout.String(), "memory\n0x80000000 13 05 50 00 73 00 10 00 00 00 00 00 00 00 00 00\n")
+}
+
+func (suite *FileModeSuite) TestTransmitsThroughTheUart() {
+	assert := assert.New(suite.T())
+	code := suite.run("LUI x1 65536\nADDI x2 x0 104\nSB x2 0(x1)\nADDI x2 x0 105\nSB x2 0(x1)\n")
+
+	assert.Equal(exitSuccess, code)
+	assert.True(strings.HasPrefix(suite.stdout.String(), "hipc 0x00000014\n"))
+}
+
+func (suite *FileModeSuite) TestMovesTheUart() {
+	assert := assert.New(suite.T())
+	suite.options.uartBase = 0x20000000
+	code := suite.run("LUI x1 131072\nADDI x2 x0 104\nSB x2 0(x1)\n")
+
+	assert.Equal(exitSuccess, code)
+	assert.True(strings.HasPrefix(suite.stdout.String(), "hpc "))
+
+	suite.stdout.Reset()
+	suite.options.uartBase = 0x1000 // in RAM
+	assert.Equal(exitUsageFailure, suite.run("EBREAK"))
+	assert.Equal("", suite.stdout.String())
+	assert.Contains(suite.stderr.String(), "overlaps")
+}
+
+func (suite *FileModeSuite) TestUartInterruptsThroughThePlic() {
+	assert := assert.New(suite.T())
+	suite.options.uartInput = strings.NewReader("z")
+	code := suite.run(strings.Join([]string{
+		"LUI x1 49152", // the PLIC
+		"ADDI x2 x0 1",
+		"SW x2 40(x1)", // the priority of the UART's source is 1
+		"LUI x3 2",
+		"ADD x3 x3 x1",
+		"ADDI x2 x0 1024",
+		"SW x2 0(x3)",  // the machine context enables the UART's source
+		"LUI x4 65536", // the UART
+		"ADDI x2 x0 1",
+		"SB x2 1(x4)", // IER enables the received data interrupt
+		"ADDI x2 x0 72",
+		"CSRW 773 x2", // mtvec is the handler
+		"ADDI x2 x0 1",
+		"SLLI x2 x2 11",
+		"CSRW 772 x2", // mie.MEIE
+		"ADDI x2 x0 8",
+		"CSRW 768 x2", // mstatus.MIE
+		"Loop: J Loop",
+		"LB x5 0(x4)", // the handler reads the byte that arrived
+		"EBREAK",
+	}, "\n"))
+
+	assert.Equal(exitSuccess, code)
+	assert.Equal("", suite.stderr.String())
+	assert.Contains(suite.stdout.String(), "x5 0x0000007a\n")
+}
+
+func (suite *FileModeSuite) TestInvalidElfExecutable() {
+	assert := assert.New(suite.T())
+	code := suite.run(elf.ELFMAG + "garbage")
+
+	assert.Equal(exitAssemblyFailure, code)
+	assert.Equal("", suite.stdout.String())
+	assert.Contains(suite.stderr.String(), "not a valid ELF file")
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	ErrorHandling "github.com/chenhowa/computer/cmd/errorHandling"
+	Computer "github.com/chenhowa/computer/lib"
+	Disassembly "github.com/chenhowa/computer/lib/assembly/disassembly"
+	Instruction "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	Clocks "github.com/chenhowa/computer/lib/clocks"
+	Delay "github.com/chenhowa/computer/lib/clocks/delay"
+	LibMemory "github.com/chenhowa/computer/lib/memory"
+	Messages "github.com/chenhowa/computer/lib/memory/messages"
+	Sources "github.com/chenhowa/computer/lib/memory/sources"
+)
+
+/*runInteractiveMode starts with empty memory, and before each instruction is executed, it shows the user the
+instruction at the Program Counter and lets them replace it. Instructions are read line by line from `input`,
+and prompts are written to `prompts`. The machine runs on the platform that `options` describe. The session ends
+when the machine halts or the input ends, after which the final state of the machine is written to `stdout`.
+It returns the exit code of the application*/
+func runInteractiveMode(input io.Reader, prompts io.Writer, stdout io.Writer, options platformOptions) int {
+	handler := ErrorHandling.MakeMemoryErrorHandler(maxErrorNumber)
+	clock := Clocks.MakeClock(&Delay.NoDelay{})
+	platform, err := makePlatform(options, &clock, &handler)
+	if err != nil {
+		fmt.Fprintln(prompts, err)
+		return exitUsageFailure
+	}
+
+	inTransformer := assemblyInputTransformer{}
+	outTransformer := makeAssemblyOutputTransformer()
+	source := Sources.MakeCommandLineSource(input, prompts, &inTransformer, &outTransformer, &Messages.InstructionMessages{})
+	inputMemory := LibMemory.MakeUserInputReadMemory(&instructionMemory{memory: &platform.memory}, &source)
+
+	machine := Computer.MakeMachineWithInstructionMemory(&platform.memory, &inputMemory, 0, &clock)
+	platform.attach(&machine)
+
+	var errRun error
+	for !machine.IsHalted() {
+		address := machine.GetProgramCounter()
+		if err := machine.Step(); err != nil {
+			if errors.Is(err, Sources.ErrInputEnded) {
+				// The instruction at `address` was never entered, so it is still the next one to execute
+				machine.SetProgramCounter(address)
+			} else {
+				errRun = err
+			}
+			break
+		}
+		if err := memoryError(&handler); err != nil {
+			machine.Halt()
+			errRun = fmt.Errorf("runInteractiveMode: instruction at address %d failed: %v", address, err)
+		} else if err := unhandledTrap(&machine, address); err != nil {
+			errRun = err
+		}
+	}
+
+	dumpState(stdout, &machine, &platform)
+	if errRun != nil {
+		fmt.Fprintln(prompts, errRun)
+		return exitExecutionFailure
+	}
+
+	return exitSuccess
+}
+
+/*assemblyInputTransformer transforms one line of RISC-V assembly into its binary instruction*/
+type assemblyInputTransformer struct {
+}
+
+func (t *assemblyInputTransformer) Transform(input string) (uint32, error) {
+	instructions, err := assemble(input)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(instructions) != 1 {
+		return 0, fmt.Errorf("Transform: expected 1 instruction, but found %d", len(instructions))
+	}
+	return instructions[0], nil
+}
+
+/*assemblyOutputTransformer transforms a binary instruction into RISC-V assembly, or into
+its hexadecimal value if it is not a valid instruction*/
+type assemblyOutputTransformer struct {
+	disassembler Disassembly.RiscVDisassembler
+}
+
+func makeAssemblyOutputTransformer() assemblyOutputTransformer {
+	transformer := assemblyOutputTransformer{
+		disassembler: Disassembly.MakeRiscVDisassembler(),
+	}
+
+	return transformer
+}
+
+func (t *assemblyOutputTransformer) Transform(output uint32) string {
+	assembly, err := t.disassembler.Disassemble(output)
+	if err != nil {
+		return fmt.Sprintf("0x%08x", output)
+	}
+	return assembly
+}
+
+/*instructionMemory is the memory that a UserInputReadMemory writes the entered instructions to.
+Compressed instructions only take up 2 bytes, so only those are written for them, and the
+instruction after them is left alone*/
+type instructionMemory struct {
+	memory LibMemory.Interface
+}
+
+func (m *instructionMemory) Read(address uint32, width LibMemory.AccessWidth) (uint64, error) {
+	return m.memory.Read(address, width)
+}
+
+func (m *instructionMemory) Write(address uint32, width LibMemory.AccessWidth, val uint64) error {
+	if width == LibMemory.Word && Instruction.IsCompressed(uint32(val)) {
+		width = LibMemory.HalfWord
+	}
+	return m.memory.Write(address, width, val)
+}
+
+func (m *instructionMemory) GetAddressSpaceSize() uint {
+	return m.memory.GetAddressSpaceSize()
+}
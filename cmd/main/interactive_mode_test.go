@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	Devices "github.com/chenhowa/computer/lib/devices"
+	LibMemory "github.com/chenhowa/computer/lib/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InteractiveModeSuite struct {
+	suite.Suite
+	prompts *bytes.Buffer
+	stdout  *bytes.Buffer
+}
+
+func TestInteractiveModeSuite(t *testing.T) {
+	suite.Run(t, new(InteractiveModeSuite))
+}
+
+func (suite *InteractiveModeSuite) SetupTest() {
+	suite.prompts = &bytes.Buffer{}
+	suite.stdout = &bytes.Buffer{}
+}
+
+func (suite *InteractiveModeSuite) run(input string) int {
+	options := platformOptions{uartBase: Devices.UartBase, uartInput: strings.NewReader(""), uartOutput: suite.stdout}
+	return runInteractiveMode(strings.NewReader(input), suite.prompts, suite.stdout, options)
+}
+
+func (suite *InteractiveModeSuite) TestRunsEnteredInstructions() {
+	assert := assert.New(suite.T())
+	code := suite.run("ADDI x1 x0 5\nADDI x2 x1 2\nEBREAK\n")
+
+	assert.Equal(exitSuccess, code)
+
+	lines := strings.Split(suite.stdout.String(), "\n")
+	assert.Equal("pc 0x0000000c", lines[0])
+	assert.Equal("x1 0x00000005", lines[2])
+	assert.Equal("x2 0x00000007", lines[3])
+	assert.Equal("memory", lines[33])
+	assert.Contains(suite.prompts.String(), "Instruction 0x00000000 at address: 0")
+}
+
+func (suite *InteractiveModeSuite) TestEndOfInputEndsSession() {
+	assert := assert.New(suite.T())
+	code := suite.run("ADDI x1 x0 5\n")
+
+	assert.Equal(exitSuccess, code)
+	assert.Equal("pc 0x00000004", strings.Split(suite.stdout.String(), "\n")[0])
+}
+
+func (suite *InteractiveModeSuite) TestRepromptsOnInvalidInstruction() {
+	assert := assert.New(suite.T())
+	code := suite.run("ADDI x1\nADDI x1 x0 5\n")
+
+	assert.Equal(exitSuccess, code)
+	assert.Contains(suite.stdout.String(), "x1 0x00000005\n")
+	assert.Equal(3, strings.Count(suite.prompts.String(), "at address:"))
+}
+
+func (suite *InteractiveModeSuite) TestCompressedInstructionsKeepTheNextInstruction() {
+	assert := assert.New(suite.T())
+	memory := LibMemory.MakeSparseMemory32(0xFF)
+	entered := instructionMemory{memory: &memory}
+
+	assert.Nil(entered.Write(0, LibMemory.Word, 0x00500093)) // ADDI x1 x0 5
+	assert.Nil(entered.Write(0, LibMemory.Word, 0x4415))     // C.LI x8 5
+	instruction, err := entered.Read(0, LibMemory.Word)
+	assert.Nil(err)
+	assert.Equal(uint64(0x00504415), instruction)
+}
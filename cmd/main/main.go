@@ -1,11 +1,21 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"math"
 	"os"
+	"strings"
+
+	Devices "github.com/chenhowa/computer/lib/devices"
 )
 
-/*main describes an application simulates a 32-bit Risc-V CPU with 16-bit memory (only 65 KiB RAM).
+/*main describes an application simulates a 32-bit Risc-V CPU with 32-bit physical addresses.
+Its memory is RAM from address 0 up to the CLINT at 0x02000000, and RAM again from 0x80000000 to the end of
+the address space. Between them are a CLINT at 0x02000000, a PLIC at 0x0C000000, and a 16550 UART, whose
+registers are 1 byte apart, at 0x10000000. The UART can be moved with the -uart-base option, which takes
+a decimal or 0x-prefixed hexadecimal address. It transmits to standard output, ahead of the state of the machine,
+and, in file mode, it receives from standard input. It is source 10 of the PLIC.
 Once the simulation is done, the application will write the state of the memory and the registers
 to standard output, so be sure to redirect.
 The simulation run in two modes: interactive and file.
@@ -13,17 +23,50 @@ The simulation run in two modes: interactive and file.
 - In interactive mode, the user is repeatedly prompted to choose between executing the
 current instruction referenced by the Program Counter, and the instruction they can choose
 to write directly to the address of the current instruction. These instructions will be RISC-V
-assembly instructions for both input and output.
+assembly instructions for both input and output. Interactive mode is chosen by giving no path
+to a file. Each prompt shows the address and the current instruction; pressing <Enter> executes it, and
+entering an instruction replaces it first. The prompts are written to standard error, and the session ends
+when the program executes EBREAK or when standard input ends. As standard input holds the instructions,
+the UART receives nothing.
 
-- In file mode, the user must enter just one command line argument that is a valid path to a
+- In file mode, the user must enter just one command line argument, after the options, that is a valid path to a
 file. The application will evaluate the contents of the file for a valid Risc-V assembly program, and
 if valid, it will load and run the program as binary instructions to the simulated CPU.
+The program is loaded at address 0, and it runs until it executes EBREAK, until the Program Counter
+moves just past its last instruction, or until it fails. The file may also be a 32-bit RISC-V ELF executable,
+built by a standard toolchain, whose segments must be in RAM, such as one linked at 0x80000000. It is run
+from its entry point until it executes EBREAK, or until it fails.
+
+The state that is written to standard output has the following format, where every number is hexadecimal:
+
+	pc 0x<8 digits>              the address of the next instruction
+	x0 0x<8 digits>              one line for each register, from x0 to x31
+	...
+	x31 0x<8 digits>
+	memory
+	0x<8 digits> <16 bytes>      one line for each 16-byte row of RAM, in order of address,
+	...                          where each byte is 2 digits. Rows that are all 0 are left out.
+
+The application exits with status 0 on success, 1 if its arguments were wrong, 2 if the file could not
+be read, 3 if the program could not be assembled or loaded, and 4 if the program failed while running. The state is
+still written when the program fails while running. All errors are written to standard error.
 */
 func main() {
-	fmt.Println("Hello, world!")
-	argsWithoutProg := os.Args[1:]
-	fmt.Println(len(argsWithoutProg))
-	for i := uint(0); i < uint(len(argsWithoutProg)); i++ {
-		fmt.Println(argsWithoutProg[i])
+	flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	uartBase := flags.Uint("uart-base", uint(Devices.UartBase), "the address that the UART is mapped at")
+	if err := flags.Parse(os.Args[1:]); err != nil || *uartBase > math.MaxUint32 || flags.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "usage: main [-uart-base address] [path to RISC-V assembly file]")
+		os.Exit(exitUsageFailure)
+	}
+
+	options := platformOptions{
+		uartBase:   uint32(*uartBase),
+		uartInput:  os.Stdin,
+		uartOutput: os.Stdout,
+	}
+	if flags.NArg() == 0 {
+		options.uartInput = strings.NewReader("")
+		os.Exit(runInteractiveMode(os.Stdin, os.Stderr, os.Stdout, options))
 	}
+	os.Exit(runFileMode(flags.Arg(0), options, os.Stdout, os.Stderr))
 }
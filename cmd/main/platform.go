@@ -0,0 +1,87 @@
+package main
+
+import (
+	"io"
+	"math"
+
+	ErrorHandling "github.com/chenhowa/computer/cmd/errorHandling"
+	Computer "github.com/chenhowa/computer/lib"
+	Clocks "github.com/chenhowa/computer/lib/clocks"
+	Devices "github.com/chenhowa/computer/lib/devices"
+	LibMemory "github.com/chenhowa/computer/lib/memory"
+)
+
+/*These constants are where the RAM of the machine is. Assembled programs are loaded at address 0, in the RAM
+below the CLINT, and ELF executables are usually linked to run from ramBase, where RAM goes on to the end
+of the address space*/
+const (
+	lowRamSize uint32 = Devices.ClintBase
+	ramBase    uint32 = 0x80000000
+)
+
+/*uartSource is the source of the PLIC that the UART raises its interrupt on*/
+const uartSource = 10
+
+/*platformOptions are the choices that the user can make about the machine that the application runs*/
+type platformOptions struct {
+	uartBase   uint32    // the address that the UART is mapped at
+	uartInput  io.Reader // what the UART receives
+	uartOutput io.Writer // what the UART transmits to
+}
+
+/*platform is the physical memory of the machine that the application runs: RAM, a CLINT, a PLIC and a UART,
+mapped on a Bus. The UART asks for its interrupts through the PLIC*/
+type platform struct {
+	memory LibMemory.PanicMemory32
+	ram    []ramRegion
+	clint  *Devices.Clint
+	plic   *Devices.Plic
+}
+
+/*ramRegion is the RAM that is mapped at `base`*/
+type ramRegion struct {
+	base   uint32
+	memory *LibMemory.SparseMemory32
+}
+
+/*makePlatform is a constructor for platform. The CLINT counts the ticks of `clock`, and the panics of the
+memories and devices are handed to `handler`. It returns an error if the UART overlaps the RAM or another device*/
+func makePlatform(options platformOptions, clock *Clocks.Clock, handler *ErrorHandling.MemoryErrorHandler) (platform, error) {
+	lowRam := LibMemory.MakeSparseMemory32(lowRamSize - 1)
+	ram := LibMemory.MakeSparseMemory32(math.MaxUint32 - ramBase)
+	clint := Devices.MakeClint(clock, 1)
+	plic := Devices.MakePlic()
+	uart := Devices.MakeUart(options.uartInput, options.uartOutput)
+	plic.AttachDevice(uartSource, &uart)
+
+	bus := LibMemory.MakeBus()
+	regions := []struct {
+		base   uint32
+		memory LibMemory.Interface
+	}{
+		{0, &lowRam},
+		{Devices.ClintBase, &clint},
+		{Devices.PlicBase, &plic},
+		{options.uartBase, &uart},
+		{ramBase, &ram},
+	}
+	for _, region := range regions {
+		if err := bus.Map(region.base, region.memory); err != nil {
+			return platform{}, err
+		}
+	}
+
+	p := platform{
+		memory: LibMemory.MakePanicMemory32(&bus, handler),
+		ram:    []ramRegion{{base: 0, memory: &lowRam}, {base: ramBase, memory: &ram}},
+		clint:  &clint,
+		plic:   &plic,
+	}
+	return p, nil
+}
+
+/*attach makes the CLINT and the PLIC raise the interrupts of `machine`*/
+func (p *platform) attach(machine *Computer.Machine) {
+	machine.AddInterruptSource(p.clint)
+	machine.AddInterruptSource(p.plic)
+}
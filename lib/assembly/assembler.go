@@ -16,6 +16,19 @@ type RiscVAssembler struct {
 	gen       codeGenerator
 }
 
+/*MakeRiscVAssembler is a constructor for RiscVAssembler. The `tokenizer`, `parser` and `gen`
+are run in that order on every input given to Assemble*/
+func MakeRiscVAssembler(tokenizer tokenizer, parser parser, gen codeGenerator) RiscVAssembler {
+	assembler := RiscVAssembler{
+		lineCount: 0,
+		tokenizer: tokenizer,
+		parser:    parser,
+		gen:       gen,
+	}
+
+	return assembler
+}
+
 /*Assemble takes a set of string RISC-V `instructions` and converts them into
 32-bit machine code instructions. Errors may occur during assembly*/
 func (assembler *RiscVAssembler) Assemble(instructions string) ([]uint32, error) {
@@ -28,12 +41,12 @@ func (assembler *RiscVAssembler) Assemble(instructions string) ([]uint32, error)
 	if errParse != nil {
 		return nil, errParse
 	}
-	assembler.lineCount += count // THIS MIGHT NOT BE CORRECT (off by one)
 
 	binInstructions, errInstructions := assembler.gen.Generate(tree)
 	if errInstructions != nil {
 		return nil, errInstructions
 	}
+	assembler.lineCount += count // THIS MIGHT NOT BE CORRECT (off by one)
 
 	return binInstructions, nil
 }
@@ -44,50 +57,57 @@ type LineCount uint
 /*CharCount is simply a count of chars that have been encountered so far*/
 type CharCount uint
 
-type token interface {
+/*AssemblyToken is a token of the RISC-V assembly language, as produced by a tokenizer*/
+type AssemblyToken interface {
 	GetTokenType() TokenType
 	GetTokenString() string
 	GetCharCountSinceNewline() CharCount
 }
 
-type tokenStream interface {
+/*TokenStream is a stream of AssemblyTokens that can be saved and later reset to the saved position*/
+type TokenStream interface {
 	HasNext() bool
-	Next() (token, error)
-	Save() tokenStreamReset
+	Next() (AssemblyToken, error)
+	Save() TokenStreamReset
 }
 
-type tokenStreamReset interface {
+/*TokenStreamReset resets the TokenStream that produced it to a previously saved position*/
+type TokenStreamReset interface {
 	Reset()
 }
 
 type tokenizer interface {
-	Tokenize(tokens string) (tokenStream, error)
+	Tokenize(tokens string) (TokenStream, error)
 }
 
 type parser interface {
-	Parse(tokenStream tokenStream) (tree abstractSyntaxTree, linesEncountered LineCount, err error)
+	Parse(tokenStream TokenStream) (tree AbstractSyntaxTree, linesEncountered LineCount, err error)
 }
 
-type abstractSyntaxTree interface {
-	GetRootIterator() astIterator
+/*AbstractSyntaxTree is a parsed RISC-V assembly program*/
+type AbstractSyntaxTree interface {
+	GetRootIterator() AstIterator
 }
 
-type astIterator interface {
+/*AstIterator is an iterator over the nodes of an AbstractSyntaxTree*/
+type AstIterator interface {
 	GetNumChildren() uint
-	GetAstNode() astNode
-	GetParentIterator() (astIterator, error)
-	GetChildIterator(index uint) (astIterator, error)
+	GetAstNode() AstNode
+	GetParentIterator() (AstIterator, error)
+	GetChildIterator(index uint) (AstIterator, error)
 }
 
-type astNode interface {
-	GetLineCount()
+/*AstNode is a node of an AbstractSyntaxTree. Only nodes whose kind is `Token` hold a valid token*/
+type AstNode interface {
+	GetLineCount() LineCount
 	GetCharCountSinceNewline() CharCount
 	GetTokenType() TokenType
 	GetTokenString() string
+	GetNodeKind() AstNodeKind
 }
 
 type codeGenerator interface {
-	Generate(tree abstractSyntaxTree) ([]uint32, error)
+	Generate(tree AbstractSyntaxTree) ([]uint32, error)
 }
 
 /*AstNodeKind is an alias for uint, representing the kinds of nodes*/
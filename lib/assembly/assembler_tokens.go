@@ -40,6 +40,52 @@ const (
 	SRL
 	SUB
 	SRA
+	MUL
+	MULH
+	MULHSU
+	MULHU
+	DIV
+	DIVU
+	REM
+	REMU
+	LRW
+	SCW
+	AMOSWAPW
+	AMOADDW
+	AMOXORW
+	AMOANDW
+	AMOORW
+	AMOMINW
+	AMOMAXW
+	AMOMINUW
+	AMOMAXUW
+	CADDI4SPN
+	CLW
+	CSW
+	CNOP
+	CADDI
+	CJAL
+	CLI
+	CADDI16SP
+	CLUI
+	CSRLI
+	CSRAI
+	CANDI
+	CSUB
+	CXOR
+	COR
+	CAND
+	CJ
+	CBEQZ
+	CBNEZ
+	CSLLI
+	CLWSP
+	CSWSP
+	CJR
+	CMV
+	CEBREAK
+	CJALR
+	CADD
 	NOP
 	JAL
 	JALR
@@ -72,6 +118,11 @@ const (
 	CSRCI
 	ECALL
 	EBREAK
+	MRET
+	SRET
+	WFI
+	SFENCEVMA
+	FENCE
 	MV
 	SEQZ
 	NOT
@@ -0,0 +1,130 @@
+package codeGeneration
+
+import (
+	"fmt"
+
+	Assembler "github.com/chenhowa/computer/lib/assembly"
+)
+
+/*RiscVCodeGenerator generates binary instructions from the Abstract Syntax Tree
+of a RISC-V assembly program. Like the assembler that uses it, it never assumes that it has been
+given the whole program: it remembers the address of the next instruction it will generate, and
+the address of every label that it has seen so far.
+
+Labels may be referenced before they are defined, as long as they are defined somewhere in the same tree.
+If generation fails, none of the labels or instructions of the tree are remembered.*/
+type RiscVCodeGenerator struct {
+	address     uint32
+	symbolTable map[string]uint32
+}
+
+/*MakeRiscVCodeGenerator is a constructor for RiscVCodeGenerator. The first instruction
+that is generated is assumed to be loaded at `startAddress`*/
+func MakeRiscVCodeGenerator(startAddress uint32) RiscVCodeGenerator {
+	gen := RiscVCodeGenerator{
+		address:     startAddress,
+		symbolTable: map[string]uint32{},
+	}
+
+	return gen
+}
+
+/*These constants are the number of bytes that a generated instruction takes up in memory.
+Compressed instructions are returned in the lowest 16 bits of their uint32*/
+const (
+	instructionSize           = 4
+	compressedInstructionSize = 2
+)
+
+/*Generate converts the instructions of the `tree` into binary instructions, in order*/
+func (gen *RiscVCodeGenerator) Generate(tree Assembler.AbstractSyntaxTree) ([]uint32, error) {
+	instructions := getInstructionNodes(tree.GetRootIterator())
+
+	symbolTable, errLabels := gen.collectLabels(instructions)
+	if errLabels != nil {
+		return nil, errLabels
+	}
+
+	address := gen.address
+	binInstructions := []uint32{}
+	for _, instruction := range instructions {
+		if isLabel(instruction) {
+			continue
+		}
+
+		binInstruction, err := generateInstruction(instruction, address, symbolTable)
+		if err != nil {
+			return nil, err
+		}
+		binInstructions = append(binInstructions, binInstruction)
+		address += sizeOf(instruction.GetAstNode().GetTokenType())
+	}
+
+	gen.address = address
+	gen.symbolTable = symbolTable
+	return binInstructions, nil
+}
+
+/*GetAddress returns the address that the next generated instruction will be loaded at*/
+func (gen *RiscVCodeGenerator) GetAddress() uint32 {
+	return gen.address
+}
+
+/*GetLabelAddress returns the address of the instruction that follows the label `label`,
+if that label has been generated*/
+func (gen *RiscVCodeGenerator) GetLabelAddress(label string) (uint32, bool) {
+	address, ok := gen.symbolTable[label]
+	return address, ok
+}
+
+/*collectLabels returns a copy of the symbol table that also contains all the labels in `instructions`*/
+func (gen *RiscVCodeGenerator) collectLabels(instructions []Assembler.AstIterator) (map[string]uint32, error) {
+	symbolTable := map[string]uint32{}
+	for label, address := range gen.symbolTable {
+		symbolTable[label] = address
+	}
+
+	address := gen.address
+	for _, instruction := range instructions {
+		if !isLabel(instruction) {
+			address += sizeOf(instruction.GetAstNode().GetTokenType())
+			continue
+		}
+
+		node := instruction.GetAstNode()
+		if _, ok := symbolTable[node.GetTokenString()]; ok {
+			return nil, fmt.Errorf("Generate: line %d: label %s is already defined", node.GetLineCount(), node.GetTokenString())
+		}
+		symbolTable[node.GetTokenString()] = address
+	}
+
+	return symbolTable, nil
+}
+
+/*getInstructionNodes returns iterators to the label or mnemonic node of every instruction of the
+program pointed to by `root`. A tree whose root is not a list of instructions has no instructions*/
+func getInstructionNodes(root Assembler.AstIterator) []Assembler.AstIterator {
+	nodes := []Assembler.AstIterator{}
+	if root.GetAstNode().GetNodeKind() != Assembler.Instructions {
+		return nodes
+	}
+
+	for i := uint(0); i < root.GetNumChildren(); i++ {
+		instruction, err := root.GetChildIterator(i)
+		if err != nil {
+			panic("getInstructionNodes: grabbed an invalid child iterator")
+		}
+
+		node, err := instruction.GetChildIterator(0)
+		if err != nil {
+			panic("getInstructionNodes: instruction has no label or mnemonic")
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+func isLabel(iter Assembler.AstIterator) bool {
+	return iter.GetAstNode().GetTokenType() == Assembler.Label
+}
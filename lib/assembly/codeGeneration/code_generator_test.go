@@ -0,0 +1,201 @@
+package codeGeneration
+
+import (
+	"testing"
+
+	Assembler "github.com/chenhowa/computer/lib/assembly"
+	Parser "github.com/chenhowa/computer/lib/assembly/parser"
+	Tokenizer "github.com/chenhowa/computer/lib/assembly/tokenizer"
+	Producer "github.com/chenhowa/computer/lib/binaryInstructionExecution/executionFactoryProducers"
+	Instruction "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CodeGeneratorSuite struct {
+	suite.Suite
+	assembler *Assembler.RiscVAssembler
+	gen       *RiscVCodeGenerator
+}
+
+func TestCodeGeneratorSuite(t *testing.T) {
+	suite.Run(t, new(CodeGeneratorSuite))
+}
+
+func (suite *CodeGeneratorSuite) SetupTest() {
+	tokenizer := Tokenizer.MakeAdaptedRiscVTokenizer(&Tokenizer.RiscVTokenizer{})
+	parser := Parser.MakeRiscVParser()
+	adaptedParser := Parser.MakeAdaptedRiscVParser(&parser)
+	gen := MakeRiscVCodeGenerator(0)
+	suite.gen = &gen
+
+	assembler := Assembler.MakeRiscVAssembler(&tokenizer, &adaptedParser, suite.gen)
+	suite.assembler = &assembler
+}
+
+func (suite *CodeGeneratorSuite) TestArithmetic() {
+	assert := assert.New(suite.T())
+	instructions, err := suite.assembler.Assemble("ADDI x1 x0 -5\nSUB x3 x1 x2\nSRAI x4 x1 3\nLUI x5 0xFFFFF")
+
+	assert.Nil(err)
+	assert.Equal([]uint32{
+		Instruction.BuildInstructionI(uint(Instruction.ImmArith), 1, uint(Producer.AddI), 0, 0xFFB),
+		Instruction.BuildInstructionR(uint(Instruction.RegArith), 3, uint(Producer.Sub), 1, 2, uint(Producer.F1)),
+		Instruction.BuildInstructionI(uint(Instruction.ImmArith), 4, uint(Producer.ShiftRight), 1, 3|(1<<10)),
+		Instruction.BuildInstructionU(uint(Instruction.LUI), 5, 0xFFFFF),
+	}, instructions)
+	assert.Equal(uint32(16), suite.gen.GetAddress())
+}
+
+func (suite *CodeGeneratorSuite) TestLoadsAndStores() {
+	assert := assert.New(suite.T())
+	instructions, err := suite.assembler.Assemble("LW x1 -4(x2)\nSB x3 1,000(x4)")
+
+	assert.Nil(err)
+	assert.Equal([]uint32{
+		Instruction.BuildInstructionI(uint(Instruction.Load), 1, uint(Producer.LoadWord), 2, 0xFFC),
+		Instruction.BuildInstructionS(uint(Instruction.Store), uint(Producer.StoreByte), 4, 3, 1000),
+	}, instructions)
+}
+
+func (suite *CodeGeneratorSuite) TestLabels() {
+	assert := assert.New(suite.T())
+	instructions, err := suite.assembler.Assemble("Start: BEQ x1 x2 End\nJ Start\nEnd:\nEBREAK")
+
+	assert.Nil(err)
+	assert.Equal([]uint32{
+		Instruction.BuildInstructionB(uint(Instruction.Branch), uint(Producer.Beq), 1, 2, 8),
+		Instruction.BuildInstructionJ(uint(Instruction.JAL), 0, 0x1FFFFC),
+		Instruction.BuildInstructionI(uint(Instruction.System), 0, uint(Producer.Private), 0, uint(Producer.EBREAK)),
+	}, instructions)
+
+	address, ok := suite.gen.GetLabelAddress("End")
+	assert.True(ok)
+	assert.Equal(uint32(8), address)
+}
+
+func (suite *CodeGeneratorSuite) TestLabels_RememberedAcrossCalls() {
+	assert := assert.New(suite.T())
+	_, err := suite.assembler.Assemble("Loop:\nNOP")
+	assert.Nil(err)
+
+	instructions, err := suite.assembler.Assemble("J Loop")
+	assert.Nil(err)
+	assert.Equal([]uint32{Instruction.BuildInstructionJ(uint(Instruction.JAL), 0, 0x1FFFFC)}, instructions)
+}
+
+func (suite *CodeGeneratorSuite) TestToolchainEncoding() {
+	assert := assert.New(suite.T())
+	instructions, err := suite.assembler.Assemble("ADDI x10 x0 5\nSUB x3 x1 x2\nSRAI x4 x1 3\nLW x6 -4(x8)\nSW x5 8(x2)\n" +
+		"BNE x1 x2 -8\nJAL x1 2048\nJ -4\nCSRR x3 768\nFENCE\nECALL\nEBREAK\n" +
+		"MUL x10 x11 x12\nDIVU x5 x6 x7\nREM x1 x2 x3\n" +
+		"LR.W x10 (x11)\nSC.W x10 x12 (x11)\nAMOADD.W.AQRL x10 x12 0(x11)\nAMOSWAP.W.AQ x1 x2 (x3)\nMRET\nSRET\nWFI\nSFENCE.VMA x10 x11")
+
+	assert.Nil(err)
+	assert.Equal([]uint32{
+		0x00500513, 0x402081B3, 0x4030D213, 0xFFC42303, 0x00512423,
+		0xFE209CE3, 0x001000EF, 0xFFDFF06F, 0x300021F3, 0x0FF0000F, 0x00000073, 0x00100073,
+		0x02C58533, 0x027352B3, 0x023160B3,
+		0x1005A52F, 0x18C5A52F, 0x06C5A52F, 0x0C21A0AF, 0x30200073, 0x10200073, 0x10500073, 0x12B50073,
+	}, instructions)
+}
+
+func (suite *CodeGeneratorSuite) TestCompressedToolchainEncoding() {
+	assert := assert.New(suite.T())
+	instructions, err := suite.assembler.Assemble("C.ADDI4SPN x8 x2 1020\nC.LW x10 124(x11)\nC.SW x15 4(x9)\nC.NOP\n" +
+		"C.ADDI x5 -32\nC.JAL -2048\nC.LI x6 31\nC.ADDI16SP x2 -512\nC.LUI x7 0xFFFE0\nC.SRLI x8 31\nC.SRAI x9 1\n" +
+		"C.ANDI x10 -1\nC.SUB x11 x12\nC.XOR x13 x14\nC.OR x15 x8\nC.AND x8 x9\nC.J 2046\nC.BEQZ x9 -256\n" +
+		"C.BNEZ x10 254\nC.SLLI x31 17\nC.LWSP x2 252(x2)\nC.JR x1\nC.MV x4 x5\nC.EBREAK\nC.JALR x6\n" +
+		"C.ADD x7 x8\nC.SWSP x10 252(x2)")
+
+	assert.Nil(err)
+	assert.Equal([]uint32{
+		0x1FE0, 0x5DE8, 0xC0DC, 0x0001, 0x1281, 0x3001, 0x437D, 0x7101, 0x7381, 0x807D, 0x8485,
+		0x997D, 0x8D91, 0x8EB9, 0x8FC1, 0x8C65, 0xAFFD, 0xD081, 0xED7D, 0x0FC6, 0x517E, 0x8082,
+		0x8216, 0x9002, 0x9302, 0x93A2, 0xDFAA,
+	}, instructions)
+	assert.Equal(uint32(2*27), suite.gen.GetAddress())
+}
+
+func (suite *CodeGeneratorSuite) TestCounterToolchainEncoding() {
+	assert := assert.New(suite.T())
+	instructions, err := suite.assembler.Assemble("RDCYCLE x5\nRDCYCLEH x5\nRDTIME x5\nRDTIMEH x5\nRDINSTRET x5\nRDINSTRETH x5")
+
+	assert.Nil(err)
+	assert.Equal([]uint32{0xC00022F3, 0xC80022F3, 0xC01022F3, 0xC81022F3, 0xC02022F3, 0xC82022F3}, instructions)
+
+	_, err = suite.assembler.Assemble("RDCYCLE x5 x6")
+	assert.NotNil(err)
+}
+
+func (suite *CodeGeneratorSuite) TestCompressedLabels() {
+	assert := assert.New(suite.T())
+	instructions, err := suite.assembler.Assemble("C.LI x8 3\nLoop: C.ADDI x8 -1\nADDI x9 x9 100\nC.BNEZ x8 Loop\nJ End\nEnd:")
+
+	assert.Nil(err)
+	assert.Equal([]uint32{0x440D, 0x147D, 0x06448493, 0xFC6D, 0x0040006F}, instructions)
+	address, ok := suite.gen.GetLabelAddress("End")
+	assert.True(ok)
+	assert.Equal(uint32(14), address)
+}
+
+func (suite *CodeGeneratorSuite) TestCompressedErrors() {
+	assert := assert.New(suite.T())
+
+	_, err := suite.assembler.Assemble("C.SUB x1 x8")
+	assert.EqualError(err, "Generate: line 1: C.SUB: operand 1 (x1) must be one of the registers x8 to x15")
+
+	_, err = suite.assembler.Assemble("C.LW x8 2(x9)")
+	assert.EqualError(err, "Generate: line 1: C.LW: operand 2 (2(x9)) must be a multiple of 4")
+
+	_, err = suite.assembler.Assemble("C.LWSP x8 4(x3)")
+	assert.EqualError(err, "Generate: line 1: C.LWSP: operand 2 (4(x3)) must use the stack pointer x2")
+
+	_, err = suite.assembler.Assemble("C.ADDI16SP x2 0")
+	assert.EqualError(err, "Generate: line 1: C.ADDI16SP: operand 2 (0) must not be 0")
+
+	_, err = suite.assembler.Assemble("C.LUI x7 32")
+	assert.EqualError(err, "Generate: line 1: C.LUI: operand 2 (32) must be the upper bits of a 6-bit immediate")
+
+	_, err = suite.assembler.Assemble("C.BEQZ x8 256")
+	assert.EqualError(err, "Generate: line 1: C.BEQZ: operand 2 (256) must be between -256 and 254")
+}
+
+func (suite *CodeGeneratorSuite) TestPseudoInstructions() {
+	assert := assert.New(suite.T())
+	instructions, err := suite.assembler.Assemble("MV x1 x2\nBGT x1 x2 0\nCSRR x3 5")
+
+	assert.Nil(err)
+	assert.Equal([]uint32{
+		Instruction.BuildInstructionI(uint(Instruction.ImmArith), 1, uint(Producer.AddI), 2, 0),
+		Instruction.BuildInstructionB(uint(Instruction.Branch), uint(Producer.Blt), 2, 1, 0),
+		Instruction.BuildInstructionI(uint(Instruction.System), 3, uint(Producer.CSRRS), 0, 5),
+	}, instructions)
+}
+
+func (suite *CodeGeneratorSuite) TestErrors() {
+	assert := assert.New(suite.T())
+
+	_, err := suite.assembler.Assemble("ADDI x1 x0 2048")
+	assert.EqualError(err, "Generate: line 1: ADDI: operand 3 (2048) must be between -2048 and 2047")
+
+	_, err = suite.assembler.Assemble("\nADD x1 x2")
+	assert.EqualError(err, "Generate: line 2: ADD: 2 operands is not a valid number of operands")
+
+	_, err = suite.assembler.Assemble("BEQ x1 x2 6\nJ 3")
+	assert.EqualError(err, "Generate: line 2: J: operand 1 (3) must be a multiple of 2")
+
+	_, err = suite.assembler.Assemble("BEQ x1 x2 4096")
+	assert.EqualError(err, "Generate: line 1: BEQ: operand 3 (4096) must be between -4096 and 4094")
+
+	_, err = suite.assembler.Assemble("J Nowhere")
+	assert.EqualError(err, "Generate: line 1: J: label Nowhere is not defined")
+
+	_, err = suite.assembler.Assemble("AMOADD.W x1 x2 4(x3)")
+	assert.EqualError(err, "Generate: line 1: AMOADD.W: operand 3 (4(x3)) does not have the form (register)")
+
+	_, err = suite.assembler.Assemble("Here:\nHere:")
+	assert.EqualError(err, "Generate: line 2: label Here is already defined")
+
+	assert.Equal(uint32(0), suite.gen.GetAddress())
+}
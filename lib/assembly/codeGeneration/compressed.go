@@ -0,0 +1,464 @@
+package codeGeneration
+
+import (
+	Assembler "github.com/chenhowa/computer/lib/assembly"
+)
+
+/*These constants are the ranges of the immediates that compressed instructions accept*/
+const (
+	minImmediate6             = -(1 << 5)
+	maxImmediate6             = (1 << 5) - 1
+	maxStackOffset            = (1 << 10) - 4 // the largest offset of C.ADDI4SPN
+	minStackAdjustment        = -(1 << 9)     // the smallest and largest amounts that C.ADDI16SP adds
+	maxStackAdjustment        = (1 << 9) - 16
+	maxWordOffset             = (1 << 7) - 4 // the largest offset of C.LW and C.SW
+	maxStackWordOffset        = (1 << 8) - 4 // the largest offset of C.LWSP and C.SWSP
+	minCompressedJumpOffset   = -(1 << 11)
+	maxCompressedJumpOffset   = (1 << 11) - 2
+	minCompressedBranchOffset = -(1 << 8)
+	maxCompressedBranchOffset = (1 << 8) - 2
+	stackPointer              = 2
+)
+
+/*segment places bits `high` to `low` of an immediate at bit `at` of a compressed instruction*/
+type segment struct {
+	high uint
+	low  uint
+	at   uint
+}
+
+/*layout lists where each bit of an immediate goes. Compressed instructions scatter the bits of their
+immediates, and each of them scatters them in its own way*/
+type layout []segment
+
+/*These layouts are those of the spec, one for each way that compressed instructions scatter their immediates*/
+var (
+	stackOffsetLayout      = layout{{5, 4, 11}, {9, 6, 7}, {2, 2, 6}, {3, 3, 5}}
+	wordOffsetLayout       = layout{{5, 3, 10}, {2, 2, 6}, {6, 6, 5}}
+	immediateLayout        = layout{{5, 5, 12}, {4, 0, 2}}
+	stackAdjustmentLayout  = layout{{9, 9, 12}, {4, 4, 6}, {6, 6, 5}, {8, 7, 3}, {5, 5, 2}}
+	jumpLayout             = layout{{11, 11, 12}, {4, 4, 11}, {9, 8, 9}, {10, 10, 8}, {6, 6, 7}, {7, 7, 6}, {3, 1, 3}, {5, 5, 2}}
+	branchLayout           = layout{{8, 8, 12}, {4, 3, 10}, {7, 6, 5}, {2, 1, 3}, {5, 5, 2}}
+	loadStackOffsetLayout  = layout{{5, 5, 12}, {4, 2, 4}, {7, 6, 2}}
+	storeStackOffsetLayout = layout{{5, 2, 9}, {7, 6, 7}}
+)
+
+func (l layout) encode(immediate uint) uint {
+	encoded := uint(0)
+	for _, s := range l {
+		width := s.high - s.low + 1
+		encoded |= ((immediate >> s.low) & ((1 << width) - 1)) << s.at
+	}
+	return encoded
+}
+
+/*compressedGenerators maps each compressed mnemonic to the function that generates its 16-bit instruction.
+The instruction is returned in the lowest 16 bits, and takes up only 2 bytes of memory.
+The F and D loads and stores are left out, as the assembler does not have floating-point registers*/
+var compressedGenerators = map[Assembler.TokenType]generationFunction{
+	Assembler.CADDI4SPN: addImmediateToStackPointer,
+	Assembler.CLW:       compressedLoadStore(2),
+	Assembler.CSW:       compressedLoadStore(6),
+	Assembler.CNOP:      compressedNop,
+	Assembler.CADDI:     compressedImmediate(0),
+	Assembler.CJAL:      compressedJump(1),
+	Assembler.CLI:       compressedImmediate(2),
+	Assembler.CADDI16SP: adjustStackPointer,
+	Assembler.CLUI:      compressedUpperImmediate,
+	Assembler.CSRLI:     compressedShift(1, 4, 0),
+	Assembler.CSRAI:     compressedShift(1, 4, 1<<10),
+	Assembler.CANDI:     compressedAndImmediate,
+	Assembler.CSUB:      compressedArithmetic(0),
+	Assembler.CXOR:      compressedArithmetic(1),
+	Assembler.COR:       compressedArithmetic(2),
+	Assembler.CAND:      compressedArithmetic(3),
+	Assembler.CJ:        compressedJump(5),
+	Assembler.CBEQZ:     compressedBranch(6),
+	Assembler.CBNEZ:     compressedBranch(7),
+	Assembler.CSLLI:     compressedShift(2, 0, 0),
+	Assembler.CLWSP:     loadFromStack,
+	Assembler.CSWSP:     storeToStack,
+	Assembler.CJR:       compressedJumpRegister(false),
+	Assembler.CMV:       compressedRegister(false),
+	Assembler.CEBREAK:   compressedBreakpoint,
+	Assembler.CJALR:     compressedJumpRegister(true),
+	Assembler.CADD:      compressedRegister(true),
+}
+
+/*sizeOf returns the number of bytes that the instruction with mnemonic `tokenType` takes up in memory*/
+func sizeOf(tokenType Assembler.TokenType) uint32 {
+	if _, ok := compressedGenerators[tokenType]; ok {
+		return compressedInstructionSize
+	}
+	return instructionSize
+}
+
+/*compressed builds a compressed instruction out of its quadrant, its Funct3 and the rest of its `fields`*/
+func compressed(quadrant uint, funct3 uint, fields uint) uint32 {
+	return uint32(quadrant | funct3<<13 | fields)
+}
+
+/*compactRegister returns the 3-bit number of the register that is operand `index`. Most compressed
+instructions can only name the registers x8 to x15*/
+func (ops *operands) compactRegister(index int) (uint, error) {
+	register, err := ops.register(index)
+	if err != nil {
+		return 0, err
+	}
+	if register < 8 || register > 15 {
+		return 0, ops.errorf("operand %d (%s) must be one of the registers x8 to x15", index+1, ops.nodes[index].GetTokenString())
+	}
+	return register - 8, nil
+}
+
+/*nonZero returns an error if `value`, the value of operand `index`, is 0*/
+func (ops *operands) nonZero(index int, value uint) error {
+	if value == 0 {
+		return ops.errorf("operand %d (%s) must not be 0", index+1, ops.nodes[index].GetTokenString())
+	}
+	return nil
+}
+
+/*isStackPointer returns an error unless `register`, the register of operand `index`, is the stack pointer*/
+func (ops *operands) isStackPointer(index int, register uint) error {
+	if register != stackPointer {
+		return ops.errorf("operand %d (%s) must use the stack pointer x2", index+1, ops.nodes[index].GetTokenString())
+	}
+	return nil
+}
+
+/*addImmediateToStackPointer generates `C.ADDI4SPN rd' x2 uimm`*/
+func addImmediateToStackPointer(ops *operands) (uint32, error) {
+	if err := ops.expect(3); err != nil {
+		return 0, err
+	}
+	dest, err := ops.compactRegister(0)
+	if err != nil {
+		return 0, err
+	}
+	base, err := ops.register(1)
+	if err == nil {
+		err = ops.isStackPointer(1, base)
+	}
+	if err != nil {
+		return 0, err
+	}
+	immediate, err := ops.immediate(2, 0, maxStackOffset)
+	if err == nil {
+		err = ops.aligned(2, immediate, 4)
+	}
+	if err == nil {
+		err = ops.nonZero(2, immediate)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return compressed(0, 0, stackOffsetLayout.encode(immediate)|dest<<2), nil
+}
+
+/*compressedLoadStore generates `C.LW rd' offset(rs1')` or `C.SW rs2' offset(rs1')`*/
+func compressedLoadStore(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		register, err := ops.compactRegister(0)
+		if err != nil {
+			return 0, err
+		}
+		base, offset, err := ops.registerAndImmediate(1, 0, maxWordOffset)
+		if err == nil {
+			err = ops.aligned(1, offset, 4)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if base < 8 || base > 15 {
+			return 0, ops.errorf("operand 2 (%s) must be one of the registers x8 to x15", ops.nodes[1].GetTokenString())
+		}
+
+		return compressed(0, funct3, wordOffsetLayout.encode(offset)|(base-8)<<7|register<<2), nil
+	}
+}
+
+/*compressedNop generates `C.NOP` as `C.ADDI x0 0`*/
+func compressedNop(ops *operands) (uint32, error) {
+	if err := ops.expect(0); err != nil {
+		return 0, err
+	}
+
+	return compressed(1, 0, 0), nil
+}
+
+/*compressedImmediate generates `C.ADDI rd imm` or `C.LI rd imm`*/
+func compressedImmediate(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		dest, err := ops.register(0)
+		if err != nil {
+			return 0, err
+		}
+		immediate, err := ops.immediate(1, minImmediate6, maxImmediate6)
+		if err != nil {
+			return 0, err
+		}
+
+		return compressed(1, funct3, immediateLayout.encode(immediate)|dest<<7), nil
+	}
+}
+
+/*adjustStackPointer generates `C.ADDI16SP x2 imm`, which adds a nonzero multiple of 16 to the stack pointer*/
+func adjustStackPointer(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	dest, err := ops.register(0)
+	if err == nil {
+		err = ops.isStackPointer(0, dest)
+	}
+	if err != nil {
+		return 0, err
+	}
+	immediate, err := ops.immediate(1, minStackAdjustment, maxStackAdjustment)
+	if err == nil {
+		err = ops.aligned(1, immediate, 16)
+	}
+	if err == nil {
+		err = ops.nonZero(1, immediate)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return compressed(1, 3, stackAdjustmentLayout.encode(immediate)|stackPointer<<7), nil
+}
+
+/*compressedUpperImmediate generates `C.LUI rd imm`. Like LUI, it takes the upper 20 bits of the value,
+which must be the sign extension of a nonzero 6-bit immediate*/
+func compressedUpperImmediate(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	dest, err := ops.register(0)
+	if err != nil {
+		return 0, err
+	}
+	if dest == 0 || dest == stackPointer {
+		return 0, ops.errorf("operand 1 (%s) must not be x0 or x2", ops.nodes[0].GetTokenString())
+	}
+	immediate, err := ops.immediate(1, 0, maxImmediate20)
+	if err == nil {
+		err = ops.nonZero(1, immediate)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if immediate > maxImmediate6 && immediate < maxImmediate20+1+minImmediate6 {
+		return 0, ops.errorf("operand 2 (%s) must be the upper bits of a 6-bit immediate", ops.nodes[1].GetTokenString())
+	}
+
+	return compressed(1, 3, immediateLayout.encode(immediate)|dest<<7), nil
+}
+
+/*compressedShift generates `C.SRLI rd' shamt`, `C.SRAI rd' shamt` or `C.SLLI rd shamt`. `flags` are the
+bits that distinguish the right shifts*/
+func compressedShift(quadrant uint, funct3 uint, flags uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		var dest uint
+		var err error
+		if quadrant == 2 {
+			dest, err = ops.register(0)
+		} else {
+			dest, err = ops.compactRegister(0)
+		}
+		if err != nil {
+			return 0, err
+		}
+		shamt, err := ops.immediate(1, 0, maxShiftAmount)
+		if err != nil {
+			return 0, err
+		}
+
+		return compressed(quadrant, funct3, flags|dest<<7|shamt<<2), nil
+	}
+}
+
+/*compressedAndImmediate generates `C.ANDI rd' imm`*/
+func compressedAndImmediate(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	dest, err := ops.compactRegister(0)
+	if err != nil {
+		return 0, err
+	}
+	immediate, err := ops.immediate(1, minImmediate6, maxImmediate6)
+	if err != nil {
+		return 0, err
+	}
+
+	return compressed(1, 4, 2<<10|immediateLayout.encode(immediate)|dest<<7), nil
+}
+
+/*compressedArithmetic generates `C.SUB rd' rs2'`, `C.XOR rd' rs2'`, `C.OR rd' rs2'` or `C.AND rd' rs2'`,
+which are selected by `operation`*/
+func compressedArithmetic(operation uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		dest, err := ops.compactRegister(0)
+		if err != nil {
+			return 0, err
+		}
+		src, err := ops.compactRegister(1)
+		if err != nil {
+			return 0, err
+		}
+
+		return compressed(1, 4, 3<<10|dest<<7|operation<<5|src<<2), nil
+	}
+}
+
+/*compressedJump generates `C.J target` or `C.JAL target`, which links to the return address register*/
+func compressedJump(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(1); err != nil {
+			return 0, err
+		}
+		offset, err := ops.target(0, minCompressedJumpOffset, maxCompressedJumpOffset)
+		if err != nil {
+			return 0, err
+		}
+
+		return compressed(1, funct3, jumpLayout.encode(offset)), nil
+	}
+}
+
+/*compressedBranch generates `C.BEQZ rs1' target` or `C.BNEZ rs1' target`*/
+func compressedBranch(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		src, err := ops.compactRegister(0)
+		if err != nil {
+			return 0, err
+		}
+		offset, err := ops.target(1, minCompressedBranchOffset, maxCompressedBranchOffset)
+		if err != nil {
+			return 0, err
+		}
+
+		return compressed(1, funct3, branchLayout.encode(offset)|src<<7), nil
+	}
+}
+
+/*loadFromStack generates `C.LWSP rd offset(x2)`*/
+func loadFromStack(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	dest, err := ops.register(0)
+	if err == nil {
+		err = ops.nonZero(0, dest)
+	}
+	if err != nil {
+		return 0, err
+	}
+	offset, err := ops.stackOffset(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return compressed(2, 2, loadStackOffsetLayout.encode(offset)|dest<<7), nil
+}
+
+/*storeToStack generates `C.SWSP rs2 offset(x2)`*/
+func storeToStack(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	src, err := ops.register(0)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := ops.stackOffset(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return compressed(2, 6, storeStackOffsetLayout.encode(offset)|src<<2), nil
+}
+
+/*stackOffset returns the offset of operand `index`, which must have the form `offset(x2)`*/
+func (ops *operands) stackOffset(index int) (uint, error) {
+	base, offset, err := ops.registerAndImmediate(index, 0, maxStackWordOffset)
+	if err == nil {
+		err = ops.isStackPointer(index, base)
+	}
+	if err == nil {
+		err = ops.aligned(index, offset, 4)
+	}
+	return offset, err
+}
+
+/*compressedJumpRegister generates `C.JR rs1` or `C.JALR rs1`, which links to the return address register*/
+func compressedJumpRegister(link bool) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(1); err != nil {
+			return 0, err
+		}
+		src, err := ops.register(0)
+		if err == nil {
+			err = ops.nonZero(0, src)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		return compressed(2, 4, linkBit(link)|src<<7), nil
+	}
+}
+
+/*compressedRegister generates `C.MV rd rs2` or `C.ADD rd rs2`, which adds rs2 to rd*/
+func compressedRegister(add bool) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		registers, err := ops.registers(2)
+		if err == nil {
+			err = ops.nonZero(1, registers[1])
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		return compressed(2, 4, linkBit(add)|registers[0]<<7|registers[1]<<2), nil
+	}
+}
+
+/*compressedBreakpoint generates `C.EBREAK`*/
+func compressedBreakpoint(ops *operands) (uint32, error) {
+	if err := ops.expect(0); err != nil {
+		return 0, err
+	}
+
+	return compressed(2, 4, linkBit(true)), nil
+}
+
+/*linkBit returns bit 12 of the instructions that share quadrant 2 and Funct3 4, which is set
+for C.EBREAK, C.JALR and C.ADD*/
+func linkBit(set bool) uint {
+	if set {
+		return 1 << 12
+	}
+	return 0
+}
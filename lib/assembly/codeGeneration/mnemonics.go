@@ -0,0 +1,607 @@
+package codeGeneration
+
+import (
+	Assembler "github.com/chenhowa/computer/lib/assembly"
+	Producer "github.com/chenhowa/computer/lib/binaryInstructionExecution/executionFactoryProducers"
+	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+)
+
+type generationFunction func(ops *operands) (uint32, error)
+
+/*These constants are the ranges of the immediates that instructions accept*/
+const (
+	minImmediate12      = -(1 << 11)
+	maxImmediate12      = (1 << 11) - 1
+	maxUnsigned12       = (1 << 12) - 1
+	maxImmediate20      = (1 << 20) - 1
+	minBranchOffset     = -(1 << 12)
+	maxBranchOffset     = (1 << 12) - 2
+	minJumpOffset       = -(1 << 20)
+	maxJumpOffset       = (1 << 20) - 2
+	maxShiftAmount      = 31
+	maxCsrImmediate     = 31
+	arithmeticShiftFlag = 1 << 10 // the immediate bit that selects an arithmetic right shift
+	fenceAll            = 0xFF    // the predecessor and successor sets of a FENCE that orders all memory accesses
+)
+
+/*linkRegister is the register that JAL and JALR write the return address to when none is given*/
+const linkRegister = 1
+
+/*generators maps each mnemonic to the function that generates its binary instruction.
+Pseudo-instructions are generated as the base instruction that they stand for*/
+var generators = map[Assembler.TokenType]generationFunction{
+	Assembler.ADDI:  immediateArithmetic(uint(Producer.AddI)),
+	Assembler.SLTI:  immediateArithmetic(uint(Producer.SLTI)),
+	Assembler.SLTIU: immediateArithmetic(uint(Producer.SLTIU)),
+	Assembler.ANDI:  immediateArithmetic(uint(Producer.AndI)),
+	Assembler.ORI:   immediateArithmetic(uint(Producer.OrI)),
+	Assembler.XORI:  immediateArithmetic(uint(Producer.XorI)),
+	Assembler.SLLI:  shiftImmediate(uint(Producer.ShiftLeftLI), 0),
+	Assembler.SRLI:  shiftImmediate(uint(Producer.ShiftRight), 0),
+	Assembler.SRAI:  shiftImmediate(uint(Producer.ShiftRight), arithmeticShiftFlag),
+	Assembler.LUI:   upperImmediate(uint(Parser.LUI)),
+	Assembler.AUIPC: upperImmediate(uint(Parser.AUIPC)),
+
+	Assembler.ADD:  registerArithmetic(uint(Producer.Add), uint(Producer.F0)),
+	Assembler.SLT:  registerArithmetic(uint(Producer.SLT), uint(Producer.F0)),
+	Assembler.SLTU: registerArithmetic(uint(Producer.SLTU), uint(Producer.F0)),
+	Assembler.AND:  registerArithmetic(uint(Producer.And), uint(Producer.F0)),
+	Assembler.OR:   registerArithmetic(uint(Producer.Or), uint(Producer.F0)),
+	Assembler.XOR:  registerArithmetic(uint(Producer.Xor), uint(Producer.F0)),
+	Assembler.SLL:  registerArithmetic(uint(Producer.SLL), uint(Producer.F0)),
+	Assembler.SRL:  registerArithmetic(uint(Producer.SRL), uint(Producer.F0)),
+	Assembler.SUB:  registerArithmetic(uint(Producer.Sub), uint(Producer.F1)),
+	Assembler.SRA:  registerArithmetic(uint(Producer.SRA), uint(Producer.F1)),
+
+	Assembler.MUL:    registerArithmetic(uint(Producer.Mul), uint(Producer.FM)),
+	Assembler.MULH:   registerArithmetic(uint(Producer.MulH), uint(Producer.FM)),
+	Assembler.MULHSU: registerArithmetic(uint(Producer.MulHSU), uint(Producer.FM)),
+	Assembler.MULHU:  registerArithmetic(uint(Producer.MulHU), uint(Producer.FM)),
+	Assembler.DIV:    registerArithmetic(uint(Producer.Div), uint(Producer.FM)),
+	Assembler.DIVU:   registerArithmetic(uint(Producer.DivU), uint(Producer.FM)),
+	Assembler.REM:    registerArithmetic(uint(Producer.Rem), uint(Producer.FM)),
+	Assembler.REMU:   registerArithmetic(uint(Producer.RemU), uint(Producer.FM)),
+
+	Assembler.LRW:      loadReserved,
+	Assembler.SCW:      atomic(uint(Producer.StoreConditional)),
+	Assembler.AMOSWAPW: atomic(uint(Producer.AmoSwap)),
+	Assembler.AMOADDW:  atomic(uint(Producer.AmoAdd)),
+	Assembler.AMOXORW:  atomic(uint(Producer.AmoXor)),
+	Assembler.AMOANDW:  atomic(uint(Producer.AmoAnd)),
+	Assembler.AMOORW:   atomic(uint(Producer.AmoOr)),
+	Assembler.AMOMINW:  atomic(uint(Producer.AmoMin)),
+	Assembler.AMOMAXW:  atomic(uint(Producer.AmoMax)),
+	Assembler.AMOMINUW: atomic(uint(Producer.AmoMinU)),
+	Assembler.AMOMAXUW: atomic(uint(Producer.AmoMaxU)),
+
+	Assembler.JAL:  jumpAndLink,
+	Assembler.JALR: jumpAndLinkRegister,
+	Assembler.BEQ:  branch(uint(Producer.Beq), false),
+	Assembler.BNE:  branch(uint(Producer.Bneq), false),
+	Assembler.BLT:  branch(uint(Producer.Blt), false),
+	Assembler.BLTU: branch(uint(Producer.Bltu), false),
+	Assembler.BGE:  branch(uint(Producer.Bge), false),
+	Assembler.BGEU: branch(uint(Producer.Bgeu), false),
+
+	Assembler.LW:  load(uint(Producer.LoadWord)),
+	Assembler.LH:  load(uint(Producer.LoadHalfWord)),
+	Assembler.LHU: load(uint(Producer.LoadHalfWordUnsigned)),
+	Assembler.LB:  load(uint(Producer.LoadByte)),
+	Assembler.LBU: load(uint(Producer.LoadByteUnsigned)),
+	Assembler.SW:  store(uint(Producer.StoreWord)),
+	Assembler.SH:  store(uint(Producer.StoreHalfWord)),
+	Assembler.SB:  store(uint(Producer.StoreByte)),
+
+	Assembler.CSRRW:  csr(uint(Producer.CSRRW)),
+	Assembler.CSRRS:  csr(uint(Producer.CSRRS)),
+	Assembler.CSRRC:  csr(uint(Producer.CSRRC)),
+	Assembler.CSRRWI: csrImmediate(uint(Producer.CSRRWI)),
+	Assembler.CSRRSI: csrImmediate(uint(Producer.CSRRSI)),
+	Assembler.CSRRCI: csrImmediate(uint(Producer.CSRRCI)),
+	Assembler.ECALL:  environment(uint(Producer.ECALL)),
+	Assembler.EBREAK: environment(uint(Producer.EBREAK)),
+	Assembler.MRET:   environment(uint(Producer.MRET)),
+	Assembler.SRET:   environment(uint(Producer.SRET)),
+	Assembler.WFI:    environment(uint(Producer.WFI)),
+	Assembler.FENCE:  fence,
+
+	Assembler.SFENCEVMA: fenceVirtualMemory,
+
+	// pseudo-instructions
+	Assembler.NOP:   nop,
+	Assembler.MV:    move,
+	Assembler.NOT:   not,
+	Assembler.SEQZ:  setEqualZero,
+	Assembler.SNEZ:  setNotEqualZero,
+	Assembler.J:     jump,
+	Assembler.BGT:   branch(uint(Producer.Blt), true),
+	Assembler.BGTU:  branch(uint(Producer.Bltu), true),
+	Assembler.BLE:   branch(uint(Producer.Bge), true),
+	Assembler.BLEU:  branch(uint(Producer.Bgeu), true),
+	Assembler.CSRR:  csrRead,
+	Assembler.CSRW:  csrWithoutRead(uint(Producer.CSRRW)),
+	Assembler.CSRS:  csrWithoutRead(uint(Producer.CSRRS)),
+	Assembler.CSRC:  csrWithoutRead(uint(Producer.CSRRC)),
+	Assembler.CSRWI: csrImmediateWithoutRead(uint(Producer.CSRRWI)),
+	Assembler.CSRSI: csrImmediateWithoutRead(uint(Producer.CSRRSI)),
+	Assembler.CSRCI: csrImmediateWithoutRead(uint(Producer.CSRRCI)),
+
+	Assembler.RDCYCLE:    counterRead(CsrManagers.Cycle),
+	Assembler.RDCYCLEH:   counterRead(CsrManagers.Cycleh),
+	Assembler.RDTIME:     counterRead(CsrManagers.Time),
+	Assembler.RDTIMEH:    counterRead(CsrManagers.Timeh),
+	Assembler.RDINSTRET:  counterRead(CsrManagers.Instret),
+	Assembler.RDINSTRETH: counterRead(CsrManagers.Instreth),
+}
+
+/*generateInstruction generates the binary instruction for the mnemonic node pointed to by `iter`,
+which will be loaded at `address`*/
+func generateInstruction(iter Assembler.AstIterator, address uint32, symbolTable map[string]uint32) (uint32, error) {
+	ops := operands{
+		mnemonic:    iter.GetAstNode(),
+		nodes:       []Assembler.AstNode{},
+		address:     address,
+		symbolTable: symbolTable,
+	}
+	for i := uint(0); i < iter.GetNumChildren(); i++ {
+		child, err := iter.GetChildIterator(i)
+		if err != nil {
+			panic("generateInstruction: grabbed an invalid child iterator")
+		}
+		ops.nodes = append(ops.nodes, child.GetAstNode())
+	}
+
+	generate, ok := generators[ops.mnemonic.GetTokenType()]
+	if !ok {
+		generate, ok = compressedGenerators[ops.mnemonic.GetTokenType()]
+	}
+	if !ok {
+		return 0, ops.errorf("instruction is not supported")
+	}
+
+	return generate(&ops)
+}
+
+func immediateArithmetic(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(3); err != nil {
+			return 0, err
+		}
+		registers, err := ops.registers(2)
+		if err != nil {
+			return 0, err
+		}
+		immediate, err := ops.immediate(2, minImmediate12, maxImmediate12)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.ImmArith), registers[0], funct3, registers[1], immediate), nil
+	}
+}
+
+/*shiftImmediate generates a shift by a constant amount. `flags` are the upper immediate
+bits that distinguish shifts that share the same Funct3*/
+func shiftImmediate(funct3 uint, flags uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(3); err != nil {
+			return 0, err
+		}
+		registers, err := ops.registers(2)
+		if err != nil {
+			return 0, err
+		}
+		shamt, err := ops.immediate(2, 0, maxShiftAmount)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.ImmArith), registers[0], funct3, registers[1], shamt|flags), nil
+	}
+}
+
+func upperImmediate(opcode uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		dest, err := ops.register(0)
+		if err != nil {
+			return 0, err
+		}
+		immediate, err := ops.immediate(1, 0, maxImmediate20)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionU(opcode, dest, immediate), nil
+	}
+}
+
+func registerArithmetic(funct3 uint, funct7 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(3); err != nil {
+			return 0, err
+		}
+		registers, err := ops.registers(3)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionR(uint(Parser.RegArith), registers[0], funct3, registers[1], registers[2], funct7), nil
+	}
+}
+
+/*jumpAndLink generates `JAL rd target`, or `JAL target`, which links to the return address register*/
+func jumpAndLink(ops *operands) (uint32, error) {
+	if err := ops.expect(1, 2); err != nil {
+		return 0, err
+	}
+
+	dest := uint(linkRegister)
+	if ops.count() == 2 {
+		register, err := ops.register(0)
+		if err != nil {
+			return 0, err
+		}
+		dest = register
+	}
+
+	offset, err := ops.target(ops.count()-1, minJumpOffset, maxJumpOffset)
+	if err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionJ(uint(Parser.JAL), dest, offset), nil
+}
+
+/*jumpAndLinkRegister generates `JALR rd rs1 offset`, `JALR rd offset(rs1)`, or `JALR rs1`, which
+links to the return address register*/
+func jumpAndLinkRegister(ops *operands) (uint32, error) {
+	if err := ops.expect(1, 2, 3); err != nil {
+		return 0, err
+	}
+
+	var dest, base, offset uint
+	var err error
+	switch ops.count() {
+	case 1:
+		dest = linkRegister
+		base, err = ops.register(0)
+	case 2:
+		if dest, err = ops.register(0); err == nil {
+			base, offset, err = ops.registerAndImmediate(1, minImmediate12, maxImmediate12)
+		}
+	case 3:
+		var registers []uint
+		if registers, err = ops.registers(2); err == nil {
+			dest, base = registers[0], registers[1]
+			offset, err = ops.immediate(2, minImmediate12, maxImmediate12)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionI(uint(Parser.JALR), dest, uint(Producer.JALR), base, offset), nil
+}
+
+/*branch generates `B rs1 rs2 target`. If `swap` is set, the registers are compared in the opposite order,
+which turns the base branches into the BGT and BLE pseudo-instructions*/
+func branch(funct3 uint, swap bool) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(3); err != nil {
+			return 0, err
+		}
+		registers, err := ops.registers(2)
+		if err != nil {
+			return 0, err
+		}
+		offset, err := ops.target(2, minBranchOffset, maxBranchOffset)
+		if err != nil {
+			return 0, err
+		}
+
+		if swap {
+			registers[0], registers[1] = registers[1], registers[0]
+		}
+		return Parser.BuildInstructionB(uint(Parser.Branch), funct3, registers[0], registers[1], offset), nil
+	}
+}
+
+/*load generates `L rd offset(rs1)`*/
+func load(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		dest, err := ops.register(0)
+		if err != nil {
+			return 0, err
+		}
+		base, offset, err := ops.registerAndImmediate(1, minImmediate12, maxImmediate12)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.Load), dest, funct3, base, offset), nil
+	}
+}
+
+/*store generates `S rs2 offset(rs1)`, which stores the value of rs2 at the address offset(rs1)*/
+func store(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		src, err := ops.register(0)
+		if err != nil {
+			return 0, err
+		}
+		base, offset, err := ops.registerAndImmediate(1, minImmediate12, maxImmediate12)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionS(uint(Parser.Store), funct3, base, src, offset), nil
+	}
+}
+
+/*csr generates `CSRR rd csr rs1`*/
+func csr(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(3); err != nil {
+			return 0, err
+		}
+		dest, err := ops.register(0)
+		if err != nil {
+			return 0, err
+		}
+		number, err := ops.immediate(1, 0, maxUnsigned12)
+		if err != nil {
+			return 0, err
+		}
+		src, err := ops.register(2)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.System), dest, funct3, src, number), nil
+	}
+}
+
+/*csrImmediate generates `CSRRI rd csr uimm`, where the 5-bit uimm takes the place of rs1*/
+func csrImmediate(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(3); err != nil {
+			return 0, err
+		}
+		dest, err := ops.register(0)
+		if err != nil {
+			return 0, err
+		}
+		number, err := ops.immediate(1, 0, maxUnsigned12)
+		if err != nil {
+			return 0, err
+		}
+		uimm, err := ops.immediate(2, 0, maxCsrImmediate)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.System), dest, funct3, uimm, number), nil
+	}
+}
+
+/*csrRead generates `CSRR rd csr` as `CSRRS rd csr x0`*/
+func csrRead(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	dest, err := ops.register(0)
+	if err != nil {
+		return 0, err
+	}
+	number, err := ops.immediate(1, 0, maxUnsigned12)
+	if err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionI(uint(Parser.System), dest, uint(Producer.CSRRS), 0, number), nil
+}
+
+/*counterRead generates `RDCOUNTER rd` as `CSRRS rd counter x0`, where `counter` is the number of the counter CSR*/
+func counterRead(counter uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(1); err != nil {
+			return 0, err
+		}
+		dest, err := ops.register(0)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.System), dest, uint(Producer.CSRRS), 0, counter), nil
+	}
+}
+
+/*csrWithoutRead generates `CSR csr rs1` as `CSRR x0 csr rs1`*/
+func csrWithoutRead(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		number, err := ops.immediate(0, 0, maxUnsigned12)
+		if err != nil {
+			return 0, err
+		}
+		src, err := ops.register(1)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.System), 0, funct3, src, number), nil
+	}
+}
+
+/*csrImmediateWithoutRead generates `CSRI csr uimm` as `CSRRI x0 csr uimm`*/
+func csrImmediateWithoutRead(funct3 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(2); err != nil {
+			return 0, err
+		}
+		number, err := ops.immediate(0, 0, maxUnsigned12)
+		if err != nil {
+			return 0, err
+		}
+		uimm, err := ops.immediate(1, 0, maxCsrImmediate)
+		if err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.System), 0, funct3, uimm, number), nil
+	}
+}
+
+/*environment generates ECALL, EBREAK, MRET, SRET or WFI, which are selected by the immediate*/
+func environment(selector uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(0); err != nil {
+			return 0, err
+		}
+
+		return Parser.BuildInstructionI(uint(Parser.System), 0, uint(Producer.Private), 0, selector), nil
+	}
+}
+
+/*fenceVirtualMemory generates `SFENCE.VMA rs1 rs2`, which orders the page table writes for the virtual
+address in rs1 and the address space in rs2 before the address translations that follow*/
+func fenceVirtualMemory(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	address, err := ops.register(0)
+	if err != nil {
+		return 0, err
+	}
+	asid, err := ops.register(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionI(uint(Parser.System), 0, uint(Producer.Private), address,
+		uint(Producer.SFENCEVMA)|asid), nil
+}
+
+/*loadReserved generates `LR.W rd (rs1)`, which loads the word at the address in rs1 and reserves it*/
+func loadReserved(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	dest, err := ops.register(0)
+	if err != nil {
+		return 0, err
+	}
+	address, err := ops.atomicAddress(1)
+	if err != nil {
+		return 0, err
+	}
+
+	acquire, release := ops.ordering()
+	return Parser.BuildInstructionA(uint(Parser.AMO), dest, uint(Producer.AtomicWord), address, 0,
+		uint(Producer.LoadReserved), acquire, release), nil
+}
+
+/*atomic generates `SC.W rd rs2 (rs1)` or `AMO.W rd rs2 (rs1)`, which combine the word at the address
+in rs1 with rs2*/
+func atomic(funct5 uint) generationFunction {
+	return func(ops *operands) (uint32, error) {
+		if err := ops.expect(3); err != nil {
+			return 0, err
+		}
+		registers, err := ops.registers(2)
+		if err != nil {
+			return 0, err
+		}
+		address, err := ops.atomicAddress(2)
+		if err != nil {
+			return 0, err
+		}
+
+		acquire, release := ops.ordering()
+		return Parser.BuildInstructionA(uint(Parser.AMO), registers[0], uint(Producer.AtomicWord), address,
+			registers[1], funct5, acquire, release), nil
+	}
+}
+
+/*fence generates `FENCE`, which orders all memory accesses before it against all those after it*/
+func fence(ops *operands) (uint32, error) {
+	if err := ops.expect(0); err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionI(uint(Parser.MiscMem), 0, uint(Producer.Fence), 0, fenceAll), nil
+}
+
+/*nop generates `NOP` as `ADDI x0 x0 0`*/
+func nop(ops *operands) (uint32, error) {
+	if err := ops.expect(0); err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionI(uint(Parser.ImmArith), 0, uint(Producer.AddI), 0, 0), nil
+}
+
+/*move generates `MV rd rs` as `ADDI rd rs 0`*/
+func move(ops *operands) (uint32, error) {
+	return unaryImmediate(ops, uint(Producer.AddI), 0)
+}
+
+/*not generates `NOT rd rs` as `XORI rd rs -1`*/
+func not(ops *operands) (uint32, error) {
+	return unaryImmediate(ops, uint(Producer.XorI), maxUnsigned12)
+}
+
+/*setEqualZero generates `SEQZ rd rs` as `SLTIU rd rs 1`*/
+func setEqualZero(ops *operands) (uint32, error) {
+	return unaryImmediate(ops, uint(Producer.SLTIU), 1)
+}
+
+func unaryImmediate(ops *operands, funct3 uint, immediate uint) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	registers, err := ops.registers(2)
+	if err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionI(uint(Parser.ImmArith), registers[0], funct3, registers[1], immediate), nil
+}
+
+/*setNotEqualZero generates `SNEZ rd rs` as `SLTU rd x0 rs`*/
+func setNotEqualZero(ops *operands) (uint32, error) {
+	if err := ops.expect(2); err != nil {
+		return 0, err
+	}
+	registers, err := ops.registers(2)
+	if err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionR(uint(Parser.RegArith), registers[0], uint(Producer.SLTU), 0, registers[1], uint(Producer.F0)), nil
+}
+
+/*jump generates `J target` as `JAL x0 target`*/
+func jump(ops *operands) (uint32, error) {
+	if err := ops.expect(1); err != nil {
+		return 0, err
+	}
+	offset, err := ops.target(0, minJumpOffset, maxJumpOffset)
+	if err != nil {
+		return 0, err
+	}
+
+	return Parser.BuildInstructionJ(uint(Parser.JAL), 0, offset), nil
+}
@@ -0,0 +1,172 @@
+package codeGeneration
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	Assembler "github.com/chenhowa/computer/lib/assembly"
+	Tokenizer "github.com/chenhowa/computer/lib/assembly/tokenizer"
+)
+
+/*operands holds the operand nodes of one instruction, as well as what is needed to
+evaluate them: the mnemonic they belong to, the address of the instruction, and the known labels*/
+type operands struct {
+	mnemonic    Assembler.AstNode
+	nodes       []Assembler.AstNode
+	address     uint32
+	symbolTable map[string]uint32
+}
+
+func (ops *operands) count() int {
+	return len(ops.nodes)
+}
+
+/*errorf prefixes the error with the location and mnemonic of the instruction being generated*/
+func (ops *operands) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Generate: line %d: %s: %s", ops.mnemonic.GetLineCount(),
+		ops.mnemonic.GetTokenString(), fmt.Sprintf(format, args...))
+}
+
+/*expect returns an error unless the instruction has one of the operand counts in `counts`*/
+func (ops *operands) expect(counts ...int) error {
+	for _, count := range counts {
+		if ops.count() == count {
+			return nil
+		}
+	}
+
+	return ops.errorf("%d operands is not a valid number of operands", ops.count())
+}
+
+/*register returns the number of the register that is operand `index`*/
+func (ops *operands) register(index int) (uint, error) {
+	node := ops.nodes[index]
+	tokenType := node.GetTokenType()
+	if tokenType < Assembler.X0 || tokenType > Assembler.X31 {
+		return 0, ops.errorf("operand %d (%s) is not a register", index+1, node.GetTokenString())
+	}
+
+	return uint(tokenType - Assembler.X0), nil
+}
+
+/*registers returns the registers that are operands 0 through `count`-1*/
+func (ops *operands) registers(count int) ([]uint, error) {
+	registers := make([]uint, count)
+	for i := 0; i < count; i++ {
+		register, err := ops.register(i)
+		if err != nil {
+			return nil, err
+		}
+		registers[i] = register
+	}
+	return registers, nil
+}
+
+/*immediate returns operand `index` as a numeric constant, which must lie between `min` and `max`, inclusive*/
+func (ops *operands) immediate(index int, min int64, max int64) (uint, error) {
+	node := ops.nodes[index]
+	if node.GetTokenType() != Assembler.NumericConstant {
+		return 0, ops.errorf("operand %d (%s) is not a numeric constant", index+1, node.GetTokenString())
+	}
+
+	return ops.checkRange(index, node.GetTokenString(), min, max)
+}
+
+/*registerAndImmediate returns the register and the numeric constant of operand `index`, which must have the
+form `offset(register)`. The constant must lie between `min` and `max`, inclusive, and is 0 if it is left out*/
+func (ops *operands) registerAndImmediate(index int, min int64, max int64) (uint, uint, error) {
+	node := ops.nodes[index]
+	if node.GetTokenType() != Assembler.RegisterAndImmediate {
+		return 0, 0, ops.errorf("operand %d (%s) does not have the form offset(register)", index+1, node.GetTokenString())
+	}
+
+	matches := registerAndImmediateRegex.FindStringSubmatch(node.GetTokenString())
+	if matches == nil {
+		return 0, 0, ops.errorf("operand %d (%s) does not have the form offset(register)", index+1, node.GetTokenString())
+	}
+
+	offset := matches[1]
+	if offset == "" {
+		offset = "0"
+	}
+	immediate, err := ops.checkRange(index, offset, min, max)
+	if err != nil {
+		return 0, 0, err
+	}
+	register, _ := strconv.ParseUint(matches[2], 10, 5)
+
+	return uint(register), immediate, nil
+}
+
+var registerAndImmediateRegex = regexp.MustCompile(`^(.*)\(x(\d+)\)$`)
+
+/*atomicAddress returns the register of operand `index`, which must have the form `(register)`, as the
+address operand of an atomic instruction has no offset. `0(register)` is accepted as well*/
+func (ops *operands) atomicAddress(index int) (uint, error) {
+	node := ops.nodes[index]
+	register, offset, err := ops.registerAndImmediate(index, 0, 0)
+	if err != nil || offset != 0 {
+		return 0, ops.errorf("operand %d (%s) does not have the form (register)", index+1, node.GetTokenString())
+	}
+
+	return register, nil
+}
+
+/*ordering returns whether the mnemonic has the acquire or release ordering of an atomic instruction*/
+func (ops *operands) ordering() (acquire bool, release bool) {
+	mnemonic := ops.mnemonic.GetTokenString()
+	if strings.HasSuffix(mnemonic, string(Tokenizer.AQRL)) {
+		return true, true
+	}
+	return strings.HasSuffix(mnemonic, string(Tokenizer.AQ)), strings.HasSuffix(mnemonic, string(Tokenizer.RL))
+}
+
+/*target returns the offset from this instruction to operand `index`, which is either a numeric
+offset or the name of a label. The offset must be a multiple of 2, and lie between `min` and `max`, inclusive*/
+func (ops *operands) target(index int, min int64, max int64) (uint, error) {
+	node := ops.nodes[index]
+	if node.GetTokenType() == Assembler.NumericConstant {
+		offset, err := ops.immediate(index, min, max)
+		if err != nil {
+			return 0, err
+		}
+		return offset, ops.aligned(index, offset, 2)
+	}
+
+	if node.GetTokenType() != Assembler.Identifier {
+		return 0, ops.errorf("operand %d (%s) is neither a label nor an offset", index+1, node.GetTokenString())
+	}
+
+	address, ok := ops.symbolTable[node.GetTokenString()]
+	if !ok {
+		return 0, ops.errorf("label %s is not defined", node.GetTokenString())
+	}
+
+	offset := int64(address) - int64(ops.address)
+	if offset < min || offset > max {
+		return 0, ops.errorf("label %s is too far away (offset %d)", node.GetTokenString(), offset)
+	}
+
+	return uint(offset), nil
+}
+
+/*aligned returns an error unless `value`, the value of operand `index`, is a multiple of `alignment`*/
+func (ops *operands) aligned(index int, value uint, alignment uint) error {
+	if value%alignment != 0 {
+		return ops.errorf("operand %d (%s) must be a multiple of %d", index+1, ops.nodes[index].GetTokenString(), alignment)
+	}
+	return nil
+}
+
+/*checkRange parses the numeric constant `constant`, and returns its two's complement representation
+if it lies between `min` and `max`*/
+func (ops *operands) checkRange(index int, constant string, min int64, max int64) (uint, error) {
+	value, err := strconv.ParseInt(strings.Replace(constant, ",", "", -1), 0, 64)
+	if err != nil || value < min || value > max {
+		return 0, ops.errorf("operand %d (%s) must be between %d and %d", index+1, constant, min, max)
+	}
+
+	return uint(value), nil
+}
@@ -0,0 +1,307 @@
+package disassembly
+
+import (
+	"fmt"
+
+	Tokenizer "github.com/chenhowa/computer/lib/assembly/tokenizer"
+	Utils "github.com/chenhowa/computer/lib/binaryInstructionExecution/bitUtils"
+	Producer "github.com/chenhowa/computer/lib/binaryInstructionExecution/executionFactoryProducers"
+	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+)
+
+/*RiscVDisassembler converts 32-bit binary instructions back into RISC-V assembly, written in
+the same syntax that the assembler accepts. Branch and jump targets are written as numeric offsets,
+since the binary instruction does not remember the labels they were assembled from.
+Compressed instructions are written as the 32-bit instructions that they expand to.
+The assembler does not accept floating-point instructions yet, so they are written with the mnemonics
+of the RISC-V specification, and with their floating-point registers named f0 to f31*/
+type RiscVDisassembler struct {
+}
+
+/*MakeRiscVDisassembler is a constructor for RiscVDisassembler*/
+func MakeRiscVDisassembler() RiscVDisassembler {
+	return RiscVDisassembler{}
+}
+
+type disassemblyFunction func(result Parser.RiscVBinaryParseResult) (string, error)
+
+/*Disassemble returns the assembly instruction for `instruction`, or an error if it is not a valid instruction*/
+func (d *RiscVDisassembler) Disassemble(instruction uint32) (assembly string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			assembly = ""
+			err = fmt.Errorf("Disassemble: 0x%08x is not a valid instruction: %v", instruction, r)
+		}
+	}()
+
+	parser := Parser.RiscVBinaryInstructionParser{}
+	result := parser.Parse(instruction)
+
+	decision := map[Parser.OpCode](disassemblyFunction){
+		Parser.ImmArith: immediateArithmetic,
+		Parser.LUI:      upperImmediate(Tokenizer.LUI),
+		Parser.AUIPC:    upperImmediate(Tokenizer.AUIPC),
+		Parser.RegArith: registerArithmetic,
+		Parser.JAL:      jumpAndLink,
+		Parser.JALR:     jumpAndLinkRegister,
+		Parser.Branch:   branch,
+		Parser.Load:     load,
+		Parser.Store:    store,
+		Parser.System:   system,
+		Parser.MiscMem:  fence,
+		Parser.AMO:      atomic,
+		Parser.LoadFP:   loadFloat,
+		Parser.StoreFP:  storeFloat,
+		Parser.OpFP:     floatOperation,
+		Parser.FMAdd:    fusedMultiplyAdd,
+		Parser.FMSub:    fusedMultiplyAdd,
+		Parser.FNMSub:   fusedMultiplyAdd,
+		Parser.FNMAdd:   fusedMultiplyAdd,
+	}
+
+	if f, ok := decision[result.OpCode]; ok {
+		return f(result)
+	}
+	return "", fmt.Errorf("Disassemble: 0x%08x has no known opcode", instruction)
+}
+
+func register(reg uint8) string {
+	return fmt.Sprintf("x%d", reg)
+}
+
+/*signed12 returns the 12-bit immediate of `result` as a signed number*/
+func signed12(result Parser.RiscVBinaryParseResult) int32 {
+	return int32(Utils.SignExtendUint32WithBit(uint32(result.TwelveBitImmediate), 11))
+}
+
+func unknownOperation(result Parser.RiscVBinaryParseResult) error {
+	return fmt.Errorf("Disassemble: funct3 %d is not a valid operation for opcode %d", result.Funct3, result.OpCode)
+}
+
+var immediateArithmeticMnemonics = map[uint8]Tokenizer.Mnemonic{
+	uint8(Producer.AddI):  Tokenizer.ADDI,
+	uint8(Producer.SLTI):  Tokenizer.SLTI,
+	uint8(Producer.SLTIU): Tokenizer.SLTIU,
+	uint8(Producer.AndI):  Tokenizer.ANDI,
+	uint8(Producer.OrI):   Tokenizer.ORI,
+	uint8(Producer.XorI):  Tokenizer.XORI,
+}
+
+/*arithmeticShiftBit is the bit of a shift-right immediate that selects an arithmetic shift*/
+const arithmeticShiftBit = 10
+
+func immediateArithmetic(result Parser.RiscVBinaryParseResult) (string, error) {
+	dest, src := register(result.FiveBitDestination), register(result.FiveBitRegister1)
+	shamt := Utils.KeepBitsInInclusiveRange(uint32(result.TwelveBitImmediate), 0, 4)
+
+	switch result.Funct3 {
+	case uint8(Producer.ShiftLeftLI):
+		return fmt.Sprintf("%s %s %s %d", Tokenizer.SLLI, dest, src, shamt), nil
+	case uint8(Producer.ShiftRight):
+		mnemonic := Tokenizer.SRLI
+		if (result.TwelveBitImmediate>>arithmeticShiftBit)&1 == 1 {
+			mnemonic = Tokenizer.SRAI
+		}
+		return fmt.Sprintf("%s %s %s %d", mnemonic, dest, src, shamt), nil
+	}
+
+	if mnemonic, ok := immediateArithmeticMnemonics[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s %s %d", mnemonic, dest, src, signed12(result)), nil
+	}
+	return "", unknownOperation(result)
+}
+
+func upperImmediate(mnemonic Tokenizer.Mnemonic) disassemblyFunction {
+	return func(result Parser.RiscVBinaryParseResult) (string, error) {
+		return fmt.Sprintf("%s %s %d", mnemonic, register(result.FiveBitDestination), result.TwentyBitImmediate), nil
+	}
+}
+
+var registerArithmeticMnemonics = map[uint8](map[uint8]Tokenizer.Mnemonic){
+	uint8(Producer.F0): map[uint8]Tokenizer.Mnemonic{
+		uint8(Producer.Add):  Tokenizer.ADD,
+		uint8(Producer.SLT):  Tokenizer.SLT,
+		uint8(Producer.SLTU): Tokenizer.SLTU,
+		uint8(Producer.And):  Tokenizer.AND,
+		uint8(Producer.Or):   Tokenizer.OR,
+		uint8(Producer.Xor):  Tokenizer.XOR,
+		uint8(Producer.SLL):  Tokenizer.SLL,
+		uint8(Producer.SRL):  Tokenizer.SRL,
+	},
+	uint8(Producer.F1): map[uint8]Tokenizer.Mnemonic{
+		uint8(Producer.Sub): Tokenizer.SUB,
+		uint8(Producer.SRA): Tokenizer.SRA,
+	},
+	uint8(Producer.FM): map[uint8]Tokenizer.Mnemonic{
+		uint8(Producer.Mul):    Tokenizer.MUL,
+		uint8(Producer.MulH):   Tokenizer.MULH,
+		uint8(Producer.MulHSU): Tokenizer.MULHSU,
+		uint8(Producer.MulHU):  Tokenizer.MULHU,
+		uint8(Producer.Div):    Tokenizer.DIV,
+		uint8(Producer.DivU):   Tokenizer.DIVU,
+		uint8(Producer.Rem):    Tokenizer.REM,
+		uint8(Producer.RemU):   Tokenizer.REMU,
+	},
+}
+
+func registerArithmetic(result Parser.RiscVBinaryParseResult) (string, error) {
+	if m, ok := registerArithmeticMnemonics[result.Funct7]; ok {
+		if mnemonic, ok := m[result.Funct3]; ok {
+			return fmt.Sprintf("%s %s %s %s", mnemonic, register(result.FiveBitDestination),
+				register(result.FiveBitRegister1), register(result.FiveBitRegister2)), nil
+		}
+	}
+	return "", fmt.Errorf("Disassemble: funct3 %d and funct7 %d are not a valid operation", result.Funct3, result.Funct7)
+}
+
+var atomicMnemonics = map[uint8]Tokenizer.Mnemonic{
+	uint8(Producer.LoadReserved):     Tokenizer.LRW,
+	uint8(Producer.StoreConditional): Tokenizer.SCW,
+	uint8(Producer.AmoSwap):          Tokenizer.AMOSWAPW,
+	uint8(Producer.AmoAdd):           Tokenizer.AMOADDW,
+	uint8(Producer.AmoXor):           Tokenizer.AMOXORW,
+	uint8(Producer.AmoAnd):           Tokenizer.AMOANDW,
+	uint8(Producer.AmoOr):            Tokenizer.AMOORW,
+	uint8(Producer.AmoMin):           Tokenizer.AMOMINW,
+	uint8(Producer.AmoMax):           Tokenizer.AMOMAXW,
+	uint8(Producer.AmoMinU):          Tokenizer.AMOMINUW,
+	uint8(Producer.AmoMaxU):          Tokenizer.AMOMAXUW,
+}
+
+func atomic(result Parser.RiscVBinaryParseResult) (string, error) {
+	mnemonic, ok := atomicMnemonics[result.Funct5]
+	if !ok || result.Funct3 != uint8(Producer.AtomicWord) {
+		return "", fmt.Errorf("Disassemble: funct3 %d and funct5 %d are not a valid atomic operation", result.Funct3, result.Funct5)
+	}
+
+	ordering := ""
+	if result.Acquire && result.Release {
+		ordering = string(Tokenizer.AQRL)
+	} else if result.Acquire {
+		ordering = string(Tokenizer.AQ)
+	} else if result.Release {
+		ordering = string(Tokenizer.RL)
+	}
+
+	address := fmt.Sprintf("(%s)", register(result.FiveBitRegister1))
+	if mnemonic == Tokenizer.LRW {
+		return fmt.Sprintf("%s%s %s %s", mnemonic, ordering, register(result.FiveBitDestination), address), nil
+	}
+	return fmt.Sprintf("%s%s %s %s %s", mnemonic, ordering, register(result.FiveBitDestination),
+		register(result.FiveBitRegister2), address), nil
+}
+
+func jumpAndLink(result Parser.RiscVBinaryParseResult) (string, error) {
+	offset := int32(Utils.SignExtendUint32WithBit(result.TwentyBitImmediate<<1, 20))
+	return fmt.Sprintf("%s %s %d", Tokenizer.JAL, register(result.FiveBitDestination), offset), nil
+}
+
+func jumpAndLinkRegister(result Parser.RiscVBinaryParseResult) (string, error) {
+	if result.Funct3 != uint8(Producer.JALR) {
+		return "", unknownOperation(result)
+	}
+	return fmt.Sprintf("%s %s %d(%s)", Tokenizer.JALR, register(result.FiveBitDestination),
+		signed12(result), register(result.FiveBitRegister1)), nil
+}
+
+var branchMnemonics = map[uint8]Tokenizer.Mnemonic{
+	uint8(Producer.Beq):  Tokenizer.BEQ,
+	uint8(Producer.Bneq): Tokenizer.BNE,
+	uint8(Producer.Blt):  Tokenizer.BLT,
+	uint8(Producer.Bltu): Tokenizer.BLTU,
+	uint8(Producer.Bge):  Tokenizer.BGE,
+	uint8(Producer.Bgeu): Tokenizer.BGEU,
+}
+
+func branch(result Parser.RiscVBinaryParseResult) (string, error) {
+	if mnemonic, ok := branchMnemonics[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s %s %d", mnemonic, register(result.FiveBitRegister1),
+			register(result.FiveBitRegister2), int32(Utils.SignExtendUint32WithBit(uint32(result.TwelveBitImmediate)<<1, 12))), nil
+	}
+	return "", unknownOperation(result)
+}
+
+var loadMnemonics = map[uint8]Tokenizer.Mnemonic{
+	uint8(Producer.LoadWord):             Tokenizer.LW,
+	uint8(Producer.LoadHalfWord):         Tokenizer.LH,
+	uint8(Producer.LoadHalfWordUnsigned): Tokenizer.LHU,
+	uint8(Producer.LoadByte):             Tokenizer.LB,
+	uint8(Producer.LoadByteUnsigned):     Tokenizer.LBU,
+}
+
+func load(result Parser.RiscVBinaryParseResult) (string, error) {
+	if mnemonic, ok := loadMnemonics[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s %d(%s)", mnemonic, register(result.FiveBitDestination),
+			signed12(result), register(result.FiveBitRegister1)), nil
+	}
+	return "", unknownOperation(result)
+}
+
+var storeMnemonics = map[uint8]Tokenizer.Mnemonic{
+	uint8(Producer.StoreWord):     Tokenizer.SW,
+	uint8(Producer.StoreHalfWord): Tokenizer.SH,
+	uint8(Producer.StoreByte):     Tokenizer.SB,
+}
+
+/*store writes the source register first, and then the offset from the base register*/
+func store(result Parser.RiscVBinaryParseResult) (string, error) {
+	if mnemonic, ok := storeMnemonics[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s %d(%s)", mnemonic, register(result.FiveBitRegister2),
+			signed12(result), register(result.FiveBitRegister1)), nil
+	}
+	return "", unknownOperation(result)
+}
+
+var csrMnemonics = map[uint8]Tokenizer.Mnemonic{
+	uint8(Producer.CSRRW): Tokenizer.CSRRW,
+	uint8(Producer.CSRRS): Tokenizer.CSRRS,
+	uint8(Producer.CSRRC): Tokenizer.CSRRC,
+}
+
+var csrImmediateMnemonics = map[uint8]Tokenizer.Mnemonic{
+	uint8(Producer.CSRRWI): Tokenizer.CSRRWI,
+	uint8(Producer.CSRRSI): Tokenizer.CSRRSI,
+	uint8(Producer.CSRRCI): Tokenizer.CSRRCI,
+}
+
+func system(result Parser.RiscVBinaryParseResult) (string, error) {
+	dest := register(result.FiveBitDestination)
+	if mnemonic, ok := csrMnemonics[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s %d %s", mnemonic, dest, result.TwelveBitImmediate, register(result.FiveBitRegister1)), nil
+	}
+	if mnemonic, ok := csrImmediateMnemonics[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s %d %d", mnemonic, dest, result.TwelveBitImmediate, result.FiveBitRegister1), nil
+	}
+
+	if result.Funct3 == uint8(Producer.Private) && result.FiveBitDestination == 0 &&
+		uint32(result.TwelveBitImmediate)&^Producer.SFENCEVMAMask == Producer.SFENCEVMA {
+		return fmt.Sprintf("%s %s %s", Tokenizer.SFENCEVMA, register(result.FiveBitRegister1),
+			register(uint8(uint32(result.TwelveBitImmediate)&Producer.SFENCEVMAMask))), nil
+	}
+	if result.Funct3 == uint8(Producer.Private) {
+		switch uint32(result.TwelveBitImmediate) {
+		case Producer.ECALL:
+			return string(Tokenizer.ECALL), nil
+		case Producer.EBREAK:
+			return string(Tokenizer.EBREAK), nil
+		case Producer.MRET:
+			return string(Tokenizer.MRET), nil
+		case Producer.SRET:
+			return string(Tokenizer.SRET), nil
+		case Producer.WFI:
+			return string(Tokenizer.WFI), nil
+		}
+	}
+	return "", unknownOperation(result)
+}
+
+/*fenceAll is the immediate of a FENCE that orders all memory accesses, which is the only FENCE the assembler writes*/
+const fenceAll = 0xFF
+
+func fence(result Parser.RiscVBinaryParseResult) (string, error) {
+	if result.Funct3 == uint8(Producer.Fence) && result.TwelveBitImmediate == fenceAll &&
+		result.FiveBitDestination == 0 && result.FiveBitRegister1 == 0 {
+		return string(Tokenizer.FENCE), nil
+	}
+	return "", unknownOperation(result)
+}
@@ -0,0 +1,167 @@
+package disassembly
+
+import (
+	"testing"
+
+	Assembler "github.com/chenhowa/computer/lib/assembly"
+	CodeGeneration "github.com/chenhowa/computer/lib/assembly/codeGeneration"
+	Parser "github.com/chenhowa/computer/lib/assembly/parser"
+	Tokenizer "github.com/chenhowa/computer/lib/assembly/tokenizer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DisassemblerSuite struct {
+	suite.Suite
+	disassembler *RiscVDisassembler
+}
+
+func TestDisassemblerSuite(t *testing.T) {
+	suite.Run(t, new(DisassemblerSuite))
+}
+
+func (suite *DisassemblerSuite) SetupTest() {
+	disassembler := MakeRiscVDisassembler()
+	suite.disassembler = &disassembler
+}
+
+func (suite *DisassemblerSuite) assemble(instruction string) uint32 {
+	tokenizer := Tokenizer.MakeAdaptedRiscVTokenizer(&Tokenizer.RiscVTokenizer{})
+	parser := Parser.MakeRiscVParser()
+	adaptedParser := Parser.MakeAdaptedRiscVParser(&parser)
+	gen := CodeGeneration.MakeRiscVCodeGenerator(0)
+	assembler := Assembler.MakeRiscVAssembler(&tokenizer, &adaptedParser, &gen)
+
+	instructions, err := assembler.Assemble(instruction)
+	suite.Require().Nil(err)
+	suite.Require().Len(instructions, 1)
+	return instructions[0]
+}
+
+func (suite *DisassemblerSuite) TestRoundTrip() {
+	assert := assert.New(suite.T())
+	instructions := []string{
+		"ADDI x1 x0 -5",
+		"SLTIU x2 x3 100",
+		"SLLI x1 x2 31",
+		"SRAI x1 x2 3",
+		"SRLI x1 x2 3",
+		"LUI x5 1048575",
+		"AUIPC x5 1",
+		"SUB x3 x1 x2",
+		"SLTU x3 x1 x2",
+		"MUL x3 x1 x2",
+		"MULHSU x3 x1 x2",
+		"DIV x3 x1 x2",
+		"REMU x31 x30 x29",
+		"LR.W x1 (x2)",
+		"SC.W.RL x3 x4 (x5)",
+		"AMOMAXU.W.AQ x1 x2 (x3)",
+		"AMOOR.W.AQRL x0 x0 (x0)",
+		"AMOXOR.W x6 x7 (x8)",
+		"JAL x1 -8",
+		"JALR x0 4(x1)",
+		"BGEU x1 x2 -16",
+		"LHU x1 -4(x2)",
+		"SB x3 1000(x4)",
+		"CSRRS x3 768 x0",
+		"CSRRWI x0 768 31",
+		"ECALL",
+		"EBREAK",
+		"MRET",
+		"SRET",
+		"WFI",
+		"SFENCE.VMA x1 x0",
+		"FENCE",
+		"BEQ x0 x0 4094",
+		"JAL x0 -1048576",
+	}
+
+	for _, instruction := range instructions {
+		assembly, err := suite.disassembler.Disassemble(suite.assemble(instruction))
+		assert.Nil(err)
+		assert.Equal(instruction, assembly)
+	}
+}
+
+func (suite *DisassemblerSuite) TestPseudoInstructionsDisassembleToBaseInstructions() {
+	assert := assert.New(suite.T())
+	assembly, err := suite.disassembler.Disassemble(suite.assemble("MV x1 x2"))
+	assert.Nil(err)
+	assert.Equal("ADDI x1 x2 0", assembly)
+}
+
+func (suite *DisassemblerSuite) TestCompressedInstructionsDisassembleToExpandedInstructions() {
+	assert := assert.New(suite.T())
+	expected := map[string]string{
+		"C.ADDI4SPN x8 x2 1020": "ADDI x8 x2 1020",
+		"C.LW x10 124(x11)":     "LW x10 124(x11)",
+		"C.LUI x7 0xFFFE0":      "LUI x7 1048544",
+		"C.BNEZ x10 -256":       "BNE x10 x0 -256",
+		"C.JAL 2046":            "JAL x1 2046",
+		"C.MV x4 x5":            "ADD x4 x0 x5",
+		"C.EBREAK":              "EBREAK",
+	}
+
+	for compressed, expanded := range expected {
+		assembly, err := suite.disassembler.Disassemble(suite.assemble(compressed))
+		assert.Nil(err)
+		assert.Equal(expanded, assembly)
+	}
+}
+
+func (suite *DisassemblerSuite) TestFloatingPointInstructions() {
+	assert := assert.New(suite.T())
+	expected := map[uint32]string{
+		0x0085b507: "FLD f10 8(x11)",
+		0xffc12007: "FLW f0 -4(x2)",
+		0x00152627: "FSW f1 12(x10)",
+		0x8084b027: "FSD f8 -2048(x9)",
+		0x0020f053: "FADD.S f0 f1 f2",
+		0x0ac59553: "FSUB.D f10 f11 f12 RTZ",
+		0x105201d3: "FMUL.S f3 f4 f5 RNE",
+		0x580170d3: "FSQRT.S f1 f2",
+		0x223120d3: "FSGNJX.D f1 f2 f3",
+		0x283110d3: "FMAX.S f1 f2 f3",
+		0xa220a553: "FEQ.D x10 f1 f2",
+		0xa0209553: "FLT.S x10 f1 f2",
+		0xc0009553: "FCVT.W.S x10 f1 RTZ",
+		0xc210f553: "FCVT.WU.D x10 f1",
+		0xd00570d3: "FCVT.S.W f1 x10",
+		0x401170d3: "FCVT.S.D f1 f2",
+		0x420100d3: "FCVT.D.S f1 f2 RNE",
+		0xe0008553: "FMV.X.W x10 f1",
+		0xf00500d3: "FMV.W.X f1 x10",
+		0xe2009553: "FCLASS.D x10 f1",
+		0x203170c3: "FMADD.S f1 f2 f3 f4",
+		0x223140cb: "FNMSUB.D f1 f2 f3 f4 RMM",
+	}
+
+	for instruction, expectedAssembly := range expected {
+		assembly, err := suite.disassembler.Disassemble(instruction)
+		assert.Nil(err)
+		assert.Equal(expectedAssembly, assembly)
+	}
+}
+
+func (suite *DisassemblerSuite) TestInvalidFloatingPointInstructions() {
+	assert := assert.New(suite.T())
+	invalid := []uint32{
+		0x0020d053, // FADD.S with the reserved rounding mode 5
+		0x0620f053, // FADD with the quad-precision format
+		0x581170d3, // FSQRT.S with register 2 set
+		0x421100d3, // FCVT.D.D
+		0xe2008553, // FMV.X.D, which RV32 does not have
+	}
+
+	for _, instruction := range invalid {
+		_, err := suite.disassembler.Disassemble(instruction)
+		assert.NotNil(err, "0x%08x", instruction)
+	}
+}
+
+func (suite *DisassemblerSuite) TestInvalidInstruction() {
+	assert := assert.New(suite.T())
+	_, err := suite.disassembler.Disassemble(0x7F)
+	assert.NotNil(err)
+}
@@ -0,0 +1,185 @@
+package disassembly
+
+import (
+	"fmt"
+
+	Execution "github.com/chenhowa/computer/lib/binaryInstructionExecution/execution"
+	FloatingPoint "github.com/chenhowa/computer/lib/binaryInstructionExecution/execution/floatingPoint"
+	Producer "github.com/chenhowa/computer/lib/binaryInstructionExecution/executionFactoryProducers"
+	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+)
+
+/*floatRegister writes a floating-point register, which are named f0 to f31*/
+func floatRegister(reg uint8) string {
+	return fmt.Sprintf("f%d", reg)
+}
+
+/*floatFormats are the mnemonic suffixes of the `fmt` fields of the F and D extensions*/
+var floatFormats = map[uint8]string{
+	uint8(Execution.SinglePrecision): "S",
+	uint8(Execution.DoublePrecision): "D",
+}
+
+func floatFormat(format uint8) (string, error) {
+	if suffix, ok := floatFormats[format]; ok {
+		return suffix, nil
+	}
+	return "", fmt.Errorf("Disassemble: fmt %d is not a supported floating-point format", format)
+}
+
+var roundingModes = map[uint8]string{
+	uint8(FloatingPoint.RoundToNearestEven):         "RNE",
+	uint8(FloatingPoint.RoundTowardsZero):           "RTZ",
+	uint8(FloatingPoint.RoundDown):                  "RDN",
+	uint8(FloatingPoint.RoundUp):                    "RUP",
+	uint8(FloatingPoint.RoundToNearestMaxMagnitude): "RMM",
+}
+
+/*withRoundingMode appends the rounding mode in the Funct3 of `result` to `assembly` as its last operand.
+The dynamic rounding mode is left out, as it is in the assembly of the RISC-V specification*/
+func withRoundingMode(assembly string, result Parser.RiscVBinaryParseResult) (string, error) {
+	if result.Funct3 == uint8(FloatingPoint.Dynamic) {
+		return assembly, nil
+	}
+	if mode, ok := roundingModes[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s", assembly, mode), nil
+	}
+	return "", fmt.Errorf("Disassemble: %d is not a valid rounding mode", result.Funct3)
+}
+
+var floatLoadMnemonics = map[uint8]string{
+	uint8(Producer.LoadFloat):  "FLW",
+	uint8(Producer.LoadDouble): "FLD",
+}
+
+func loadFloat(result Parser.RiscVBinaryParseResult) (string, error) {
+	if mnemonic, ok := floatLoadMnemonics[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s %d(%s)", mnemonic, floatRegister(result.FiveBitDestination),
+			signed12(result), register(result.FiveBitRegister1)), nil
+	}
+	return "", unknownOperation(result)
+}
+
+var floatStoreMnemonics = map[uint8]string{
+	uint8(Producer.StoreFloat):  "FSW",
+	uint8(Producer.StoreDouble): "FSD",
+}
+
+func storeFloat(result Parser.RiscVBinaryParseResult) (string, error) {
+	if mnemonic, ok := floatStoreMnemonics[result.Funct3]; ok {
+		return fmt.Sprintf("%s %s %d(%s)", mnemonic, floatRegister(result.FiveBitRegister2),
+			signed12(result), register(result.FiveBitRegister1)), nil
+	}
+	return "", unknownOperation(result)
+}
+
+var fusedMnemonics = map[Parser.OpCode]string{
+	Parser.FMAdd:  "FMADD",
+	Parser.FMSub:  "FMSUB",
+	Parser.FNMSub: "FNMSUB",
+	Parser.FNMAdd: "FNMADD",
+}
+
+func fusedMultiplyAdd(result Parser.RiscVBinaryParseResult) (string, error) {
+	format, err := floatFormat(result.Funct2)
+	if err != nil {
+		return "", err
+	}
+
+	return withRoundingMode(fmt.Sprintf("%s.%s %s %s %s %s", fusedMnemonics[result.OpCode], format,
+		floatRegister(result.FiveBitDestination), floatRegister(result.FiveBitRegister1),
+		floatRegister(result.FiveBitRegister2), floatRegister(result.FiveBitRegister3)), result)
+}
+
+/*roundedFloatMnemonics are the operations on two floating-point registers that round their result*/
+var roundedFloatMnemonics = map[uint8]string{
+	uint8(Producer.FAdd): "FADD",
+	uint8(Producer.FSub): "FSUB",
+	uint8(Producer.FMul): "FMUL",
+	uint8(Producer.FDiv): "FDIV",
+}
+
+/*selectedFloatMnemonics are the operations on two floating-point registers that share a Funct5*/
+var selectedFloatMnemonics = map[uint8](map[uint8]string){
+	uint8(Producer.FSignInject): map[uint8]string{
+		uint8(Producer.FSgnj):  "FSGNJ",
+		uint8(Producer.FSgnjn): "FSGNJN",
+		uint8(Producer.FSgnjx): "FSGNJX",
+	},
+	uint8(Producer.FMinMax): map[uint8]string{
+		uint8(Producer.FMin): "FMIN",
+		uint8(Producer.FMax): "FMAX",
+	},
+}
+
+var compareMnemonics = map[uint8]string{
+	uint8(Producer.FLe): "FLE",
+	uint8(Producer.FLt): "FLT",
+	uint8(Producer.FEq): "FEQ",
+}
+
+/*integerFormats are the mnemonic suffixes of the integers that register 2 selects in a conversion*/
+var integerFormats = map[uint8]string{
+	uint8(Producer.FCvtWord):         "W",
+	uint8(Producer.FCvtWordUnsigned): "WU",
+}
+
+/*floatOperation writes an OP-FP instruction. Operations that read a single source register
+require register 2 to hold the value that the specification gives it*/
+func floatOperation(result Parser.RiscVBinaryParseResult) (string, error) {
+	format, err := floatFormat(result.Funct2)
+	if err != nil {
+		return "", err
+	}
+
+	dest, src1, src2 := floatRegister(result.FiveBitDestination), floatRegister(result.FiveBitRegister1),
+		floatRegister(result.FiveBitRegister2)
+	single := result.Funct2 == uint8(Execution.SinglePrecision)
+
+	if mnemonic, ok := roundedFloatMnemonics[result.Funct5]; ok {
+		return withRoundingMode(fmt.Sprintf("%s.%s %s %s %s", mnemonic, format, dest, src1, src2), result)
+	}
+	if m, ok := selectedFloatMnemonics[result.Funct5]; ok {
+		if mnemonic, ok := m[result.Funct3]; ok {
+			return fmt.Sprintf("%s.%s %s %s %s", mnemonic, format, dest, src1, src2), nil
+		}
+		return "", unknownOperation(result)
+	}
+
+	switch result.Funct5 {
+	case uint8(Producer.FCompare):
+		if mnemonic, ok := compareMnemonics[result.Funct3]; ok {
+			return fmt.Sprintf("%s.%s %s %s %s", mnemonic, format, register(result.FiveBitDestination), src1, src2), nil
+		}
+	case uint8(Producer.FSqrt):
+		if result.FiveBitRegister2 == 0 {
+			return withRoundingMode(fmt.Sprintf("FSQRT.%s %s %s", format, dest, src1), result)
+		}
+	case uint8(Producer.FConvertFormat):
+		if source, ok := floatFormats[result.FiveBitRegister2]; ok && source != format {
+			return withRoundingMode(fmt.Sprintf("FCVT.%s.%s %s %s", format, source, dest, src1), result)
+		}
+	case uint8(Producer.FConvertToInt):
+		if integer, ok := integerFormats[result.FiveBitRegister2]; ok {
+			return withRoundingMode(fmt.Sprintf("FCVT.%s.%s %s %s", integer, format,
+				register(result.FiveBitDestination), src1), result)
+		}
+	case uint8(Producer.FConvertFromInt):
+		if integer, ok := integerFormats[result.FiveBitRegister2]; ok {
+			return withRoundingMode(fmt.Sprintf("FCVT.%s.%s %s %s", format, integer, dest,
+				register(result.FiveBitRegister1)), result)
+		}
+	case uint8(Producer.FMoveToInt):
+		if result.FiveBitRegister2 == 0 && result.Funct3 == uint8(Producer.FClass) {
+			return fmt.Sprintf("FCLASS.%s %s %s", format, register(result.FiveBitDestination), src1), nil
+		} else if result.FiveBitRegister2 == 0 && result.Funct3 == uint8(Producer.FMvXW) && single {
+			return fmt.Sprintf("FMV.X.W %s %s", register(result.FiveBitDestination), src1), nil
+		}
+	case uint8(Producer.FMoveFromInt):
+		if result.FiveBitRegister2 == 0 && result.Funct3 == uint8(Producer.FMvWX) && single {
+			return fmt.Sprintf("FMV.W.X %s %s", dest, register(result.FiveBitRegister1)), nil
+		}
+	}
+	return "", fmt.Errorf("Disassemble: funct5 %d, funct3 %d and register 2 %d are not a valid floating-point operation",
+		result.Funct5, result.Funct3, result.FiveBitRegister2)
+}
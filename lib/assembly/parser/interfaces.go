@@ -2,42 +2,21 @@ package parser
 
 import Assembler "github.com/chenhowa/computer/lib/assembly"
 
-type tokenStream interface {
-	HasNext() bool
-	Next() (Token, error)
-	Save() TokenStreamReset
-}
+/*These aliases let the parser share its interfaces with the assembly package, so that
+the parser can be plugged directly into an assembly.RiscVAssembler*/
+type tokenStream = Assembler.TokenStream
 
 /*TokenStreamReset is an interface that can reset a tokenStream
  */
-type TokenStreamReset interface {
-	Reset()
-}
+type TokenStreamReset = Assembler.TokenStreamReset
 
 /*Token is an interface that represents a token of the Risc-V 32I Assembly Language*/
-type Token interface {
-	GetTokenType() Assembler.TokenType
-	GetTokenString() string
-	GetCharCountSinceNewline() Assembler.CharCount
-}
+type Token = Assembler.AssemblyToken
 
-type abstractSyntaxTree interface {
-	GetRootIterator() AstIterator
-}
+type abstractSyntaxTree = Assembler.AbstractSyntaxTree
 
 /*AstIterator is an interface that represents an iterator over an AST of RiscV Tokens*/
-type AstIterator interface {
-	GetNumChildren() uint
-	GetAstNode() AstNode
-	GetParentIterator() (AstIterator, error)
-	GetChildIterator(index uint) (AstIterator, error)
-}
+type AstIterator = Assembler.AstIterator
 
 /*AstNode is an interface that represents a node in an AST of RiscV Tokens*/
-type AstNode interface {
-	GetLineCount() Assembler.LineCount
-	GetCharCountSinceNewline() Assembler.CharCount
-	GetTokenType() Assembler.TokenType
-	GetTokenString() string
-	GetNodeKind() Assembler.AstNodeKind
-}
+type AstNode = Assembler.AstNode
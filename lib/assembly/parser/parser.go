@@ -24,30 +24,37 @@ func MakeRiscVParser() RiscVParser {
 /*Parse takes a `tokenStream` and attempts to parse all the tokens in the stream into an Abstract Syntax Tree representation
 of the RISC-V Assembly Program. If the parse is unsuccessful, it will return a non-nil error `err`.
 If the parse is successful, it will return the AST `tree`, as well as `linesEncountered`, which represents the number
-of newline tokens that were encountered in the parsing of `tokenStream` */
+of newline tokens that were encountered in the parsing of `tokenStream`. Each node of the tree holds the line
+of `tokenStream` (starting from 1) that it was found on*/
 func (parser *RiscVParser) Parse(tokenStream tokenStream) (tree RiscVAst, linesEncountered Assembler.LineCount, err error) {
 
 	//optionalNewlines() && optionalInstructions() && optionalNewlines()
 	newlinesAst, newlinesOk := optionalNewlines(tokenStream)
-	if newlinesOk {
-		return newlinesAst, 0, nil
-	}
+	lines := countNewlines(newlinesAst, newlinesOk)
 
 	//Since it's optional, it doesn't matter whether it succeeded, or failed
-	instructionsAst, instructionsOk := optionalInstructions(tokenStream)
+	instructionsAst, instructionLines, instructionsOk := optionalInstructions(tokenStream, lines+1)
+	lines += instructionLines
 
-	if instructionsOk {
-		return instructionsAst, 0, nil
-	}
-	/*
-		// Since it's optional, it doesn't matter whether it succeeded, or failed.
-		newlinesAst2, newlinesOk2 := optionalNewlines(tokenStream)
+	// Since it's optional, it doesn't matter whether it succeeded, or failed.
+	newlinesAst2, newlinesOk2 := optionalNewlines(tokenStream)
+	lines += countNewlines(newlinesAst2, newlinesOk2)
 
-		if newlinesOk || instructionsOk || newlinesOk2 {
-			finalTree := combine(newlinesAst, instructionsAst, newlinesAst2)
+	reset := tokenStream.Save()
+	token, tokenErr := tokenStream.Next()
+	reset.Reset()
 
-			return finalTree, getLineCount(finalTree), nil
-		}*/
+	if tokenErr == nil && token.GetTokenType() == Assembler.EndOfInput {
+		if instructionsOk {
+			return instructionsAst, lines, nil
+		} else if newlinesOk {
+			return newlinesAst, lines, nil
+		} else {
+			// An empty program is still a valid program
+			node := makeRiscVAstNode(nil, 1, nil, Assembler.Instructions)
+			return RiscVAst{root: &node}, 0, nil
+		}
+	}
 
 	// If no parses succeeded at all, all we can say is that the program could not be parsed
 	node := RiscVAstNode{
@@ -59,55 +66,71 @@ func (parser *RiscVParser) Parse(tokenStream tokenStream) (tree RiscVAst, linesE
 	errorAst := RiscVAst{
 		root: &node,
 	}
-	return errorAst, 0, errors.New("Parse: Input program could not be parsed at all")
+	if !newlinesOk && !instructionsOk {
+		return errorAst, 0, errors.New("Parse: Input program could not be parsed at all")
+	}
+
+	return errorAst, 0, fmt.Errorf("Parse: unexpected token \"%s\" on line %d, character %d",
+		token.GetTokenString(), lines+1, token.GetCharCountSinceNewline())
 }
 
-func optionalInstructions(stream tokenStream) (tree RiscVAst, success bool) {
+/*optionalInstructions parses as many instructions as it can. The first instruction is on line `line`*/
+func optionalInstructions(stream tokenStream, line Assembler.LineCount) (tree RiscVAst, linesEncountered Assembler.LineCount, success bool) {
 	reset := stream.Save()
-	node := makeRiscVAstNode(nil, 0, nil, Assembler.Instructions)
+	node := makeRiscVAstNode(nil, line, nil, Assembler.Instructions)
 	ast := RiscVAst{
 		root: &node,
 	}
-	optionalInstructionsAst, ok := _optionalInstructions(stream, &ast)
+	optionalInstructionsAst, lines, ok := _optionalInstructions(stream, &ast, line)
 
 	if ok {
-		return optionalInstructionsAst, true
+		return optionalInstructionsAst, lines, true
 	} else {
 		reset.Reset()
-		return RiscVAst{}, false
+		return RiscVAst{}, 0, false
 	}
 }
 
-func _optionalInstructions(stream tokenStream, rootLevelAst *RiscVAst) (tree RiscVAst, success bool) {
-	instructionAst, ok := instruction(stream)
+func _optionalInstructions(stream tokenStream, rootLevelAst *RiscVAst, line Assembler.LineCount) (tree RiscVAst, linesEncountered Assembler.LineCount, success bool) {
+	instructionAst, ok := instruction(stream, line)
 	if ok {
 		newAst1 := addAsChild(*rootLevelAst, rootLevelAst.getRootIterator(), instructionAst)
 		_, ok1 := newline(stream) // we require a newline between each instruction
 		if ok1 {
-			optionalNewlines(stream) // more than 1 newline is acceptable, but not required.
-			newAst2, ok2 := _optionalInstructions(stream, &newAst1)
+			newlinesAst, newlinesOk := optionalNewlines(stream) // more than 1 newline is acceptable, but not required.
+			lines := 1 + countNewlines(newlinesAst, newlinesOk)
+			newAst2, moreLines, ok2 := _optionalInstructions(stream, &newAst1, line+lines)
 			if ok2 {
-				return newAst2, true
+				return newAst2, lines + moreLines, true
 			} else {
-				return newAst1, true
+				return newAst1, lines, true
+			}
+		} else if isLabelInstruction(instructionAst) {
+			// ... except after a label, which may share its line with the instruction it labels
+			newAst2, moreLines, ok2 := _optionalInstructions(stream, &newAst1, line)
+			if ok2 {
+				return newAst2, moreLines, true
+			} else {
+				return newAst1, 0, true
 			}
 		} else {
-			return newAst1, true
+			return newAst1, 0, true
 		}
 
 	} else {
-		return RiscVAst{}, false
+		return RiscVAst{}, 0, false
 	}
 }
 
-func instruction(stream tokenStream) (tree RiscVAst, success bool) {
+func instruction(stream tokenStream, line Assembler.LineCount) (tree RiscVAst, success bool) {
 	reset := stream.Save()
-	parent := makeRiscVAstNode(nil, 0, nil, Assembler.Instruction)
+	parent := makeRiscVAstNode(nil, line, nil, Assembler.Instruction)
 	instructionAst := RiscVAst{
 		root: &parent,
 	}
 	mnemonicInstructionAst, mnemonicOk := mnemonicInstruction(stream)
 	if mnemonicOk {
+		setLineCount(mnemonicInstructionAst.root, line)
 		instructionAst = addAsChild(instructionAst, instructionAst.getRootIterator(), mnemonicInstructionAst)
 		return instructionAst, true
 	}
@@ -116,6 +139,7 @@ func instruction(stream tokenStream) (tree RiscVAst, success bool) {
 	labelInstructionAst, labelOk := labelInstruction(stream)
 
 	if labelOk {
+		setLineCount(labelInstructionAst.root, line)
 		instructionAst = addAsChild(instructionAst, instructionAst.getRootIterator(), labelInstructionAst)
 		return instructionAst, true
 	}
@@ -124,6 +148,11 @@ func instruction(stream tokenStream) (tree RiscVAst, success bool) {
 	return RiscVAst{}, false
 }
 
+/*maxOperands is the largest number of operands that any mnemonic takes*/
+const maxOperands = 3
+
+/*mnemonicInstruction parses a mnemonic followed by up to `maxOperands` operands. Whether the
+mnemonic accepts that many operands is left for code generation to decide*/
 func mnemonicInstruction(stream tokenStream) (tree RiscVAst, success bool) {
 	mnemonicAst, mnemonicOk := mnemonic(stream)
 
@@ -131,21 +160,16 @@ func mnemonicInstruction(stream tokenStream) (tree RiscVAst, success bool) {
 		return RiscVAst{}, false
 	}
 
-	operand1Ast, operand1Ok := operand(stream)
+	for i := 0; i < maxOperands; i++ {
+		operandAst, operandOk := operand(stream)
 
-	if !operand1Ok {
-		return RiscVAst{}, false
-	}
-
-	operand2Ast, operand2Ok := operand(stream)
+		if !operandOk {
+			break
+		}
 
-	if !operand2Ok {
-		return RiscVAst{}, false
+		addAsChild(mnemonicAst, mnemonicAst.getRootIterator(), operandAst)
 	}
 
-	addAsChild(mnemonicAst, mnemonicAst.getRootIterator(), operand1Ast)
-	addAsChild(mnemonicAst, mnemonicAst.getRootIterator(), operand2Ast)
-
 	return mnemonicAst, true
 }
 
@@ -188,6 +212,26 @@ func addAsChild(parentTree RiscVAst, nodeToAddAt RiscVAstIterator, childTree Ris
 	return tree
 }
 
+/*AdaptedRiscVParser adapts a RiscVParser so that it fits the `parser` interface of the assembly package*/
+type AdaptedRiscVParser struct {
+	parser *RiscVParser
+}
+
+/*MakeAdaptedRiscVParser is a constructor for AdaptedRiscVParser*/
+func MakeAdaptedRiscVParser(parser *RiscVParser) AdaptedRiscVParser {
+	adapted := AdaptedRiscVParser{
+		parser: parser,
+	}
+
+	return adapted
+}
+
+/*Parse parses the `tokenStream` with the adapted RiscVParser*/
+func (p *AdaptedRiscVParser) Parse(tokenStream tokenStream) (tree abstractSyntaxTree, linesEncountered Assembler.LineCount, err error) {
+	ast, lines, err := p.parser.Parse(tokenStream)
+	return &ast, lines, err
+}
+
 /*RiscVAst represents an Abstract Syntax Tree of a valid RISC-V 32I Assembly Program*/
 type RiscVAst struct {
 	root *RiscVAstNode
@@ -122,6 +122,35 @@ func newline(stream tokenStream) (tree RiscVAst, success bool) {
 	}
 }
 
+func countNewlines(newlinesAst RiscVAst, success bool) Assembler.LineCount {
+	if !success {
+		return 0
+	}
+
+	count := Assembler.LineCount(0)
+	for node := newlinesAst.root; node != nil; {
+		count++
+		if len(node.children) > 0 {
+			node = node.children[0]
+		} else {
+			node = nil
+		}
+	}
+	return count
+}
+
+func isLabelInstruction(instructionAst RiscVAst) bool {
+	children := instructionAst.root.children
+	return len(children) == 1 && children[0].data != nil && isLabel(children[0].data)
+}
+
+func setLineCount(node *RiscVAstNode, line Assembler.LineCount) {
+	node.lineCount = line
+	for _, child := range node.children {
+		setLineCount(child, line)
+	}
+}
+
 func convertToString(iter AstIterator) string {
 	var builder strings.Builder
 	builder.WriteString("(" + getIterRepr(iter))
@@ -32,6 +32,52 @@ var mnemonicToToken = map[Mnemonic](Assembler.TokenType){
 	SRL:        Assembler.SRL,
 	SUB:        Assembler.SUB,
 	SRA:        Assembler.SRA,
+	MUL:        Assembler.MUL,
+	MULH:       Assembler.MULH,
+	MULHSU:     Assembler.MULHSU,
+	MULHU:      Assembler.MULHU,
+	DIV:        Assembler.DIV,
+	DIVU:       Assembler.DIVU,
+	REM:        Assembler.REM,
+	REMU:       Assembler.REMU,
+	LRW:        Assembler.LRW,
+	SCW:        Assembler.SCW,
+	AMOSWAPW:   Assembler.AMOSWAPW,
+	AMOADDW:    Assembler.AMOADDW,
+	AMOXORW:    Assembler.AMOXORW,
+	AMOANDW:    Assembler.AMOANDW,
+	AMOORW:     Assembler.AMOORW,
+	AMOMINW:    Assembler.AMOMINW,
+	AMOMAXW:    Assembler.AMOMAXW,
+	AMOMINUW:   Assembler.AMOMINUW,
+	AMOMAXUW:   Assembler.AMOMAXUW,
+	CADDI4SPN:  Assembler.CADDI4SPN,
+	CLW:        Assembler.CLW,
+	CSW:        Assembler.CSW,
+	CNOP:       Assembler.CNOP,
+	CADDI:      Assembler.CADDI,
+	CJAL:       Assembler.CJAL,
+	CLI:        Assembler.CLI,
+	CADDI16SP:  Assembler.CADDI16SP,
+	CLUI:       Assembler.CLUI,
+	CSRLI:      Assembler.CSRLI,
+	CSRAI:      Assembler.CSRAI,
+	CANDI:      Assembler.CANDI,
+	CSUB:       Assembler.CSUB,
+	CXOR:       Assembler.CXOR,
+	COR:        Assembler.COR,
+	CAND:       Assembler.CAND,
+	CJ:         Assembler.CJ,
+	CBEQZ:      Assembler.CBEQZ,
+	CBNEZ:      Assembler.CBNEZ,
+	CSLLI:      Assembler.CSLLI,
+	CLWSP:      Assembler.CLWSP,
+	CSWSP:      Assembler.CSWSP,
+	CJR:        Assembler.CJR,
+	CMV:        Assembler.CMV,
+	CEBREAK:    Assembler.CEBREAK,
+	CJALR:      Assembler.CJALR,
+	CADD:       Assembler.CADD,
 	NOP:        Assembler.NOP,
 	JAL:        Assembler.JAL,
 	JALR:       Assembler.JALR,
@@ -64,6 +110,11 @@ var mnemonicToToken = map[Mnemonic](Assembler.TokenType){
 	CSRCI:      Assembler.CSRCI,
 	ECALL:      Assembler.ECALL,
 	EBREAK:     Assembler.EBREAK,
+	MRET:       Assembler.MRET,
+	SRET:       Assembler.SRET,
+	WFI:        Assembler.WFI,
+	SFENCEVMA:  Assembler.SFENCEVMA,
+	FENCE:      Assembler.FENCE,
 	MV:         Assembler.MV,
 	SEQZ:       Assembler.SEQZ,
 	NOT:        Assembler.NOT,
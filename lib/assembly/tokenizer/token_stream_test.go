@@ -95,8 +95,22 @@ func (suite *RiscVTokenStreamSuite) TestNext_TwoMnemonicTokensAndNewline() {
 	suite.AssertExpectedTokenEqualsActual(&expected, &token)
 }
 
+func (suite *RiscVTokenStreamSuite) TestNext_NewlineBeforeMnemonic() {
+	input := fmt.Sprintf("%s\n%s", string(ADDI), string(SUB))
+	stream := MakeRiscVTokenStream(input)
+
+	expected := makeRiscVToken(Assembler.ADDI, string(ADDI), 0)
+	suite.AssertNextTokenIs(&stream, &expected)
+
+	expected = makeRiscVToken(Assembler.Newline, "\n", Assembler.CharCount(uint(len(ADDI))))
+	suite.AssertNextTokenIs(&stream, &expected)
+
+	expected = makeRiscVToken(Assembler.SUB, string(SUB), 0)
+	suite.AssertNextTokenIs(&stream, &expected)
+}
+
 func (suite *RiscVTokenStreamSuite) TestNext_NumericConstants() {
-	input := "1 213 1,123 1,000,000"
+	input := "1 213 1,123 1,000,000 -42 0x1F"
 	stream := MakeRiscVTokenStream(input)
 
 	expected := makeRiscVToken(Assembler.NumericConstant, string("1"), Assembler.CharCount(0))
@@ -110,6 +124,12 @@ func (suite *RiscVTokenStreamSuite) TestNext_NumericConstants() {
 
 	expected = makeRiscVToken(Assembler.NumericConstant, string("1000000"), Assembler.CharCount(12))
 	suite.AssertNextTokenIs(&stream, &expected)
+
+	expected = makeRiscVToken(Assembler.NumericConstant, string("-42"), Assembler.CharCount(22))
+	suite.AssertNextTokenIs(&stream, &expected)
+
+	expected = makeRiscVToken(Assembler.NumericConstant, string("0x1F"), Assembler.CharCount(26))
+	suite.AssertNextTokenIs(&stream, &expected)
 }
 
 func (suite *RiscVTokenStreamSuite) TestNext_Labels_Success() {
@@ -183,6 +203,32 @@ func (suite *RiscVTokenStreamSuite) TestNext_Memory_RegisterImmediatePair_Succes
 	suite.AssertNextTokenIs(&stream, &expected)
 }
 
+func (suite *RiscVTokenStreamSuite) TestNext_Memory_RegisterWithoutImmediate() {
+	input := "(x5)"
+	stream := MakeRiscVTokenStream(input)
+	expected := makeRiscVToken(Assembler.RegisterAndImmediate, "(x5)", Assembler.CharCount(0))
+	suite.AssertNextTokenIs(&stream, &expected)
+}
+
+func (suite *RiscVTokenStreamSuite) TestNext_AtomicMnemonics() {
+	input := "LR.W AMOADD.W.AQ SC.W.RL AMOSWAP.W.AQRL"
+	stream := MakeRiscVTokenStream(input)
+
+	expected := makeRiscVToken(Assembler.LRW, "LR.W", Assembler.CharCount(0))
+	suite.AssertNextTokenIs(&stream, &expected)
+	expected = makeRiscVToken(Assembler.AMOADDW, "AMOADD.W.AQ", Assembler.CharCount(5))
+	suite.AssertNextTokenIs(&stream, &expected)
+	expected = makeRiscVToken(Assembler.SCW, "SC.W.RL", Assembler.CharCount(17))
+	suite.AssertNextTokenIs(&stream, &expected)
+	expected = makeRiscVToken(Assembler.AMOSWAPW, "AMOSWAP.W.AQRL", Assembler.CharCount(25))
+	suite.AssertNextTokenIs(&stream, &expected)
+
+	// only atomic mnemonics have an ordering
+	stream = MakeRiscVTokenStream("ADD.AQ")
+	_, err := stream.Next()
+	assert.NotEqual(suite.T(), nil, err)
+}
+
 func (suite *RiscVTokenStreamSuite) TestNext_Memory_RegisterImmediatePair_Failure() {
 	input := "8(x32)"
 	stream := MakeRiscVTokenStream(input)
@@ -26,6 +26,52 @@ const (
 	SRL        Mnemonic = "SRL"
 	SUB        Mnemonic = "SUB"
 	SRA        Mnemonic = "SRA"
+	MUL        Mnemonic = "MUL"
+	MULH       Mnemonic = "MULH"
+	MULHSU     Mnemonic = "MULHSU"
+	MULHU      Mnemonic = "MULHU"
+	DIV        Mnemonic = "DIV"
+	DIVU       Mnemonic = "DIVU"
+	REM        Mnemonic = "REM"
+	REMU       Mnemonic = "REMU"
+	LRW        Mnemonic = "LR.W"
+	SCW        Mnemonic = "SC.W"
+	AMOSWAPW   Mnemonic = "AMOSWAP.W"
+	AMOADDW    Mnemonic = "AMOADD.W"
+	AMOXORW    Mnemonic = "AMOXOR.W"
+	AMOANDW    Mnemonic = "AMOAND.W"
+	AMOORW     Mnemonic = "AMOOR.W"
+	AMOMINW    Mnemonic = "AMOMIN.W"
+	AMOMAXW    Mnemonic = "AMOMAX.W"
+	AMOMINUW   Mnemonic = "AMOMINU.W"
+	AMOMAXUW   Mnemonic = "AMOMAXU.W"
+	CADDI4SPN  Mnemonic = "C.ADDI4SPN"
+	CLW        Mnemonic = "C.LW"
+	CSW        Mnemonic = "C.SW"
+	CNOP       Mnemonic = "C.NOP"
+	CADDI      Mnemonic = "C.ADDI"
+	CJAL       Mnemonic = "C.JAL"
+	CLI        Mnemonic = "C.LI"
+	CADDI16SP  Mnemonic = "C.ADDI16SP"
+	CLUI       Mnemonic = "C.LUI"
+	CSRLI      Mnemonic = "C.SRLI"
+	CSRAI      Mnemonic = "C.SRAI"
+	CANDI      Mnemonic = "C.ANDI"
+	CSUB       Mnemonic = "C.SUB"
+	CXOR       Mnemonic = "C.XOR"
+	COR        Mnemonic = "C.OR"
+	CAND       Mnemonic = "C.AND"
+	CJ         Mnemonic = "C.J"
+	CBEQZ      Mnemonic = "C.BEQZ"
+	CBNEZ      Mnemonic = "C.BNEZ"
+	CSLLI      Mnemonic = "C.SLLI"
+	CLWSP      Mnemonic = "C.LWSP"
+	CSWSP      Mnemonic = "C.SWSP"
+	CJR        Mnemonic = "C.JR"
+	CMV        Mnemonic = "C.MV"
+	CEBREAK    Mnemonic = "C.EBREAK"
+	CJALR      Mnemonic = "C.JALR"
+	CADD       Mnemonic = "C.ADD"
 	NOP        Mnemonic = "NOP"
 	JAL        Mnemonic = "JAL"
 	JALR       Mnemonic = "JALR"
@@ -58,6 +104,11 @@ const (
 	CSRCI      Mnemonic = "CSRCI"
 	ECALL      Mnemonic = "ECALL"
 	EBREAK     Mnemonic = "EBREAK"
+	MRET       Mnemonic = "MRET"
+	SRET       Mnemonic = "SRET"
+	WFI        Mnemonic = "WFI"
+	SFENCEVMA  Mnemonic = "SFENCE.VMA"
+	FENCE      Mnemonic = "FENCE"
 	MV         Mnemonic = "MV"
 	SEQZ       Mnemonic = "SEQZ"
 	NOT        Mnemonic = "NOT"
@@ -75,6 +126,17 @@ const (
 	BLEU       Mnemonic = "BLEU"
 )
 
+/*AtomicOrdering represents the suffixes that give an atomic mnemonic, such as AMOADD.W.AQ,
+acquire ordering, release ordering, or both*/
+type AtomicOrdering string
+
+/*These string constants represent the valid AtomicOrderings*/
+const (
+	AQ   AtomicOrdering = ".AQ"
+	RL   AtomicOrdering = ".RL"
+	AQRL AtomicOrdering = ".AQRL"
+)
+
 /*Register represents strings that are valid Registers*/
 type Register string
 
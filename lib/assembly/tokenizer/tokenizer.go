@@ -28,3 +28,56 @@ func (t *RiscVTokenizer) Tokenize(tokens string) *RiscVTokenStream {
 	stream := MakeRiscVTokenStream(tokens)
 	return &stream
 }
+
+/*AdaptedRiscVTokenizer adapts a RiscVTokenizer so that it fits the `tokenizer` interface of the assembly package*/
+type AdaptedRiscVTokenizer struct {
+	tokenizer *RiscVTokenizer
+}
+
+/*MakeAdaptedRiscVTokenizer is a constructor for AdaptedRiscVTokenizer*/
+func MakeAdaptedRiscVTokenizer(tokenizer *RiscVTokenizer) AdaptedRiscVTokenizer {
+	adapted := AdaptedRiscVTokenizer{
+		tokenizer: tokenizer,
+	}
+
+	return adapted
+}
+
+/*Tokenize produces an Assembler.TokenStream over the `tokens`*/
+func (t *AdaptedRiscVTokenizer) Tokenize(tokens string) (Assembler.TokenStream, error) {
+	stream := MakeAdaptedRiscVTokenStream(t.tokenizer.Tokenize(tokens))
+	return &stream, nil
+}
+
+/*AdaptedRiscVTokenStream adapts a RiscVTokenStream so that it fits the Assembler.TokenStream interface*/
+type AdaptedRiscVTokenStream struct {
+	stream *RiscVTokenStream
+}
+
+/*MakeAdaptedRiscVTokenStream is a constructor for AdaptedRiscVTokenStream*/
+func MakeAdaptedRiscVTokenStream(stream *RiscVTokenStream) AdaptedRiscVTokenStream {
+	adapted := AdaptedRiscVTokenStream{
+		stream: stream,
+	}
+
+	return adapted
+}
+
+/*HasNext returns whether any tokens other than the end of the input remain in the stream*/
+func (s *AdaptedRiscVTokenStream) HasNext() bool {
+	reset := s.stream.Save()
+	token, _ := s.stream.Next()
+	reset.Reset()
+	return token.GetTokenType() != Assembler.EndOfInput
+}
+
+/*Next returns the next token in the stream*/
+func (s *AdaptedRiscVTokenStream) Next() (Assembler.AssemblyToken, error) {
+	token, err := s.stream.Next()
+	return &token, err
+}
+
+/*Save saves the current position of the stream*/
+func (s *AdaptedRiscVTokenStream) Save() Assembler.TokenStreamReset {
+	return s.stream.Save()
+}
@@ -9,8 +9,12 @@ import (
 	Assembler "github.com/chenhowa/computer/lib/assembly"
 )
 
+/*numericConstant matches a decimal constant, which may be negative and may group its digits
+with commas, or a hexadecimal constant such as 0x1F*/
+const numericConstant = `((0)|(-?[1-9]\d*)|(-?[1-9](\d|\d\d)?(,\d\d\d)*)|(0x[0-9A-Fa-f]+))`
+
 func isNumericConstant(tokenString string) bool {
-	var rg = regexp.MustCompile(`(^0$)|(^[1-9]\d*$)|(^[1-9](\d|\d\d)?(,\d\d\d)*$)`)
+	var rg = regexp.MustCompile(`^` + numericConstant + `$`)
 
 	match := rg.MatchString(tokenString)
 	return match
@@ -38,6 +42,10 @@ func isUnskippableChar(val byte) bool {
 		return true
 	}
 
+	if val == '-' {
+		return true
+	}
+
 	if val == ':' {
 		return true
 	}
@@ -46,6 +54,10 @@ func isUnskippableChar(val byte) bool {
 		return true
 	}
 
+	if val == '.' { // as in atomic mnemonics such as LR.W
+		return true
+	}
+
 	return false
 }
 
@@ -67,7 +79,12 @@ func cleanTokenString(tokenType Assembler.TokenType, tokenString string) string
 }
 
 func continueReadingTokenInput(latestChar byte, readInput string) bool {
-	return !suddenNewline(readInput, latestChar) && isUnskippableChar(latestChar)
+	return !suddenNewline(readInput, latestChar) && !isNewline(readInput) && isUnskippableChar(latestChar)
+}
+
+/*isNewline returns whether `readInput` is a complete newline token, which nothing may follow*/
+func isNewline(readInput string) bool {
+	return readInput == "\n"
 }
 
 func suddenNewline(readInput string, latestChar byte) bool {
@@ -88,6 +105,11 @@ func getTokenType(tokenString string) (Assembler.TokenType, error) {
 		return tokenType, nil
 	}
 
+	tokenType, ok = atomicMnemonicWithOrdering(tokenString)
+	if ok {
+		return tokenType, nil
+	}
+
 	if tokenString == "\n" {
 		return Assembler.Newline, nil
 	}
@@ -122,6 +144,22 @@ func getTokenType(tokenString string) (Assembler.TokenType, error) {
 	return tokenType, fmt.Errorf("getTokenType: no token type found for this token %s", tokenString)
 }
 
+/*atomicMnemonicWithOrdering returns the token type of `tokenString` if it is an atomic mnemonic
+followed by an AtomicOrdering*/
+func atomicMnemonicWithOrdering(tokenString string) (Assembler.TokenType, bool) {
+	for _, ordering := range []AtomicOrdering{AQRL, AQ, RL} {
+		if !strings.HasSuffix(tokenString, string(ordering)) {
+			continue
+		}
+
+		tokenType, ok := mnemonicToToken[Mnemonic(strings.TrimSuffix(tokenString, string(ordering)))]
+		if ok && tokenType >= Assembler.LRW && tokenType <= Assembler.AMOMAXUW {
+			return tokenType, true
+		}
+	}
+	return 0, false
+}
+
 func isRegister(tokenString string) bool {
 	var rg = regexp.MustCompile(`^x(\d|([1-3]\d))$`)
 
@@ -130,10 +168,10 @@ func isRegister(tokenString string) bool {
 }
 
 func isRegisterImmediate(tokenString string) bool {
-	numericConstant := `((0)|([1-9]\d*)|([1-9](\d|\d\d)?(,\d\d\d)*))`
 	register := `(\(x(\d|([1-2]\d)|(3[0-1]))\))`
 
-	var rg = regexp.MustCompile(`^` + numericConstant + register + `$`)
+	// the offset may be left out, as in the (x5) address operand of atomic instructions
+	var rg = regexp.MustCompile(`^` + numericConstant + `?` + register + `$`)
 	return rg.MatchString(tokenString)
 }
 
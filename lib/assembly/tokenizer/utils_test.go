@@ -28,6 +28,9 @@ func (suite *UtilSuite) TestIsNumericConstant_True() {
 	assert.Equal(true, isNumericConstant("1111111"))
 	assert.Equal(true, isNumericConstant("1,111,111"))
 	assert.Equal(true, isNumericConstant("982838"))
+	assert.Equal(true, isNumericConstant("-1"))
+	assert.Equal(true, isNumericConstant("-1,000"))
+	assert.Equal(true, isNumericConstant("0x1F"))
 }
 
 func (suite *UtilSuite) TestIsNumericConstant_False() {
@@ -39,4 +42,7 @@ func (suite *UtilSuite) TestIsNumericConstant_False() {
 	assert.Equal(false, isNumericConstant("100,0"))
 	assert.Equal(false, isNumericConstant("1000,"))
 	assert.Equal(false, isNumericConstant(",1000"))
+	assert.Equal(false, isNumericConstant("-0"))
+	assert.Equal(false, isNumericConstant("0x"))
+	assert.Equal(false, isNumericConstant("1-"))
 }
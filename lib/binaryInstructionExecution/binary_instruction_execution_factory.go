@@ -49,6 +49,10 @@ func (factory *RiscVBinaryInstructionExecutionFactory) Produce(instruction uint3
 		executor = factory.produceB(result, factory.executor)
 	case Parser.S:
 		executor = factory.produceS(result, factory.executor)
+	case Parser.A:
+		executor = factory.produceA(result, factory.executor)
+	case Parser.F, Parser.D:
+		executor = factory.produceF(result, factory.executor)
 	default:
 		panic(fmt.Sprintf("unrecognized instruction type: %d", result.InstructionType))
 	}
@@ -114,3 +118,23 @@ func (factory *RiscVBinaryInstructionExecutionFactory) produceS(result Parser.Ri
 	// This works because Go does Pointer Escape analysis.
 	return &executor
 }
+
+func (factory *RiscVBinaryInstructionExecutionFactory) produceA(result Parser.RiscVBinaryParseResult, ex Producer.RiscVExecutor) binaryExecutor {
+
+	var executor = Producer.ExecutorA{
+		Executor: ex,
+		Result:   result,
+	}
+	// This works because Go does Pointer Escape analysis.
+	return &executor
+}
+
+func (factory *RiscVBinaryInstructionExecutionFactory) produceF(result Parser.RiscVBinaryParseResult, ex Producer.RiscVExecutor) binaryExecutor {
+
+	var executor = Producer.ExecutorF{
+		Executor: ex,
+		Result:   result,
+	}
+	// This works because Go does Pointer Escape analysis.
+	return &executor
+}
@@ -27,7 +27,7 @@ func (suite *ExecutionFactorySuite) SetupTest() {
 }
 
 func (suite *ExecutionFactorySuite) TestInstruction_I_XorImmediate() {
-	instruction := BuildInstructionI(uint(Parser.ImmArith), 15, uint(Producer.XorI), 12, uint(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 2, 11)))
+	instruction := Parser.BuildInstructionI(uint(Parser.ImmArith), 15, uint(Producer.XorI), 12, uint(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 2, 11)))
 
 	suite.executorMock.On("xorImmediate", uint(15), uint(12), Utils.KeepBitsInInclusiveRange(math.MaxUint32, 2, 11))
 	executor := suite.factory.Produce(uint32(instruction))
@@ -36,7 +36,7 @@ func (suite *ExecutionFactorySuite) TestInstruction_I_XorImmediate() {
 }
 
 func (suite *ExecutionFactorySuite) TestInstruction_U_AUIPC() {
-	instruction := BuildInstructionU(uint(Parser.AUIPC), 20, uint(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 1, 19)))
+	instruction := Parser.BuildInstructionU(uint(Parser.AUIPC), 20, uint(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 1, 19)))
 
 	suite.executorMock.On("addUpperImmediateToPC", uint(20), Utils.KeepBitsInInclusiveRange(math.MaxUint32, 1, 19))
 	suite.factory.Produce(uint32(instruction)).Execute()
@@ -45,28 +45,93 @@ func (suite *ExecutionFactorySuite) TestInstruction_U_AUIPC() {
 }
 
 func (suite *ExecutionFactorySuite) TestInstruction_R_ShiftRightArithmetic() {
-	instruction := BuildInstructionR(uint(Parser.RegArith), 11, uint(Producer.SRA), 4, 5, uint(Producer.F1))
+	instruction := Parser.BuildInstructionR(uint(Parser.RegArith), 11, uint(Producer.SRA), 4, 5, uint(Producer.F1))
 	suite.executorMock.On("shiftRightArithmetic", uint(11), uint(4), uint(5))
 	suite.factory.Produce(uint32(instruction)).Execute()
 	suite.executorMock.AssertCalled(suite.T(), "shiftRightArithmetic", uint(11), uint(4), uint(5))
 }
 
+func (suite *ExecutionFactorySuite) TestInstruction_R_RemainderUnsigned() {
+	instruction := Parser.BuildInstructionR(uint(Parser.RegArith), 3, uint(Producer.RemU), 30, 31, uint(Producer.FM))
+	suite.executorMock.On("remainderUnsigned", uint(3), uint(30), uint(31))
+	suite.factory.Produce(uint32(instruction)).Execute()
+	suite.executorMock.AssertCalled(suite.T(), "remainderUnsigned", uint(3), uint(30), uint(31))
+}
+
+func (suite *ExecutionFactorySuite) TestInstruction_A_StoreConditional() {
+	instruction := Parser.BuildInstructionA(uint(Parser.AMO), 5, uint(Producer.AtomicWord), 6, 7, uint(Producer.StoreConditional), true, true)
+	suite.executorMock.On("storeConditional", uint(5), uint(6), uint(7))
+	suite.factory.Produce(instruction).Execute()
+	suite.executorMock.AssertCalled(suite.T(), "storeConditional", uint(5), uint(6), uint(7))
+}
+
+func (suite *ExecutionFactorySuite) TestInstruction_A_InvalidWidth() {
+	instruction := Parser.BuildInstructionA(uint(Parser.AMO), 5, 3, 6, 7, uint(Producer.AmoAdd), false, false)
+	suite.Panics(func() { suite.factory.Produce(instruction).Execute() })
+}
+
+func (suite *ExecutionFactorySuite) TestInstruction_F_ConvertToUnsignedInt() {
+	instruction := Parser.BuildInstructionR(uint(Parser.OpFP), 5, 1, 6, uint(Producer.FCvtWordUnsigned), uint(Producer.FConvertToInt)<<2|1)
+	suite.executorMock.On("floatToUnsignedInt", uint(1), uint(5), uint(6), uint(1), uint(1))
+	suite.factory.Produce(instruction).Execute()
+	suite.executorMock.AssertCalled(suite.T(), "floatToUnsignedInt", uint(1), uint(5), uint(6), uint(1), uint(1))
+}
+
+func (suite *ExecutionFactorySuite) TestInstruction_F_NegatedMultiplySubtract() {
+	instruction := Parser.BuildInstructionR4(uint(Parser.FNMSub), 1, 7, 2, 3, 4, 0)
+	suite.executorMock.On("floatNegatedMultiplySubtract", uint(0), uint(1), uint(2), uint(3), uint(4), uint(7))
+	suite.factory.Produce(instruction).Execute()
+	suite.executorMock.AssertCalled(suite.T(), "floatNegatedMultiplySubtract", uint(0), uint(1), uint(2), uint(3), uint(4), uint(7))
+}
+
+func (suite *ExecutionFactorySuite) TestInstruction_F_LoadStore() {
+	load := Parser.BuildInstructionI(uint(Parser.LoadFP), 1, uint(Producer.LoadDouble), 2, 8)
+	suite.executorMock.On("loadDouble", uint(1), uint(2), uint32(8))
+	suite.factory.Produce(load).Execute()
+	suite.executorMock.AssertCalled(suite.T(), "loadDouble", uint(1), uint(2), uint32(8))
+
+	store := Parser.BuildInstructionS(uint(Parser.StoreFP), uint(Producer.StoreFloat), 2, 1, 8)
+	suite.executorMock.On("storeFloat", uint(2), uint(1), uint32(8))
+	suite.factory.Produce(store).Execute()
+	suite.executorMock.AssertCalled(suite.T(), "storeFloat", uint(2), uint(1), uint32(8))
+}
+
+func (suite *ExecutionFactorySuite) TestInstruction_F_InvalidOperation() {
+	instruction := Parser.BuildInstructionR(uint(Parser.OpFP), 1, 3, 2, 3, uint(Producer.FSignInject)<<2)
+	suite.Panics(func() { suite.factory.Produce(instruction).Execute() })
+}
+
 func (suite *ExecutionFactorySuite) TestInstruction_J_JAL() {
-	instruction := uint32(BuildInstructionJ(uint(Parser.JAL), 15, 46))
+	instruction := uint32(Parser.BuildInstructionJ(uint(Parser.JAL), 15, 46))
 	suite.executorMock.On("jumpAndLink", uint(15), uint32(46))
 	suite.factory.Produce(instruction).Execute()
 	suite.executorMock.AssertCalled(suite.T(), "jumpAndLink", uint(15), uint32(46))
 }
 
 func (suite *ExecutionFactorySuite) TestInstruction_B_BGE() {
-	instruction := uint32(BuildInstructionB(uint(Parser.Branch), uint(Producer.Bge), 2, 3, 103))
-	suite.executorMock.On("branchGreaterThanOrEqual", uint(2), uint(3), uint32(103))
+	instruction := uint32(Parser.BuildInstructionB(uint(Parser.Branch), uint(Producer.Bge), 2, 3, 104))
+	suite.executorMock.On("branchGreaterThanOrEqual", uint(2), uint(3), uint32(104))
 	suite.factory.Produce(instruction).Execute()
-	suite.executorMock.AssertCalled(suite.T(), "branchGreaterThanOrEqual", uint(2), uint(3), uint32(103))
+	suite.executorMock.AssertCalled(suite.T(), "branchGreaterThanOrEqual", uint(2), uint(3), uint32(104))
+}
+
+func (suite *ExecutionFactorySuite) TestInstruction_B_BackwardBranch() {
+	// bne x1, x2, -8 as encoded by a standard RISC-V assembler
+	offset := Utils.KeepBitsInInclusiveRange(uint32(0xFFFFFFF8), 0, 12)
+	suite.executorMock.On("branchNotEqual", uint(1), uint(2), offset)
+	suite.factory.Produce(0xFE209CE3).Execute()
+	suite.executorMock.AssertCalled(suite.T(), "branchNotEqual", uint(1), uint(2), offset)
+}
+
+func (suite *ExecutionFactorySuite) TestInstruction_I_Fence() {
+	// fence iorw, iorw
+	suite.executorMock.On("fence", uint(0), uint(0), uint32(0xFF))
+	suite.factory.Produce(0x0FF0000F).Execute()
+	suite.executorMock.AssertCalled(suite.T(), "fence", uint(0), uint(0), uint32(0xFF))
 }
 
 func (suite *ExecutionFactorySuite) TestInstruction_S_StoreByte() {
-	instruction := uint32(BuildInstructionS(uint(Parser.Store), uint(Producer.StoreByte), 12, 30, 1000))
+	instruction := uint32(Parser.BuildInstructionS(uint(Parser.Store), uint(Producer.StoreByte), 12, 30, 1000))
 	suite.executorMock.On("storeByte", uint(12), uint(30), uint32(1000))
 	suite.factory.Produce(instruction).Execute()
 	suite.executorMock.AssertCalled(suite.T(), "storeByte", uint(12), uint(30), uint32(1000))
@@ -0,0 +1,178 @@
+package floatingPoint
+
+import "math/big"
+
+/*Add returns `a` + `b` rounded with `mode`, and the exceptions that the addition raised*/
+func Add(f Format, a uint64, b uint64, mode RoundingMode) (uint64, Flags) {
+	return f.add(f.decode(a), f.decode(b), mode)
+}
+
+/*Subtract returns `a` - `b` rounded with `mode`, and the exceptions that the subtraction raised*/
+func Subtract(f Format, a uint64, b uint64, mode RoundingMode) (uint64, Flags) {
+	return f.add(f.decode(a), f.decode(f.Negate(b)), mode)
+}
+
+func (f Format) add(x value, y value, mode RoundingMode) (uint64, Flags) {
+	if x.nan || y.nan {
+		return f.nanResult(x, y)
+	}
+	if x.isInf() && y.isInf() && x.isNegative() != y.isNegative() {
+		return f.CanonicalNaN(), InvalidOperation
+	}
+	if x.isInf() {
+		return f.infinity(x.isNegative()), 0
+	}
+	if y.isInf() {
+		return f.infinity(y.isNegative()), 0
+	}
+
+	sum := f.workFloat().Add(x.number, y.number)
+	if sum.Sign() == 0 {
+		return f.zero(f.exactZeroIsNegative(x, y, mode)), 0
+	}
+	return f.round(toOdd(sum), mode)
+}
+
+/*exactZeroIsNegative returns whether a sum of `x` and `y` that is exactly zero is -0, which is
+only when both are -0, or when they cancel out and the sum is rounded down*/
+func (f Format) exactZeroIsNegative(x value, y value, mode RoundingMode) bool {
+	if x.isZero() && y.isZero() && x.isNegative() == y.isNegative() {
+		return x.isNegative()
+	}
+	return mode == RoundDown
+}
+
+/*Multiply returns `a` * `b` rounded with `mode`, and the exceptions that the multiplication raised*/
+func Multiply(f Format, a uint64, b uint64, mode RoundingMode) (uint64, Flags) {
+	x, y := f.decode(a), f.decode(b)
+	if x.nan || y.nan {
+		return f.nanResult(x, y)
+	}
+
+	negative := x.isNegative() != y.isNegative()
+	if (x.isInf() && y.isZero()) || (x.isZero() && y.isInf()) {
+		return f.CanonicalNaN(), InvalidOperation
+	}
+	if x.isInf() || y.isInf() {
+		return f.infinity(negative), 0
+	}
+	if x.isZero() || y.isZero() {
+		return f.zero(negative), 0
+	}
+
+	return f.round(toOdd(f.workFloat().Mul(x.number, y.number)), mode)
+}
+
+/*Divide returns `a` / `b` rounded with `mode`, and the exceptions that the division raised*/
+func Divide(f Format, a uint64, b uint64, mode RoundingMode) (uint64, Flags) {
+	x, y := f.decode(a), f.decode(b)
+	if x.nan || y.nan {
+		return f.nanResult(x, y)
+	}
+
+	negative := x.isNegative() != y.isNegative()
+	if (x.isInf() && y.isInf()) || (x.isZero() && y.isZero()) {
+		return f.CanonicalNaN(), InvalidOperation
+	}
+	if x.isInf() {
+		return f.infinity(negative), 0
+	}
+	if y.isZero() {
+		return f.infinity(negative), DivideByZero
+	}
+	if x.isZero() || y.isInf() {
+		return f.zero(negative), 0
+	}
+
+	return f.round(toOdd(f.workFloat().Quo(x.number, y.number)), mode)
+}
+
+/*SquareRoot returns the square root of `a` rounded with `mode`, and the exceptions that it raised.
+The square root of -0 is -0*/
+func SquareRoot(f Format, a uint64, mode RoundingMode) (uint64, Flags) {
+	x := f.decode(a)
+	if x.nan {
+		return f.nanResult(x, x)
+	}
+	if x.isZero() {
+		return a, 0
+	}
+	if x.isNegative() {
+		return f.CanonicalNaN(), InvalidOperation
+	}
+	if x.isInf() {
+		return a, 0
+	}
+
+	return f.round(f.squareRootToOdd(x.number), mode)
+}
+
+/*squareRootToOdd returns the square root of the positive, finite `number`, rounded to odd
+at the work precision. It is computed as the integer square root of a scaled significand*/
+func (f Format) squareRootToOdd(number *big.Float) *big.Float {
+	mantissa := new(big.Float)
+	exponent := number.MantExp(mantissa)
+
+	// number = significand * 2^scale, where the scale is even and the significand is wide
+	// enough for its square root to have all of the bits of the work precision
+	shift := 2 * int(f.workPrecision())
+	scale := exponent - shift
+	if scale%2 != 0 {
+		shift++
+		scale--
+	}
+	significand, _ := new(big.Float).SetMantExp(mantissa, shift).Int(nil)
+
+	root := new(big.Int).Sqrt(significand)
+	if new(big.Int).Mul(root, root).Cmp(significand) != 0 {
+		root.SetBit(root, 0, 1)
+	}
+	return new(big.Float).SetMantExp(new(big.Float).SetInt(root), scale/2)
+}
+
+/*FusedMultiplyAdd returns `a` * `b` + `c` with a single rounding with `mode`, and the exceptions
+that it raised. Multiplying infinity by zero is invalid even if `c` is a quiet NaN*/
+func FusedMultiplyAdd(f Format, a uint64, b uint64, c uint64, mode RoundingMode) (uint64, Flags) {
+	x, y, z := f.decode(a), f.decode(b), f.decode(c)
+	invalidProduct := (x.isInf() && y.isZero()) || (x.isZero() && y.isInf())
+	if x.nan || y.nan || z.nan {
+		_, flags := f.nanResult(x, y)
+		_, addendFlags := f.nanResult(z, z)
+		if invalidProduct {
+			flags |= InvalidOperation
+		}
+		return f.CanonicalNaN(), flags | addendFlags
+	}
+	if invalidProduct {
+		return f.CanonicalNaN(), InvalidOperation
+	}
+
+	productNegative := x.isNegative() != y.isNegative()
+	productInf := x.isInf() || y.isInf()
+	if productInf && z.isInf() && productNegative != z.isNegative() {
+		return f.CanonicalNaN(), InvalidOperation
+	}
+	if productInf {
+		return f.infinity(productNegative), 0
+	}
+	if z.isInf() {
+		return f.infinity(z.isNegative()), 0
+	}
+
+	// the product of two significands has at most twice their bits, so it is exact
+	product := new(big.Float).SetPrec(2 * f.precision).Mul(x.number, y.number)
+	sum := f.workFloat().Add(product, z.number)
+	if sum.Sign() == 0 {
+		return f.zero(f.exactZeroIsNegative(value{number: product}, z, mode)), 0
+	}
+	return f.round(toOdd(sum), mode)
+}
+
+/*nanResult returns the result of an operation on `x` and `y` where at least one is NaN: the
+canonical NaN, which is invalid if either is a signaling NaN*/
+func (f Format) nanResult(x value, y value) (uint64, Flags) {
+	if x.signaling || y.signaling {
+		return f.CanonicalNaN(), InvalidOperation
+	}
+	return f.CanonicalNaN(), 0
+}
@@ -0,0 +1,107 @@
+package floatingPoint
+
+import "math/bits"
+
+/*Min returns the smaller of `a` and `b`, where -0 is smaller than +0. If only one of them is NaN,
+the other is returned, and if both are, the canonical NaN is. Signaling NaNs are invalid*/
+func Min(f Format, a uint64, b uint64) (uint64, Flags) {
+	return f.minMax(a, b, false)
+}
+
+/*Max returns the larger of `a` and `b`, where +0 is larger than -0. NaNs are treated as they are by Min*/
+func Max(f Format, a uint64, b uint64) (uint64, Flags) {
+	return f.minMax(a, b, true)
+}
+
+func (f Format) minMax(a uint64, b uint64, max bool) (uint64, Flags) {
+	x, y := f.decode(a), f.decode(b)
+	_, flags := f.nanResult(x, y)
+	if x.nan && y.nan {
+		return f.CanonicalNaN(), flags
+	}
+	if x.nan {
+		return b, flags
+	}
+	if y.nan {
+		return a, flags
+	}
+
+	less := x.number.Cmp(y.number) < 0
+	if x.isZero() && y.isZero() {
+		less = x.isNegative() && !y.isNegative()
+	}
+	if less != max {
+		return a, flags
+	}
+	return b, flags
+}
+
+/*Equal returns whether `a` equals `b`. It is a quiet comparison: only signaling NaNs are invalid*/
+func Equal(f Format, a uint64, b uint64) (bool, Flags) {
+	x, y := f.decode(a), f.decode(b)
+	if x.nan || y.nan {
+		_, flags := f.nanResult(x, y)
+		return false, flags
+	}
+	return x.number.Cmp(y.number) == 0, 0
+}
+
+/*Less returns whether `a` is less than `b`. It is a signaling comparison: any NaN is invalid*/
+func Less(f Format, a uint64, b uint64) (bool, Flags) {
+	x, y := f.decode(a), f.decode(b)
+	if x.nan || y.nan {
+		return false, InvalidOperation
+	}
+	return x.number.Cmp(y.number) < 0, 0
+}
+
+/*LessOrEqual returns whether `a` is less than or equal to `b`. It is a signaling comparison: any NaN is invalid*/
+func LessOrEqual(f Format, a uint64, b uint64) (bool, Flags) {
+	x, y := f.decode(a), f.decode(b)
+	if x.nan || y.nan {
+		return false, InvalidOperation
+	}
+	return x.number.Cmp(y.number) <= 0, 0
+}
+
+/*These constants are the bits of the mask that Classify returns*/
+const (
+	NegativeInfinity  uint32 = 1 << 0
+	NegativeNormal    uint32 = 1 << 1
+	NegativeSubnormal uint32 = 1 << 2
+	NegativeZero      uint32 = 1 << 3
+	PositiveZero      uint32 = 1 << 4
+	PositiveSubnormal uint32 = 1 << 5
+	PositiveNormal    uint32 = 1 << 6
+	PositiveInfinity  uint32 = 1 << 7
+	SignalingNaN      uint32 = 1 << 8
+	QuietNaN          uint32 = 1 << 9
+)
+
+/*Classify returns a mask with the one bit set that describes the class of `a`*/
+func Classify(f Format, a uint64) uint32 {
+	x := f.decode(a)
+	exponent := (a >> (f.precision - 1)) & f.maxBiasedExponent()
+
+	class := uint32(0)
+	switch {
+	case x.nan && x.signaling:
+		return SignalingNaN
+	case x.nan:
+		return QuietNaN
+	case x.isInf():
+		class = PositiveInfinity
+	case x.isZero():
+		class = PositiveZero
+	case exponent == 0:
+		class = PositiveSubnormal
+	default:
+		class = PositiveNormal
+	}
+
+	if x.isNegative() {
+		// the negative classes mirror the positive ones around the zeroes
+		class = NegativeInfinity << (bits.TrailingZeros32(PositiveInfinity) - bits.TrailingZeros32(class))
+	}
+	return class
+}
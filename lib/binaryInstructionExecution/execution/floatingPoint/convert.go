@@ -0,0 +1,70 @@
+package floatingPoint
+
+import (
+	"math"
+	"math/big"
+)
+
+/*ToInt32 returns `a` rounded to an integer with `mode`, as a signed 32-bit integer. NaNs and values
+out of range are invalid, and give the largest integer, or the smallest for values below the range*/
+func ToInt32(f Format, a uint64, mode RoundingMode) (uint32, Flags) {
+	return f.toInteger(a, mode, big.NewInt(math.MinInt32), big.NewInt(math.MaxInt32))
+}
+
+/*ToUint32 returns `a` rounded to an integer with `mode`, as an unsigned 32-bit integer. NaNs and values
+out of range are treated as they are by ToInt32*/
+func ToUint32(f Format, a uint64, mode RoundingMode) (uint32, Flags) {
+	return f.toInteger(a, mode, big.NewInt(0), big.NewInt(math.MaxUint32))
+}
+
+func (f Format) toInteger(a uint64, mode RoundingMode, min *big.Int, max *big.Int) (uint32, Flags) {
+	x := f.decode(a)
+	if x.nan {
+		return uint32(max.Int64()), InvalidOperation
+	}
+	if x.isInf() {
+		if x.isNegative() {
+			return uint32(min.Int64()), InvalidOperation
+		}
+		return uint32(max.Int64()), InvalidOperation
+	}
+
+	magnitude := new(big.Float).Abs(x.number)
+	integer, inexact := roundToMultiple(magnitude, 0, mode, x.isNegative())
+	if x.isNegative() {
+		integer.Neg(integer)
+	}
+
+	if integer.Cmp(min) < 0 {
+		return uint32(min.Int64()), InvalidOperation
+	}
+	if integer.Cmp(max) > 0 {
+		return uint32(max.Int64()), InvalidOperation
+	}
+	if inexact {
+		return uint32(integer.Int64()), Inexact
+	}
+	return uint32(integer.Int64()), 0
+}
+
+/*FromInt32 returns the signed 32-bit integer `i` rounded to the format with `mode`*/
+func FromInt32(f Format, i uint32, mode RoundingMode) (uint64, Flags) {
+	return f.round(new(big.Float).SetInt64(int64(int32(i))), mode)
+}
+
+/*FromUint32 returns the unsigned 32-bit integer `i` rounded to the format with `mode`*/
+func FromUint32(f Format, i uint32, mode RoundingMode) (uint64, Flags) {
+	return f.round(new(big.Float).SetUint64(uint64(i)), mode)
+}
+
+/*Convert returns `a`, a value of the format `from`, rounded to the format `to` with `mode`*/
+func Convert(from Format, to Format, a uint64, mode RoundingMode) (uint64, Flags) {
+	x := from.decode(a)
+	if x.nan {
+		return to.nanResult(x, x)
+	}
+	if x.isInf() {
+		return to.infinity(x.isNegative()), 0
+	}
+	return to.round(x.number, mode)
+}
@@ -0,0 +1,305 @@
+package floatingPoint
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FloatingPointSuite struct {
+	suite.Suite
+}
+
+func TestFloatingPointSuite(t *testing.T) {
+	suite.Run(t, new(FloatingPointSuite))
+}
+
+func single(f float32) uint64 {
+	return uint64(math.Float32bits(f))
+}
+
+func double(f float64) uint64 {
+	return math.Float64bits(f)
+}
+
+/*randomSingle returns bits that are weighted towards the edges of the format: zeroes,
+subnormals, and values near overflow*/
+func randomSingle(random *rand.Rand) uint64 {
+	bits := random.Uint32()
+	switch random.Intn(4) {
+	case 0:
+		bits &= 0x807FFFFF
+	case 1:
+		bits |= 0x7F000000
+		bits &^= 0x00800000
+	}
+	return uint64(bits)
+}
+
+func randomDouble(random *rand.Rand) uint64 {
+	bits := random.Uint64()
+	switch random.Intn(4) {
+	case 0:
+		bits &= 0x800FFFFFFFFFFFFF
+	case 1:
+		bits |= 0x7FE0000000000000
+		bits &^= 0x0010000000000000
+	}
+	return bits
+}
+
+/*nativeResult canonicalizes the NaNs that Go produces, which keep the payload of an operand*/
+func nativeResult(f Format, bits uint64) uint64 {
+	if f.decode(bits).nan {
+		return f.CanonicalNaN()
+	}
+	return bits
+}
+
+func (suite *FloatingPointSuite) TestRoundToNearestEven_MatchesNative() {
+	assert := assert.New(suite.T())
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		a, b := randomSingle(random), randomSingle(random)
+		x, y := math.Float32frombits(uint32(a)), math.Float32frombits(uint32(b))
+
+		sum, _ := Add(Single, a, b, RoundToNearestEven)
+		assert.Equal(nativeResult(Single, single(x+y)), sum, "%08X + %08X", a, b)
+		difference, _ := Subtract(Single, a, b, RoundToNearestEven)
+		assert.Equal(nativeResult(Single, single(x-y)), difference, "%08X - %08X", a, b)
+		product, _ := Multiply(Single, a, b, RoundToNearestEven)
+		assert.Equal(nativeResult(Single, single(x*y)), product, "%08X * %08X", a, b)
+		quotient, _ := Divide(Single, a, b, RoundToNearestEven)
+		assert.Equal(nativeResult(Single, single(x/y)), quotient, "%08X / %08X", a, b)
+		root, _ := SquareRoot(Single, a, RoundToNearestEven)
+		assert.Equal(nativeResult(Single, single(float32(math.Sqrt(float64(x))))), root, "sqrt %08X", a)
+		widened, _ := Convert(Single, Double, a, RoundToNearestEven)
+		assert.Equal(nativeResult(Double, double(float64(x))), widened, "widen %08X", a)
+	}
+
+	for i := 0; i < 20000; i++ {
+		a, b, c := randomDouble(random), randomDouble(random), randomDouble(random)
+		x, y, z := math.Float64frombits(a), math.Float64frombits(b), math.Float64frombits(c)
+
+		sum, _ := Add(Double, a, b, RoundToNearestEven)
+		assert.Equal(nativeResult(Double, double(x+y)), sum, "%016X + %016X", a, b)
+		product, _ := Multiply(Double, a, b, RoundToNearestEven)
+		assert.Equal(nativeResult(Double, double(x*y)), product, "%016X * %016X", a, b)
+		quotient, _ := Divide(Double, a, b, RoundToNearestEven)
+		assert.Equal(nativeResult(Double, double(x/y)), quotient, "%016X / %016X", a, b)
+		root, _ := SquareRoot(Double, a, RoundToNearestEven)
+		assert.Equal(nativeResult(Double, double(math.Sqrt(x))), root, "sqrt %016X", a)
+		fused, _ := FusedMultiplyAdd(Double, a, b, c, RoundToNearestEven)
+		assert.Equal(nativeResult(Double, double(math.FMA(x, y, z))), fused, "%016X * %016X + %016X", a, b, c)
+		narrowed, _ := Convert(Double, Single, a, RoundToNearestEven)
+		assert.Equal(nativeResult(Single, single(float32(x))), narrowed, "narrow %016X", a)
+	}
+}
+
+func (suite *FloatingPointSuite) TestRoundingModes() {
+	assert := assert.New(suite.T())
+	// 1/3 rounds up to the nearest single
+	third := single(1.0 / 3)
+
+	results := map[RoundingMode]uint64{}
+	for _, mode := range []RoundingMode{RoundToNearestEven, RoundTowardsZero, RoundDown, RoundUp, RoundToNearestMaxMagnitude} {
+		result, flags := Divide(Single, single(1), single(3), mode)
+		assert.Equal(Inexact, flags)
+		results[mode] = result
+	}
+	assert.Equal(third, results[RoundToNearestEven])
+	assert.Equal(third-1, results[RoundTowardsZero])
+	assert.Equal(third-1, results[RoundDown])
+	assert.Equal(third, results[RoundUp])
+	assert.Equal(third, results[RoundToNearestMaxMagnitude])
+
+	negative, _ := Divide(Single, single(-1), single(3), RoundDown)
+	assert.Equal(Single.Negate(third), negative)
+	negative, _ = Divide(Single, single(-1), single(3), RoundUp)
+	assert.Equal(Single.Negate(third-1), negative)
+
+	// 2^24 + 1 is halfway between two singles
+	even, _ := FromUint32(Single, 1<<24+1, RoundToNearestEven)
+	assert.Equal(single(1<<24), even)
+	away, _ := FromUint32(Single, 1<<24+1, RoundToNearestMaxMagnitude)
+	assert.Equal(single(1<<24+2), away)
+}
+
+func (suite *FloatingPointSuite) TestExactZeroes() {
+	assert := assert.New(suite.T())
+
+	result, flags := Subtract(Single, single(1), single(1), RoundToNearestEven)
+	assert.Equal(single(0), result)
+	assert.Equal(Flags(0), flags)
+	result, _ = Subtract(Single, single(1), single(1), RoundDown)
+	assert.Equal(Single.Negate(single(0)), result)
+	result, _ = Add(Double, Double.Negate(0), Double.Negate(0), RoundUp)
+	assert.Equal(Double.Negate(0), result)
+	result, _ = FusedMultiplyAdd(Single, single(2), single(-1), single(2), RoundDown)
+	assert.Equal(Single.Negate(single(0)), result)
+}
+
+func (suite *FloatingPointSuite) TestOverflowAndUnderflow() {
+	assert := assert.New(suite.T())
+	max := single(math.MaxFloat32)
+
+	result, flags := Multiply(Single, max, single(2), RoundToNearestEven)
+	assert.Equal(single(float32(math.Inf(1))), result)
+	assert.Equal(Overflow|Inexact, flags)
+	result, flags = Multiply(Single, max, single(2), RoundTowardsZero)
+	assert.Equal(max, result)
+	assert.Equal(Overflow|Inexact, flags)
+	result, _ = Multiply(Single, max, single(-2), RoundUp)
+	assert.Equal(Single.Negate(max), result)
+
+	smallest := single(math.SmallestNonzeroFloat32)
+	result, flags = Multiply(Single, smallest, single(0.5), RoundToNearestEven)
+	assert.Equal(single(0), result)
+	assert.Equal(Underflow|Inexact, flags)
+	result, flags = Multiply(Single, smallest, single(0.5), RoundUp)
+	assert.Equal(smallest, result)
+	assert.Equal(Underflow|Inexact, flags)
+
+	// an exact subnormal result does not underflow
+	result, flags = Multiply(Single, smallest, single(2), RoundToNearestEven)
+	assert.Equal(uint64(2), result)
+	assert.Equal(Flags(0), flags)
+
+	// tininess is detected after rounding: this product rounds up to the smallest normal value
+	below := uint64(0x007FFFFF)
+	result, flags = Multiply(Single, below, single(1+1.0/(1<<23)), RoundToNearestEven)
+	assert.Equal(uint64(0x00800000), result)
+	assert.Equal(Inexact, flags)
+}
+
+func (suite *FloatingPointSuite) TestInvalidOperations() {
+	assert := assert.New(suite.T())
+	inf := single(float32(math.Inf(1)))
+	signaling := uint64(0x7F800001)
+	quiet := uint64(0x7FC12345)
+
+	cases := []struct {
+		result uint64
+		flags  Flags
+	}{}
+	add := func(result uint64, flags Flags) {
+		cases = append(cases, struct {
+			result uint64
+			flags  Flags
+		}{result, flags})
+	}
+
+	add(Subtract(Single, inf, inf, RoundToNearestEven))
+	add(Multiply(Single, inf, 0, RoundToNearestEven))
+	add(Divide(Single, 0, 0, RoundToNearestEven))
+	add(Divide(Single, inf, inf, RoundToNearestEven))
+	add(SquareRoot(Single, single(-1), RoundToNearestEven))
+	add(Add(Single, signaling, single(1), RoundToNearestEven))
+	add(FusedMultiplyAdd(Single, inf, 0, quiet, RoundToNearestEven))
+	for _, c := range cases {
+		assert.Equal(Single.CanonicalNaN(), c.result)
+		assert.Equal(InvalidOperation, c.flags)
+	}
+
+	result, flags := Add(Single, quiet, single(1), RoundToNearestEven)
+	assert.Equal(Single.CanonicalNaN(), result)
+	assert.Equal(Flags(0), flags)
+
+	result, flags = Divide(Single, single(-1), 0, RoundToNearestEven)
+	assert.Equal(Single.Negate(inf), result)
+	assert.Equal(DivideByZero, flags)
+}
+
+func (suite *FloatingPointSuite) TestMinMax() {
+	assert := assert.New(suite.T())
+	negativeZero := Single.Negate(0)
+
+	result, _ := Min(Single, 0, negativeZero)
+	assert.Equal(negativeZero, result)
+	result, _ = Max(Single, negativeZero, 0)
+	assert.Equal(uint64(0), result)
+
+	result, flags := Min(Single, Single.CanonicalNaN(), single(2))
+	assert.Equal(single(2), result)
+	assert.Equal(Flags(0), flags)
+	result, flags = Max(Single, single(2), 0x7F800001)
+	assert.Equal(single(2), result)
+	assert.Equal(InvalidOperation, flags)
+	result, _ = Max(Single, 0x7F800001, 0x7FC00001)
+	assert.Equal(Single.CanonicalNaN(), result)
+}
+
+func (suite *FloatingPointSuite) TestComparisons() {
+	assert := assert.New(suite.T())
+	quiet := Double.CanonicalNaN()
+
+	equal, flags := Equal(Double, double(1), quiet)
+	assert.False(equal)
+	assert.Equal(Flags(0), flags)
+	_, flags = Equal(Double, double(1), 0x7FF0000000000001)
+	assert.Equal(InvalidOperation, flags)
+	less, flags := Less(Double, double(1), quiet)
+	assert.False(less)
+	assert.Equal(InvalidOperation, flags)
+
+	equal, _ = Equal(Double, 0, Double.Negate(0))
+	assert.True(equal)
+	less, _ = Less(Double, Double.Negate(0), 0)
+	assert.False(less)
+	lessOrEqual, _ := LessOrEqual(Double, double(-2), double(1))
+	assert.True(lessOrEqual)
+}
+
+func (suite *FloatingPointSuite) TestClassify() {
+	assert := assert.New(suite.T())
+
+	assert.Equal(NegativeInfinity, Classify(Single, single(float32(math.Inf(-1)))))
+	assert.Equal(NegativeNormal, Classify(Single, single(-1)))
+	assert.Equal(NegativeSubnormal, Classify(Single, Single.Negate(1)))
+	assert.Equal(NegativeZero, Classify(Single, Single.Negate(0)))
+	assert.Equal(PositiveZero, Classify(Single, 0))
+	assert.Equal(PositiveSubnormal, Classify(Double, 1))
+	assert.Equal(PositiveNormal, Classify(Double, double(1)))
+	assert.Equal(PositiveInfinity, Classify(Double, double(math.Inf(1))))
+	assert.Equal(SignalingNaN, Classify(Double, 0x7FF0000000000001))
+	assert.Equal(QuietNaN, Classify(Double, Double.CanonicalNaN()))
+}
+
+func (suite *FloatingPointSuite) TestIntegerConversions() {
+	assert := assert.New(suite.T())
+
+	result, flags := ToInt32(Single, single(-2.5), RoundToNearestEven)
+	assert.Equal(uint32(0xFFFFFFFE), result)
+	assert.Equal(Inexact, flags)
+	result, _ = ToInt32(Single, single(-2.5), RoundToNearestMaxMagnitude)
+	assert.Equal(uint32(0xFFFFFFFD), result)
+	result, flags = ToInt32(Double, double(1<<31), RoundToNearestEven)
+	assert.Equal(uint32(math.MaxInt32), result)
+	assert.Equal(InvalidOperation, flags)
+	result, flags = ToInt32(Double, double(-(1 << 31)), RoundToNearestEven)
+	assert.Equal(uint32(0x80000000), result)
+	assert.Equal(Flags(0), flags)
+	result, flags = ToInt32(Single, Single.CanonicalNaN(), RoundToNearestEven)
+	assert.Equal(uint32(math.MaxInt32), result)
+	assert.Equal(InvalidOperation, flags)
+
+	result, flags = ToUint32(Single, single(-0.5), RoundToNearestEven)
+	assert.Equal(uint32(0), result)
+	assert.Equal(Inexact, flags)
+	result, flags = ToUint32(Single, single(-1), RoundToNearestEven)
+	assert.Equal(uint32(0), result)
+	assert.Equal(InvalidOperation, flags)
+	result, _ = ToUint32(Double, double(math.MaxUint32), RoundTowardsZero)
+	assert.Equal(uint32(math.MaxUint32), result)
+
+	converted, flags := FromInt32(Double, 0xFFFFFFFF, RoundToNearestEven)
+	assert.Equal(double(-1), converted)
+	assert.Equal(Flags(0), flags)
+	converted, flags = FromUint32(Single, 0xFFFFFFFF, RoundTowardsZero)
+	assert.Equal(single(0xFFFFFF00), converted)
+	assert.Equal(Inexact, flags)
+}
@@ -0,0 +1,282 @@
+package floatingPoint
+
+import "math/big"
+
+/*Format describes a binary IEEE-754 format by the precision of its significand, which includes
+the implicit leading bit, and the width of its exponent. Values of a Format are passed around
+as their encoding, in the lowest bits of a uint64*/
+type Format struct {
+	precision    uint
+	exponentBits uint
+}
+
+/*These are the formats of the F and D extensions*/
+var (
+	Single = Format{precision: 24, exponentBits: 8}
+	Double = Format{precision: 53, exponentBits: 11}
+)
+
+/*RoundingMode is a rounding mode of IEEE-754. Its values are those of the rm field of
+an instruction and of the frm CSR*/
+type RoundingMode uint
+
+/*These constants are the rounding modes of RISC-V. Dynamic is only valid in the rm field of
+an instruction, where it selects the rounding mode in frm*/
+const (
+	RoundToNearestEven         RoundingMode = 0
+	RoundTowardsZero           RoundingMode = 1
+	RoundDown                  RoundingMode = 2
+	RoundUp                    RoundingMode = 3
+	RoundToNearestMaxMagnitude RoundingMode = 4
+	Dynamic                    RoundingMode = 7
+)
+
+/*IsValid returns whether `mode` is a rounding mode that a result can be rounded with*/
+func (mode RoundingMode) IsValid() bool {
+	return mode <= RoundToNearestMaxMagnitude
+}
+
+/*Flags are the IEEE-754 exception flags, as they are accrued in the fflags CSR*/
+type Flags uint32
+
+/*These constants are the exception flags, in the order of their bits in fflags*/
+const (
+	Inexact Flags = 1 << iota
+	Underflow
+	Overflow
+	DivideByZero
+	InvalidOperation
+)
+
+func (f Format) width() uint {
+	return f.precision + f.exponentBits
+}
+
+func (f Format) bias() int {
+	return 1<<(f.exponentBits-1) - 1
+}
+
+/*minExponent is the exponent of the smallest normal value*/
+func (f Format) minExponent() int {
+	return 1 - f.bias()
+}
+
+/*maxBiasedExponent is the biased exponent of infinities and NaNs*/
+func (f Format) maxBiasedExponent() uint64 {
+	return 1<<f.exponentBits - 1
+}
+
+func (f Format) fractionMask() uint64 {
+	return 1<<(f.precision-1) - 1
+}
+
+func (f Format) signBit() uint64 {
+	return 1 << (f.width() - 1)
+}
+
+func (f Format) quietBit() uint64 {
+	return 1 << (f.precision - 2)
+}
+
+/*workPrecision is the precision that intermediate results are rounded to odd at. As it is more than
+2 bits wider than the format, rounding the intermediate result again gives the correctly rounded result*/
+func (f Format) workPrecision() uint {
+	return 2*f.precision + 3
+}
+
+/*CanonicalNaN returns the quiet NaN that every operation returns when its result is NaN*/
+func (f Format) CanonicalNaN() uint64 {
+	return f.maxBiasedExponent()<<(f.precision-1) | f.quietBit()
+}
+
+/*Negate returns `bits` with its sign flipped*/
+func (f Format) Negate(bits uint64) uint64 {
+	return bits ^ f.signBit()
+}
+
+/*IsNegative returns whether the sign bit of `bits` is set*/
+func (f Format) IsNegative(bits uint64) bool {
+	return bits&f.signBit() != 0
+}
+
+/*WithSign returns `bits` with the sign bit set if `negative`, and cleared otherwise*/
+func (f Format) WithSign(bits uint64, negative bool) uint64 {
+	bits &^= f.signBit()
+	if negative {
+		bits |= f.signBit()
+	}
+	return bits
+}
+
+func (f Format) zero(negative bool) uint64 {
+	return f.WithSign(0, negative)
+}
+
+func (f Format) infinity(negative bool) uint64 {
+	return f.WithSign(f.maxBiasedExponent()<<(f.precision-1), negative)
+}
+
+func (f Format) maxFinite(negative bool) uint64 {
+	return f.WithSign(f.infinity(false)-1, negative)
+}
+
+/*value is a decoded floating-point value. `number` holds zeroes, infinities and finite values,
+all with their sign, and is nil for NaNs*/
+type value struct {
+	number    *big.Float
+	nan       bool
+	signaling bool
+}
+
+func (f Format) decode(bits uint64) value {
+	negative := f.IsNegative(bits)
+	exponent := (bits >> (f.precision - 1)) & f.maxBiasedExponent()
+	fraction := bits & f.fractionMask()
+
+	if exponent == f.maxBiasedExponent() {
+		if fraction != 0 {
+			return value{nan: true, signaling: fraction&f.quietBit() == 0}
+		}
+		return value{number: new(big.Float).SetInf(negative)}
+	}
+
+	significand := fraction
+	scale := f.minExponent() - int(f.precision-1)
+	if exponent != 0 {
+		significand |= 1 << (f.precision - 1)
+		scale = int(exponent) - f.bias() - int(f.precision-1)
+	}
+
+	number := new(big.Float).SetMantExp(new(big.Float).SetUint64(significand), scale)
+	if negative {
+		number.Neg(number)
+	}
+	return value{number: number}
+}
+
+func (v value) isZero() bool {
+	return !v.nan && v.number.Sign() == 0
+}
+
+func (v value) isInf() bool {
+	return !v.nan && v.number.IsInf()
+}
+
+func (v value) isNegative() bool {
+	return !v.nan && v.number.Signbit()
+}
+
+/*round rounds the finite value `number` to the format with `mode`. `number` must either be exact, or have
+been rounded to odd at the work precision*/
+func (f Format) round(number *big.Float, mode RoundingMode) (uint64, Flags) {
+	negative := number.Signbit()
+	if number.Sign() == 0 {
+		return f.zero(negative), 0
+	}
+
+	magnitude := new(big.Float).Abs(number)
+	exponent := magnitude.MantExp(nil) - 1
+	precision := int(f.precision)
+	quantum := exponent
+	if quantum < f.minExponent() {
+		quantum = f.minExponent()
+	}
+	quantum -= precision - 1
+
+	significand, inexact := roundToMultiple(magnitude, quantum, mode, negative)
+	flags := Flags(0)
+	if inexact {
+		flags |= Inexact
+
+		// RISC-V detects tininess after rounding: the result is tiny if rounding it with an unbounded
+		// exponent range would still give a magnitude below the smallest normal value
+		tiny := exponent < f.minExponent()
+		if exponent == f.minExponent()-1 {
+			unbounded, _ := roundToMultiple(magnitude, exponent-(precision-1), mode, negative)
+			tiny = unbounded.BitLen() <= precision
+		}
+		if tiny {
+			flags |= Underflow
+		}
+	}
+
+	if significand.BitLen() > precision {
+		significand.Rsh(significand, 1)
+		quantum++
+	}
+
+	biasedExponent := uint64(0)
+	if significand.BitLen() == precision {
+		biasedExponent = uint64(quantum + precision - 1 + f.bias())
+	}
+	if biasedExponent >= f.maxBiasedExponent() {
+		return f.overflow(negative, mode), flags | Overflow | Inexact
+	}
+
+	bits := biasedExponent<<(f.precision-1) | significand.Uint64()&f.fractionMask()
+	return f.WithSign(bits, negative), flags
+}
+
+/*overflow returns the result of a value that is too large for the format: infinity, or the largest
+finite value if `mode` rounds towards zero for the sign of the value*/
+func (f Format) overflow(negative bool, mode RoundingMode) uint64 {
+	towardsZero := mode == RoundTowardsZero || (mode == RoundDown && !negative) || (mode == RoundUp && negative)
+	if towardsZero {
+		return f.maxFinite(negative)
+	}
+	return f.infinity(negative)
+}
+
+/*roundToMultiple rounds the positive `magnitude` to an integer multiple of 2^`quantum` with `mode`,
+where `negative` is the sign of the value that `magnitude` belongs to. It returns the multiple, and
+whether rounding changed the value*/
+func roundToMultiple(magnitude *big.Float, quantum int, mode RoundingMode, negative bool) (*big.Int, bool) {
+	scaled := new(big.Float).SetMantExp(magnitude, -quantum)
+	multiple, _ := scaled.Int(nil)
+	remainder := new(big.Float).Sub(scaled, new(big.Float).SetInt(multiple))
+	inexact := remainder.Sign() != 0
+	half := remainder.Cmp(big.NewFloat(0.5))
+
+	roundUp := false
+	switch mode {
+	case RoundToNearestEven:
+		roundUp = half > 0 || (half == 0 && multiple.Bit(0) == 1)
+	case RoundToNearestMaxMagnitude:
+		roundUp = half >= 0
+	case RoundDown:
+		roundUp = inexact && negative
+	case RoundUp:
+		roundUp = inexact && !negative
+	}
+
+	if roundUp {
+		multiple.Add(multiple, big.NewInt(1))
+	}
+	return multiple, inexact
+}
+
+/*toOdd rounds `number`, which was computed by rounding towards zero, to odd: if it is inexact, the lowest
+bit of its significand is set. This keeps a record of the lost bits that a later rounding can use*/
+func toOdd(number *big.Float) *big.Float {
+	if number.Acc() == big.Exact || number.Sign() == 0 || number.IsInf() {
+		return number
+	}
+
+	mantissa := new(big.Float)
+	exponent := number.MantExp(mantissa)
+	precision := int(number.Prec())
+	significand, _ := new(big.Float).SetMantExp(mantissa, precision).Int(nil)
+	negative := significand.Sign() < 0
+	significand.Abs(significand)
+	significand.SetBit(significand, 0, 1)
+	if negative {
+		significand.Neg(significand)
+	}
+
+	return new(big.Float).SetMantExp(new(big.Float).SetInt(significand), exponent-precision)
+}
+
+/*workFloat returns a big.Float that rounds towards zero at the work precision of `f`*/
+func (f Format) workFloat() *big.Float {
+	return new(big.Float).SetPrec(f.workPrecision()).SetMode(big.ToZero)
+}
@@ -0,0 +1,188 @@
+package execution
+
+/*AdaptedInstructionManager is an adapter for a program counter manager with exported, 32-bit methods
+(such as instructionManagers.PCInstructionManager32), to help it fit the `instructionManager`
+interface that the RiscVInstructionExecutor requires.
+*/
+type AdaptedInstructionManager struct {
+	manager pcManager
+}
+
+type pcManager interface {
+	GetCurrentInstructionAddress() uint32
+	GetNextInstructionAddress() uint32
+	AddOffsetForNextAddress(offset uint32)
+	LoadInstructionAddressForNextAddress(newAddress uint32)
+}
+
+/*MakeAdaptedInstructionManager is a constructor for AdaptedInstructionManager*/
+func MakeAdaptedInstructionManager(manager pcManager) AdaptedInstructionManager {
+	adapted := AdaptedInstructionManager{
+		manager: manager,
+	}
+
+	return adapted
+}
+
+func (m *AdaptedInstructionManager) getCurrentInstructionAddress() uint32 {
+	return m.manager.GetCurrentInstructionAddress()
+}
+
+func (m *AdaptedInstructionManager) getNextInstructionAddress() uint32 {
+	return m.manager.GetNextInstructionAddress()
+}
+
+/*addOffsetForNextInstructionAddress adds `offset` to the address of the current instruction. Since the addition
+wraps around, negative (two's complement) offsets move the PC backwards*/
+func (m *AdaptedInstructionManager) addOffsetForNextInstructionAddress(offset uint32) {
+	m.manager.AddOffsetForNextAddress(offset)
+}
+
+func (m *AdaptedInstructionManager) loadAsNextInstructionAddress(newAddress uint32) {
+	m.manager.LoadInstructionAddressForNextAddress(newAddress)
+}
+
+/*AdaptedCsrOperator is an adapter for a CSR manager with exported methods (such as csrManagers.MachineCsrFile),
+to help it fit the `csrOperator` interface that the RiscVInstructionExecutor requires.
+*/
+type AdaptedCsrOperator struct {
+	manager csrManager
+}
+
+type csrManager interface {
+	Get(register uint) uint32
+	Set(register uint, val uint32)
+}
+
+/*MakeAdaptedCsrOperator is a constructor for AdaptedCsrOperator*/
+func MakeAdaptedCsrOperator(manager csrManager) AdaptedCsrOperator {
+	adapted := AdaptedCsrOperator{
+		manager: manager,
+	}
+
+	return adapted
+}
+
+func (op *AdaptedCsrOperator) get(reg uint) uint32 {
+	return op.manager.Get(reg)
+}
+
+func (op *AdaptedCsrOperator) set(reg uint, val uint32) {
+	op.manager.Set(reg, val)
+}
+
+/*AdaptedExecutionEnvManager is an adapter for an execution environment manager with exported methods
+(such as envManagers.NoOpExecManager), to help it fit the `executionEnvManager` interface.
+*/
+type AdaptedExecutionEnvManager struct {
+	manager execManager
+}
+
+type execManager interface {
+	ExecuteCall()
+}
+
+/*MakeAdaptedExecutionEnvManager is a constructor for AdaptedExecutionEnvManager*/
+func MakeAdaptedExecutionEnvManager(manager execManager) AdaptedExecutionEnvManager {
+	adapted := AdaptedExecutionEnvManager{
+		manager: manager,
+	}
+
+	return adapted
+}
+
+func (m *AdaptedExecutionEnvManager) executeCall() {
+	m.manager.ExecuteCall()
+}
+
+/*AdaptedDebugEnvManager is an adapter for a debugging environment manager with exported methods
+(such as envManagers.NoOpDebugManager), to help it fit the `debugEnvManager` interface.
+*/
+type AdaptedDebugEnvManager struct {
+	manager debugManager
+}
+
+type debugManager interface {
+	DebugBreak()
+}
+
+/*MakeAdaptedDebugEnvManager is a constructor for AdaptedDebugEnvManager*/
+func MakeAdaptedDebugEnvManager(manager debugManager) AdaptedDebugEnvManager {
+	adapted := AdaptedDebugEnvManager{
+		manager: manager,
+	}
+
+	return adapted
+}
+
+func (m *AdaptedDebugEnvManager) debugBreak() {
+	m.manager.DebugBreak()
+}
+
+/*AdaptedTrapEnvManager is an adapter for a trap manager with exported methods
+(such as csrManagers.MachineCsrFile), to help it fit the `trapEnvManager` interface.
+*/
+type AdaptedTrapEnvManager struct {
+	manager trapManager
+}
+
+type trapManager interface {
+	ReturnFromTrap() uint32
+	ReturnFromSupervisorTrap() uint32
+	WaitForInterrupt()
+}
+
+/*MakeAdaptedTrapEnvManager is a constructor for AdaptedTrapEnvManager*/
+func MakeAdaptedTrapEnvManager(manager trapManager) AdaptedTrapEnvManager {
+	adapted := AdaptedTrapEnvManager{
+		manager: manager,
+	}
+
+	return adapted
+}
+
+func (m *AdaptedTrapEnvManager) returnFromTrap() uint32 {
+	return m.manager.ReturnFromTrap()
+}
+
+func (m *AdaptedTrapEnvManager) returnFromSupervisorTrap() uint32 {
+	return m.manager.ReturnFromSupervisorTrap()
+}
+
+func (m *AdaptedTrapEnvManager) waitForInterrupt() {
+	m.manager.WaitForInterrupt()
+}
+
+/*AdaptedAddressTranslator is an adapter for an MMU with exported methods (such as virtualMemory.Sv32Mmu),
+to help it fit the `addressTranslator` interface that the RiscVInstructionExecutor requires.
+*/
+type AdaptedAddressTranslator struct {
+	mmu mmu
+}
+
+type mmu interface {
+	TranslateLoad(address uint32, size uint32) uint32
+	TranslateStore(address uint32, size uint32) uint32
+	Fence(address uint32, asid uint32, allAddresses bool, allAsids bool)
+}
+
+/*MakeAdaptedAddressTranslator is a constructor for AdaptedAddressTranslator*/
+func MakeAdaptedAddressTranslator(mmu mmu) AdaptedAddressTranslator {
+	adapted := AdaptedAddressTranslator{
+		mmu: mmu,
+	}
+
+	return adapted
+}
+
+func (t *AdaptedAddressTranslator) translateLoad(address uint32, size uint32) uint32 {
+	return t.mmu.TranslateLoad(address, size)
+}
+
+func (t *AdaptedAddressTranslator) translateStore(address uint32, size uint32) uint32 {
+	return t.mmu.TranslateStore(address, size)
+}
+
+func (t *AdaptedAddressTranslator) fence(address uint32, asid uint32, allAddresses bool, allAsids bool) {
+	t.mmu.Fence(address, asid, allAddresses, allAsids)
+}
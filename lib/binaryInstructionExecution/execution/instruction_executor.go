@@ -4,6 +4,8 @@ import (
 	"math"
 
 	Utils "github.com/chenhowa/computer/lib/binaryInstructionExecution/bitUtils"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
 )
 
 /*The RiscVInstructionExecutor is responsible for taking the operands of
@@ -11,7 +13,59 @@ a binary instruction and turning them into MESSAGES to the Operator and Instruct
 executing the instruction using an internal object.
 */
 type RiscVInstructionExecutor struct {
-	operator instructionOperator
+	operator   instructionOperator
+	translator addressTranslator
+
+	// the reservation that LoadReserved registers, and that StoreConditional needs to succeed
+	reserved        bool
+	reservedAddress uint32
+
+	// the floating-point control and status register, which holds fflags and frm
+	fcsr uint32
+
+	// what misaligned loads and stores do, and how many of them there have been
+	misalignedPolicy MisalignedAccessPolicy
+	misaligned       MisalignedAccessStatistics
+}
+
+/*MakeRiscVInstructionExecutor is a constructor for RiscVInstructionExecutor, whose
+32 registers start with the values in `registers`. Loads and stores use their addresses
+as they are, until an address translator is given with UseAddressTranslator*/
+func MakeRiscVInstructionExecutor(registers [32]uint32) RiscVInstructionExecutor {
+	operator := makeAdaptedOperator(registers)
+	executor := RiscVInstructionExecutor{
+		operator:   &operator,
+		translator: untranslatedAddresses{},
+	}
+
+	return executor
+}
+
+/*UseAddressTranslator makes loads and stores send the addresses that `translator` translates
+their addresses to, rather than their addresses themselves, to memory*/
+func (ex *RiscVInstructionExecutor) UseAddressTranslator(translator addressTranslator) {
+	ex.translator = translator
+}
+
+type addressTranslator interface {
+	translateLoad(address uint32, size uint32) uint32
+	translateStore(address uint32, size uint32) uint32
+	fence(address uint32, asid uint32, allAddresses bool, allAsids bool)
+}
+
+/*untranslatedAddresses is the address translator of an executor without an MMU,
+which leaves addresses as they are*/
+type untranslatedAddresses struct{}
+
+func (untranslatedAddresses) translateLoad(address uint32, size uint32) uint32 {
+	return address
+}
+
+func (untranslatedAddresses) translateStore(address uint32, size uint32) uint32 {
+	return address
+}
+
+func (untranslatedAddresses) fence(address uint32, asid uint32, allAddresses bool, allAsids bool) {
 }
 
 type executionEnvManager interface {
@@ -22,6 +76,12 @@ type debugEnvManager interface {
 	debugBreak()
 }
 
+type trapEnvManager interface {
+	returnFromTrap() uint32
+	returnFromSupervisorTrap() uint32
+	waitForInterrupt()
+}
+
 type csrOperator interface {
 	get(reg uint) uint32
 	set(reg uint, val uint32)
@@ -35,14 +95,14 @@ type instructionManager interface {
 }
 
 type instructionOperator interface {
-	loadWord(dest uint, address uint32, memory instructionReadMemory)
-	loadHalfWord(dest uint, address uint32, memory instructionReadMemory)
-	loadHalfWordUnsigned(dest uint, address uint32, memory instructionReadMemory)
-	loadByte(dest uint, address uint32, memory instructionReadMemory)
-	loadByteUnsigned(dest uint, address uint32, memory instructionReadMemory)
-	storeWord(src uint, address uint32, memory instructionWriteMemory)
-	storeHalfWord(src uint, address uint32, memory instructionWriteMemory)
-	storeByte(src uint, address uint32, memory instructionWriteMemory)
+	loadWord(dest uint, address uint32, memory Memory.Reader)
+	loadHalfWord(dest uint, address uint32, memory Memory.Reader)
+	loadHalfWordUnsigned(dest uint, address uint32, memory Memory.Reader)
+	loadByte(dest uint, address uint32, memory Memory.Reader)
+	loadByteUnsigned(dest uint, address uint32, memory Memory.Reader)
+	storeWord(src uint, address uint32, memory Memory.Writer)
+	storeHalfWord(src uint, address uint32, memory Memory.Writer)
+	storeByte(src uint, address uint32, memory Memory.Writer)
 	add(dest uint, reg1 uint, reg2 uint)
 	addImmediate(dest uint, reg uint, immediate uint32)
 	sub(dest uint, reg1 uint, reg2 uint)
@@ -54,20 +114,15 @@ type instructionOperator interface {
 	xorImmediate(dest uint, reg uint, immediate uint32)
 	leftShiftImmediate(dest uint, reg uint, immediate uint32)
 	rightShiftImmediate(dest uint, reg uint, immediate uint32, preserveSign bool)
+	multiply(dest uint, reg1 uint, reg2 uint)
+	divide(destDividend uint, destRem uint, reg1 uint, reg2 uint)
 	get(reg uint) uint32
-}
-
-type instructionReadMemory interface {
-	Get(address uint32) uint32
-}
-
-type instructionWriteMemory interface {
-	Set(address uint32, value uint32, bitsToSet uint)
-}
-
-type instructionReadWriteMemory interface {
-	instructionReadMemory
-	instructionWriteMemory
+	loadFloat(dest uint, address uint32, memory Memory.Reader)
+	loadDouble(dest uint, address uint32, memory Memory.Reader)
+	storeFloat(src uint, address uint32, memory Memory.Writer)
+	storeDouble(src uint, address uint32, memory Memory.Writer)
+	getFloat(reg uint) uint64
+	setFloat(reg uint, val uint64)
 }
 
 /* resetRegisterZero resets the value of register 0 to 0,
@@ -89,6 +144,15 @@ func (ex *RiscVInstructionExecutor) Get(reg uint) uint32 {
 	return ex.operator.get(reg)
 }
 
+/*Set allows the caller to write `val` into the register `reg`, as when a loader prepares the stack pointer.
+Writes to register 0 are discarded, since its value is always 0
+*/
+func (ex *RiscVInstructionExecutor) Set(reg uint, val uint32) {
+	defer ex.resetRegisterZero()
+	ex.operator.andImmediate(reg, reg, 0)
+	ex.operator.orImmediate(reg, reg, val)
+}
+
 /*AddImmediate adds an immediate to a value in a register, and stores the result
 in the destination register. The immediate value is `immediate` 12 least-significant
 bits, sign-extended based on the 12th bit.
@@ -277,180 +341,414 @@ func (ex *RiscVInstructionExecutor) ShiftRightArithmetic(dest uint, reg uint, sh
 	ex.operator.rightShiftImmediate(dest, reg, lowerFiveBits, true)
 }
 
+/*Multiply multiplies the values in registers `reg1` and `reg2`, and writes the lower 32 bits
+of the product into register `dest`. The lower bits are the same for signed and unsigned operands*/
+func (ex *RiscVInstructionExecutor) Multiply(dest uint, reg1 uint, reg2 uint) {
+	defer ex.resetRegisterZero()
+	ex.operator.multiply(dest, reg1, reg2)
+}
+
+/*MultiplyHigh multiplies the signed values in registers `reg1` and `reg2`, and writes the upper 32 bits
+of the 64-bit product into register `dest`*/
+func (ex *RiscVInstructionExecutor) MultiplyHigh(dest uint, reg1 uint, reg2 uint) {
+	defer ex.resetRegisterZero()
+	product := int64(int32(ex.Get(reg1))) * int64(int32(ex.Get(reg2)))
+	ex.Set(dest, uint32(product>>32))
+}
+
+/*MultiplyHighSignedUnsigned multiplies the signed value in register `reg1` by the unsigned value
+in register `reg2`, and writes the upper 32 bits of the 64-bit product into register `dest`*/
+func (ex *RiscVInstructionExecutor) MultiplyHighSignedUnsigned(dest uint, reg1 uint, reg2 uint) {
+	defer ex.resetRegisterZero()
+	product := int64(int32(ex.Get(reg1))) * int64(ex.Get(reg2))
+	ex.Set(dest, uint32(product>>32))
+}
+
+/*MultiplyHighUnsigned multiplies the unsigned values in registers `reg1` and `reg2`, and writes the upper
+32 bits of the 64-bit product into register `dest`*/
+func (ex *RiscVInstructionExecutor) MultiplyHighUnsigned(dest uint, reg1 uint, reg2 uint) {
+	defer ex.resetRegisterZero()
+	product := uint64(ex.Get(reg1)) * uint64(ex.Get(reg2))
+	ex.Set(dest, uint32(product>>32))
+}
+
+/*Divide divides the signed value in register `reg1` by the signed value in `reg2`, rounding towards zero,
+and writes the quotient into register `dest`. As RiscV does not trap, division by zero gives -1, and the
+overflowing division of the most negative value by -1 gives the most negative value*/
+func (ex *RiscVInstructionExecutor) Divide(dest uint, reg1 uint, reg2 uint) {
+	defer ex.resetRegisterZero()
+	dividend := int32(ex.Get(reg1))
+	divisor := int32(ex.Get(reg2))
+
+	switch {
+	case divisor == 0:
+		ex.Set(dest, math.MaxUint32)
+	case dividend == math.MinInt32 && divisor == -1:
+		ex.Set(dest, uint32(dividend))
+	default:
+		ex.Set(dest, uint32(dividend/divisor))
+	}
+}
+
+/*DivideUnsigned divides the unsigned value in register `reg1` by the unsigned value in `reg2`, and writes
+the quotient into register `dest`. Division by zero gives the largest unsigned value*/
+func (ex *RiscVInstructionExecutor) DivideUnsigned(dest uint, reg1 uint, reg2 uint) {
+	defer ex.resetRegisterZero()
+	// the remainder is written to register 0, which discards it
+	ex.operator.divide(dest, 0, reg1, reg2)
+}
+
+/*Remainder writes the remainder of the signed division of register `reg1` by register `reg2` into
+register `dest`. The remainder has the sign of the dividend. Division by zero leaves the dividend as the
+remainder, and the overflowing division of the most negative value by -1 leaves 0*/
+func (ex *RiscVInstructionExecutor) Remainder(dest uint, reg1 uint, reg2 uint) {
+	defer ex.resetRegisterZero()
+	dividend := int32(ex.Get(reg1))
+	divisor := int32(ex.Get(reg2))
+
+	switch {
+	case divisor == 0:
+		ex.Set(dest, uint32(dividend))
+	case dividend == math.MinInt32 && divisor == -1:
+		ex.Set(dest, 0)
+	default:
+		ex.Set(dest, uint32(dividend%divisor))
+	}
+}
+
+/*RemainderUnsigned writes the remainder of the unsigned division of register `reg1` by register `reg2`
+into register `dest`. Division by zero leaves the dividend as the remainder*/
+func (ex *RiscVInstructionExecutor) RemainderUnsigned(dest uint, reg1 uint, reg2 uint) {
+	defer ex.resetRegisterZero()
+	// the quotient is written to register 0, which discards it
+	ex.operator.divide(0, dest, reg1, reg2)
+}
+
 /*BranchEqual compares the values in registers `reg1` and `reg2`. If `reg1` equals `reg2`, then
-the 12 lowest bits of `immediate` are added to the pc through the `manager`
+the sign-extended 13 lowest bits of `immediate` are added to the pc through the `manager`
 */
 func (ex *RiscVInstructionExecutor) BranchEqual(reg1 uint, reg2 uint, immediate uint32, manager instructionManager) {
 	defer ex.resetRegisterZero()
 
 	if ex.Get(reg1) == ex.Get(reg2) {
-		manager.addOffsetForNextInstructionAddress(Utils.KeepBitsInInclusiveRange(immediate, 0, 11))
+		manager.addOffsetForNextInstructionAddress(branchOffset(immediate))
 	}
 }
 
 /*BranchNotEqual compares the values in registers `reg1` and `reg2`. If `reg1` does NOT equal `reg2`, then
-the 12 lowest bits of `immediate` are added to the pc through the `manager`
+the sign-extended 13 lowest bits of `immediate` are added to the pc through the `manager`
 */
 func (ex *RiscVInstructionExecutor) BranchNotEqual(reg1 uint, reg2 uint, immediate uint32, manager instructionManager) {
 	defer ex.resetRegisterZero()
 	if ex.Get(reg1) != ex.Get(reg2) {
-		manager.addOffsetForNextInstructionAddress(Utils.KeepBitsInInclusiveRange(immediate, 0, 11))
+		manager.addOffsetForNextInstructionAddress(branchOffset(immediate))
 	}
 }
 
 /*BranchLessThan compares the values in registers `reg1` and `reg2` as SIGNED values. If `reg1` < `reg2`, then
-the 12 lowest bits of `immediate` are added to the pc through the `manager`
+the sign-extended 13 lowest bits of `immediate` are added to the pc through the `manager`
 */
 func (ex *RiscVInstructionExecutor) BranchLessThan(reg1 uint, reg2 uint, immediate uint32, manager instructionManager) {
 	defer ex.resetRegisterZero()
 	if int32(ex.Get(reg1)) < int32(ex.Get(reg2)) {
-		manager.addOffsetForNextInstructionAddress(Utils.KeepBitsInInclusiveRange(immediate, 0, 11))
+		manager.addOffsetForNextInstructionAddress(branchOffset(immediate))
 	}
 }
 
 /*BranchLessThanUnsigned compares the values in registers `reg1` and `reg2` as UNSIGNED values. If `reg1` < `reg2`, then
-the 12 lowest bits of `immediate` are added to the pc through the `manager`
+the sign-extended 13 lowest bits of `immediate` are added to the pc through the `manager`
 */
 func (ex *RiscVInstructionExecutor) BranchLessThanUnsigned(reg1 uint, reg2 uint, immediate uint32, manager instructionManager) {
 	defer ex.resetRegisterZero()
 	if ex.Get(reg1) < ex.Get(reg2) {
-		manager.addOffsetForNextInstructionAddress(Utils.KeepBitsInInclusiveRange(immediate, 0, 11))
+		manager.addOffsetForNextInstructionAddress(branchOffset(immediate))
 	}
 }
 
 /*BranchGreaterThanOrEqual compares the values in registers `reg1` and `reg2` as SIGNED values. If `reg1` >= `reg2`, then
-the 12 lowest bits of `immediate` are added to the pc through the `manager`
+the sign-extended 13 lowest bits of `immediate` are added to the pc through the `manager`
 */
 func (ex *RiscVInstructionExecutor) BranchGreaterThanOrEqual(reg1 uint, reg2 uint, immediate uint32, manager instructionManager) {
 	defer ex.resetRegisterZero()
 	if int32(ex.Get(reg1)) >= int32(ex.Get(reg2)) {
-		manager.addOffsetForNextInstructionAddress(Utils.KeepBitsInInclusiveRange(immediate, 0, 11))
+		manager.addOffsetForNextInstructionAddress(branchOffset(immediate))
 	}
 }
 
 /*BranchGreaterThanOrEqualUnsigned compares the values in registers `reg1` and `reg2` as UNSIGNED values. If `reg1` >= `reg2`, then
-the 12 lowest bits of `immediate` are added to the pc through the `manager`
+the sign-extended 13 lowest bits of `immediate` are added to the pc through the `manager`
 */
 func (ex *RiscVInstructionExecutor) BranchGreaterThanOrEqualUnsigned(reg1 uint, reg2 uint, immediate uint32, manager instructionManager) {
 	defer ex.resetRegisterZero()
 	if ex.Get(reg1) >= ex.Get(reg2) {
-		manager.addOffsetForNextInstructionAddress(Utils.KeepBitsInInclusiveRange(immediate, 0, 11))
+		manager.addOffsetForNextInstructionAddress(branchOffset(immediate))
 	}
 }
 
-/*JumpAndLink saves the address of the next instruction into the
-register `dest` and then adds the sign-extended lowest 20 bits of
-the `pcOffset` to the program counter using `manager`
+/*branchOffset sign-extends the 13-bit byte offset of a branch*/
+func branchOffset(immediate uint32) uint32 {
+	return Utils.SignExtendUint32WithBit(Utils.KeepBitsInInclusiveRange(immediate, 0, 12), 12)
+}
+
+/*JumpAndLink adds the sign-extended lowest 21 bits of the `pcOffset` to the program counter
+using `manager`, and then saves the address of the instruction after the jump into the register `dest`.
+If the `manager` rejects the target, `dest` is left as it was
 */
 func (ex *RiscVInstructionExecutor) JumpAndLink(dest uint, pcOffset uint32, manager instructionManager) {
 	defer ex.resetRegisterZero()
-	ex.operator.andImmediate(dest, dest, 0)
-	ex.operator.orImmediate(dest, dest, manager.getNextInstructionAddress())
+	link := manager.getNextInstructionAddress()
 
-	lower20Bits := Utils.SignExtendUint32WithBit(Utils.KeepBitsInInclusiveRange(pcOffset, 0, 19), 19)
-	manager.addOffsetForNextInstructionAddress(lower20Bits)
+	lower21Bits := Utils.SignExtendUint32WithBit(Utils.KeepBitsInInclusiveRange(pcOffset, 0, 20), 20)
+	manager.addOffsetForNextInstructionAddress(lower21Bits)
+	ex.operator.andImmediate(dest, dest, 0)
+	ex.operator.orImmediate(dest, dest, link)
 }
 
-/*JumpAndLinkRegister saves the address of the next instruction into the
-register `dest`. It then adds the sign-extended lowest 12 bits of `pcOffset`
+/*JumpAndLinkRegister adds the sign-extended lowest 12 bits of `pcOffset`
 to the value in the register `basereg`, sets the LSB of the result to 0,
-and loads the result into the program counter through the `manager`
+and loads the result into the program counter through the `manager`. It then saves
+the address of the instruction after the jump into the register `dest`, which may be `basereg`.
+If the `manager` rejects the target, `dest` is left as it was
 */
 func (ex *RiscVInstructionExecutor) JumpAndLinkRegister(dest uint, basereg uint, pcOffset uint32, manager instructionManager) {
 	defer ex.resetRegisterZero()
-	ex.operator.andImmediate(dest, dest, 0)
-	ex.operator.orImmediate(dest, dest, manager.getNextInstructionAddress())
+	link := manager.getNextInstructionAddress()
 
 	lower12Bits := Utils.SignExtendUint32WithBit(Utils.KeepBitsInInclusiveRange(pcOffset, 0, 11), 11)
 	address := Utils.KeepBitsInInclusiveRange(lower12Bits+ex.Get(basereg), 1, 31)
 	manager.loadAsNextInstructionAddress(address)
+	ex.operator.andImmediate(dest, dest, 0)
+	ex.operator.orImmediate(dest, dest, link)
 }
 
 /*LoadWord compiles an address from sign-extended lower 12 bits of offset, adds that to uint32 stored in
 register `reg`, and then reads 1 Word from that address from memory into the destination register `dest`
 */
-func (ex *RiscVInstructionExecutor) LoadWord(dest uint, reg uint, offset uint32, memory instructionReadMemory) {
+func (ex *RiscVInstructionExecutor) LoadWord(dest uint, reg uint, offset uint32, memory Memory.Reader) {
 	defer ex.resetRegisterZero()
 	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
 	address := Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
-	ex.operator.loadWord(dest, address, memory)
+	physical, memory := ex.loadAddress(address, 4, memory)
+	ex.operator.loadWord(dest, physical, memory)
 }
 
 /*LoadHalfWord compiles an address from sign-extended lower 12 bits of offset, adds that to uint32 stored in
 register `reg`, and then reads 1 Sign-extended HalfWord from that address from memory into the destination register `dest`
 */
-func (ex *RiscVInstructionExecutor) LoadHalfWord(dest uint, reg uint, offset uint32, memory instructionReadMemory) {
+func (ex *RiscVInstructionExecutor) LoadHalfWord(dest uint, reg uint, offset uint32, memory Memory.Reader) {
 	defer ex.resetRegisterZero()
 	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
 	address := Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
-	ex.operator.loadHalfWord(dest, address, memory)
+	physical, memory := ex.loadAddress(address, 2, memory)
+	ex.operator.loadHalfWord(dest, physical, memory)
 
 }
 
 /*LoadHalfWordUnsigned compiles an address from sign-extended lower 12 bits of offset, adds that to uint32 stored in
 register `reg`, and then reads 1 HalfWord from that address from memory into the destination register `dest`
 */
-func (ex *RiscVInstructionExecutor) LoadHalfWordUnsigned(dest uint, reg uint, offset uint32, memory instructionReadMemory) {
+func (ex *RiscVInstructionExecutor) LoadHalfWordUnsigned(dest uint, reg uint, offset uint32, memory Memory.Reader) {
 	defer ex.resetRegisterZero()
 	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
 	address := Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
-	ex.operator.loadHalfWordUnsigned(dest, address, memory)
+	physical, memory := ex.loadAddress(address, 2, memory)
+	ex.operator.loadHalfWordUnsigned(dest, physical, memory)
 
 }
 
 /*LoadByte compiles an address from sign-extended lower 12 bits of offset, adds that to uint32 stored in
 register `reg`, and then reads 1 Sign-extended Byte from that address from memory into the destination register `dest`
 */
-func (ex *RiscVInstructionExecutor) LoadByte(dest uint, reg uint, offset uint32, memory instructionReadMemory) {
+func (ex *RiscVInstructionExecutor) LoadByte(dest uint, reg uint, offset uint32, memory Memory.Reader) {
 	defer ex.resetRegisterZero()
 	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
 	address := Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
-	ex.operator.loadByte(dest, address, memory)
+	physical, memory := ex.loadAddress(address, 1, memory)
+	ex.operator.loadByte(dest, physical, memory)
 
 }
 
 /*LoadByteUnsigned compiles an address from sign-extended lower 12 bits of offset, adds that to uint32 stored in
 register `reg`, and then reads 1 Byte from that address from memory into the destination register `dest`
 */
-func (ex *RiscVInstructionExecutor) LoadByteUnsigned(dest uint, reg uint, offset uint32, memory instructionReadMemory) {
+func (ex *RiscVInstructionExecutor) LoadByteUnsigned(dest uint, reg uint, offset uint32, memory Memory.Reader) {
 	defer ex.resetRegisterZero()
 	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
 	address := Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
-	ex.operator.loadByteUnsigned(dest, address, memory)
+	physical, memory := ex.loadAddress(address, 1, memory)
+	ex.operator.loadByteUnsigned(dest, physical, memory)
 
 }
 
 /*StoreWord compiles an address from the sign-extended lower 12 bits of `offset`, adds that to the uint32 stored
 in register `reg`, and then stores 4 Bytes from the register `src` into memory at the calculated 32-bit address*/
-func (ex *RiscVInstructionExecutor) StoreWord(src uint, reg uint, offset uint32, memory instructionWriteMemory) {
+func (ex *RiscVInstructionExecutor) StoreWord(src uint, reg uint, offset uint32, memory Memory.Writer) {
 	defer ex.resetRegisterZero()
 	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
 	address := Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
-	ex.operator.storeWord(src, address, memory)
+	physical, memory := ex.storeAddress(address, 4, memory)
+	ex.operator.storeWord(src, physical, memory)
 }
 
 /*StoreHalfWord compiles an address from the sign-extended lower 12 bits of `offset`, adds that to the uint32 stored
 in register `reg`, and then stores 2 Bytes from the register `src` into memory at the calculated 32-bit address*/
-func (ex *RiscVInstructionExecutor) StoreHalfWord(src uint, reg uint, offset uint32, memory instructionWriteMemory) {
+func (ex *RiscVInstructionExecutor) StoreHalfWord(src uint, reg uint, offset uint32, memory Memory.Writer) {
 	defer ex.resetRegisterZero()
 	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
 	address := Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
-	ex.operator.storeHalfWord(src, address, memory)
+	physical, memory := ex.storeAddress(address, 2, memory)
+	ex.operator.storeHalfWord(src, physical, memory)
 }
 
 /*StoreByte compiles an address from the sign-extended lower 12 bits of `offset`, adds that to the uint32 stored
 in register `reg`, and then stores 1 Byte from the register `src` into memory at the calculated 32-bit address*/
-func (ex *RiscVInstructionExecutor) StoreByte(src uint, reg uint, offset uint32, memory instructionWriteMemory) {
+func (ex *RiscVInstructionExecutor) StoreByte(src uint, reg uint, offset uint32, memory Memory.Writer) {
 	defer ex.resetRegisterZero()
 	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
 	address := Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
-	ex.operator.storeByte(src, address, memory)
+	physical, memory := ex.storeAddress(address, 1, memory)
+	ex.operator.storeByte(src, physical, memory)
+}
+
+/*LoadReserved loads the word at the address in register `reg` into register `dest`, and registers a
+reservation on that address, which a later StoreConditional to the same address needs to succeed*/
+func (ex *RiscVInstructionExecutor) LoadReserved(dest uint, reg uint, memory Memory.Reader) {
+	defer ex.resetRegisterZero()
+	address := ex.Get(reg)
+	ex.checkAlignment(address, 4, ex.atomicPolicy(), Traps.LoadAddressMisaligned, Traps.LoadAccessFault)
+	physical := ex.translator.translateLoad(address, 4)
+	word, err := memory.Read(physical, Memory.Word)
+	panicIfAccessFailed(err, physical, Traps.LoadAccessFault)
+	ex.reserved = true
+	ex.reservedAddress = address
+	ex.Set(dest, uint32(word))
+}
+
+/*StoreConditional stores the word in register `src` at the address in register `reg`, but only if
+that address holds a reservation from LoadReserved. Register `dest` is set to 0 if the store happened,
+and to 1 if it did not. Either way, the reservation is used up*/
+func (ex *RiscVInstructionExecutor) StoreConditional(dest uint, reg uint, src uint, memory Memory.Writer) {
+	defer ex.resetRegisterZero()
+	address := ex.Get(reg)
+	ex.checkAlignment(address, 4, ex.atomicPolicy(), Traps.StoreAddressMisaligned, Traps.StoreAccessFault)
+	physical := ex.translator.translateStore(address, 4)
+	succeeded := ex.reserved && ex.reservedAddress == address
+	ex.reserved = false
+
+	if succeeded {
+		panicIfAccessFailed(memory.Write(physical, Memory.Word, uint64(ex.Get(src))), physical, Traps.StoreAccessFault)
+		ex.Set(dest, 0)
+	} else {
+		ex.Set(dest, 1)
+	}
+}
+
+/*atomicOperation reads the word at the address in register `reg`, writes the result of `operation` on
+that word and the value of register `src` back to the same address, and places the original word
+in register `dest`. `src` is read before `dest` is written, so they may be the same register*/
+func (ex *RiscVInstructionExecutor) atomicOperation(dest uint, reg uint, src uint, memory Memory.ReadWriter,
+	operation func(word uint32, operand uint32) uint32) {
+	defer ex.resetRegisterZero()
+	address := ex.Get(reg)
+	ex.checkAlignment(address, 4, ex.atomicPolicy(), Traps.StoreAddressMisaligned, Traps.StoreAccessFault)
+	physical := ex.translator.translateStore(address, 4)
+	operand := ex.Get(src)
+	word, err := memory.Read(physical, Memory.Word)
+	panicIfAccessFailed(err, physical, Traps.StoreAccessFault)
+	panicIfAccessFailed(memory.Write(physical, Memory.Word, uint64(operation(uint32(word), operand))), physical,
+		Traps.StoreAccessFault)
+	ex.Set(dest, uint32(word))
+}
+
+/*AtomicSwap atomically swaps the word at the address in register `reg` with the value of register `src`,
+placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicSwap(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		return operand
+	})
+}
+
+/*AtomicAdd atomically adds the value of register `src` to the word at the address in register `reg`,
+placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicAdd(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		return word + operand
+	})
+}
+
+/*AtomicXor atomically XORs the value of register `src` into the word at the address in register `reg`,
+placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicXor(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		return word ^ operand
+	})
+}
+
+/*AtomicAnd atomically ANDs the value of register `src` into the word at the address in register `reg`,
+placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicAnd(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		return word & operand
+	})
+}
+
+/*AtomicOr atomically ORs the value of register `src` into the word at the address in register `reg`,
+placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicOr(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		return word | operand
+	})
+}
+
+/*AtomicMin atomically replaces the word at the address in register `reg` with the signed minimum of
+that word and the value of register `src`, placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicMin(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		if int32(operand) < int32(word) {
+			return operand
+		}
+		return word
+	})
+}
+
+/*AtomicMax atomically replaces the word at the address in register `reg` with the signed maximum of
+that word and the value of register `src`, placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicMax(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		if int32(operand) > int32(word) {
+			return operand
+		}
+		return word
+	})
+}
+
+/*AtomicMinUnsigned atomically replaces the word at the address in register `reg` with the unsigned minimum
+of that word and the value of register `src`, placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicMinUnsigned(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		if operand < word {
+			return operand
+		}
+		return word
+	})
+}
+
+/*AtomicMaxUnsigned atomically replaces the word at the address in register `reg` with the unsigned maximum
+of that word and the value of register `src`, placing the original word in register `dest`*/
+func (ex *RiscVInstructionExecutor) AtomicMaxUnsigned(dest uint, reg uint, src uint, memory Memory.ReadWriter) {
+	ex.atomicOperation(dest, reg, src, memory, func(word uint32, operand uint32) uint32 {
+		if operand > word {
+			return operand
+		}
+		return word
+	})
 }
 
 /*CsrReadAndWrite atomically reads the value of CSR `csr` into register `dest` and writes the value of
 register `reg` into CSR `csr`. However, the read does not occur AT ALL if `dest` == 0*/
 func (ex *RiscVInstructionExecutor) CsrReadAndWrite(dest uint, reg uint, csr uint, csrOperator csrOperator) {
 	defer ex.resetRegisterZero()
+	csrOperator = ex.withFloatingPointCsrs(csrOperator)
 
 	regVal := ex.Get(reg)
 
@@ -468,6 +766,7 @@ OR between the value in `csr` and the value in `reg`, and the result is written
 However, the write to `csr` will not happen AT ALL if `reg` == 0*/
 func (ex *RiscVInstructionExecutor) CsrReadAndSet(dest uint, reg uint, csr uint, csrOperator csrOperator) {
 	defer ex.resetRegisterZero()
+	csrOperator = ex.withFloatingPointCsrs(csrOperator)
 
 	regVal := ex.Get(reg)
 	csrVal := csrOperator.get(csr)
@@ -486,6 +785,7 @@ func (ex *RiscVInstructionExecutor) CsrReadAndSet(dest uint, reg uint, csr uint,
 */
 func (ex *RiscVInstructionExecutor) CsrReadAndClear(dest uint, reg uint, csr uint, csrOperator csrOperator) {
 	defer ex.resetRegisterZero()
+	csrOperator = ex.withFloatingPointCsrs(csrOperator)
 
 	regVal := ex.Get(reg)
 	csrVal := csrOperator.get(csr)
@@ -503,6 +803,7 @@ lowest 5 bits of `immediate` into CSR `csr`. However, the read does not occur AT
 */
 func (ex *RiscVInstructionExecutor) CsrReadAndWriteImmediate(dest uint, immediate uint32, csr uint, csrOperator csrOperator) {
 	defer ex.resetRegisterZero()
+	csrOperator = ex.withFloatingPointCsrs(csrOperator)
 
 	if dest != 0 {
 		csrVal := csrOperator.get(csr)
@@ -520,6 +821,7 @@ and the result is written back into the CSR `csr`
 However, the write to `csr` will not happen AT ALL if the lowest 5 bits of `immediate` == 0*/
 func (ex *RiscVInstructionExecutor) CsrReadAndSetImmediate(dest uint, immediate uint32, csr uint, csrOperator csrOperator) {
 	defer ex.resetRegisterZero()
+	csrOperator = ex.withFloatingPointCsrs(csrOperator)
 
 	immVal := Utils.KeepBitsInInclusiveRange(immediate, 0, 4)
 	csrVal := csrOperator.get(csr)
@@ -539,6 +841,7 @@ However, the write to `csr` will not happen AT ALL if the lowest 5 bits of `imme
 */
 func (ex *RiscVInstructionExecutor) CsrReadAndClearImmediate(dest uint, immediate uint32, csr uint, csrOperator csrOperator) {
 	defer ex.resetRegisterZero()
+	csrOperator = ex.withFloatingPointCsrs(csrOperator)
 
 	immVal := Utils.KeepBitsInInclusiveRange(immediate, 0, 4)
 	csrVal := csrOperator.get(csr)
@@ -569,3 +872,36 @@ func (ex *RiscVInstructionExecutor) EnvBreak(env debugEnvManager) {
 
 	env.debugBreak()
 }
+
+/*TrapReturn returns from a trap handler (as MRET does), by jumping to the address that `env`
+restores from the trap*/
+func (ex *RiscVInstructionExecutor) TrapReturn(manager instructionManager, env trapEnvManager) {
+	defer ex.resetRegisterZero()
+
+	manager.loadAsNextInstructionAddress(env.returnFromTrap())
+}
+
+/*SupervisorTrapReturn returns from a supervisor-mode trap handler (as SRET does), by jumping to the address
+that `env` restores from the trap*/
+func (ex *RiscVInstructionExecutor) SupervisorTrapReturn(manager instructionManager, env trapEnvManager) {
+	defer ex.resetRegisterZero()
+
+	manager.loadAsNextInstructionAddress(env.returnFromSupervisorTrap())
+}
+
+/*WaitForInterrupt stalls the hart until an interrupt may need servicing (as WFI does). How long that is,
+and whether it is allowed at all, is up to `env`*/
+func (ex *RiscVInstructionExecutor) WaitForInterrupt(env trapEnvManager) {
+	defer ex.resetRegisterZero()
+
+	env.waitForInterrupt()
+}
+
+/*FenceVirtualMemory orders the writes to the page tables before the address translations that follow (as SFENCE.VMA does).
+Only the translations of the virtual address in register `addressReg`, and of the address space in register `asidReg`, are
+ordered, unless the register is register 0, in which case those of every address, or every address space, are*/
+func (ex *RiscVInstructionExecutor) FenceVirtualMemory(addressReg uint, asidReg uint) {
+	defer ex.resetRegisterZero()
+
+	ex.translator.fence(ex.Get(addressReg), ex.Get(asidReg), addressReg == 0, asidReg == 0)
+}
@@ -0,0 +1,408 @@
+package execution
+
+import (
+	"fmt"
+	"math"
+
+	Utils "github.com/chenhowa/computer/lib/binaryInstructionExecution/bitUtils"
+	FloatingPoint "github.com/chenhowa/computer/lib/binaryInstructionExecution/execution/floatingPoint"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
+)
+
+/*These constants are the values of the `fmt` field of a floating-point instruction*/
+const (
+	SinglePrecision uint = 0
+	DoublePrecision uint = 1
+)
+
+/*These constants are the numbers of the floating-point CSRs. fflags and frm are fields of fcsr:
+the accrued exception flags are its lowest 5 bits, and the dynamic rounding mode is the 3 bits above them*/
+const (
+	FflagsCsr uint = 0x001
+	FrmCsr    uint = 0x002
+	FcsrCsr   uint = 0x003
+)
+
+/*nanBox is the upper half of a floating-point register that holds a single precision value*/
+const nanBox uint64 = 0xFFFFFFFF00000000
+
+/*GetFloat allows the caller to read the raw 64 bits of floating-point register `reg`. Single precision
+values are NaN-boxed, so their upper 32 bits are all ones
+*/
+func (ex *RiscVInstructionExecutor) GetFloat(reg uint) uint64 {
+	return ex.operator.getFloat(reg)
+}
+
+/*SetFloat allows the caller to write the raw 64 bits `val` into floating-point register `reg`*/
+func (ex *RiscVInstructionExecutor) SetFloat(reg uint, val uint64) {
+	ex.operator.setFloat(reg, val)
+}
+
+/*format returns the floating-point format that the `fmt` field `format` selects. Only the single and
+double precision formats of the F and D extensions are supported*/
+func (ex *RiscVInstructionExecutor) format(format uint) FloatingPoint.Format {
+	switch format {
+	case SinglePrecision:
+		return FloatingPoint.Single
+	case DoublePrecision:
+		return FloatingPoint.Double
+	default:
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("floating-point format %d is not supported", format)))
+	}
+}
+
+/*readFloat returns the value of floating-point register `reg` in `format`. A single precision value
+that is not properly NaN-boxed is read as the canonical NaN*/
+func (ex *RiscVInstructionExecutor) readFloat(format uint, reg uint) uint64 {
+	bits := ex.operator.getFloat(reg)
+	if format == SinglePrecision {
+		if bits&nanBox != nanBox {
+			return FloatingPoint.Single.CanonicalNaN()
+		}
+		return bits &^ nanBox
+	}
+	return bits
+}
+
+/*writeFloat writes `bits`, a value in `format`, into floating-point register `dest`, NaN-boxing single precision values*/
+func (ex *RiscVInstructionExecutor) writeFloat(format uint, dest uint, bits uint64) {
+	if format == SinglePrecision {
+		bits |= nanBox
+	}
+	ex.operator.setFloat(dest, bits)
+}
+
+/*roundingMode returns the rounding mode that the `rm` field `rm` selects. The dynamic rounding mode
+is the one in frm. Reserved rounding modes are illegal, and so is a reserved mode in frm*/
+func (ex *RiscVInstructionExecutor) roundingMode(rm uint) FloatingPoint.RoundingMode {
+	mode := FloatingPoint.RoundingMode(rm)
+	if mode == FloatingPoint.Dynamic {
+		mode = FloatingPoint.RoundingMode(ex.fcsr >> 5)
+	}
+	if !mode.IsValid() {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("rounding mode %d is reserved", mode)))
+	}
+	return mode
+}
+
+/*raise accrues the exception `flags` in fflags*/
+func (ex *RiscVInstructionExecutor) raise(flags FloatingPoint.Flags) {
+	ex.fcsr |= uint32(flags)
+}
+
+/*floatAddress compiles an address from the sign-extended lower 12 bits of `offset` and the value of register `reg`*/
+func (ex *RiscVInstructionExecutor) floatAddress(reg uint, offset uint32) uint32 {
+	lower12Bits := offset & Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), 0, 11)
+	return Utils.SignExtendUint32WithBit(lower12Bits, 11) + ex.Get(reg)
+}
+
+/*LoadFloat reads 1 single precision value from the address compiled from `offset` and register `reg`
+into floating-point register `dest`
+*/
+func (ex *RiscVInstructionExecutor) LoadFloat(dest uint, reg uint, offset uint32, memory Memory.Reader) {
+	physical, memory := ex.loadAddress(ex.floatAddress(reg, offset), 4, memory)
+	ex.operator.loadFloat(dest, physical, memory)
+}
+
+/*LoadDouble reads 1 double precision value from the address compiled from `offset` and register `reg`
+into floating-point register `dest`
+*/
+func (ex *RiscVInstructionExecutor) LoadDouble(dest uint, reg uint, offset uint32, memory Memory.Reader) {
+	physical, memory := ex.loadAddress(ex.floatAddress(reg, offset), 8, memory)
+	ex.operator.loadDouble(dest, physical, memory)
+}
+
+/*StoreFloat writes the lower 32 bits of floating-point register `src` to the address compiled from `offset`
+and register `reg`. The value is not NaN-unboxed, so it is stored as it is
+*/
+func (ex *RiscVInstructionExecutor) StoreFloat(src uint, reg uint, offset uint32, memory Memory.Writer) {
+	physical, memory := ex.storeAddress(ex.floatAddress(reg, offset), 4, memory)
+	ex.operator.storeFloat(src, physical, memory)
+}
+
+/*StoreDouble writes floating-point register `src` to the address compiled from `offset` and register `reg`*/
+func (ex *RiscVInstructionExecutor) StoreDouble(src uint, reg uint, offset uint32, memory Memory.Writer) {
+	physical, memory := ex.storeAddress(ex.floatAddress(reg, offset), 8, memory)
+	ex.operator.storeDouble(src, physical, memory)
+}
+
+/*floatArithmetic applies `operation` to the values of floating-point registers `reg1` and `reg2`, and writes
+the result into floating-point register `dest`, accruing the exceptions that it raised*/
+func (ex *RiscVInstructionExecutor) floatArithmetic(format uint, dest uint, reg1 uint, reg2 uint, rm uint,
+	operation func(f FloatingPoint.Format, a uint64, b uint64, mode FloatingPoint.RoundingMode) (uint64, FloatingPoint.Flags)) {
+	f := ex.format(format)
+	mode := ex.roundingMode(rm)
+	result, flags := operation(f, ex.readFloat(format, reg1), ex.readFloat(format, reg2), mode)
+	ex.raise(flags)
+	ex.writeFloat(format, dest, result)
+}
+
+/*FloatAdd adds the values of floating-point registers `reg1` and `reg2`, rounding with `rm`*/
+func (ex *RiscVInstructionExecutor) FloatAdd(format uint, dest uint, reg1 uint, reg2 uint, rm uint) {
+	ex.floatArithmetic(format, dest, reg1, reg2, rm, FloatingPoint.Add)
+}
+
+/*FloatSubtract subtracts the value of floating-point register `reg2` from that of `reg1`, rounding with `rm`*/
+func (ex *RiscVInstructionExecutor) FloatSubtract(format uint, dest uint, reg1 uint, reg2 uint, rm uint) {
+	ex.floatArithmetic(format, dest, reg1, reg2, rm, FloatingPoint.Subtract)
+}
+
+/*FloatMultiply multiplies the values of floating-point registers `reg1` and `reg2`, rounding with `rm`*/
+func (ex *RiscVInstructionExecutor) FloatMultiply(format uint, dest uint, reg1 uint, reg2 uint, rm uint) {
+	ex.floatArithmetic(format, dest, reg1, reg2, rm, FloatingPoint.Multiply)
+}
+
+/*FloatDivide divides the value of floating-point register `reg1` by that of `reg2`, rounding with `rm`*/
+func (ex *RiscVInstructionExecutor) FloatDivide(format uint, dest uint, reg1 uint, reg2 uint, rm uint) {
+	ex.floatArithmetic(format, dest, reg1, reg2, rm, FloatingPoint.Divide)
+}
+
+/*FloatSquareRoot takes the square root of the value of floating-point register `reg`, rounding with `rm`*/
+func (ex *RiscVInstructionExecutor) FloatSquareRoot(format uint, dest uint, reg uint, rm uint) {
+	f := ex.format(format)
+	mode := ex.roundingMode(rm)
+	result, flags := FloatingPoint.SquareRoot(f, ex.readFloat(format, reg), mode)
+	ex.raise(flags)
+	ex.writeFloat(format, dest, result)
+}
+
+/*fusedMultiplyAdd computes `reg1` * `reg2` + `reg3` with a single rounding, after negating the product
+if `negateProduct`, and the addend if `negateAddend`. Negating the inputs keeps the sign of NaNs and of exact zeroes
+that the specification gives each of the four fused instructions*/
+func (ex *RiscVInstructionExecutor) fusedMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, rm uint,
+	negateProduct bool, negateAddend bool) {
+	f := ex.format(format)
+	mode := ex.roundingMode(rm)
+	a, b, c := ex.readFloat(format, reg1), ex.readFloat(format, reg2), ex.readFloat(format, reg3)
+	if negateProduct {
+		a = f.Negate(a)
+	}
+	if negateAddend {
+		c = f.Negate(c)
+	}
+
+	result, flags := FloatingPoint.FusedMultiplyAdd(f, a, b, c, mode)
+	ex.raise(flags)
+	ex.writeFloat(format, dest, result)
+}
+
+/*FloatMultiplyAdd computes (`reg1` * `reg2`) + `reg3` with a single rounding*/
+func (ex *RiscVInstructionExecutor) FloatMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, rm uint) {
+	ex.fusedMultiplyAdd(format, dest, reg1, reg2, reg3, rm, false, false)
+}
+
+/*FloatMultiplySubtract computes (`reg1` * `reg2`) - `reg3` with a single rounding*/
+func (ex *RiscVInstructionExecutor) FloatMultiplySubtract(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, rm uint) {
+	ex.fusedMultiplyAdd(format, dest, reg1, reg2, reg3, rm, false, true)
+}
+
+/*FloatNegatedMultiplySubtract computes -(`reg1` * `reg2`) + `reg3` with a single rounding*/
+func (ex *RiscVInstructionExecutor) FloatNegatedMultiplySubtract(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, rm uint) {
+	ex.fusedMultiplyAdd(format, dest, reg1, reg2, reg3, rm, true, false)
+}
+
+/*FloatNegatedMultiplyAdd computes -(`reg1` * `reg2`) - `reg3` with a single rounding*/
+func (ex *RiscVInstructionExecutor) FloatNegatedMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, rm uint) {
+	ex.fusedMultiplyAdd(format, dest, reg1, reg2, reg3, rm, true, true)
+}
+
+/*floatSignInjection writes the value of floating-point register `reg1` into `dest`, with the sign that
+`sign` computes from the signs of `reg1` and `reg2`. It is not an arithmetic operation, so it raises no
+exceptions, and does not canonicalize NaNs*/
+func (ex *RiscVInstructionExecutor) floatSignInjection(format uint, dest uint, reg1 uint, reg2 uint,
+	sign func(negative1 bool, negative2 bool) bool) {
+	f := ex.format(format)
+	a, b := ex.readFloat(format, reg1), ex.readFloat(format, reg2)
+	ex.writeFloat(format, dest, f.WithSign(a, sign(f.IsNegative(a), f.IsNegative(b))))
+}
+
+/*FloatSignInject writes the value of `reg1` with the sign of `reg2` into `dest`*/
+func (ex *RiscVInstructionExecutor) FloatSignInject(format uint, dest uint, reg1 uint, reg2 uint) {
+	ex.floatSignInjection(format, dest, reg1, reg2, func(negative1 bool, negative2 bool) bool {
+		return negative2
+	})
+}
+
+/*FloatSignInjectNegated writes the value of `reg1` with the opposite of the sign of `reg2` into `dest`*/
+func (ex *RiscVInstructionExecutor) FloatSignInjectNegated(format uint, dest uint, reg1 uint, reg2 uint) {
+	ex.floatSignInjection(format, dest, reg1, reg2, func(negative1 bool, negative2 bool) bool {
+		return !negative2
+	})
+}
+
+/*FloatSignInjectXor writes the value of `reg1` into `dest`, negated if `reg2` is negative*/
+func (ex *RiscVInstructionExecutor) FloatSignInjectXor(format uint, dest uint, reg1 uint, reg2 uint) {
+	ex.floatSignInjection(format, dest, reg1, reg2, func(negative1 bool, negative2 bool) bool {
+		return negative1 != negative2
+	})
+}
+
+/*FloatMin writes the smaller of the values of floating-point registers `reg1` and `reg2` into `dest`*/
+func (ex *RiscVInstructionExecutor) FloatMin(format uint, dest uint, reg1 uint, reg2 uint) {
+	f := ex.format(format)
+	result, flags := FloatingPoint.Min(f, ex.readFloat(format, reg1), ex.readFloat(format, reg2))
+	ex.raise(flags)
+	ex.writeFloat(format, dest, result)
+}
+
+/*FloatMax writes the larger of the values of floating-point registers `reg1` and `reg2` into `dest`*/
+func (ex *RiscVInstructionExecutor) FloatMax(format uint, dest uint, reg1 uint, reg2 uint) {
+	f := ex.format(format)
+	result, flags := FloatingPoint.Max(f, ex.readFloat(format, reg1), ex.readFloat(format, reg2))
+	ex.raise(flags)
+	ex.writeFloat(format, dest, result)
+}
+
+/*floatComparison sets integer register `dest` to 1 if `comparison` holds for the values of floating-point
+registers `reg1` and `reg2`, and to 0 otherwise*/
+func (ex *RiscVInstructionExecutor) floatComparison(format uint, dest uint, reg1 uint, reg2 uint,
+	comparison func(f FloatingPoint.Format, a uint64, b uint64) (bool, FloatingPoint.Flags)) {
+	defer ex.resetRegisterZero()
+	f := ex.format(format)
+	holds, flags := comparison(f, ex.readFloat(format, reg1), ex.readFloat(format, reg2))
+	ex.raise(flags)
+	if holds {
+		ex.Set(dest, 1)
+	} else {
+		ex.Set(dest, 0)
+	}
+}
+
+/*FloatEqual sets integer register `dest` to 1 if the values of `reg1` and `reg2` are equal*/
+func (ex *RiscVInstructionExecutor) FloatEqual(format uint, dest uint, reg1 uint, reg2 uint) {
+	ex.floatComparison(format, dest, reg1, reg2, FloatingPoint.Equal)
+}
+
+/*FloatLessThan sets integer register `dest` to 1 if the value of `reg1` is less than that of `reg2`*/
+func (ex *RiscVInstructionExecutor) FloatLessThan(format uint, dest uint, reg1 uint, reg2 uint) {
+	ex.floatComparison(format, dest, reg1, reg2, FloatingPoint.Less)
+}
+
+/*FloatLessOrEqual sets integer register `dest` to 1 if the value of `reg1` is less than or equal to that of `reg2`*/
+func (ex *RiscVInstructionExecutor) FloatLessOrEqual(format uint, dest uint, reg1 uint, reg2 uint) {
+	ex.floatComparison(format, dest, reg1, reg2, FloatingPoint.LessOrEqual)
+}
+
+/*FloatClassify sets integer register `dest` to the mask that describes the class of the value of floating-point register `reg`*/
+func (ex *RiscVInstructionExecutor) FloatClassify(format uint, dest uint, reg uint) {
+	defer ex.resetRegisterZero()
+	ex.Set(dest, FloatingPoint.Classify(ex.format(format), ex.readFloat(format, reg)))
+}
+
+/*FloatToInt converts the value of floating-point register `reg` to a signed integer in integer register `dest`,
+rounding with `rm`. NaNs and values out of range give the nearest integer in range, and are invalid*/
+func (ex *RiscVInstructionExecutor) FloatToInt(format uint, dest uint, reg uint, rm uint) {
+	ex.floatToInteger(format, dest, reg, rm, FloatingPoint.ToInt32)
+}
+
+/*FloatToUnsignedInt converts the value of floating-point register `reg` to an unsigned integer in integer register `dest`,
+rounding with `rm`*/
+func (ex *RiscVInstructionExecutor) FloatToUnsignedInt(format uint, dest uint, reg uint, rm uint) {
+	ex.floatToInteger(format, dest, reg, rm, FloatingPoint.ToUint32)
+}
+
+func (ex *RiscVInstructionExecutor) floatToInteger(format uint, dest uint, reg uint, rm uint,
+	conversion func(f FloatingPoint.Format, a uint64, mode FloatingPoint.RoundingMode) (uint32, FloatingPoint.Flags)) {
+	defer ex.resetRegisterZero()
+	f := ex.format(format)
+	mode := ex.roundingMode(rm)
+	result, flags := conversion(f, ex.readFloat(format, reg), mode)
+	ex.raise(flags)
+	ex.Set(dest, result)
+}
+
+/*IntToFloat converts the signed integer in integer register `reg` to a value in floating-point register `dest`,
+rounding with `rm`*/
+func (ex *RiscVInstructionExecutor) IntToFloat(format uint, dest uint, reg uint, rm uint) {
+	ex.integerToFloat(format, dest, reg, rm, FloatingPoint.FromInt32)
+}
+
+/*UnsignedIntToFloat converts the unsigned integer in integer register `reg` to a value in floating-point register `dest`,
+rounding with `rm`*/
+func (ex *RiscVInstructionExecutor) UnsignedIntToFloat(format uint, dest uint, reg uint, rm uint) {
+	ex.integerToFloat(format, dest, reg, rm, FloatingPoint.FromUint32)
+}
+
+func (ex *RiscVInstructionExecutor) integerToFloat(format uint, dest uint, reg uint, rm uint,
+	conversion func(f FloatingPoint.Format, i uint32, mode FloatingPoint.RoundingMode) (uint64, FloatingPoint.Flags)) {
+	f := ex.format(format)
+	mode := ex.roundingMode(rm)
+	result, flags := conversion(f, ex.Get(reg), mode)
+	ex.raise(flags)
+	ex.writeFloat(format, dest, result)
+}
+
+/*FloatConvert converts the value of floating-point register `reg` to a value in `format` in floating-point
+register `dest`, rounding with `rm`. The source value has the other of the two formats*/
+func (ex *RiscVInstructionExecutor) FloatConvert(format uint, dest uint, reg uint, rm uint) {
+	source := DoublePrecision
+	if format == DoublePrecision {
+		source = SinglePrecision
+	}
+	to := ex.format(format)
+	mode := ex.roundingMode(rm)
+	result, flags := FloatingPoint.Convert(ex.format(source), to, ex.readFloat(source, reg), mode)
+	ex.raise(flags)
+	ex.writeFloat(format, dest, result)
+}
+
+/*FloatMoveToInt copies the single precision bits of floating-point register `reg` into integer register `dest`,
+without NaN-unboxing them*/
+func (ex *RiscVInstructionExecutor) FloatMoveToInt(dest uint, reg uint) {
+	defer ex.resetRegisterZero()
+	ex.Set(dest, uint32(ex.operator.getFloat(reg)))
+}
+
+/*IntMoveToFloat copies the bits of integer register `reg` into floating-point register `dest`, NaN-boxing them*/
+func (ex *RiscVInstructionExecutor) IntMoveToFloat(dest uint, reg uint) {
+	ex.writeFloat(SinglePrecision, dest, uint64(ex.Get(reg)))
+}
+
+/*GetCsr returns the value of CSR `csr`, where the floating-point CSRs are those of this executor,
+and every other CSR is read from `csrOperator`*/
+func (ex *RiscVInstructionExecutor) GetCsr(csr uint, csrOperator csrOperator) uint32 {
+	return ex.withFloatingPointCsrs(csrOperator).get(csr)
+}
+
+/*floatingPointCsrs serves the floating-point CSRs out of the fcsr of an executor, and passes every other CSR
+on to the CSR operator that it wraps*/
+type floatingPointCsrs struct {
+	executor *RiscVInstructionExecutor
+	csrs     csrOperator
+}
+
+func (ex *RiscVInstructionExecutor) withFloatingPointCsrs(csrs csrOperator) csrOperator {
+	return &floatingPointCsrs{
+		executor: ex,
+		csrs:     csrs,
+	}
+}
+
+func (op *floatingPointCsrs) get(reg uint) uint32 {
+	fcsr := op.executor.fcsr
+	switch reg {
+	case FflagsCsr:
+		return fcsr & 0x1F
+	case FrmCsr:
+		return (fcsr >> 5) & 0x7
+	case FcsrCsr:
+		return fcsr & 0xFF
+	default:
+		return op.csrs.get(reg)
+	}
+}
+
+func (op *floatingPointCsrs) set(reg uint, val uint32) {
+	fcsr := &op.executor.fcsr
+	switch reg {
+	case FflagsCsr:
+		*fcsr = (*fcsr &^ 0x1F) | (val & 0x1F)
+	case FrmCsr:
+		*fcsr = (*fcsr & 0x1F) | (val&0x7)<<5
+	case FcsrCsr:
+		*fcsr = val & 0xFF
+	default:
+		op.csrs.set(reg, val)
+	}
+}
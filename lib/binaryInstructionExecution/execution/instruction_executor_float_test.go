@@ -0,0 +1,132 @@
+package execution
+
+import (
+	"math"
+
+	Memory "github.com/chenhowa/computer/lib/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func boxed(f float32) uint64 {
+	return nanBox | uint64(math.Float32bits(f))
+}
+
+func (suite *InstructionExecutorSuite) TestLoadStoreFloat() {
+	suite.memory.On("Read", uint32(12), Memory.Word).Return()
+	suite.memory.On("Write", uint32(12), Memory.Word, uint64(0x40400000)).Return()
+	suite.memory.val = 0x40400000
+
+	suite.executor.LoadFloat(1, 2, 10, suite.memory)
+	assert.Equal(suite.T(), boxed(3), suite.executor.GetFloat(1))
+	suite.executor.StoreFloat(1, 2, 10, suite.memory)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(12), Memory.Word, uint64(0x40400000))
+
+	suite.memory.On("Read", uint32(12), Memory.DoubleWord).Return()
+	suite.memory.val = 0x4008000000000000
+	suite.executor.LoadDouble(3, 2, 10, suite.memory)
+	assert.Equal(suite.T(), math.Float64bits(3), suite.executor.GetFloat(3))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(12), Memory.DoubleWord)
+}
+
+func (suite *InstructionExecutorSuite) TestFloatNaNBoxing() {
+	suite.executor.SetFloat(1, boxed(1))
+	suite.executor.SetFloat(2, boxed(2))
+	suite.executor.FloatAdd(SinglePrecision, 3, 1, 2, 0)
+	assert.Equal(suite.T(), boxed(3), suite.executor.GetFloat(3))
+
+	// a single precision value that is not NaN-boxed is read as the canonical NaN
+	suite.executor.SetFloat(2, uint64(math.Float32bits(2)))
+	suite.executor.FloatAdd(SinglePrecision, 3, 1, 2, 0)
+	assert.Equal(suite.T(), uint64(0xFFFFFFFF7FC00000), suite.executor.GetFloat(3))
+
+	// moves and stores do not look at the box
+	suite.executor.FloatMoveToInt(4, 2)
+	suite.assertRegisterEquals(4, math.Float32bits(2))
+	suite.executor.IntMoveToFloat(5, 4)
+	assert.Equal(suite.T(), boxed(2), suite.executor.GetFloat(5))
+
+	// sign injection keeps the payload of NaNs
+	suite.executor.SetFloat(6, boxed(-1))
+	suite.executor.SetFloat(7, nanBox|0x7F800001)
+	suite.executor.FloatSignInject(SinglePrecision, 8, 7, 6)
+	assert.Equal(suite.T(), nanBox|0xFF800001, suite.executor.GetFloat(8))
+	assert.Equal(suite.T(), uint32(0), suite.executor.GetCsr(FflagsCsr, suite.csr))
+}
+
+func (suite *InstructionExecutorSuite) TestFloatRoundingModes() {
+	suite.executor.SetFloat(1, boxed(1))
+	suite.executor.SetFloat(2, boxed(3))
+
+	suite.executor.FloatDivide(SinglePrecision, 3, 1, 2, 1) // towards zero
+	assert.Equal(suite.T(), uint64(0xFFFFFFFF3EAAAAAA), suite.executor.GetFloat(3))
+
+	// the dynamic rounding mode is the one in frm
+	suite.executor.CsrReadAndWriteImmediate(0, 3, FrmCsr, suite.csr) // up
+	suite.executor.FloatDivide(SinglePrecision, 3, 1, 2, 7)
+	assert.Equal(suite.T(), uint64(0xFFFFFFFF3EAAAAAB), suite.executor.GetFloat(3))
+	assert.Equal(suite.T(), uint32(3<<5|1), suite.executor.GetCsr(FcsrCsr, suite.csr))
+
+	assert.Panics(suite.T(), func() { suite.executor.FloatDivide(SinglePrecision, 3, 1, 2, 5) })
+	suite.executor.CsrReadAndWriteImmediate(0, 6, FrmCsr, suite.csr)
+	assert.Panics(suite.T(), func() { suite.executor.FloatDivide(SinglePrecision, 3, 1, 2, 7) })
+}
+
+func (suite *InstructionExecutorSuite) TestFloatCsrs() {
+	suite.csr.On("get", mock.Anything)
+	suite.csr.On("set", mock.Anything, mock.Anything)
+
+	suite.executor.CsrReadAndWrite(0, 17, FcsrCsr, suite.csr) // 0b10001
+	suite.executor.CsrReadAndSetImmediate(1, 0x4, FflagsCsr, suite.csr)
+	suite.assertRegisterEquals(1, 0x11)
+	suite.executor.CsrReadAndClear(2, 16, FrmCsr, suite.csr) // no frm bits are set in 16
+	suite.assertRegisterEquals(2, 0)
+	assert.Equal(suite.T(), uint32(0x15), suite.executor.GetCsr(FcsrCsr, suite.csr))
+
+	// fcsr only has 8 bits, and the floating-point CSRs never reach the CSR operator
+	suite.executor.CsrReadAndWrite(0, 0, FcsrCsr, suite.csr)
+	suite.executor.Set(3, 0x1FF)
+	suite.executor.CsrReadAndWrite(0, 3, FcsrCsr, suite.csr)
+	assert.Equal(suite.T(), uint32(0xFF), suite.executor.GetCsr(FcsrCsr, suite.csr))
+	assert.Equal(suite.T(), uint32(0x7), suite.executor.GetCsr(FrmCsr, suite.csr))
+	suite.csr.AssertNotCalled(suite.T(), "get", mock.Anything)
+	suite.csr.AssertNotCalled(suite.T(), "set", mock.Anything, mock.Anything)
+
+	assert.Equal(suite.T(), uint32(22), suite.executor.GetCsr(0x340, suite.csr))
+}
+
+func (suite *InstructionExecutorSuite) TestFloatFlagsAccrue() {
+	suite.executor.SetFloat(1, boxed(1))
+	suite.executor.SetFloat(2, boxed(0))
+
+	suite.executor.FloatDivide(SinglePrecision, 3, 1, 2, 0)
+	assert.Equal(suite.T(), boxed(float32(math.Inf(1))), suite.executor.GetFloat(3))
+	suite.executor.FloatToInt(SinglePrecision, 4, 3, 0)
+	suite.assertRegisterEquals(4, math.MaxInt32)
+	assert.Equal(suite.T(), uint32(0x18), suite.executor.GetCsr(FflagsCsr, suite.csr)) // NV and DZ
+}
+
+func (suite *InstructionExecutorSuite) TestDoublePrecision() {
+	suite.executor.Set(1, 0xFFFFFFFD) // -3
+	suite.executor.IntToFloat(DoublePrecision, 1, 1, 0)
+	assert.Equal(suite.T(), math.Float64bits(-3), suite.executor.GetFloat(1))
+
+	suite.executor.SetFloat(2, math.Float64bits(0.5))
+	suite.executor.SetFloat(3, math.Float64bits(4))
+	suite.executor.FloatMultiplyAdd(DoublePrecision, 4, 2, 3, 1, 0) // 0.5 * 4 + -3
+	assert.Equal(suite.T(), math.Float64bits(-1), suite.executor.GetFloat(4))
+	suite.executor.FloatNegatedMultiplyAdd(DoublePrecision, 4, 2, 3, 1, 0) // -(0.5 * 4) - -3
+	assert.Equal(suite.T(), math.Float64bits(1), suite.executor.GetFloat(4))
+
+	suite.executor.FloatLessThan(DoublePrecision, 5, 1, 4)
+	suite.assertRegisterEquals(5, 1)
+	suite.executor.FloatClassify(DoublePrecision, 6, 1)
+	suite.assertRegisterEquals(6, 1<<1)
+
+	suite.executor.FloatConvert(SinglePrecision, 7, 1, 0)
+	assert.Equal(suite.T(), boxed(-3), suite.executor.GetFloat(7))
+	suite.executor.FloatConvert(DoublePrecision, 8, 7, 0)
+	assert.Equal(suite.T(), math.Float64bits(-3), suite.executor.GetFloat(8))
+
+	assert.Panics(suite.T(), func() { suite.executor.FloatAdd(2, 1, 1, 1, 0) })
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	Util "github.com/chenhowa/computer/lib/binaryInstructionExecution/bitUtils"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
 )
 
 const resultRegister = 30
@@ -78,20 +80,62 @@ func (im *ExecutorInstructionManagerMock) loadAsNextInstructionAddress(newAddres
 	im.pcAddress = newAddress
 }
 
+/*offsetTranslator translates every address to the one `offset` bytes after it*/
+type offsetTranslator struct {
+	mock.Mock
+	offset uint32
+}
+
+func (t *offsetTranslator) translateLoad(address uint32, size uint32) uint32 {
+	t.Called(address, size)
+	return address + t.offset
+}
+
+func (t *offsetTranslator) translateStore(address uint32, size uint32) uint32 {
+	t.Called(address, size)
+	return address + t.offset
+}
+
+func (t *offsetTranslator) fence(address uint32, asid uint32, allAddresses bool, allAsids bool) {
+	t.Called(address, asid, allAddresses, allAsids)
+}
+
+/*swappedPagesTranslator swaps the first two pages, which are 16 bytes long, so that the bytes of an access that
+crosses from one into the other are not next to each other. Like an MMU, it does not translate such an access*/
+type swappedPagesTranslator struct{}
+
+func (swappedPagesTranslator) translateLoad(address uint32, size uint32) uint32 {
+	if address%16+size > 16 {
+		panic(Traps.MakeException(Traps.LoadAddressMisaligned, address, "the load crosses a page boundary"))
+	}
+	return address ^ 16
+}
+
+func (swappedPagesTranslator) translateStore(address uint32, size uint32) uint32 {
+	if address%16+size > 16 {
+		panic(Traps.MakeException(Traps.StoreAddressMisaligned, address, "the store crosses a page boundary"))
+	}
+	return address ^ 16
+}
+
+func (swappedPagesTranslator) fence(address uint32, asid uint32, allAddresses bool, allAsids bool) {
+}
+
 type ExecutorMemoryMock struct {
 	mock.Mock
-	val uint32
+	val uint64
 }
 
-func (m *ExecutorMemoryMock) Get(address uint32) uint32 {
-	m.Called(address)
+func (m *ExecutorMemoryMock) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	m.Called(address, width)
 
-	return m.val
+	return m.val & width.Mask(), nil
 }
 
-func (m *ExecutorMemoryMock) Set(address uint32, val uint32, bits uint) {
-	m.Called(address, val, bits)
+func (m *ExecutorMemoryMock) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	m.Called(address, width, val)
 	m.val = val
+	return nil
 }
 
 func TestInstructionExecutorSuite(t *testing.T) {
@@ -101,7 +145,8 @@ func TestInstructionExecutorSuite(t *testing.T) {
 func (suite *InstructionExecutorSuite) SetupTest() {
 	operator := makeAdaptedOperator([32]uint32{24, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17})
 	suite.executor = RiscVInstructionExecutor{
-		operator: &operator,
+		operator:   &operator,
+		translator: untranslatedAddresses{},
 	}
 
 	memory := ExecutorMemoryMock{
@@ -138,9 +183,9 @@ func (suite *InstructionExecutorSuite) TestLoadWord_Basic() {
 	suite.memory.val = 15
 
 	// basic test of loading a word
-	suite.memory.On("Get", uint32(13))
+	suite.memory.On("Read", uint32(13), Memory.Word)
 	suite.executor.LoadWord(resultRegister, 1, 12, suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(13))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(13), Memory.Word)
 	suite.assertRegisterEquals(resultRegister, uint32(15))
 }
 
@@ -149,16 +194,16 @@ func (suite *InstructionExecutorSuite) TestLoadWord_Advanced() {
 
 	//test 12-bit sign extension is occurring if 12th bit is 1
 	suite.memory.val = 15
-	suite.memory.On("Get", uint32(0)).Return() // duet to overflow.
+	suite.memory.On("Read", uint32(0), Memory.Word).Return() // duet to overflow.
 	suite.executor.LoadWord(resultRegister, 1, uint32(math.MaxUint32), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(0))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(0), Memory.Word)
 	suite.assertRegisterEquals(resultRegister, uint32(15))
 
 	//test 12-bit sign extension is not occurring if 12th bit is 0
 	suite.memory.val = 16
-	suite.memory.On("Get", uint32(1<<11)).Return() // due to overflow.
+	suite.memory.On("Read", uint32(1<<11), Memory.Word).Return() // due to overflow.
 	suite.executor.LoadWord(resultRegister, 1, uint32(math.MaxUint32-(1<<11)), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(1<<11))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(1<<11), Memory.Word)
 	suite.assertRegisterEquals(resultRegister, uint32(16))
 }
 
@@ -166,23 +211,23 @@ func (suite *InstructionExecutorSuite) TestLoadHalfWord() {
 	suite.memory.val = 14
 
 	// basic test of loading a word
-	suite.memory.On("Get", uint32(13))
+	suite.memory.On("Read", uint32(13), Memory.HalfWord)
 	suite.executor.LoadHalfWord(resultRegister, 1, 12, suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(13))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(13), Memory.HalfWord)
 	suite.assertRegisterEquals(resultRegister, uint32(14))
 
 	//test 12-bit sign extension is occurring if 12th bit is 1
 	suite.memory.val = 15
-	suite.memory.On("Get", uint32(0)).Return() // duet to overflow.
+	suite.memory.On("Read", uint32(0), Memory.HalfWord).Return() // duet to overflow.
 	suite.executor.LoadHalfWord(resultRegister, 1, uint32(math.MaxUint32), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(0))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(0), Memory.HalfWord)
 	suite.assertRegisterEquals(resultRegister, uint32(15))
 
 	//test 12-bit sign extension is not occurring if 12th bit is 0
 	suite.memory.val = 16
-	suite.memory.On("Get", uint32(1<<11)).Return() // due to overflow.
+	suite.memory.On("Read", uint32(1<<11), Memory.HalfWord).Return() // due to overflow.
 	suite.executor.LoadHalfWord(resultRegister, 1, uint32(math.MaxUint32-(1<<11)), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(1<<11))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(1<<11), Memory.HalfWord)
 	suite.assertRegisterEquals(resultRegister, uint32(16))
 }
 
@@ -190,23 +235,23 @@ func (suite *InstructionExecutorSuite) TestLoadHalfWordUnsigned() {
 	suite.memory.val = 14
 
 	// basic test of loading a word
-	suite.memory.On("Get", uint32(13))
+	suite.memory.On("Read", uint32(13), Memory.HalfWord)
 	suite.executor.LoadHalfWordUnsigned(resultRegister, 1, 12, suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(13))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(13), Memory.HalfWord)
 	suite.assertRegisterEquals(resultRegister, uint32(14))
 
 	//test 12-bit sign extension is occurring if 12th bit is 1
 	suite.memory.val = 15
-	suite.memory.On("Get", uint32(0)).Return() // duet to overflow.
+	suite.memory.On("Read", uint32(0), Memory.HalfWord).Return() // duet to overflow.
 	suite.executor.LoadHalfWordUnsigned(resultRegister, 1, uint32(math.MaxUint32), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(0))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(0), Memory.HalfWord)
 	suite.assertRegisterEquals(resultRegister, uint32(15))
 
 	//test 12-bit sign extension is not occurring if 12th bit is 0
 	suite.memory.val = 16
-	suite.memory.On("Get", uint32(1<<11)).Return() // due to overflow.
+	suite.memory.On("Read", uint32(1<<11), Memory.HalfWord).Return() // due to overflow.
 	suite.executor.LoadHalfWordUnsigned(resultRegister, 1, uint32(math.MaxUint32-(1<<11)), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(1<<11))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(1<<11), Memory.HalfWord)
 	suite.assertRegisterEquals(resultRegister, uint32(16))
 }
 
@@ -214,23 +259,23 @@ func (suite *InstructionExecutorSuite) TestLoadByte() {
 
 	// basic test of loading a word
 	suite.memory.val = 14
-	suite.memory.On("Get", uint32(13))
+	suite.memory.On("Read", uint32(13), Memory.Byte)
 	suite.executor.LoadByte(resultRegister, 1, 12, suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(13))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(13), Memory.Byte)
 	suite.assertRegisterEquals(resultRegister, uint32(14))
 
 	//test 12-bit sign extension is occurring if 12th bit is 1
 	suite.memory.val = 15
-	suite.memory.On("Get", uint32(0)).Return() // duet to overflow.
+	suite.memory.On("Read", uint32(0), Memory.Byte).Return() // duet to overflow.
 	suite.executor.LoadByte(resultRegister, 1, uint32(math.MaxUint32), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(0))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(0), Memory.Byte)
 	suite.assertRegisterEquals(resultRegister, uint32(15))
 
 	//test 12-bit sign extension is not occurring if 12th bit is 0
 	suite.memory.val = 16
-	suite.memory.On("Get", uint32(1<<11)).Return() // due to overflow.
+	suite.memory.On("Read", uint32(1<<11), Memory.Byte).Return() // due to overflow.
 	suite.executor.LoadByte(resultRegister, 1, uint32(math.MaxUint32-(1<<11)), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(1<<11))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(1<<11), Memory.Byte)
 	suite.assertRegisterEquals(resultRegister, uint32(16))
 }
 
@@ -238,23 +283,23 @@ func (suite *InstructionExecutorSuite) TestLoadByteUnsigned() {
 	suite.memory.val = 14
 
 	// basic test of loading a word
-	suite.memory.On("Get", uint32(13))
+	suite.memory.On("Read", uint32(13), Memory.Byte)
 	suite.executor.LoadByteUnsigned(resultRegister, 1, 12, suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(13))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(13), Memory.Byte)
 	suite.assertRegisterEquals(resultRegister, uint32(14))
 
 	//test 12-bit sign extension is occurring if 12th bit is 1
 	suite.memory.val = 15
-	suite.memory.On("Get", uint32(0)).Return() // duet to overflow.
+	suite.memory.On("Read", uint32(0), Memory.Byte).Return() // duet to overflow.
 	suite.executor.LoadByteUnsigned(resultRegister, 1, uint32(math.MaxUint32), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(0))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(0), Memory.Byte)
 	suite.assertRegisterEquals(resultRegister, uint32(15))
 
 	//test 12-bit sign extension is not occurring if 12th bit is 0
 	suite.memory.val = 16
-	suite.memory.On("Get", uint32(1<<11)).Return() // due to overflow.
+	suite.memory.On("Read", uint32(1<<11), Memory.Byte).Return() // due to overflow.
 	suite.executor.LoadByteUnsigned(resultRegister, 1, uint32(math.MaxUint32-(1<<11)), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Get", uint32(1<<11))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(1<<11), Memory.Byte)
 	suite.assertRegisterEquals(resultRegister, uint32(16))
 }
 
@@ -263,24 +308,24 @@ func (suite *InstructionExecutorSuite) TestStoreWord() {
 
 	//basic test of storing a word
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(13), uint32(14), uint(32))
+	suite.memory.On("Write", uint32(13), Memory.Word, uint64(14))
 	suite.executor.StoreWord(14, 1, 12, suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(13), uint32(14), uint(32))
-	assert.Equal(uint32(14), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(13), Memory.Word, uint64(14))
+	assert.Equal(uint64(14), suite.memory.val)
 
 	//test 12-bit sign extension is occurring if 12th bit is 1
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(0), uint32(15), uint(32)) // due to overflow.
+	suite.memory.On("Write", uint32(0), Memory.Word, uint64(15)) // due to overflow.
 	suite.executor.StoreWord(15, 1, uint32(math.MaxUint32), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(0), uint32(15), uint(32))
-	assert.Equal(uint32(15), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(0), Memory.Word, uint64(15))
+	assert.Equal(uint64(15), suite.memory.val)
 
 	//test 12-bit sign extension is not occurring if 12th bit is 0
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(1<<11), uint32(16), uint(32)) // due to overflow.
+	suite.memory.On("Write", uint32(1<<11), Memory.Word, uint64(16)) // due to overflow.
 	suite.executor.StoreWord(16, 1, uint32(math.MaxUint32-(1<<11)), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(1<<11), uint32(16), uint(32))
-	assert.Equal(uint32(16), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(1<<11), Memory.Word, uint64(16))
+	assert.Equal(uint64(16), suite.memory.val)
 }
 
 func (suite *InstructionExecutorSuite) TestStoreHalfWord() {
@@ -288,24 +333,24 @@ func (suite *InstructionExecutorSuite) TestStoreHalfWord() {
 
 	//basic test of storing a word
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(13), uint32(14), uint(32))
+	suite.memory.On("Write", uint32(13), Memory.HalfWord, uint64(14))
 	suite.executor.StoreHalfWord(14, 1, 12, suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(13), uint32(14), uint(32))
-	assert.Equal(uint32(14), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(13), Memory.HalfWord, uint64(14))
+	assert.Equal(uint64(14), suite.memory.val)
 
 	//test 12-bit sign extension is occurring if 12th bit is 1
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(0), uint32(15), uint(32)) // due to overflow.
+	suite.memory.On("Write", uint32(0), Memory.HalfWord, uint64(15)) // due to overflow.
 	suite.executor.StoreHalfWord(15, 1, uint32(math.MaxUint32), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(0), uint32(15), uint(32))
-	assert.Equal(uint32(15), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(0), Memory.HalfWord, uint64(15))
+	assert.Equal(uint64(15), suite.memory.val)
 
 	//test 12-bit sign extension is not occurring if 12th bit is 0
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(1<<11), uint32(16), uint(32)) // due to overflow.
+	suite.memory.On("Write", uint32(1<<11), Memory.HalfWord, uint64(16)) // due to overflow.
 	suite.executor.StoreHalfWord(16, 1, uint32(math.MaxUint32-(1<<11)), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(1<<11), uint32(16), uint(32))
-	assert.Equal(uint32(16), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(1<<11), Memory.HalfWord, uint64(16))
+	assert.Equal(uint64(16), suite.memory.val)
 }
 
 func (suite *InstructionExecutorSuite) TestStoreByte() {
@@ -313,28 +358,28 @@ func (suite *InstructionExecutorSuite) TestStoreByte() {
 
 	//basic test of storing a word
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(13), uint32(14), uint(32))
+	suite.memory.On("Write", uint32(13), Memory.Byte, uint64(14))
 	suite.executor.StoreByte(14, 1, 12, suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(13), uint32(14), uint(32))
-	assert.Equal(uint32(14), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(13), Memory.Byte, uint64(14))
+	assert.Equal(uint64(14), suite.memory.val)
 
 	//test 12-bit sign extension is occurring if 12th bit is 1
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(0), uint32(15), uint(32)) // due to overflow.
+	suite.memory.On("Write", uint32(0), Memory.Byte, uint64(15)) // due to overflow.
 	suite.executor.StoreByte(15, 1, uint32(math.MaxUint32), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(0), uint32(15), uint(32))
-	assert.Equal(uint32(15), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(0), Memory.Byte, uint64(15))
+	assert.Equal(uint64(15), suite.memory.val)
 
 	//test 12-bit sign extension is not occurring if 12th bit is 0
 	suite.memory.val = 0
-	suite.memory.On("Set", uint32(1<<11), uint32(16), uint(32)) // due to overflow.
+	suite.memory.On("Write", uint32(1<<11), Memory.Byte, uint64(16)) // due to overflow.
 	suite.executor.StoreByte(16, 1, uint32(math.MaxUint32-(1<<11)), suite.memory)
-	suite.memory.AssertCalled(suite.T(), "Set", uint32(1<<11), uint32(16), uint(32))
-	assert.Equal(uint32(16), suite.memory.val)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(1<<11), Memory.Byte, uint64(16))
+	assert.Equal(uint64(16), suite.memory.val)
 }
 
 func (suite *InstructionExecutorSuite) LoadMemoryIntoRegisterX(x uint) {
-	suite.memory.On("Get", mock.Anything)
+	suite.memory.On("Read", mock.Anything, mock.Anything)
 	suite.executor.LoadWord(x, 5, 5, suite.memory)
 }
 
@@ -359,7 +404,7 @@ func (suite *InstructionExecutorSuite) TestSLTI() {
 	suite.assertRegisterEquals(3, 0)
 
 	// Test that the sign-extension of 12th bit works when it is 1
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.assertRegisterEquals(1, math.MaxUint32-1)
 	// this is -2 in two's complement
@@ -368,7 +413,7 @@ func (suite *InstructionExecutorSuite) TestSLTI() {
 	suite.assertRegisterEquals(resultRegister, 1)
 
 	// Test that sign extension does not occur when 12th bit is 0
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.assertRegisterEquals(1, math.MaxUint32-1)                                                              // -1 in two's complement
 	suite.executor.SetLessThanImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10)) // this is some positive number, since no sign extension
@@ -386,14 +431,14 @@ func (suite *InstructionExecutorSuite) TestSLTIUnsigned() {
 	suite.assertRegisterEquals(3, 0)
 
 	// Test that the sign-extension of 12th bit works when it is 1
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.assertRegisterEquals(1, math.MaxUint32-1)
 	suite.executor.SetLessThanImmediateUnsigned(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 11))
 	suite.assertRegisterEquals(resultRegister, 1)
 
 	// Test that sign extension does not occur when 12th bit is 0
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.assertRegisterEquals(1, math.MaxUint32-1)
 	suite.executor.SetLessThanImmediateUnsigned(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10))
@@ -406,13 +451,13 @@ func (suite *InstructionExecutorSuite) TestAndImmediate() {
 	suite.assertRegisterEquals(resultRegister, 14)
 
 	// Test with 11th bit
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.AndImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 11))
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
 
 	// Test without 11th bit
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.AndImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10))
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 10))
@@ -424,13 +469,13 @@ func (suite *InstructionExecutorSuite) TestOrImmediate() {
 	suite.assertRegisterEquals(resultRegister, 15)
 
 	// Test with 11th bit
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.OrImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11))
 	suite.assertRegisterEquals(resultRegister, math.MaxUint32)
 
 	// Test without 11th bit
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 9)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 9))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.OrImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 10, 10))
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10))
@@ -442,13 +487,13 @@ func (suite *InstructionExecutorSuite) TestXorImmediate() {
 	suite.assertRegisterEquals(resultRegister, 13)
 
 	// Test with 11th bit
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.XorImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 10, 11))
 	suite.assertRegisterEquals(resultRegister, ^Util.KeepBitsInInclusiveRange(math.MaxUint32, 10, 10))
 
 	// Test without 11th bit
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 10))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.XorImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 10, 10))
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 9))
@@ -470,13 +515,13 @@ func (suite *InstructionExecutorSuite) TestRightShiftLogicalImmediate() {
 	suite.assertRegisterEquals(resultRegister, 15>>2)
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits, where MSB is 0
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 30)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 30))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.ShiftRightLogicalImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 14))
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits, where MSB is 1
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 17, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 17, 31))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.ShiftRightLogicalImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 15))
@@ -488,13 +533,13 @@ func (suite *InstructionExecutorSuite) TestRightShiftArithmeticImmediate() {
 	suite.assertRegisterEquals(resultRegister, 15>>2)
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits, where MSB is 0
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 30)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 30))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.ShiftRightArithmeticImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 14))
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits, where MSB is 1
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 17, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 17, 31))
 	suite.LoadMemoryIntoRegisterX(1)
 	suite.executor.ShiftRightArithmeticImmediate(resultRegister, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
@@ -581,9 +626,9 @@ func (suite *InstructionExecutorSuite) TestAnd() {
 	suite.assertRegisterEquals(resultRegister, 2)
 
 	//advanced test
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 14, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 14, 31))
 	suite.LoadMemoryIntoRegisterX(1)
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 20)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 20))
 	suite.LoadMemoryIntoRegisterX(2)
 	suite.executor.And(resultRegister, 1, 2)
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 14, 20))
@@ -596,9 +641,9 @@ func (suite *InstructionExecutorSuite) TestOr() {
 
 	//advanced test
 	val := Util.KeepBitsInInclusiveRange(math.MaxUint32, 12, 16)
-	suite.memory.val = val
+	suite.memory.val = uint64(val)
 	suite.LoadMemoryIntoRegisterX(1)
-	suite.memory.val = ^val
+	suite.memory.val = uint64(^val)
 	suite.LoadMemoryIntoRegisterX(2)
 	suite.executor.Or(resultRegister, 1, 2)
 	suite.assertRegisterEquals(resultRegister, math.MaxUint32)
@@ -610,9 +655,9 @@ func (suite *InstructionExecutorSuite) TestXor() {
 	suite.assertRegisterEquals(resultRegister, 9)
 
 	//Advanced test
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 29)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 29))
 	suite.LoadMemoryIntoRegisterX(1)
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 29, 30)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 29, 30))
 	suite.LoadMemoryIntoRegisterX(2)
 	suite.executor.Xor(resultRegister, 1, 2)
 	expected := Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 28) + Util.KeepBitsInInclusiveRange(math.MaxUint32, 30, 30)
@@ -625,7 +670,7 @@ func (suite *InstructionExecutorSuite) TestLeftShiftLogical() {
 	suite.assertRegisterEquals(resultRegister, 1<<5)
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5) // 16 + 32
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.LoadMemoryIntoRegisterX(5)
 	suite.executor.ShiftLeftLogical(resultRegister, 1, 5)
 	suite.assertRegisterEquals(resultRegister, 1<<16)
@@ -637,17 +682,17 @@ func (suite *InstructionExecutorSuite) TestRightShiftLogical() {
 	suite.assertRegisterEquals(resultRegister, 15>>2)
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits, where MSB is 0
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 30)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 30))
 	suite.LoadMemoryIntoRegisterX(1)
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5) // 16 + 32
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.LoadMemoryIntoRegisterX(2)
 	suite.executor.ShiftRightLogical(resultRegister, 1, 2)
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 14))
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits, where MSB is 1
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 17, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 17, 31))
 	suite.LoadMemoryIntoRegisterX(1)
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5) // 16 + 32
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.LoadMemoryIntoRegisterX(2)
 	suite.executor.ShiftRightLogical(resultRegister, 1, 2)
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 15))
@@ -659,25 +704,97 @@ func (suite *InstructionExecutorSuite) TestRightShiftArithmetic() {
 	suite.assertRegisterEquals(resultRegister, 15>>2)
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits, where MSB is 0
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 30)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 30))
 	suite.LoadMemoryIntoRegisterX(1)
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5) // 16 + 32
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.LoadMemoryIntoRegisterX(2)
 	suite.executor.ShiftRightArithmetic(resultRegister, 1, 2)
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 14))
 
 	// Test with number that uses upper 27 bits as well as lower 5 bits, where MSB is 1
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 17, 31)
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 17, 31))
 	suite.LoadMemoryIntoRegisterX(1)
-	suite.memory.val = Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5) // 16 + 32
+	suite.memory.val = uint64(Util.KeepBitsInInclusiveRange(math.MaxUint32, 4, 5)) // 16 + 32
 	suite.LoadMemoryIntoRegisterX(2)
 	suite.executor.ShiftRightArithmetic(resultRegister, 1, 2)
 	suite.assertRegisterEquals(resultRegister, Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 31))
 }
 
+func (suite *InstructionExecutorSuite) TestMultiply() {
+	suite.executor.Multiply(resultRegister, 6, 7)
+	suite.assertRegisterEquals(resultRegister, 42)
+
+	// Only the lower 32 bits of the product are kept
+	suite.executor.Set(1, math.MaxUint32)
+	suite.executor.Multiply(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, math.MaxUint32-1)
+}
+
+func (suite *InstructionExecutorSuite) TestMultiplyHigh() {
+	suite.executor.Set(1, math.MaxUint32) // -1
+	suite.executor.Set(2, 1<<31)          // -2^31 signed, 2^31 unsigned
+
+	suite.executor.MultiplyHigh(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, 0) // 2^31
+	suite.executor.MultiplyHigh(resultRegister, 1, 3)
+	suite.assertRegisterEquals(resultRegister, math.MaxUint32) // -3
+
+	suite.executor.MultiplyHighSignedUnsigned(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, math.MaxUint32) // -2^31
+	suite.executor.MultiplyHighSignedUnsigned(resultRegister, 2, 1)
+	suite.assertRegisterEquals(resultRegister, 1<<31) // -2^31 * (2^32 - 1)
+
+	suite.executor.MultiplyHighUnsigned(resultRegister, 1, 1)
+	suite.assertRegisterEquals(resultRegister, math.MaxUint32-1)
+	suite.executor.MultiplyHighUnsigned(resultRegister, 2, 4)
+	suite.assertRegisterEquals(resultRegister, 2)
+}
+
+func (suite *InstructionExecutorSuite) TestDivide() {
+	suite.executor.Set(1, uint32(0xFFFFFFF9)) // -7
+	suite.executor.Divide(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, uint32(0xFFFFFFFD)) // rounds towards zero
+	suite.executor.Remainder(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, math.MaxUint32) // takes the sign of the dividend
+
+	suite.executor.DivideUnsigned(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, 0x7FFFFFFC)
+	suite.executor.RemainderUnsigned(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, 1)
+
+	// The operands may be overwritten by the result
+	suite.executor.DivideUnsigned(7, 7, 2)
+	suite.assertRegisterEquals(7, 3)
+	suite.executor.RemainderUnsigned(9, 9, 2)
+	suite.assertRegisterEquals(9, 1)
+}
+
+func (suite *InstructionExecutorSuite) TestDivide_ByZero() {
+	suite.executor.Set(1, uint32(0xFFFFFFF9))
+
+	suite.executor.Divide(resultRegister, 1, 0)
+	suite.assertRegisterEquals(resultRegister, math.MaxUint32)
+	suite.executor.DivideUnsigned(resultRegister, 1, 0)
+	suite.assertRegisterEquals(resultRegister, math.MaxUint32)
+	suite.executor.Remainder(resultRegister, 1, 0)
+	suite.assertRegisterEquals(resultRegister, 0xFFFFFFF9)
+	suite.executor.RemainderUnsigned(resultRegister, 1, 0)
+	suite.assertRegisterEquals(resultRegister, 0xFFFFFFF9)
+}
+
+func (suite *InstructionExecutorSuite) TestDivide_Overflow() {
+	suite.executor.Set(1, 1<<31)
+	suite.executor.Set(2, math.MaxUint32)
+
+	suite.executor.Divide(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, 1<<31)
+	suite.executor.Remainder(resultRegister, 1, 2)
+	suite.assertRegisterEquals(resultRegister, 0)
+}
+
 func (suite *InstructionExecutorSuite) TestBranchEqual() {
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31) // bit 13 is dropped, bit 12 is the sign
 
 	// Test if not equal
 	suite.executor.BranchEqual(1, 2, offset, suite.pcManager)
@@ -692,8 +809,8 @@ func (suite *InstructionExecutorSuite) TestBranchEqual() {
 }
 
 func (suite *InstructionExecutorSuite) TestBranchNotEqual() {
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 
 	//Test if equal
 	suite.executor.BranchNotEqual(2, 2, offset, suite.pcManager)
@@ -709,8 +826,8 @@ func (suite *InstructionExecutorSuite) TestBranchNotEqual() {
 }
 
 func (suite *InstructionExecutorSuite) TestBranchLessThan_Basic() {
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 	//Test basic
 	suite.executor.BranchLessThan(2, 2, offset, suite.pcManager)
 	suite.assertManagerAddressEquals(0)
@@ -733,8 +850,8 @@ func (suite *InstructionExecutorSuite) TestBranchLessThan_Advanced() {
 	suite.memory.val = math.MaxUint32 - 1
 	suite.LoadMemoryIntoRegisterX(2)
 
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 
 	suite.executor.BranchLessThan(1, 2, offset, suite.pcManager)
 	suite.assertManagerAddressEquals(0)
@@ -749,8 +866,8 @@ func (suite *InstructionExecutorSuite) TestBranchLessThan_Advanced() {
 }
 
 func (suite *InstructionExecutorSuite) TestBranchLessThanUnsigned_Basic() {
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 	//Test basic
 	suite.executor.BranchLessThanUnsigned(2, 2, offset, suite.pcManager)
 	suite.assertManagerAddressEquals(0)
@@ -773,8 +890,8 @@ func (suite *InstructionExecutorSuite) TestBranchLessThanUnsigned_Advanced() {
 	suite.memory.val = math.MaxUint32 - 1
 	suite.LoadMemoryIntoRegisterX(2)
 
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 
 	suite.executor.BranchLessThanUnsigned(1, 2, offset, suite.pcManager)
 	suite.assertManagerAddressEquals(0)
@@ -789,8 +906,8 @@ func (suite *InstructionExecutorSuite) TestBranchLessThanUnsigned_Advanced() {
 }
 
 func (suite *InstructionExecutorSuite) TestBranchGreaterThanOrEqual_Basic() {
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 	//Test basic
 	suite.executor.BranchGreaterThanOrEqual(1, 2, offset, suite.pcManager)
 	suite.assertManagerAddressEquals(0)
@@ -815,8 +932,8 @@ func (suite *InstructionExecutorSuite) TestBranchGreaterThanOrEqual_Advanced() {
 	suite.memory.val = math.MaxUint32 - 1 // register 2 contains -2
 	suite.LoadMemoryIntoRegisterX(2)
 
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 
 	suite.executor.BranchGreaterThanOrEqual(2, 1, offset, suite.pcManager)
 	suite.assertManagerAddressEquals(0)
@@ -831,8 +948,8 @@ func (suite *InstructionExecutorSuite) TestBranchGreaterThanOrEqual_Advanced() {
 }
 
 func (suite *InstructionExecutorSuite) TestBranchGreaterThanOrEqualUnsigned_Basic() {
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 	//Test basic
 	suite.executor.BranchGreaterThanOrEqualUnsigned(1, 2, offset, suite.pcManager)
 	suite.assertManagerAddressEquals(0)
@@ -857,8 +974,8 @@ func (suite *InstructionExecutorSuite) TestBranchGreaterThanOrEqualUnsigned_Adva
 	suite.memory.val = math.MaxUint32 - 1 // register 2 contains -2
 	suite.LoadMemoryIntoRegisterX(2)
 
-	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 12)
-	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 11)
+	offset := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 13)
+	actual := Util.KeepBitsInInclusiveRange(math.MaxUint32, 11, 31)
 
 	suite.executor.BranchGreaterThanOrEqualUnsigned(2, 1, offset, suite.pcManager)
 	suite.assertManagerAddressEquals(0)
@@ -876,7 +993,7 @@ func (suite *InstructionExecutorSuite) TestJumpAndLink() {
 	suite.pcManager.pcAddress = 1
 	suite.pcManager.On("addOffsetForNextInstructionAddress", uint32(math.MaxUint32))
 	suite.pcManager.On("getNextInstructionAddress")
-	suite.executor.JumpAndLink(1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 19), suite.pcManager)
+	suite.executor.JumpAndLink(1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 0, 20), suite.pcManager)
 	suite.assertRegisterEquals(1, 1+4)
 	suite.assertManagerAddressEquals(0) // 1 + math.MaxUint32
 	suite.pcManager.AssertCalled(suite.T(), "addOffsetForNextInstructionAddress", uint32(math.MaxUint32))
@@ -905,15 +1022,200 @@ func (suite *InstructionExecutorSuite) TestJumpAndLinkRegister() {
 	suite.executor.JumpAndLinkRegister(30, 1, Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 10), suite.pcManager)
 	suite.assertRegisterEquals(30, 2+4)
 	suite.assertManagerAddressEquals(Util.KeepBitsInInclusiveRange(math.MaxUint32, 1, 10))
+
+	// the target is computed from the base register before the link overwrites it
+	suite.pcManager.pcAddress = 8
+	suite.executor.Set(5, 0x100)
+	suite.pcManager.On("loadAsNextInstructionAddress", uint32(0x108))
+	suite.executor.JumpAndLinkRegister(5, 5, 8, suite.pcManager)
+	suite.assertRegisterEquals(5, 8+4)
+	suite.assertManagerAddressEquals(0x108)
+}
+
+func (suite *InstructionExecutorSuite) TestLoadReservedAndStoreConditional() {
+	suite.memory.On("Read", mock.Anything, mock.Anything)
+	suite.memory.On("Write", mock.Anything, mock.Anything, mock.Anything)
+	suite.memory.val = 99
+
+	// Without a reservation, the store does not happen
+	suite.executor.StoreConditional(resultRegister, 4, 5, suite.memory)
+	suite.assertRegisterEquals(resultRegister, 1)
+	suite.memory.AssertNotCalled(suite.T(), "Write", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.executor.LoadReserved(resultRegister, 4, suite.memory)
+	suite.assertRegisterEquals(resultRegister, 99)
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(4), Memory.Word)
+
+	// A reservation on another address does not count
+	suite.executor.StoreConditional(resultRegister, 8, 5, suite.memory)
+	suite.assertRegisterEquals(resultRegister, 1)
+	suite.memory.AssertNotCalled(suite.T(), "Write", mock.Anything, mock.Anything, mock.Anything)
+
+	// and is used up by the failed store
+	suite.executor.StoreConditional(resultRegister, 4, 5, suite.memory)
+	suite.assertRegisterEquals(resultRegister, 1)
+
+	suite.executor.LoadReserved(resultRegister, 4, suite.memory)
+	suite.executor.StoreConditional(resultRegister, 4, 5, suite.memory)
+	suite.assertRegisterEquals(resultRegister, 0)
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(4), Memory.Word, uint64(5))
+
+	suite.executor.StoreConditional(resultRegister, 4, 5, suite.memory)
+	suite.assertRegisterEquals(resultRegister, 1)
+}
+
+func (suite *InstructionExecutorSuite) TestAtomicOperations() {
+	suite.memory.On("Read", mock.Anything, mock.Anything)
+	suite.memory.On("Write", mock.Anything, mock.Anything, mock.Anything)
+	suite.executor.Set(9, math.MaxUint32) // -1
+
+	tests := []struct {
+		operation func(dest uint, reg uint, src uint, memory Memory.ReadWriter)
+		src       uint
+		expected  uint32
+	}{
+		{suite.executor.AtomicSwap, 5, 5},
+		{suite.executor.AtomicAdd, 5, 17},
+		{suite.executor.AtomicXor, 5, 9},
+		{suite.executor.AtomicAnd, 5, 4},
+		{suite.executor.AtomicOr, 5, 13},
+		{suite.executor.AtomicMin, 9, math.MaxUint32},
+		{suite.executor.AtomicMin, 5, 5},
+		{suite.executor.AtomicMax, 9, 12},
+		{suite.executor.AtomicMinUnsigned, 9, 12},
+		{suite.executor.AtomicMaxUnsigned, 9, math.MaxUint32},
+	}
+
+	for _, test := range tests {
+		suite.memory.val = 12
+		test.operation(resultRegister, 4, test.src, suite.memory)
+		suite.assertRegisterEquals(resultRegister, 12)
+		suite.memory.AssertCalled(suite.T(), "Read", uint32(4), Memory.Word)
+		assert.Equal(suite.T(), uint64(test.expected), suite.memory.val)
+	}
+
+	// The source register is read before the original word is written over it
+	suite.memory.val = 12
+	suite.executor.AtomicAdd(5, 4, 5, suite.memory)
+	suite.assertRegisterEquals(5, 12)
+	assert.Equal(suite.T(), uint64(17), suite.memory.val)
+}
+
+func (suite *InstructionExecutorSuite) TestAtomicsNeedAlignedWords() {
+	assertMisaligned := func(cause Traps.Cause, access func()) {
+		defer func() {
+			exception, ok := recover().(Traps.Exception)
+			assert.True(suite.T(), ok)
+			assert.Equal(suite.T(), cause, exception.Cause)
+			assert.Equal(suite.T(), uint32(3), exception.Value)
+		}()
+		access()
+	}
+
+	assertMisaligned(Traps.LoadAddressMisaligned, func() { suite.executor.LoadReserved(resultRegister, 3, suite.memory) })
+	assertMisaligned(Traps.StoreAddressMisaligned, func() { suite.executor.StoreConditional(resultRegister, 3, 5, suite.memory) })
+	assertMisaligned(Traps.StoreAddressMisaligned, func() { suite.executor.AtomicAdd(resultRegister, 3, 5, suite.memory) })
+	suite.memory.AssertNotCalled(suite.T(), "Read", mock.Anything, mock.Anything)
+}
+
+func (suite *InstructionExecutorSuite) TestMisalignedAccessPolicies() {
+	assertRaises := func(cause Traps.Cause, address uint32, access func()) {
+		defer func() {
+			exception, ok := recover().(Traps.Exception)
+			assert.True(suite.T(), ok)
+			assert.Equal(suite.T(), cause, exception.Cause)
+			assert.Equal(suite.T(), address, exception.Value)
+		}()
+		access()
+	}
+	suite.memory.On("Read", mock.Anything, mock.Anything)
+	suite.memory.On("Write", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.executor.LoadWord(resultRegister, 3, 0, suite.memory) // allowed by default
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(3), Memory.Word)
+	suite.executor.LoadByte(resultRegister, 3, 0, suite.memory) // bytes are always aligned
+	assertRaises(Traps.LoadAddressMisaligned, 3, func() { suite.executor.LoadReserved(resultRegister, 3, suite.memory) })
+
+	suite.executor.SetMisalignedAccessPolicy(TrapMisaligned)
+	assertRaises(Traps.LoadAddressMisaligned, 3, func() { suite.executor.LoadHalfWord(resultRegister, 3, 0, suite.memory) })
+	assertRaises(Traps.StoreAddressMisaligned, 2, func() { suite.executor.StoreWord(5, 2, 0, suite.memory) })
+	assertRaises(Traps.StoreAddressMisaligned, 4, func() { suite.executor.StoreDouble(5, 4, 0, suite.memory) })
+
+	suite.executor.SetMisalignedAccessPolicy(FaultMisaligned)
+	assertRaises(Traps.LoadAccessFault, 6, func() { suite.executor.LoadFloat(5, 6, 0, suite.memory) })
+	assertRaises(Traps.StoreAccessFault, 3, func() { suite.executor.AtomicAdd(resultRegister, 3, 5, suite.memory) })
+	suite.memory.AssertNumberOfCalls(suite.T(), "Read", 2)
+	suite.memory.AssertNotCalled(suite.T(), "Write", mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Equal(suite.T(), MisalignedAccessStatistics{Loads: 4, Stores: 3}, suite.executor.GetMisalignedAccessStatistics())
+}
+
+func (suite *InstructionExecutorSuite) TestAllowedMisalignedAccessesCrossPages() {
+	memory := Memory.MakeBasicMemory(0xFF)
+	suite.executor.UseAddressTranslator(swappedPagesTranslator{})
+	suite.executor.Set(3, 14)
+	suite.executor.Set(5, 0x44332211)
+
+	suite.executor.StoreWord(5, 3, 0, &memory)
+	low, _ := memory.Read(30, Memory.HalfWord)
+	high, _ := memory.Read(0, Memory.HalfWord)
+	assert.Equal(suite.T(), uint64(0x2211), low)
+	assert.Equal(suite.T(), uint64(0x4433), high)
+
+	suite.executor.LoadWord(resultRegister, 3, 0, &memory)
+	suite.assertRegisterEquals(resultRegister, 0x44332211)
+	suite.executor.LoadHalfWordUnsigned(resultRegister, 3, 1, &memory)
+	suite.assertRegisterEquals(resultRegister, 0x3322)
+	assert.Equal(suite.T(), MisalignedAccessStatistics{Loads: 2, Stores: 1}, suite.executor.GetMisalignedAccessStatistics())
+}
+
+func (suite *InstructionExecutorSuite) TestMemoryAccessesUseTranslatedAddresses() {
+	suite.memory.On("Read", mock.Anything, mock.Anything)
+	suite.memory.On("Write", mock.Anything, mock.Anything, mock.Anything)
+	translator := offsetTranslator{offset: 0x100}
+	translator.On("translateLoad", mock.Anything, mock.Anything)
+	translator.On("translateStore", mock.Anything, mock.Anything)
+	suite.executor.UseAddressTranslator(&translator)
+
+	suite.executor.LoadHalfWord(resultRegister, 4, 2, suite.memory)
+	translator.AssertCalled(suite.T(), "translateLoad", uint32(6), uint32(2))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(0x106), Memory.HalfWord)
+
+	suite.executor.StoreByte(5, 4, 1, suite.memory)
+	translator.AssertCalled(suite.T(), "translateStore", uint32(5), uint32(1))
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(0x105), Memory.Byte, uint64(5))
+
+	suite.executor.LoadReserved(resultRegister, 8, suite.memory)
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(0x108), Memory.Word)
+	suite.executor.StoreConditional(resultRegister, 8, 5, suite.memory)
+	suite.assertRegisterEquals(resultRegister, 0) // the reservation is on the virtual address
+	suite.memory.AssertCalled(suite.T(), "Write", uint32(0x108), Memory.Word, uint64(5))
+
+	suite.executor.AtomicAdd(resultRegister, 12, 5, suite.memory)
+	translator.AssertCalled(suite.T(), "translateStore", uint32(12), uint32(4))
+	suite.memory.AssertCalled(suite.T(), "Read", uint32(0x10C), Memory.Word)
+}
+
+func (suite *InstructionExecutorSuite) TestFenceVirtualMemory() {
+	translator := offsetTranslator{}
+	translator.On("fence", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.executor.UseAddressTranslator(&translator)
+
+	suite.executor.FenceVirtualMemory(4, 5)
+	translator.AssertCalled(suite.T(), "fence", uint32(4), uint32(5), false, false)
+	suite.executor.FenceVirtualMemory(0, 5)
+	translator.AssertCalled(suite.T(), "fence", mock.Anything, uint32(5), true, false)
+	suite.executor.FenceVirtualMemory(4, 0)
+	translator.AssertCalled(suite.T(), "fence", uint32(4), mock.Anything, false, true)
 }
 
 func (suite *InstructionExecutorSuite) TestCsrReadAndWrite() {
 	suite.csr.val = 15
 	suite.csr.On("get", mock.Anything)
-	suite.csr.On("set", uint(3), uint32(2))
-	suite.executor.CsrReadAndWrite(0, 2, 3, suite.csr)
+	suite.csr.On("set", uint(5), uint32(2))
+	suite.executor.CsrReadAndWrite(0, 2, 5, suite.csr)
 	suite.csr.AssertNotCalled(suite.T(), "get", mock.Anything)
-	suite.csr.AssertCalled(suite.T(), "set", uint(3), uint32(2))
+	suite.csr.AssertCalled(suite.T(), "set", uint(5), uint32(2))
 	suite.assertRegisterEquals(2, 2)
 	suite.assertRegisterEquals(0, 0)
 
@@ -937,11 +1239,11 @@ func (suite *InstructionExecutorSuite) TestCsrReadAndSet() {
 	suite.assertRegisterEquals(30, 4)
 
 	suite.csr.val = 8 // 0b1000
-	suite.csr.On("get", uint(3))
-	suite.csr.On("set", uint(3), uint32(10))
-	suite.executor.CsrReadAndSet(30, 2, 3, suite.csr)
-	suite.csr.AssertCalled(suite.T(), "get", uint(3))
-	suite.csr.AssertCalled(suite.T(), "set", uint(3), uint32(10))
+	suite.csr.On("get", uint(5))
+	suite.csr.On("set", uint(5), uint32(10))
+	suite.executor.CsrReadAndSet(30, 2, 5, suite.csr)
+	suite.csr.AssertCalled(suite.T(), "get", uint(5))
+	suite.csr.AssertCalled(suite.T(), "set", uint(5), uint32(10))
 	suite.assertRegisterEquals(30, 8)
 
 }
@@ -956,21 +1258,21 @@ func (suite *InstructionExecutorSuite) TestCsrReadAndClear() {
 	suite.assertRegisterEquals(30, 14)
 
 	suite.csr.val = 15 // 0b1111
-	suite.csr.On("get", uint(3))
-	suite.csr.On("set", uint(3), uint32(13))
-	suite.executor.CsrReadAndClear(30, 2, 3, suite.csr)
-	suite.csr.AssertCalled(suite.T(), "get", uint(3))
-	suite.csr.AssertCalled(suite.T(), "set", uint(3), uint32(13))
+	suite.csr.On("get", uint(5))
+	suite.csr.On("set", uint(5), uint32(13))
+	suite.executor.CsrReadAndClear(30, 2, 5, suite.csr)
+	suite.csr.AssertCalled(suite.T(), "get", uint(5))
+	suite.csr.AssertCalled(suite.T(), "set", uint(5), uint32(13))
 	suite.assertRegisterEquals(30, 15)
 }
 
 func (suite *InstructionExecutorSuite) TestCsrReadAndWriteImmediate() {
 	suite.csr.val = 15
 	suite.csr.On("get", mock.Anything)
-	suite.csr.On("set", uint(3), uint32(2))
-	suite.executor.CsrReadAndWriteImmediate(0, 66, 3, suite.csr) //use immediate 0b100010, to show that the 6th bit is not used.
+	suite.csr.On("set", uint(5), uint32(2))
+	suite.executor.CsrReadAndWriteImmediate(0, 66, 5, suite.csr) //use immediate 0b100010, to show that the 6th bit is not used.
 	suite.csr.AssertNotCalled(suite.T(), "get", mock.Anything)
-	suite.csr.AssertCalled(suite.T(), "set", uint(3), uint32(2))
+	suite.csr.AssertCalled(suite.T(), "set", uint(5), uint32(2))
 	suite.assertRegisterEquals(2, 2)
 	suite.assertRegisterEquals(0, 0)
 
@@ -994,11 +1296,11 @@ func (suite *InstructionExecutorSuite) TestCsrReadAndSetImmediate() {
 	suite.assertRegisterEquals(30, 4)
 
 	suite.csr.val = 8 // 0b1000
-	suite.csr.On("get", uint(3))
-	suite.csr.On("set", uint(3), uint32(10))
-	suite.executor.CsrReadAndSetImmediate(30, 66, 3, suite.csr) // use immediate 0b100010, to show that the 6th bit is not used
-	suite.csr.AssertCalled(suite.T(), "get", uint(3))
-	suite.csr.AssertCalled(suite.T(), "set", uint(3), uint32(10))
+	suite.csr.On("get", uint(5))
+	suite.csr.On("set", uint(5), uint32(10))
+	suite.executor.CsrReadAndSetImmediate(30, 66, 5, suite.csr) // use immediate 0b100010, to show that the 6th bit is not used
+	suite.csr.AssertCalled(suite.T(), "get", uint(5))
+	suite.csr.AssertCalled(suite.T(), "set", uint(5), uint32(10))
 	suite.assertRegisterEquals(30, 8)
 }
 
@@ -1012,11 +1314,11 @@ func (suite *InstructionExecutorSuite) TestCsrReadAndClearImmediate() {
 	suite.assertRegisterEquals(30, 14)
 
 	suite.csr.val = 15 // 0b1111
-	suite.csr.On("get", uint(3))
-	suite.csr.On("set", uint(3), uint32(13))
-	suite.executor.CsrReadAndClearImmediate(30, 66, 3, suite.csr) // use immediate 0b100010, to show that the 6th bit is not used
-	suite.csr.AssertCalled(suite.T(), "get", uint(3))
-	suite.csr.AssertCalled(suite.T(), "set", uint(3), uint32(13))
+	suite.csr.On("get", uint(5))
+	suite.csr.On("set", uint(5), uint32(13))
+	suite.executor.CsrReadAndClearImmediate(30, 66, 5, suite.csr) // use immediate 0b100010, to show that the 6th bit is not used
+	suite.csr.AssertCalled(suite.T(), "get", uint(5))
+	suite.csr.AssertCalled(suite.T(), "set", uint(5), uint32(13))
 	suite.assertRegisterEquals(30, 15)
 }
 
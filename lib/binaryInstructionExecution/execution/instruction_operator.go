@@ -1,9 +1,9 @@
 package execution
 
 import (
-	"math"
-
 	Operator "github.com/chenhowa/computer/lib/binaryInstructionExecution/execution/operators"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
 )
 
 /*adaptedOperator is a an adapter for the imported Operator struct to help fit
@@ -23,94 +23,44 @@ func makeAdaptedOperator(regs [32]uint32) adaptedOperator {
 	return adapted
 }
 
-/*adaptedMemory is an adapter for structs of types `instructionReadMemory`, `instructionWriteMemory`,
-and `instructionReadWriteMemory`, to help the struct fit the constraints of the imported Operator struct.
-*/
-type adaptedMemory struct {
-	rMemory instructionReadMemory
-	wMemory instructionWriteMemory
-}
-
-func (m *adaptedMemory) Get(address uint16) uint32 {
-	return m.rMemory.Get(uint32(address))
-}
-
-func (m *adaptedMemory) Set(address uint16, value uint32, bitsToSet uint) {
-	m.wMemory.Set(uint32(address), value, bitsToSet)
-}
-
-/*panicIfOutsideMemory will panic if the uint32 `address` cannot be casted to
-uint16 without losing bits. Since the Operator only supports 16-bit addresses.
-*/
-func panicIfOutsideMemory(address uint32) {
-	if address > uint32(math.MaxUint16) {
-		panic("address outside uint16 actual memory space")
+/*panicIfAccessFailed panics with an access fault exception of `cause` at `address`, if the access
+to memory there returned the error `err`*/
+func panicIfAccessFailed(err error, address uint32, cause Traps.Cause) {
+	if err != nil {
+		panic(Traps.MakeException(cause, address, err.Error()))
 	}
 }
 
-func (op *adaptedOperator) loadWord(dest uint, address uint32, memory instructionReadMemory) {
-	panicIfOutsideMemory(address)
-	m := adaptedMemory{
-		rMemory: memory,
-	}
-	op.operator.Load_word(dest, uint16(address), &m)
-
+func (op *adaptedOperator) loadWord(dest uint, address uint32, memory Memory.Reader) {
+	panicIfAccessFailed(op.operator.Load_word(dest, address, memory), address, Traps.LoadAccessFault)
 }
 
-func (op *adaptedOperator) loadHalfWord(dest uint, address uint32, memory instructionReadMemory) {
-	panicIfOutsideMemory(address)
-	m := adaptedMemory{
-		rMemory: memory,
-	}
-	op.operator.Load_halfword(dest, uint16(address), &m)
+func (op *adaptedOperator) loadHalfWord(dest uint, address uint32, memory Memory.Reader) {
+	panicIfAccessFailed(op.operator.Load_halfword(dest, address, memory), address, Traps.LoadAccessFault)
 }
 
-func (op *adaptedOperator) loadHalfWordUnsigned(dest uint, address uint32, memory instructionReadMemory) {
-	panicIfOutsideMemory(address)
-	m := adaptedMemory{
-		rMemory: memory,
-	}
-	op.operator.Load_halfword_unsigned(dest, uint16(address), &m)
+func (op *adaptedOperator) loadHalfWordUnsigned(dest uint, address uint32, memory Memory.Reader) {
+	panicIfAccessFailed(op.operator.Load_halfword_unsigned(dest, address, memory), address, Traps.LoadAccessFault)
 }
 
-func (op *adaptedOperator) loadByte(dest uint, address uint32, memory instructionReadMemory) {
-	panicIfOutsideMemory(address)
-	m := adaptedMemory{
-		rMemory: memory,
-	}
-	op.operator.Load_byte(dest, uint16(address), &m)
+func (op *adaptedOperator) loadByte(dest uint, address uint32, memory Memory.Reader) {
+	panicIfAccessFailed(op.operator.Load_byte(dest, address, memory), address, Traps.LoadAccessFault)
 }
 
-func (op *adaptedOperator) loadByteUnsigned(dest uint, address uint32, memory instructionReadMemory) {
-	panicIfOutsideMemory(address)
-	m := adaptedMemory{
-		rMemory: memory,
-	}
-	op.operator.Load_byte_unsigned(dest, uint16(address), &m)
+func (op *adaptedOperator) loadByteUnsigned(dest uint, address uint32, memory Memory.Reader) {
+	panicIfAccessFailed(op.operator.Load_byte_unsigned(dest, address, memory), address, Traps.LoadAccessFault)
 }
 
-func (op *adaptedOperator) storeWord(src uint, address uint32, memory instructionWriteMemory) {
-	panicIfOutsideMemory(address)
-	m := adaptedMemory{
-		wMemory: memory,
-	}
-	op.operator.Store_word(src, uint16(address), &m)
+func (op *adaptedOperator) storeWord(src uint, address uint32, memory Memory.Writer) {
+	panicIfAccessFailed(op.operator.Store_word(src, address, memory), address, Traps.StoreAccessFault)
 }
 
-func (op *adaptedOperator) storeHalfWord(src uint, address uint32, memory instructionWriteMemory) {
-	panicIfOutsideMemory(address)
-	m := adaptedMemory{
-		wMemory: memory,
-	}
-	op.operator.Store_word(src, uint16(address), &m)
+func (op *adaptedOperator) storeHalfWord(src uint, address uint32, memory Memory.Writer) {
+	panicIfAccessFailed(op.operator.Store_halfword(src, address, memory), address, Traps.StoreAccessFault)
 }
 
-func (op *adaptedOperator) storeByte(src uint, address uint32, memory instructionWriteMemory) {
-	panicIfOutsideMemory(address)
-	m := adaptedMemory{
-		wMemory: memory,
-	}
-	op.operator.Store_word(src, uint16(address), &m)
+func (op *adaptedOperator) storeByte(src uint, address uint32, memory Memory.Writer) {
+	panicIfAccessFailed(op.operator.Store_byte(src, address, memory), address, Traps.StoreAccessFault)
 }
 
 func (op *adaptedOperator) add(dest uint, reg1 uint, reg2 uint) {
@@ -167,3 +117,27 @@ func (op *adaptedOperator) divide(destDividend uint, destRem uint, reg1 uint, re
 func (op *adaptedOperator) get(reg uint) uint32 {
 	return op.operator.Get(reg)
 }
+
+func (op *adaptedOperator) loadFloat(dest uint, address uint32, memory Memory.Reader) {
+	panicIfAccessFailed(op.operator.Load_float(dest, address, memory), address, Traps.LoadAccessFault)
+}
+
+func (op *adaptedOperator) loadDouble(dest uint, address uint32, memory Memory.Reader) {
+	panicIfAccessFailed(op.operator.Load_double(dest, address, memory), address, Traps.LoadAccessFault)
+}
+
+func (op *adaptedOperator) storeFloat(src uint, address uint32, memory Memory.Writer) {
+	panicIfAccessFailed(op.operator.Store_float(src, address, memory), address, Traps.StoreAccessFault)
+}
+
+func (op *adaptedOperator) storeDouble(src uint, address uint32, memory Memory.Writer) {
+	panicIfAccessFailed(op.operator.Store_double(src, address, memory), address, Traps.StoreAccessFault)
+}
+
+func (op *adaptedOperator) getFloat(reg uint) uint64 {
+	return op.operator.Get_float(reg)
+}
+
+func (op *adaptedOperator) setFloat(reg uint, val uint64) {
+	op.operator.Set_float(reg, val)
+}
@@ -4,6 +4,8 @@ import (
 	"math"
 	"testing"
 
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -15,15 +17,19 @@ type OperatorSuite struct {
 }
 
 type MemoryMock struct {
-	val uint32
+	val uint64
+	err error
 }
 
-func (m *MemoryMock) Get(address uint32) uint32 {
-	return m.val
+func (m *MemoryMock) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	return m.val & width.Mask(), m.err
 }
 
-func (m *MemoryMock) Set(address uint32, val uint32, bits uint) {
-	m.val = val
+func (m *MemoryMock) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	if m.err == nil {
+		m.val = val & width.Mask()
+	}
+	return m.err
 }
 
 func (suite *OperatorSuite) SetupTest() {
@@ -46,7 +52,7 @@ func (suite *OperatorSuite) TestAdd() {
 	var assert = assert.New(suite.T())
 
 	var startValue uint32 = 10
-	suite.memory.val = startValue
+	suite.memory.val = uint64(startValue)
 	suite.operator.loadWord(0, 5, &suite.memory)
 	suite.operator.loadWord(15, 5, &suite.memory)
 
@@ -59,7 +65,7 @@ func (suite *OperatorSuite) TestSub() {
 
 	suite.operator.sub(0, 3, 1)
 	suite.operator.storeWord(0, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(2))
+	assert.Equal(suite.memory.val, uint64(2))
 }
 
 func (suite *OperatorSuite) TestBitAnd() {
@@ -67,7 +73,7 @@ func (suite *OperatorSuite) TestBitAnd() {
 
 	suite.operator.and(0, 1, 3)
 	suite.operator.storeWord(0, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(1))
+	assert.Equal(suite.memory.val, uint64(1))
 }
 
 func (suite *OperatorSuite) TestBitOr() {
@@ -75,7 +81,7 @@ func (suite *OperatorSuite) TestBitOr() {
 
 	suite.operator.or(0, 1, 2)
 	suite.operator.storeWord(0, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(3))
+	assert.Equal(suite.memory.val, uint64(3))
 }
 
 func (suite *OperatorSuite) TestBitXor() {
@@ -89,7 +95,7 @@ func (suite *OperatorSuite) TestMultiply() {
 	var assert = assert.New(suite.T())
 	suite.operator.multiply(0, 2, 3)
 	suite.operator.storeWord(0, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(6))
+	assert.Equal(suite.memory.val, uint64(6))
 }
 
 func (suite *OperatorSuite) TestDivide() {
@@ -176,7 +182,7 @@ func (suite *OperatorSuite) TestStoreWord() {
 	suite.memory.val = 0
 	suite.operator.storeWord(10, 3, &suite.memory)
 
-	assert.Equal(uint32(10), suite.memory.val)
+	assert.Equal(uint64(10), suite.memory.val)
 }
 
 func (suite *OperatorSuite) TestStoreHalfWord() {
@@ -184,7 +190,7 @@ func (suite *OperatorSuite) TestStoreHalfWord() {
 	suite.memory.val = 0
 	suite.operator.storeHalfWord(10, 3, &suite.memory)
 
-	assert.Equal(uint32(10), suite.memory.val)
+	assert.Equal(uint64(10), suite.memory.val)
 }
 
 func (suite *OperatorSuite) TestStoreByte() {
@@ -192,5 +198,22 @@ func (suite *OperatorSuite) TestStoreByte() {
 	suite.memory.val = 0
 	suite.operator.storeByte(10, 3, &suite.memory)
 
-	assert.Equal(uint32(10), suite.memory.val)
+	assert.Equal(uint64(10), suite.memory.val)
+}
+
+func (suite *OperatorSuite) TestFailedAccessesAreAccessFaults() {
+	assertFault := func(cause Traps.Cause, access func()) {
+		defer func() {
+			exception, ok := recover().(Traps.Exception)
+			assert.True(suite.T(), ok)
+			assert.Equal(suite.T(), cause, exception.Cause)
+			assert.Equal(suite.T(), uint32(0x80000000), exception.Value)
+		}()
+		access()
+	}
+	suite.memory.err = Memory.ErrAddressOutOfRange
+
+	assertFault(Traps.LoadAccessFault, func() { suite.operator.loadHalfWord(0, 0x80000000, &suite.memory) })
+	assertFault(Traps.LoadAccessFault, func() { suite.operator.loadDouble(0, 0x80000000, &suite.memory) })
+	assertFault(Traps.StoreAccessFault, func() { suite.operator.storeByte(1, 0x80000000, &suite.memory) })
 }
@@ -0,0 +1,168 @@
+package execution
+
+import (
+	"fmt"
+
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
+)
+
+/*MisalignedAccessPolicy is what an executor does with a load or a store whose address is not a multiple
+of the number of bytes that it accesses. The spec lets an implementation choose*/
+type MisalignedAccessPolicy uint
+
+/*These constants are the MisalignedAccessPolicy choices. AllowMisaligned accesses the bytes as they are,
+like hardware that supports misaligned accesses does. Each byte is translated on its own, so the access can
+cross into another page, even though the MMU would raise a misaligned exception for it as a whole. TrapMisaligned raises a misaligned exception, so that
+the trap handler can emulate the access, and FaultMisaligned raises an access fault, like a strict core would.
+Atomic accesses are never allowed to be misaligned, so AllowMisaligned raises a misaligned exception for them*/
+const (
+	AllowMisaligned MisalignedAccessPolicy = iota
+	TrapMisaligned
+	FaultMisaligned
+)
+
+/*MisalignedAccessStatistics counts the loads and stores whose addresses were misaligned, whether they were
+allowed or not. Atomic accesses that read and write count as stores*/
+type MisalignedAccessStatistics struct {
+	Loads  uint
+	Stores uint
+}
+
+/*SetMisalignedAccessPolicy chooses what misaligned loads and stores do from now on. By default, they are allowed*/
+func (ex *RiscVInstructionExecutor) SetMisalignedAccessPolicy(policy MisalignedAccessPolicy) {
+	ex.misalignedPolicy = policy
+}
+
+/*GetMisalignedAccessStatistics returns the number of misaligned loads and stores that have been executed*/
+func (ex *RiscVInstructionExecutor) GetMisalignedAccessStatistics() MisalignedAccessStatistics {
+	return ex.misaligned
+}
+
+/*loadAddress checks the alignment of a load of `size` bytes at the virtual `address`, and translates it.
+It returns the physical address to load from, and the memory to load it from, which is `memory` unless the load
+is misaligned and its bytes are not next to each other in physical memory*/
+func (ex *RiscVInstructionExecutor) loadAddress(address uint32, size uint32, memory Memory.Reader) (uint32, Memory.Reader) {
+	if !ex.checkAlignment(address, size, ex.misalignedPolicy, Traps.LoadAddressMisaligned, Traps.LoadAccessFault) {
+		return ex.translator.translateLoad(address, size), memory
+	}
+
+	physical := translateBytes(address, size, ex.translator.translateLoad)
+	if contiguous(physical) {
+		return physical[0], memory
+	}
+	return physical[0], scatteredReader{memory: memory, addresses: physical}
+}
+
+/*storeAddress checks the alignment of a store of `size` bytes at the virtual `address`, and translates it.
+It returns the physical address to store to, and the memory to store it to, which is `memory` unless the store
+is misaligned and its bytes are not next to each other in physical memory*/
+func (ex *RiscVInstructionExecutor) storeAddress(address uint32, size uint32, memory Memory.Writer) (uint32, Memory.Writer) {
+	if !ex.checkAlignment(address, size, ex.misalignedPolicy, Traps.StoreAddressMisaligned, Traps.StoreAccessFault) {
+		return ex.translator.translateStore(address, size), memory
+	}
+
+	physical := translateBytes(address, size, ex.translator.translateStore)
+	if contiguous(physical) {
+		return physical[0], memory
+	}
+	return physical[0], scatteredWriter{memory: memory, addresses: physical}
+}
+
+/*translateBytes returns the physical addresses of the `size` bytes at the virtual `address`, which are each
+translated on their own by `translate`*/
+func translateBytes(address uint32, size uint32, translate func(address uint32, size uint32) uint32) []uint32 {
+	physical := make([]uint32, size)
+	for i := range physical {
+		physical[i] = translate(address+uint32(i), 1)
+	}
+	return physical
+}
+
+/*contiguous returns whether the `physical` addresses of the bytes of an access are next to each other*/
+func contiguous(physical []uint32) bool {
+	for i, address := range physical {
+		if address != physical[0]+uint32(i) {
+			return false
+		}
+	}
+	return true
+}
+
+/*scatteredReader is the memory that a load reads when its bytes are scattered over physical memory.
+Byte i of the load, at addresses[0] + i, is read from addresses[i] of `memory`*/
+type scatteredReader struct {
+	memory    Memory.Reader
+	addresses []uint32
+}
+
+func (r scatteredReader) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	offset := address - r.addresses[0]
+	if err := Memory.CheckAccess(offset, width, uint(len(r.addresses))); err != nil {
+		return 0, err
+	}
+
+	var val uint64
+	for i := uint32(0); i < uint32(width); i++ {
+		b, err := r.memory.Read(r.addresses[offset+i], Memory.Byte)
+		if err != nil {
+			return 0, err
+		}
+		val |= b << (8 * i)
+	}
+	return val, nil
+}
+
+/*scatteredWriter is the memory that a store writes when its bytes are scattered over physical memory.
+Byte i of the store, at addresses[0] + i, is written to addresses[i] of `memory`*/
+type scatteredWriter struct {
+	memory    Memory.Writer
+	addresses []uint32
+}
+
+func (w scatteredWriter) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	offset := address - w.addresses[0]
+	if err := Memory.CheckAccess(offset, width, uint(len(w.addresses))); err != nil {
+		return err
+	}
+
+	for i := uint32(0); i < uint32(width); i++ {
+		if err := w.memory.Write(w.addresses[offset+i], Memory.Byte, val>>(8*i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*atomicPolicy is the policy for atomic accesses, which cannot be allowed to be misaligned*/
+func (ex *RiscVInstructionExecutor) atomicPolicy() MisalignedAccessPolicy {
+	if ex.misalignedPolicy == AllowMisaligned {
+		return TrapMisaligned
+	}
+	return ex.misalignedPolicy
+}
+
+/*checkAlignment counts the access of `size` bytes at `address` if it is misaligned, and then panics with the
+exception of cause `misaligned` or `fault`, as `policy` chooses. It returns whether the access is misaligned,
+which it only does when `policy` allows it. Loads are told apart from stores by their causes*/
+func (ex *RiscVInstructionExecutor) checkAlignment(address uint32, size uint32, policy MisalignedAccessPolicy,
+	misaligned Traps.Cause, fault Traps.Cause) bool {
+	if address%size == 0 {
+		return false
+	}
+
+	if misaligned == Traps.LoadAddressMisaligned {
+		ex.misaligned.Loads++
+	} else {
+		ex.misaligned.Stores++
+	}
+
+	reason := fmt.Sprintf("address %#x of a %d byte access is misaligned", address, size)
+	switch policy {
+	case TrapMisaligned:
+		panic(Traps.MakeException(misaligned, address, reason))
+	case FaultMisaligned:
+		panic(Traps.MakeException(fault, address, reason))
+	}
+	return true
+}
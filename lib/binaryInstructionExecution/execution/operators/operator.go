@@ -1,17 +1,27 @@
 package operators
 
 import (
+	"math"
+
 	Utils "github.com/chenhowa/computer/lib/binaryInstructionExecution/bitUtils"
+	Memory "github.com/chenhowa/computer/lib/memory"
 )
 
-/*Operator represents operations a set of 32 registers.
+/*Operator represents operations a set of 32 registers, and on the
+32 floating-point registers of the F and D extensions.
 
- */
+Floating-point registers are 64 bits wide. Single precision values are
+NaN-boxed in them: the upper 32 bits of the register are all ones.
+*/
 type Operator struct {
-	registers [32]uint32
-	flags     uint16
+	registers      [32]uint32
+	floatRegisters [32]uint64
+	flags          uint16
 }
 
+/*singleBox is the upper half of a floating-point register that holds a NaN-boxed single precision value*/
+const singleBox uint64 = 0xFFFFFFFF00000000
+
 /*MakeOperator is a construction function for the Operator struct
  */
 func MakeOperator(registers [32]uint32, flags uint16) Operator {
@@ -23,35 +33,20 @@ func MakeOperator(registers [32]uint32, flags uint16) Operator {
 	return op
 }
 
-/*ReadMemory is an interface that reads a uint32 data
-value from a memory address (possibly virtualized)
-*/
-type ReadMemory interface {
-	Get(address uint16) uint32
-}
-
-/*WriteMemory is an interface that writes a uint32 data
-value to a memory address (possibly virtualized)
-*/
-type WriteMemory interface {
-	Set(address uint16, value uint32, bitsToSet uint)
-}
-
-type ReadWriteMemory interface {
-	ReadMemory
-	WriteMemory
-}
-
 func (c *Operator) Get(reg uint) uint32 {
 	return c.registers[reg]
 }
 
-func (c *Operator) Load(reg uint, address uint16, m ReadMemory) {
-	c.registers[reg] = m.Get(address)
+/*
+	Loads and stores access memory with 32-bit addresses. When memory returns an error,
+	a load leaves its register as it was, and the error is returned.
+*/
+func (c *Operator) Load(reg uint, address uint32, m Memory.Reader) error {
+	return c.Load_word(reg, address, m)
 }
 
-func (c *Operator) Store(reg uint, address uint16, m WriteMemory) {
-	m.Set(address, c.registers[reg], 32)
+func (c *Operator) Store(reg uint, address uint32, m Memory.Writer) error {
+	return c.Store_word(reg, address, m)
 }
 
 func (c *Operator) Add_immediate(dest uint, reg uint, immediate uint32) {
@@ -157,54 +152,114 @@ func (c *Operator) Multiply(dest uint, reg1 uint, reg2 uint) {
 }
 
 /*
-	This function should flag the underflow.
+	This function should flag the underflow. Division by zero gives the largest
+	quotient, and leaves the dividend as the remainder.
 */
 func (c *Operator) Divide(dest_dividend uint, dest_rem uint, reg1 uint, reg2 uint) {
 	var operand1 = c.registers[reg1]
 	var operand2 = c.registers[reg2]
-	dividend := operand1 / operand2
-	remainder := operand1 % operand2
+	dividend := uint32(math.MaxUint32)
+	remainder := operand1
+	if operand2 != 0 {
+		dividend = operand1 / operand2
+		remainder = operand1 % operand2
+	}
 
 	c.registers[dest_dividend] = dividend
 	c.registers[dest_rem] = remainder
 }
 
-func (c *Operator) Load_word(dest uint, address uint16, memory ReadMemory) {
-	value := memory.Get(address)
-	c.registers[dest] = value
+/*load reads `width` bytes at `address` into register `dest`, after passing them through `extend`*/
+func (c *Operator) load(dest uint, address uint32, width Memory.AccessWidth, memory Memory.Reader, extend func(value uint32) uint32) error {
+	value, err := memory.Read(address, width)
+	if err != nil {
+		return err
+	}
+	c.registers[dest] = extend(uint32(value))
+	return nil
+}
+
+func (c *Operator) Load_word(dest uint, address uint32, memory Memory.Reader) error {
+	return c.load(dest, address, Memory.Word, memory, func(value uint32) uint32 {
+		return value
+	})
+}
+
+func (c *Operator) Load_halfword(dest uint, address uint32, memory Memory.Reader) error {
+	return c.load(dest, address, Memory.HalfWord, memory, func(value uint32) uint32 {
+		return Utils.SignExtendUint32WithBit(value, 15)
+	})
+}
+
+func (c *Operator) Load_halfword_unsigned(dest uint, address uint32, memory Memory.Reader) error {
+	return c.load(dest, address, Memory.HalfWord, memory, func(value uint32) uint32 {
+		return value
+	})
+}
+
+func (c *Operator) Load_byte(dest uint, address uint32, memory Memory.Reader) error {
+	return c.load(dest, address, Memory.Byte, memory, func(value uint32) uint32 {
+		return Utils.SignExtendUint32WithBit(value, 7)
+	})
 }
 
-func (c *Operator) Load_halfword(dest uint, address uint16, memory ReadMemory) {
-	value := memory.Get(address)
-	c.registers[dest] = Utils.SignExtendUint32WithBit(Utils.KeepBitsInInclusiveRange(value, 0, 15), 15)
+func (c *Operator) Load_byte_unsigned(dest uint, address uint32, memory Memory.Reader) error {
+	return c.load(dest, address, Memory.Byte, memory, func(value uint32) uint32 {
+		return value
+	})
 }
 
-func (c *Operator) Load_halfword_unsigned(dest uint, address uint16, memory ReadMemory) {
-	value := memory.Get(address)
-	c.registers[dest] = Utils.KeepBitsInInclusiveRange(value, 0, 15)
+func (c *Operator) Store_word(src uint, address uint32, memory Memory.Writer) error {
+	return memory.Write(address, Memory.Word, uint64(c.Get(src)))
 }
 
-func (c *Operator) Load_byte(dest uint, address uint16, memory ReadMemory) {
-	value := memory.Get(address)
-	c.registers[dest] = Utils.SignExtendUint32WithBit(Utils.KeepBitsInInclusiveRange(value, 0, 7), 7)
+func (c *Operator) Store_halfword(src uint, address uint32, memory Memory.Writer) error {
+	return memory.Write(address, Memory.HalfWord, uint64(c.Get(src)))
 }
 
-func (c *Operator) Load_byte_unsigned(dest uint, address uint16, memory ReadMemory) {
-	value := memory.Get(address)
-	c.registers[dest] = Utils.KeepBitsInInclusiveRange(value, 0, 7)
+func (c *Operator) Store_byte(src uint, address uint32, memory Memory.Writer) error {
+	return memory.Write(address, Memory.Byte, uint64(c.Get(src)))
 }
 
-func (c *Operator) Store_word(src uint, address uint16, memory WriteMemory) {
-	value := c.Get(src)
-	memory.Set(address, value, 32)
+func (c *Operator) Get_float(reg uint) uint64 {
+	return c.floatRegisters[reg]
 }
 
-func (c *Operator) Store_halfword(src uint, address uint16, memory WriteMemory) {
-	value := c.Get(src)
-	memory.Set(address, value, 16)
+func (c *Operator) Set_float(reg uint, value uint64) {
+	c.floatRegisters[reg] = value
+}
+
+/*
+	Loads a single precision value, and NaN-boxes it.
+*/
+func (c *Operator) Load_float(dest uint, address uint32, memory Memory.Reader) error {
+	value, err := memory.Read(address, Memory.Word)
+	if err != nil {
+		return err
+	}
+	c.floatRegisters[dest] = singleBox | value
+	return nil
+}
+
+/*
+	Loads a little-endian double precision value.
+*/
+func (c *Operator) Load_double(dest uint, address uint32, memory Memory.Reader) error {
+	value, err := memory.Read(address, Memory.DoubleWord)
+	if err != nil {
+		return err
+	}
+	c.floatRegisters[dest] = value
+	return nil
+}
+
+/*
+	Stores the lower 32 bits of the register, whether or not it is NaN-boxed.
+*/
+func (c *Operator) Store_float(src uint, address uint32, memory Memory.Writer) error {
+	return memory.Write(address, Memory.Word, uint64(uint32(c.floatRegisters[src])))
 }
 
-func (c *Operator) Store_byte(src uint, address uint16, memory WriteMemory) {
-	value := c.Get(src)
-	memory.Set(address, value, 8)
+func (c *Operator) Store_double(src uint, address uint32, memory Memory.Writer) error {
+	return memory.Write(address, Memory.DoubleWord, c.floatRegisters[src])
 }
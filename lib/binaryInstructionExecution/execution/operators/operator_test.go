@@ -1,9 +1,11 @@
 package operators
 
 import (
+	"errors"
 	"math"
 	"testing"
 
+	Memory "github.com/chenhowa/computer/lib/memory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -15,15 +17,22 @@ type OperatorSuite struct {
 }
 
 type MemoryMock struct {
-	val uint32
+	val   uint64
+	width Memory.AccessWidth
+	err   error
 }
 
-func (m *MemoryMock) Get(address uint16) uint32 {
-	return m.val
+func (m *MemoryMock) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	m.width = width
+	return m.val & width.Mask(), m.err
 }
 
-func (m *MemoryMock) Set(address uint16, val uint32, bits uint) {
-	m.val = val
+func (m *MemoryMock) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	m.width = width
+	if m.err == nil {
+		m.val = val & width.Mask()
+	}
+	return m.err
 }
 
 func (suite *OperatorSuite) SetupTest() {
@@ -46,8 +55,8 @@ func (suite *OperatorSuite) TestLoadStore() {
 	var assert = assert.New(suite.T())
 
 	var start_value uint32 = 10
-	suite.memory.val = start_value
-	assert.Equal(suite.memory.val, start_value)
+	suite.memory.val = uint64(start_value)
+	assert.Equal(suite.memory.val, uint64(start_value))
 
 	suite.operator.Load(0, 5, &suite.memory)
 	assert.Equal(suite.operator.registers[0], start_value)
@@ -56,14 +65,33 @@ func (suite *OperatorSuite) TestLoadStore() {
 	assert.Equal(suite.operator.registers[15], start_value)
 
 	suite.operator.Store(1, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(1))
+	assert.Equal(suite.memory.val, uint64(1))
+}
+
+func (suite *OperatorSuite) TestLoadStoreFloat() {
+	var assert = assert.New(suite.T())
+
+	suite.memory.val = 0x3F800000
+	suite.operator.Load_float(2, 8, &suite.memory)
+	assert.Equal(uint64(0xFFFFFFFF3F800000), suite.operator.Get_float(2))
+
+	suite.memory.val = 0x400921FB54442D18
+	suite.operator.Load_double(3, 8, &suite.memory)
+	assert.Equal(uint64(0x400921FB54442D18), suite.operator.Get_float(3))
+	assert.Equal(Memory.DoubleWord, suite.memory.width)
+
+	suite.operator.Set_float(4, 0x1122334455667788)
+	suite.operator.Store_float(4, 8, &suite.memory)
+	assert.Equal(uint64(0x55667788), suite.memory.val)
+	suite.operator.Store_double(4, 8, &suite.memory)
+	assert.Equal(uint64(0x1122334455667788), suite.memory.val)
 }
 
 func (suite *OperatorSuite) TestAdd() {
 	var assert = assert.New(suite.T())
 
 	var start_value uint32 = 10
-	suite.memory.val = start_value
+	suite.memory.val = uint64(start_value)
 	suite.operator.Load(0, 5, &suite.memory)
 	suite.operator.Load(15, 5, &suite.memory)
 
@@ -76,7 +104,7 @@ func (suite *OperatorSuite) TestSub() {
 
 	suite.operator.Sub(0, 3, 1)
 	suite.operator.Store(0, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(2))
+	assert.Equal(suite.memory.val, uint64(2))
 }
 
 func (suite *OperatorSuite) TestBitAnd() {
@@ -84,7 +112,7 @@ func (suite *OperatorSuite) TestBitAnd() {
 
 	suite.operator.Bit_and(0, 1, 3)
 	suite.operator.Store(0, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(1))
+	assert.Equal(suite.memory.val, uint64(1))
 }
 
 func (suite *OperatorSuite) TestBitOr() {
@@ -92,7 +120,7 @@ func (suite *OperatorSuite) TestBitOr() {
 
 	suite.operator.Bit_or(0, 1, 2)
 	suite.operator.Store(0, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(3))
+	assert.Equal(suite.memory.val, uint64(3))
 }
 
 func (suite *OperatorSuite) TestBitXor() {
@@ -114,7 +142,7 @@ func (suite *OperatorSuite) TestMultiply() {
 	var assert = assert.New(suite.T())
 	suite.operator.Multiply(0, 2, 3)
 	suite.operator.Store(0, 5, &suite.memory)
-	assert.Equal(suite.memory.val, uint32(6))
+	assert.Equal(suite.memory.val, uint64(6))
 }
 
 func (suite *OperatorSuite) TestDivide() {
@@ -123,6 +151,12 @@ func (suite *OperatorSuite) TestDivide() {
 	suite.AssertRegisterEquals(1, uint32(2))
 }
 
+func (suite *OperatorSuite) TestDivide_ByZero() {
+	suite.operator.Divide(0, 1, 5, 31)
+	suite.AssertRegisterEquals(0, math.MaxUint32)
+	suite.AssertRegisterEquals(1, 5)
+}
+
 func (suite *OperatorSuite) TestAddImmediate() {
 	suite.operator.Add_immediate(0, 1, 10)
 	suite.AssertRegisterEquals(0, 11)
@@ -206,7 +240,7 @@ func (suite *OperatorSuite) TestStoreWord() {
 	suite.memory.val = 0
 	suite.operator.Store_word(10, 3, &suite.memory)
 
-	assert.Equal(uint32(10), suite.memory.val)
+	assert.Equal(uint64(10), suite.memory.val)
 }
 
 func (suite *OperatorSuite) TestStoreHalfWord() {
@@ -214,7 +248,7 @@ func (suite *OperatorSuite) TestStoreHalfWord() {
 	suite.memory.val = 0
 	suite.operator.Store_halfword(10, 3, &suite.memory)
 
-	assert.Equal(uint32(10), suite.memory.val)
+	assert.Equal(uint64(10), suite.memory.val)
 }
 
 func (suite *OperatorSuite) TestStoreByte() {
@@ -222,5 +256,27 @@ func (suite *OperatorSuite) TestStoreByte() {
 	suite.memory.val = 0
 	suite.operator.Store_byte(10, 3, &suite.memory)
 
-	assert.Equal(uint32(10), suite.memory.val)
+	assert.Equal(uint64(10), suite.memory.val)
+}
+
+func (suite *OperatorSuite) TestAccessWidths() {
+	assert := assert.New(suite.T())
+
+	suite.operator.Load_halfword(0, 0x12345678, &suite.memory)
+	assert.Equal(Memory.HalfWord, suite.memory.width)
+	suite.operator.Store_byte(1, 0x12345678, &suite.memory)
+	assert.Equal(Memory.Byte, suite.memory.width)
+	suite.operator.Store_word(1, 0x12345678, &suite.memory)
+	assert.Equal(Memory.Word, suite.memory.width)
+}
+
+func (suite *OperatorSuite) TestMemoryErrors() {
+	assert := assert.New(suite.T())
+	suite.memory.val = 7
+	suite.memory.err = Memory.ErrAddressOutOfRange
+
+	assert.True(errors.Is(suite.operator.Load_word(2, 0x80000000, &suite.memory), Memory.ErrAddressOutOfRange))
+	suite.AssertRegisterEquals(2, 2) // the register is left as it was
+	assert.True(errors.Is(suite.operator.Store_word(3, 0x80000000, &suite.memory), Memory.ErrAddressOutOfRange))
+	assert.Equal(uint64(7), suite.memory.val)
 }
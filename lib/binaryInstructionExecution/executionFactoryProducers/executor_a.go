@@ -0,0 +1,83 @@
+package executionFactoryProducers
+
+import (
+	"fmt"
+
+	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	Traps "github.com/chenhowa/computer/lib/traps"
+)
+
+/*ExecutorA contains instructions for executing
+an A-type (atomic) instruction ParseResult
+*/
+type ExecutorA struct {
+	Executor RiscVExecutor
+	Result   Parser.RiscVBinaryParseResult
+}
+
+type executionFunctionA func(ex RiscVExecutor, dest uint, addressReg uint, src uint)
+
+type validOperationA uint
+
+/*These constants represent the valid Funct5 operations of an atomic instruction
+ */
+const (
+	AmoAdd           validOperationA = 0x00
+	AmoSwap          validOperationA = 0x01
+	LoadReserved     validOperationA = 0x02
+	StoreConditional validOperationA = 0x03
+	AmoXor           validOperationA = 0x04
+	AmoOr            validOperationA = 0x08
+	AmoAnd           validOperationA = 0x0C
+	AmoMin           validOperationA = 0x10
+	AmoMax           validOperationA = 0x14
+	AmoMinU          validOperationA = 0x18
+	AmoMaxU          validOperationA = 0x1C
+)
+
+type atomicWidth uint
+
+/*AtomicWord is the Funct3 of atomic instructions that operate on 32-bit words, the only width of RV32A
+ */
+const AtomicWord atomicWidth = 2
+
+/*Execute will execute the A-type instruction. The acquire and release bits are not passed on,
+because the instructions of a single hart are executed one at a time, in program order, so
+every atomic instruction is already ordered with the memory accesses around it
+*/
+func (ex *ExecutorA) Execute() {
+	dest := uint(ex.Result.FiveBitDestination)
+	addressReg := uint(ex.Result.FiveBitRegister1)
+	src := uint(ex.Result.FiveBitRegister2)
+	func5 := validOperationA(ex.Result.Funct5)
+
+	if atomicWidth(ex.Result.Funct3) != AtomicWord {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionA: %d width not found", ex.Result.Funct3)))
+	}
+
+	decision := map[Parser.OpCode](map[validOperationA](executionFunctionA)){
+		Parser.AMO: map[validOperationA](executionFunctionA){
+			LoadReserved:     (RiscVExecutor).loadReserved,
+			StoreConditional: (RiscVExecutor).storeConditional,
+			AmoSwap:          (RiscVExecutor).atomicSwap,
+			AmoAdd:           (RiscVExecutor).atomicAdd,
+			AmoXor:           (RiscVExecutor).atomicXor,
+			AmoAnd:           (RiscVExecutor).atomicAnd,
+			AmoOr:            (RiscVExecutor).atomicOr,
+			AmoMin:           (RiscVExecutor).atomicMin,
+			AmoMax:           (RiscVExecutor).atomicMax,
+			AmoMinU:          (RiscVExecutor).atomicMinUnsigned,
+			AmoMaxU:          (RiscVExecutor).atomicMaxUnsigned,
+		},
+	}
+
+	if m, ok := decision[ex.Result.OpCode]; ok {
+		if f, ok := m[func5]; ok {
+			f(ex.Executor, dest, addressReg, src)
+		} else {
+			panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionA: %d operation not found", func5)))
+		}
+	} else {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionA: %d opcode not found", ex.Result.OpCode)))
+	}
+}
@@ -0,0 +1,460 @@
+package executionFactoryProducers
+
+import (
+	"fmt"
+
+	Execution "github.com/chenhowa/computer/lib/binaryInstructionExecution/execution"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
+)
+
+/*AdaptedRiscVExecutor adapts an execution.RiscVInstructionExecutor to the RiscVExecutor interface.
+It binds the executor to the memory, program counter, CSRs, trap and environment managers that it
+operates on, so that each decoded instruction only has to supply its operands.
+*/
+type AdaptedRiscVExecutor struct {
+	executor *Execution.RiscVInstructionExecutor
+	memory   Memory.ReadWriter
+	manager  *Execution.AdaptedInstructionManager
+	csr      *Execution.AdaptedCsrOperator
+	trapEnv  *Execution.AdaptedTrapEnvManager
+	execEnv  *Execution.AdaptedExecutionEnvManager
+	debugEnv *Execution.AdaptedDebugEnvManager
+}
+
+/*MakeAdaptedRiscVExecutor is a constructor for AdaptedRiscVExecutor*/
+func MakeAdaptedRiscVExecutor(executor *Execution.RiscVInstructionExecutor, memory Memory.ReadWriter,
+	manager *Execution.AdaptedInstructionManager, csr *Execution.AdaptedCsrOperator, trapEnv *Execution.AdaptedTrapEnvManager,
+	execEnv *Execution.AdaptedExecutionEnvManager, debugEnv *Execution.AdaptedDebugEnvManager) AdaptedRiscVExecutor {
+	adapted := AdaptedRiscVExecutor{
+		executor: executor,
+		memory:   memory,
+		manager:  manager,
+		csr:      csr,
+		trapEnv:  trapEnv,
+		execEnv:  execEnv,
+		debugEnv: debugEnv,
+	}
+
+	return adapted
+}
+
+/*These constants are the values of the immediate of a SYSTEM instruction
+whose Funct3 is Private, that select which environment instruction to run
+*/
+const (
+	ECALL  uint32 = 0
+	EBREAK uint32 = 1
+	SRET   uint32 = 0x102
+	WFI    uint32 = 0x105
+	MRET   uint32 = 0x302
+)
+
+/*SFENCEVMA is the immediate of SFENCE.VMA, except for the bits in SFENCEVMAMask, which hold its rs2*/
+const (
+	SFENCEVMA     uint32 = 0x120
+	SFENCEVMAMask uint32 = 0x1F
+)
+
+/*shiftArithmeticBit is the bit of a shift-right immediate that selects an
+arithmetic shift rather than a logical one*/
+const shiftArithmeticBit = 10
+
+func (ex *AdaptedRiscVExecutor) addImmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.AddImmediate(dest, reg, immediate)
+}
+
+func (ex *AdaptedRiscVExecutor) setLessThanImmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.SetLessThanImmediate(dest, reg, immediate)
+}
+
+func (ex *AdaptedRiscVExecutor) setLessThanImmediateUnsigned(dest uint, reg uint, immediate uint32) {
+	ex.executor.SetLessThanImmediateUnsigned(dest, reg, immediate)
+}
+
+func (ex *AdaptedRiscVExecutor) andImmmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.AndImmediate(dest, reg, immediate)
+}
+
+func (ex *AdaptedRiscVExecutor) orImmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.OrImmediate(dest, reg, immediate)
+}
+
+func (ex *AdaptedRiscVExecutor) xorImmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.XorImmediate(dest, reg, immediate)
+}
+
+func (ex *AdaptedRiscVExecutor) shiftLeftLogicalImmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.ShiftLeftLogicalImmediate(dest, reg, immediate)
+}
+
+/*shiftRight chooses between a logical and an arithmetic shift using the upper bits
+of the immediate, as both share the same Funct3*/
+func (ex *AdaptedRiscVExecutor) shiftRight(dest uint, reg uint, immediate uint32) {
+	if (immediate>>shiftArithmeticBit)&1 == 1 {
+		ex.executor.ShiftRightArithmeticImmediate(dest, reg, immediate)
+	} else {
+		ex.executor.ShiftRightLogicalImmediate(dest, reg, immediate)
+	}
+}
+
+func (ex *AdaptedRiscVExecutor) loadUpperImmediate(dest uint, immediate uint32) {
+	ex.executor.LoadUpperImmediate(dest, immediate)
+}
+
+func (ex *AdaptedRiscVExecutor) addUpperImmediateToPC(dest uint, immediate uint32) {
+	ex.executor.AddUpperImmediateToPC(dest, immediate, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) add(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.Add(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) sub(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.Sub(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) setLessThan(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.SetLessThan(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) setLessThanUnsigned(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.SetLessThanUnsigned(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) and(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.And(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) or(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.Or(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) xor(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.Xor(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) shiftLeftLogical(dest uint, reg uint, shiftreg uint) {
+	ex.executor.ShiftLeftLogical(dest, reg, shiftreg)
+}
+
+func (ex *AdaptedRiscVExecutor) shiftRightLogical(dest uint, reg uint, shiftreg uint) {
+	ex.executor.ShiftRightLogical(dest, reg, shiftreg)
+}
+
+func (ex *AdaptedRiscVExecutor) shiftRightArithmetic(dest uint, reg uint, shiftreg uint) {
+	ex.executor.ShiftRightArithmetic(dest, reg, shiftreg)
+}
+
+func (ex *AdaptedRiscVExecutor) multiply(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.Multiply(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) multiplyHigh(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.MultiplyHigh(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) multiplyHighSignedUnsigned(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.MultiplyHighSignedUnsigned(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) multiplyHighUnsigned(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.MultiplyHighUnsigned(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) divide(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.Divide(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) divideUnsigned(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.DivideUnsigned(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) remainder(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.Remainder(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) remainderUnsigned(dest uint, reg1 uint, reg2 uint) {
+	ex.executor.RemainderUnsigned(dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) branchEqual(src1 uint, src2 uint, offset uint32) {
+	ex.executor.BranchEqual(src1, src2, offset, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) branchNotEqual(src1 uint, src2 uint, offset uint32) {
+	ex.executor.BranchNotEqual(src1, src2, offset, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) branchLessThan(src1 uint, src2 uint, offset uint32) {
+	ex.executor.BranchLessThan(src1, src2, offset, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) branchLessThanUnsigned(src1 uint, src2 uint, offset uint32) {
+	ex.executor.BranchLessThanUnsigned(src1, src2, offset, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) branchGreaterThanOrEqual(src1 uint, src2 uint, offset uint32) {
+	ex.executor.BranchGreaterThanOrEqual(src1, src2, offset, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) branchGreaterThanOrEqualUnsigned(src1 uint, src2 uint, offset uint32) {
+	ex.executor.BranchGreaterThanOrEqualUnsigned(src1, src2, offset, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) jumpAndLink(dest uint, pcOffset uint32) {
+	ex.executor.JumpAndLink(dest, pcOffset, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) jumpAndLinkRegister(dest uint, basereg uint, pcOffset uint32) {
+	ex.executor.JumpAndLinkRegister(dest, basereg, pcOffset, ex.manager)
+}
+
+func (ex *AdaptedRiscVExecutor) loadWord(dest uint, reg uint, offset uint32) {
+	ex.executor.LoadWord(dest, reg, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) loadHalfWord(dest uint, reg uint, offset uint32) {
+	ex.executor.LoadHalfWord(dest, reg, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) loadHalfWordUnsigned(dest uint, reg uint, offset uint32) {
+	ex.executor.LoadHalfWordUnsigned(dest, reg, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) loadByte(dest uint, reg uint, offset uint32) {
+	ex.executor.LoadByte(dest, reg, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) loadByteUnsigned(dest uint, reg uint, offset uint32) {
+	ex.executor.LoadByteUnsigned(dest, reg, offset, ex.memory)
+}
+
+/*storeWord receives the base register as `reg1` and the source register as `reg2`,
+which is the order that ExecutorS decodes them in*/
+func (ex *AdaptedRiscVExecutor) storeWord(reg1 uint, reg2 uint, offset uint32) {
+	ex.executor.StoreWord(reg2, reg1, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) storeHalfWord(reg1 uint, reg2 uint, offset uint32) {
+	ex.executor.StoreHalfWord(reg2, reg1, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) storeByte(reg1 uint, reg2 uint, offset uint32) {
+	ex.executor.StoreByte(reg2, reg1, offset, ex.memory)
+}
+
+/*The CSR instructions carry the CSR number in their 12-bit immediate*/
+func (ex *AdaptedRiscVExecutor) loadReserved(dest uint, addressReg uint, src uint) {
+	ex.executor.LoadReserved(dest, addressReg, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) storeConditional(dest uint, addressReg uint, src uint) {
+	ex.executor.StoreConditional(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicSwap(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicSwap(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicAdd(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicAdd(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicXor(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicXor(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicAnd(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicAnd(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicOr(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicOr(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicMin(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicMin(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicMax(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicMax(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicMinUnsigned(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicMinUnsigned(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) atomicMaxUnsigned(dest uint, addressReg uint, src uint) {
+	ex.executor.AtomicMaxUnsigned(dest, addressReg, src, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) loadFloat(dest uint, reg uint, offset uint32) {
+	ex.executor.LoadFloat(dest, reg, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) loadDouble(dest uint, reg uint, offset uint32) {
+	ex.executor.LoadDouble(dest, reg, offset, ex.memory)
+}
+
+/*storeFloat receives the base register as `reg1` and the source register as `reg2`, like storeWord*/
+func (ex *AdaptedRiscVExecutor) storeFloat(reg1 uint, reg2 uint, offset uint32) {
+	ex.executor.StoreFloat(reg2, reg1, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) storeDouble(reg1 uint, reg2 uint, offset uint32) {
+	ex.executor.StoreDouble(reg2, reg1, offset, ex.memory)
+}
+
+func (ex *AdaptedRiscVExecutor) floatAdd(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatAdd(format, dest, reg1, reg2, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatSubtract(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatSubtract(format, dest, reg1, reg2, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatMultiply(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatMultiply(format, dest, reg1, reg2, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatDivide(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatDivide(format, dest, reg1, reg2, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatSquareRoot(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatSquareRoot(format, dest, reg1, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatConvert(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatConvert(format, dest, reg1, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatToInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatToInt(format, dest, reg1, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatToUnsignedInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatToUnsignedInt(format, dest, reg1, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) intToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.IntToFloat(format, dest, reg1, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) unsignedIntToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.UnsignedIntToFloat(format, dest, reg1, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatSignInject(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatSignInject(format, dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) floatSignInjectNegated(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatSignInjectNegated(format, dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) floatSignInjectXor(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatSignInjectXor(format, dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) floatMin(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatMin(format, dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) floatMax(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatMax(format, dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) floatEqual(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatEqual(format, dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) floatLessThan(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatLessThan(format, dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) floatLessOrEqual(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatLessOrEqual(format, dest, reg1, reg2)
+}
+
+func (ex *AdaptedRiscVExecutor) floatClassify(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatClassify(format, dest, reg1)
+}
+
+func (ex *AdaptedRiscVExecutor) floatMoveToInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.FloatMoveToInt(dest, reg1)
+}
+
+func (ex *AdaptedRiscVExecutor) intMoveToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	ex.executor.IntMoveToFloat(dest, reg1)
+}
+
+func (ex *AdaptedRiscVExecutor) floatMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint) {
+	ex.executor.FloatMultiplyAdd(format, dest, reg1, reg2, reg3, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatMultiplySubtract(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint) {
+	ex.executor.FloatMultiplySubtract(format, dest, reg1, reg2, reg3, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatNegatedMultiplySubtract(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint) {
+	ex.executor.FloatNegatedMultiplySubtract(format, dest, reg1, reg2, reg3, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) floatNegatedMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint) {
+	ex.executor.FloatNegatedMultiplyAdd(format, dest, reg1, reg2, reg3, roundingMode)
+}
+
+func (ex *AdaptedRiscVExecutor) csrReadAndWrite(dest uint, reg uint, immediate uint32) {
+	ex.executor.CsrReadAndWrite(dest, reg, uint(immediate), ex.csr)
+}
+
+func (ex *AdaptedRiscVExecutor) csrReadAndSet(dest uint, reg uint, immediate uint32) {
+	ex.executor.CsrReadAndSet(dest, reg, uint(immediate), ex.csr)
+}
+
+func (ex *AdaptedRiscVExecutor) csrReadAndClear(dest uint, reg uint, immediate uint32) {
+	ex.executor.CsrReadAndClear(dest, reg, uint(immediate), ex.csr)
+}
+
+/*The immediate CSR instructions carry their 5-bit immediate where the source register usually is*/
+func (ex *AdaptedRiscVExecutor) csrReadAndWriteImmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.CsrReadAndWriteImmediate(dest, uint32(reg), uint(immediate), ex.csr)
+}
+
+func (ex *AdaptedRiscVExecutor) csrReadAndSetImmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.CsrReadAndSetImmediate(dest, uint32(reg), uint(immediate), ex.csr)
+}
+
+func (ex *AdaptedRiscVExecutor) csrReadAndClearImmediate(dest uint, reg uint, immediate uint32) {
+	ex.executor.CsrReadAndClearImmediate(dest, uint32(reg), uint(immediate), ex.csr)
+}
+
+/*private runs the environment instruction (ECALL, EBREAK, SRET, WFI, MRET or SFENCE.VMA) selected by `immediate`*/
+func (ex *AdaptedRiscVExecutor) private(dest uint, reg uint, immediate uint32) {
+	if immediate&^SFENCEVMAMask == SFENCEVMA {
+		ex.executor.FenceVirtualMemory(reg, uint(immediate&SFENCEVMAMask))
+		return
+	}
+
+	switch immediate {
+	case ECALL:
+		ex.executor.EnvCall(ex.execEnv)
+	case EBREAK:
+		ex.executor.EnvBreak(ex.debugEnv)
+	case SRET:
+		ex.executor.SupervisorTrapReturn(ex.manager, ex.trapEnv)
+	case WFI:
+		ex.executor.WaitForInterrupt(ex.trapEnv)
+	case MRET:
+		ex.executor.TrapReturn(ex.manager, ex.trapEnv)
+	default:
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("private: %d environment instruction not found", immediate)))
+	}
+}
+
+/*fence orders memory accesses. This machine runs one instruction at a time, on one hart, and
+fetches every instruction straight from memory, so FENCE and FENCE.I have nothing to do*/
+func (ex *AdaptedRiscVExecutor) fence(dest uint, reg uint, immediate uint32) {
+}
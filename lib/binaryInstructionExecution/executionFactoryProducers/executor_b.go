@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	Traps "github.com/chenhowa/computer/lib/traps"
 )
 
 /*ExecutorB contains instructions for executing
@@ -22,18 +23,19 @@ type validOperationB uint
 B-type instructions
 */
 const (
-	Beq validOperationB = iota + 1
-	Bneq
-	Blt
-	Bltu
-	Bge
-	Bgeu
+	Beq  validOperationB = 0
+	Bneq validOperationB = 1
+	Blt  validOperationB = 4
+	Bge  validOperationB = 5
+	Bltu validOperationB = 6
+	Bgeu validOperationB = 7
 )
 
 /*Execute will execute the B-type instruction
  */
 func (ex *ExecutorB) Execute() {
-	immediate := uint32(ex.Result.TwelveBitImmediate)
+	// The encoded immediate leaves out the lowest bit of the byte offset, which is always 0
+	immediate := uint32(ex.Result.TwelveBitImmediate) << 1
 	src1 := uint(ex.Result.FiveBitRegister1)
 	src2 := uint(ex.Result.FiveBitRegister2)
 	func3 := validOperationB(ex.Result.Funct3)
@@ -53,9 +55,9 @@ func (ex *ExecutorB) Execute() {
 		if f, ok := m[func3]; ok {
 			f(ex.Executor, src1, src2, immediate)
 		} else {
-			panic(fmt.Sprintf("executionFunctionB: %d operation not found", func3))
+			panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionB: %d operation not found", func3)))
 		}
 	} else {
-		panic(fmt.Sprintf("executionFunctionB: %d opcode not found", ex.Result.OpCode))
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionB: %d opcode not found", ex.Result.OpCode)))
 	}
 }
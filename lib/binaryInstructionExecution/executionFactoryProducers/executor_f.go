@@ -0,0 +1,149 @@
+package executionFactoryProducers
+
+import (
+	"fmt"
+
+	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	Traps "github.com/chenhowa/computer/lib/traps"
+)
+
+/*ExecutorF contains instructions for executing a floating-point instruction ParseResult,
+of either single (F-type) or double (D-type) precision
+*/
+type ExecutorF struct {
+	Executor RiscVExecutor
+	Result   Parser.RiscVBinaryParseResult
+}
+
+type executionFunctionF func(ex RiscVExecutor, format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+
+type executionFunctionR4 func(ex RiscVExecutor, format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint)
+
+type validOperationF uint
+
+/*These constants represent the valid Funct5 operations of an OP-FP instruction
+ */
+const (
+	FAdd            validOperationF = 0x00
+	FSub            validOperationF = 0x01
+	FMul            validOperationF = 0x02
+	FDiv            validOperationF = 0x03
+	FSignInject     validOperationF = 0x04
+	FMinMax         validOperationF = 0x05
+	FConvertFormat  validOperationF = 0x08
+	FSqrt           validOperationF = 0x0B
+	FCompare        validOperationF = 0x14
+	FConvertToInt   validOperationF = 0x18
+	FConvertFromInt validOperationF = 0x1A
+	FMoveToInt      validOperationF = 0x1C
+	FMoveFromInt    validOperationF = 0x1E
+)
+
+type floatSelector uint8
+
+/*These constants represent the operations that share a Funct5. The Funct3 selects between them,
+except for the conversions to and from integers, where register 2 does
+*/
+const (
+	FSgnj            floatSelector = 0
+	FSgnjn           floatSelector = 1
+	FSgnjx           floatSelector = 2
+	FMin             floatSelector = 0
+	FMax             floatSelector = 1
+	FLe              floatSelector = 0
+	FLt              floatSelector = 1
+	FEq              floatSelector = 2
+	FMvXW            floatSelector = 0
+	FClass           floatSelector = 1
+	FMvWX            floatSelector = 0
+	FCvtWord         floatSelector = 0
+	FCvtWordUnsigned floatSelector = 1
+)
+
+/*Execute will execute the floating-point instruction. The format of its operands is passed on
+as the `fmt` field of the instruction, and its rounding mode as the Funct3
+*/
+func (ex *ExecutorF) Execute() {
+	dest := uint(ex.Result.FiveBitDestination)
+	reg1 := uint(ex.Result.FiveBitRegister1)
+	reg2 := uint(ex.Result.FiveBitRegister2)
+	reg3 := uint(ex.Result.FiveBitRegister3)
+	format := uint(ex.Result.Funct2)
+	roundingMode := uint(ex.Result.Funct3)
+
+	fused := map[Parser.OpCode](executionFunctionR4){
+		Parser.FMAdd:  (RiscVExecutor).floatMultiplyAdd,
+		Parser.FMSub:  (RiscVExecutor).floatMultiplySubtract,
+		Parser.FNMSub: (RiscVExecutor).floatNegatedMultiplySubtract,
+		Parser.FNMAdd: (RiscVExecutor).floatNegatedMultiplyAdd,
+	}
+
+	if f, ok := fused[ex.Result.OpCode]; ok {
+		f(ex.Executor, format, dest, reg1, reg2, reg3, roundingMode)
+		return
+	} else if ex.Result.OpCode != Parser.OpFP {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionF: %d opcode not found", ex.Result.OpCode)))
+	}
+
+	func5 := validOperationF(ex.Result.Funct5)
+	rounded := map[validOperationF](executionFunctionF){
+		FAdd:           (RiscVExecutor).floatAdd,
+		FSub:           (RiscVExecutor).floatSubtract,
+		FMul:           (RiscVExecutor).floatMultiply,
+		FDiv:           (RiscVExecutor).floatDivide,
+		FSqrt:          (RiscVExecutor).floatSquareRoot,
+		FConvertFormat: (RiscVExecutor).floatConvert,
+	}
+
+	if f, ok := rounded[func5]; ok {
+		f(ex.Executor, format, dest, reg1, reg2, roundingMode)
+		return
+	}
+
+	selected := map[validOperationF](map[floatSelector](executionFunctionF)){
+		FSignInject: map[floatSelector](executionFunctionF){
+			FSgnj:  (RiscVExecutor).floatSignInject,
+			FSgnjn: (RiscVExecutor).floatSignInjectNegated,
+			FSgnjx: (RiscVExecutor).floatSignInjectXor,
+		},
+		FMinMax: map[floatSelector](executionFunctionF){
+			FMin: (RiscVExecutor).floatMin,
+			FMax: (RiscVExecutor).floatMax,
+		},
+		FCompare: map[floatSelector](executionFunctionF){
+			FLe: (RiscVExecutor).floatLessOrEqual,
+			FLt: (RiscVExecutor).floatLessThan,
+			FEq: (RiscVExecutor).floatEqual,
+		},
+		FMoveToInt: map[floatSelector](executionFunctionF){
+			FMvXW:  (RiscVExecutor).floatMoveToInt,
+			FClass: (RiscVExecutor).floatClassify,
+		},
+		FMoveFromInt: map[floatSelector](executionFunctionF){
+			FMvWX: (RiscVExecutor).intMoveToFloat,
+		},
+		FConvertToInt: map[floatSelector](executionFunctionF){
+			FCvtWord:         (RiscVExecutor).floatToInt,
+			FCvtWordUnsigned: (RiscVExecutor).floatToUnsignedInt,
+		},
+		FConvertFromInt: map[floatSelector](executionFunctionF){
+			FCvtWord:         (RiscVExecutor).intToFloat,
+			FCvtWordUnsigned: (RiscVExecutor).unsignedIntToFloat,
+		},
+	}
+
+	selector := floatSelector(ex.Result.Funct3)
+	if func5 == FConvertToInt || func5 == FConvertFromInt {
+		selector = floatSelector(ex.Result.FiveBitRegister2)
+	}
+
+	if m, ok := selected[func5]; ok {
+		if f, ok := m[selector]; ok {
+			f(ex.Executor, format, dest, reg1, reg2, roundingMode)
+		} else {
+			panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionF: %d operation not found", selector)))
+		}
+	} else {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionF: %d operation not found", func5)))
+	}
+}
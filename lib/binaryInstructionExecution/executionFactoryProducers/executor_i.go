@@ -2,6 +2,7 @@ package executionFactoryProducers
 
 import "fmt"
 import Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+import Traps "github.com/chenhowa/computer/lib/traps"
 
 /*ExecutorI stores instructions for executing a given set
 of commands for an I-type instruction ParseResult
@@ -18,45 +19,61 @@ that are available for I-type instructions when Opcode is
 ImmArith
 */
 const (
-	AddI validOperationI = iota
-	SLTI
-	SLTIU
-	AndI
-	OrI
-	XorI
-	ShiftLeftLI
-	ShiftRight
+	AddI        validOperationI = 0
+	ShiftLeftLI validOperationI = 1
+	SLTI        validOperationI = 2
+	SLTIU       validOperationI = 3
+	XorI        validOperationI = 4
+	ShiftRight  validOperationI = 5
+	OrI         validOperationI = 6
+	AndI        validOperationI = 7
 )
 
 /*These constants represent the possible operations
 that are available for I-type instructions when OpCode is JALR
 */
 const (
-	JALR validOperationI = iota
+	JALR validOperationI = 0
 )
 
 /* These contstants represent the valid possible operations
 for I-type instructions when OpCode is LOAD
 */
 const (
-	LoadWord validOperationI = iota
-	LoadHalfWord
-	LoadHalfWordUnsigned
-	LoadByte
-	LoadByteUnsigned
+	LoadByte             validOperationI = 0
+	LoadHalfWord         validOperationI = 1
+	LoadWord             validOperationI = 2
+	LoadByteUnsigned     validOperationI = 4
+	LoadHalfWordUnsigned validOperationI = 5
+)
+
+/*These constants represent the valid possible operations
+for I-type instructions when OpCode is LOAD-FP
+*/
+const (
+	LoadFloat  validOperationI = 2
+	LoadDouble validOperationI = 3
+)
+
+/*These constants represent the valid possible operations
+for I-type instructions when OpCode is MISC-MEM
+*/
+const (
+	Fence  validOperationI = 0
+	FenceI validOperationI = 1
 )
 
 /*These constants represent the valid possible operations
 for I-type instructions when OpCode is SYSTEM
 */
 const (
-	CSRRW validOperationI = iota
-	CSRRS
-	CSRRC
-	CSRRWI
-	CSRRSI
-	CSRRCI
-	Private
+	Private validOperationI = 0
+	CSRRW   validOperationI = 1
+	CSRRS   validOperationI = 2
+	CSRRC   validOperationI = 3
+	CSRRWI  validOperationI = 5
+	CSRRSI  validOperationI = 6
+	CSRRCI  validOperationI = 7
 )
 
 type executionFunctionI func(ex RiscVExecutor, dest uint, reg uint, immediate uint32)
@@ -90,6 +107,14 @@ func (ex *ExecutorI) Execute() {
 			LoadByte:             (RiscVExecutor).loadByte,
 			LoadByteUnsigned:     (RiscVExecutor).loadByteUnsigned,
 		},
+		Parser.LoadFP: map[validOperationI](executionFunctionI){
+			LoadFloat:  (RiscVExecutor).loadFloat,
+			LoadDouble: (RiscVExecutor).loadDouble,
+		},
+		Parser.MiscMem: map[validOperationI](executionFunctionI){
+			Fence:  (RiscVExecutor).fence,
+			FenceI: (RiscVExecutor).fence,
+		},
 		Parser.System: map[validOperationI](executionFunctionI){
 			CSRRW:   (RiscVExecutor).csrReadAndWrite,
 			CSRRS:   (RiscVExecutor).csrReadAndSet,
@@ -105,10 +130,10 @@ func (ex *ExecutorI) Execute() {
 		if f, ok := m[func3]; ok {
 			f(ex.Executor, dest, src, immediate)
 		} else {
-			panic(fmt.Sprintf("executionFunctionI: %d operation not found", func3))
+			panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionI: %d operation not found", func3)))
 		}
 	} else {
-		panic(fmt.Sprintf("executionFunctionI: %d opcode not found", ex.Result.OpCode))
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionI: %d opcode not found", ex.Result.OpCode)))
 	}
 
 }
@@ -3,6 +3,7 @@ package executionFactoryProducers
 import Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
 
 import "fmt"
+import Traps "github.com/chenhowa/computer/lib/traps"
 
 /*ExecutorJ contains instructions for executing
 a J-type instruction ParseResult
@@ -15,13 +16,14 @@ type ExecutorJ struct {
 /*Execute will execute the J-type instruction
  */
 func (ex *ExecutorJ) Execute() {
-	immediate := uint32(ex.Result.TwentyBitImmediate)
+	// The encoded immediate leaves out the lowest bit of the byte offset, which is always 0
+	immediate := uint32(ex.Result.TwentyBitImmediate) << 1
 	dest := uint(ex.Result.FiveBitDestination)
 
 	switch ex.Result.OpCode {
 	case Parser.JAL:
 		ex.Executor.jumpAndLink(dest, immediate)
 	default:
-		panic(fmt.Sprintf("executionFunctionJ: %d opcode not found", ex.Result.OpCode))
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionJ: %d opcode not found", ex.Result.OpCode)))
 	}
 }
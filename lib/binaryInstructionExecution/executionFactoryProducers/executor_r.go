@@ -2,6 +2,7 @@ package executionFactoryProducers
 
 import "fmt"
 import Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+import Traps "github.com/chenhowa/computer/lib/traps"
 
 type validOperationR uint
 
@@ -9,29 +10,43 @@ type validOperationR uint
 R-type instructions, when func7 is F0
 */
 const (
-	Add validOperationR = iota
-	SLT
-	SLTU
-	And
-	Or
-	Xor
-	SLL
-	SRL
+	Add  validOperationR = 0
+	SLL  validOperationR = 1
+	SLT  validOperationR = 2
+	SLTU validOperationR = 3
+	Xor  validOperationR = 4
+	SRL  validOperationR = 5
+	Or   validOperationR = 6
+	And  validOperationR = 7
 )
 
 /*These constants define the valid operation codes for
-R-type instructions, when func7 is F0*/
+R-type instructions, when func7 is F1*/
 const (
-	Sub validOperationR = iota
-	SRA
+	Sub validOperationR = 0
+	SRA validOperationR = 5
+)
+
+/*These constants define the valid operation codes for
+R-type instructions of the M extension, when func7 is FM*/
+const (
+	Mul    validOperationR = 0
+	MulH   validOperationR = 1
+	MulHSU validOperationR = 2
+	MulHU  validOperationR = 3
+	Div    validOperationR = 4
+	DivU   validOperationR = 5
+	Rem    validOperationR = 6
+	RemU   validOperationR = 7
 )
 
 /*These constants are valid Funct7 constants
 for an R-type instruction.
 */
 const (
-	F0 funct7 = iota
-	F1
+	F0 funct7 = 0x00
+	F1 funct7 = 0x20
+	FM funct7 = 0x01
 )
 
 type executionFunctionR func(ex RiscVExecutor, dest uint, reg1 uint, reg2 uint)
@@ -71,6 +86,16 @@ func (ex *ExecutorR) Execute() {
 				Sub: (RiscVExecutor).sub,
 				SRA: (RiscVExecutor).shiftRightArithmetic,
 			},
+			FM: map[validOperationR](executionFunctionR){
+				Mul:    (RiscVExecutor).multiply,
+				MulH:   (RiscVExecutor).multiplyHigh,
+				MulHSU: (RiscVExecutor).multiplyHighSignedUnsigned,
+				MulHU:  (RiscVExecutor).multiplyHighUnsigned,
+				Div:    (RiscVExecutor).divide,
+				DivU:   (RiscVExecutor).divideUnsigned,
+				Rem:    (RiscVExecutor).remainder,
+				RemU:   (RiscVExecutor).remainderUnsigned,
+			},
 		},
 	}
 
@@ -79,13 +104,13 @@ func (ex *ExecutorR) Execute() {
 			if f, ok := m2[func3]; ok {
 				f(ex.Executor, dest, src1, src2)
 			} else {
-				panic(fmt.Sprintf("executionFunctionR: %d operation not found", func3))
+				panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionR: %d operation not found", func3)))
 			}
 		} else {
-			panic(fmt.Sprintf("executionFunctionR: %d func7 not found", func7))
+			panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionR: %d func7 not found", func7)))
 		}
 	} else {
-		panic(fmt.Sprintf("executionFunctionR: %d opcode not found", ex.Result.OpCode))
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionR: %d opcode not found", ex.Result.OpCode)))
 
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	Traps "github.com/chenhowa/computer/lib/traps"
 )
 
 /*ExecutorS contains instructions for executing
@@ -22,9 +23,17 @@ type validOperationS uint
 instruction is S-type and OpCode is Store
 */
 const (
-	StoreWord validOperationS = iota
-	StoreHalfWord
-	StoreByte
+	StoreByte     validOperationS = 0
+	StoreHalfWord validOperationS = 1
+	StoreWord     validOperationS = 2
+)
+
+/* These constants represent valid operations when
+instruction is S-type and OpCode is StoreFP
+*/
+const (
+	StoreFloat  validOperationS = 2
+	StoreDouble validOperationS = 3
 )
 
 /*Execute will execute the S-type instruction
@@ -41,15 +50,19 @@ func (ex *ExecutorS) Execute() {
 			StoreHalfWord: (RiscVExecutor).storeHalfWord,
 			StoreByte:     (RiscVExecutor).storeByte,
 		},
+		Parser.StoreFP: map[validOperationS](executionFunctionS){
+			StoreFloat:  (RiscVExecutor).storeFloat,
+			StoreDouble: (RiscVExecutor).storeDouble,
+		},
 	}
 
 	if m, ok := decision[ex.Result.OpCode]; ok {
 		if f, ok := m[func3]; ok {
 			f(ex.Executor, base, src, immediate)
 		} else {
-			panic(fmt.Sprintf("executionFunctionS: %d operation not found", func3))
+			panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionS: %d operation not found", func3)))
 		}
 	} else {
-		panic(fmt.Sprintf("executionFunctionS: %d opcode not found", ex.Result.OpCode))
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionS: %d opcode not found", ex.Result.OpCode)))
 	}
 }
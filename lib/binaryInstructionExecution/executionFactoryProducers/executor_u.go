@@ -2,6 +2,7 @@ package executionFactoryProducers
 
 import "fmt"
 import Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+import Traps "github.com/chenhowa/computer/lib/traps"
 
 /*ExecutorU stores execution of a
 U-type instruction ParseResult
@@ -23,6 +24,6 @@ func (ex *ExecutorU) Execute() {
 	case Parser.AUIPC:
 		ex.Executor.addUpperImmediateToPC(dest, immediate)
 	default:
-		panic(fmt.Sprintf("executionFunctionU: %d opcode not found", ex.Result.OpCode))
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("executionFunctionU: %d opcode not found", ex.Result.OpCode)))
 	}
 }
@@ -1,7 +1,7 @@
 package executionFactoryProducers
 
 /*The RiscVExecutor describes a receiver that is capable of
-executing all 32I RiscV instructions, using the fields encoded
+executing all 32I, 32M, 32A, 32F and 32D RiscV instructions, using the fields encoded
 in each type of instruction
 */
 type RiscVExecutor interface {
@@ -25,6 +25,14 @@ type RiscVExecutor interface {
 	shiftLeftLogical(dest uint, reg uint, shiftreg uint)
 	shiftRightLogical(dest uint, reg uint, shiftreg uint)
 	shiftRightArithmetic(dest uint, reg uint, shiftreg uint)
+	multiply(dest uint, reg1 uint, reg2 uint)
+	multiplyHigh(dest uint, reg1 uint, reg2 uint)
+	multiplyHighSignedUnsigned(dest uint, reg1 uint, reg2 uint)
+	multiplyHighUnsigned(dest uint, reg1 uint, reg2 uint)
+	divide(dest uint, reg1 uint, reg2 uint)
+	divideUnsigned(dest uint, reg1 uint, reg2 uint)
+	remainder(dest uint, reg1 uint, reg2 uint)
+	remainderUnsigned(dest uint, reg1 uint, reg2 uint)
 	branchEqual(src1 uint, src2 uint, offset uint32)
 	branchNotEqual(src1 uint, src2 uint, offset uint32)
 	branchLessThan(src1 uint, src2 uint, offset uint32)
@@ -41,6 +49,46 @@ type RiscVExecutor interface {
 	storeWord(reg1 uint, reg2 uint, offset uint32)
 	storeHalfWord(reg1 uint, reg2 uint, offset uint32)
 	storeByte(reg1 uint, reg2 uint, offset uint32)
+	loadReserved(dest uint, addressReg uint, src uint)
+	storeConditional(dest uint, addressReg uint, src uint)
+	atomicSwap(dest uint, addressReg uint, src uint)
+	atomicAdd(dest uint, addressReg uint, src uint)
+	atomicXor(dest uint, addressReg uint, src uint)
+	atomicAnd(dest uint, addressReg uint, src uint)
+	atomicOr(dest uint, addressReg uint, src uint)
+	atomicMin(dest uint, addressReg uint, src uint)
+	atomicMax(dest uint, addressReg uint, src uint)
+	atomicMinUnsigned(dest uint, addressReg uint, src uint)
+	atomicMaxUnsigned(dest uint, addressReg uint, src uint)
+	loadFloat(dest uint, reg uint, offset uint32)
+	loadDouble(dest uint, reg uint, offset uint32)
+	storeFloat(reg1 uint, reg2 uint, offset uint32)
+	storeDouble(reg1 uint, reg2 uint, offset uint32)
+	floatAdd(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatSubtract(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatMultiply(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatDivide(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatSquareRoot(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatConvert(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatSignInject(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatSignInjectNegated(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatSignInjectXor(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatMin(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatMax(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatEqual(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatLessThan(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatLessOrEqual(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatClassify(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatMoveToInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	intMoveToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatToInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatToUnsignedInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	intToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	unsignedIntToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint)
+	floatMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint)
+	floatMultiplySubtract(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint)
+	floatNegatedMultiplySubtract(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint)
+	floatNegatedMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint)
 	csrReadAndWrite(dest uint, reg uint, immediate uint32)
 	csrReadAndSet(dest uint, reg uint, immediate uint32)
 	csrReadAndClear(dest uint, reg uint, immediate uint32)
@@ -48,4 +96,5 @@ type RiscVExecutor interface {
 	csrReadAndSetImmediate(dest uint, reg uint, immediate uint32)
 	csrReadAndClearImmediate(dest uint, reg uint, immediate uint32)
 	private(dest uint, reg uint, immediate uint32)
+	fence(dest uint, reg uint, immediate uint32)
 }
@@ -72,6 +72,30 @@ func (em *RiscVExecutorMock) shiftRightArithmetic(dest uint, reg uint, shiftreg
 	em.Called(dest, reg, shiftreg)
 
 }
+func (em *RiscVExecutorMock) multiply(dest uint, reg1 uint, reg2 uint) {
+	em.Called(dest, reg1, reg2)
+}
+func (em *RiscVExecutorMock) multiplyHigh(dest uint, reg1 uint, reg2 uint) {
+	em.Called(dest, reg1, reg2)
+}
+func (em *RiscVExecutorMock) multiplyHighSignedUnsigned(dest uint, reg1 uint, reg2 uint) {
+	em.Called(dest, reg1, reg2)
+}
+func (em *RiscVExecutorMock) multiplyHighUnsigned(dest uint, reg1 uint, reg2 uint) {
+	em.Called(dest, reg1, reg2)
+}
+func (em *RiscVExecutorMock) divide(dest uint, reg1 uint, reg2 uint) {
+	em.Called(dest, reg1, reg2)
+}
+func (em *RiscVExecutorMock) divideUnsigned(dest uint, reg1 uint, reg2 uint) {
+	em.Called(dest, reg1, reg2)
+}
+func (em *RiscVExecutorMock) remainder(dest uint, reg1 uint, reg2 uint) {
+	em.Called(dest, reg1, reg2)
+}
+func (em *RiscVExecutorMock) remainderUnsigned(dest uint, reg1 uint, reg2 uint) {
+	em.Called(dest, reg1, reg2)
+}
 func (em *RiscVExecutorMock) branchEqual(src1 uint, src2 uint, offset uint32) {
 	em.Called(src1, src2, offset)
 }
@@ -131,6 +155,126 @@ func (em *RiscVExecutorMock) storeByte(reg1 uint, reg2 uint, offset uint32) {
 	em.Called(reg1, reg2, offset)
 
 }
+func (em *RiscVExecutorMock) loadReserved(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) storeConditional(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicSwap(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicAdd(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicXor(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicAnd(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicOr(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicMin(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicMax(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicMinUnsigned(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) atomicMaxUnsigned(dest uint, addressReg uint, src uint) {
+	em.Called(dest, addressReg, src)
+}
+func (em *RiscVExecutorMock) loadFloat(dest uint, reg uint, offset uint32) {
+	em.Called(dest, reg, offset)
+}
+func (em *RiscVExecutorMock) loadDouble(dest uint, reg uint, offset uint32) {
+	em.Called(dest, reg, offset)
+}
+func (em *RiscVExecutorMock) storeFloat(reg1 uint, reg2 uint, offset uint32) {
+	em.Called(reg1, reg2, offset)
+}
+func (em *RiscVExecutorMock) storeDouble(reg1 uint, reg2 uint, offset uint32) {
+	em.Called(reg1, reg2, offset)
+}
+func (em *RiscVExecutorMock) floatAdd(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatSubtract(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatMultiply(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatDivide(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatSquareRoot(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatConvert(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatSignInject(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatSignInjectNegated(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatSignInjectXor(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatMin(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatMax(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatEqual(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatLessThan(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatLessOrEqual(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatClassify(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatMoveToInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) intMoveToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatToInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatToUnsignedInt(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) intToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) unsignedIntToFloat(format uint, dest uint, reg1 uint, reg2 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, roundingMode)
+}
+func (em *RiscVExecutorMock) floatMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, reg3, roundingMode)
+}
+func (em *RiscVExecutorMock) floatMultiplySubtract(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, reg3, roundingMode)
+}
+func (em *RiscVExecutorMock) floatNegatedMultiplySubtract(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, reg3, roundingMode)
+}
+func (em *RiscVExecutorMock) floatNegatedMultiplyAdd(format uint, dest uint, reg1 uint, reg2 uint, reg3 uint, roundingMode uint) {
+	em.Called(format, dest, reg1, reg2, reg3, roundingMode)
+}
 func (em *RiscVExecutorMock) csrReadAndWrite(dest uint, reg uint, immediate uint32) {
 	em.Called(dest, reg, immediate)
 }
@@ -155,5 +299,8 @@ func (em *RiscVExecutorMock) csrReadAndClearImmediate(dest uint, reg uint, immed
 
 }
 func (em *RiscVExecutorMock) private(dest uint, reg uint, immediate uint32) {
-	em.private(dest, reg, immediate)
+	em.Called(dest, reg, immediate)
+}
+func (em *RiscVExecutorMock) fence(dest uint, reg uint, immediate uint32) {
+	em.Called(dest, reg, immediate)
 }
@@ -49,3 +49,151 @@ publishes it as a uint.
 func (builder *BinaryInstructionBuilder) Build() uint {
 	return builder.currentValue
 }
+
+/*BuildInstructionI builds a 32-bit I Format instruction out of the arguments.
+Uses lowest bits of arguments as follows:
+	- 7 bits of `opcode`
+	- 5 bits of `rd`
+	- 3 bits `funct3`
+	- 5 bits of `rs1`
+	- 12 bits of `immediate`
+*/
+func BuildInstructionI(opcode uint, rd uint, funct3 uint, rs1 uint, immediate uint) uint32 {
+	builder := MakeInstructionBuilder(32)
+	builder.AddNextXBits(7, opcode)
+	builder.AddNextXBits(5, rd)
+	builder.AddNextXBits(3, funct3)
+	builder.AddNextXBits(5, rs1)
+	builder.AddNextXBits(12, immediate)
+	instruction := builder.Build()
+	return uint32(instruction)
+}
+
+/*BuildInstructionU builds a 32-bit U Format instruction out of the arguments.
+Uses lowest bits of arguments as follows:
+	- 7 bits of `opcode`
+	- 5 bits of `rd`
+	- 20 bits of `immediate`*/
+func BuildInstructionU(opcode uint, rd uint, immediate uint) uint32 {
+	builder := MakeInstructionBuilder(32)
+	builder.AddNextXBits(7, opcode)
+	builder.AddNextXBits(5, rd)
+	builder.AddNextXBits(20, immediate)
+	return uint32(builder.Build())
+}
+
+/*BuildInstructionR builds a 32-bit R Format instruction out of the arguments.
+Uses lowest bits of arguments as follows:
+	- 7 bits of `opcode`
+	- 5 bits of `rd`
+	- 3 bits of `funct3`
+	- 5 bits of `rs1`
+	- 5 bits of `rs2`
+	- 7 bitse of `funct7`
+*/
+func BuildInstructionR(opcode uint, rd uint, funct3 uint, rs1 uint, rs2 uint, funct7 uint) uint32 {
+	builder := MakeInstructionBuilder(32)
+	builder.AddNextXBits(7, opcode)
+	builder.AddNextXBits(5, rd)
+	builder.AddNextXBits(3, funct3)
+	builder.AddNextXBits(5, rs1)
+	builder.AddNextXBits(5, rs2)
+	builder.AddNextXBits(7, funct7)
+	return uint32(builder.Build())
+}
+
+/*BuildInstructionR4 builds a 32-bit R4 Format instruction, the format of the fused multiply-add
+instructions, out of the arguments. Uses lowest bits of arguments as follows:
+	- 7 bits of `opcode`
+	- 5 bits of `rd`
+	- 3 bits of `rm`
+	- 5 bits of `rs1`
+	- 5 bits of `rs2`
+	- 2 bits of `format`
+	- 5 bits of `rs3`
+*/
+func BuildInstructionR4(opcode uint, rd uint, rm uint, rs1 uint, rs2 uint, rs3 uint, format uint) uint32 {
+	return BuildInstructionR(opcode, rd, rm, rs1, rs2, rs3<<2|format&3)
+}
+
+/*BuildInstructionA builds a 32-bit atomic instruction, which has the R Format, out of the arguments.
+Uses lowest bits of arguments as follows:
+	- 7 bits of `opcode`
+	- 5 bits of `rd`
+	- 3 bits of `funct3`
+	- 5 bits of `rs1`
+	- 5 bits of `rs2`
+	- 1 bit each for `release` and `acquire`
+	- 5 bits of `funct5`
+*/
+func BuildInstructionA(opcode uint, rd uint, funct3 uint, rs1 uint, rs2 uint, funct5 uint, acquire bool, release bool) uint32 {
+	ordering := uint(0)
+	if acquire {
+		ordering |= 2
+	}
+	if release {
+		ordering |= 1
+	}
+	return BuildInstructionR(opcode, rd, funct3, rs1, rs2, funct5<<2|ordering)
+}
+
+/*BuildInstructionJ builds a 32-bit J instruction out of the arguments.
+Uses lowest bits of arguments as follows:
+	- 7 bits of `opcode`
+	- 5 bits of `rd`
+	- 21 bits of the byte `offset`, whose lowest bit is dropped, as
+	  jump targets are always a multiple of 2 bytes away*/
+func BuildInstructionJ(opcode uint, rd uint, offset uint) uint32 {
+	builder := MakeInstructionBuilder(32)
+	builder.AddNextXBits(7, opcode)
+	builder.AddNextXBits(5, rd)
+	builder.AddNextXBits(8, uint(getBitsInInclusiveRange(offset, 12, 19)))
+	builder.AddNextXBits(1, uint(getBitsInInclusiveRange(offset, 11, 11)))
+	builder.AddNextXBits(10, uint(getBitsInInclusiveRange(offset, 1, 10)))
+	builder.AddNextXBits(1, uint(getBitsInInclusiveRange(offset, 20, 20)))
+
+	return uint32(builder.Build())
+}
+
+/*BuildInstructionB builds a 32-bit B instruction out of the arguments.
+Uses the lowest bits of arguments as follows:
+	- 7 bits of `opcode`
+	- 3 bits of `funct3`
+	- 5 bits of `rs1`
+	- 5 bits of `rs2`
+	- 13 bits of the byte offset `immediate`, whose lowest bit is dropped, as
+	  branch targets are always a multiple of 2 bytes away
+*/
+func BuildInstructionB(opcode uint, funct3 uint, rs1 uint, rs2 uint, immediate uint) uint32 {
+	builder := MakeInstructionBuilder(32)
+	builder.AddNextXBits(7, opcode)
+	builder.AddNextXBits(1, uint(getBitsInInclusiveRange(immediate, 11, 11)))
+	builder.AddNextXBits(4, uint(getBitsInInclusiveRange(immediate, 1, 4)))
+	builder.AddNextXBits(3, funct3)
+	builder.AddNextXBits(5, rs1)
+	builder.AddNextXBits(5, rs2)
+	builder.AddNextXBits(6, uint(getBitsInInclusiveRange(immediate, 5, 10)))
+	builder.AddNextXBits(1, uint(getBitsInInclusiveRange(immediate, 12, 12)))
+
+	return uint32(builder.Build())
+}
+
+/*BuildInstructionS builds a 32-bit S instruction out of the arguments.
+Uses the lowest bits of arguments as follows:
+	- 7 bits of `opcode`
+	- 3 bits of `funct3`
+	- 5 bits of `rs1`, the base register
+	- 5 bits of `rs2`, the source register
+	- 12 bits of `immediate`
+*/
+func BuildInstructionS(opcode uint, funct3 uint, rs1 uint, rs2 uint, immediate uint) uint32 {
+	builder := MakeInstructionBuilder(32)
+	builder.AddNextXBits(7, opcode)
+	builder.AddNextXBits(5, uint(getBitsInInclusiveRange(immediate, 0, 4)))
+	builder.AddNextXBits(3, funct3)
+	builder.AddNextXBits(5, rs1)
+	builder.AddNextXBits(5, rs2)
+	builder.AddNextXBits(7, uint(getBitsInInclusiveRange(immediate, 5, 11)))
+
+	return uint32(builder.Build())
+}
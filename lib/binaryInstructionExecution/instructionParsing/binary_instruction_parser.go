@@ -2,6 +2,7 @@ package instructionParsing
 
 import "fmt"
 import Utils "github.com/chenhowa/computer/lib/binaryInstructionExecution/bitUtils"
+import Traps "github.com/chenhowa/computer/lib/traps"
 
 /*RiscVBinaryInstructionParser parses 32-bit binary instructions
 into the appropriate fields
@@ -15,55 +16,83 @@ type RiscVBinaryInstructionParser struct {
 */
 type OpCode uint
 
-/*These constants represent the various opcodes of instructions
- */
+/*These constants represent the various opcodes of instructions. Their values are the
+major opcodes of the RISC-V specification, which take up the lowest 7 bits of each instruction
+*/
 const (
-	ImmArith OpCode = iota
-	LUI
-	AUIPC
-	RegArith
-	JAL
-	JALR
-	Branch
-	Load
-	Store
-	System
+	Load     OpCode = 0x03
+	LoadFP   OpCode = 0x07 // FLW and FLD
+	MiscMem  OpCode = 0x0F // FENCE and FENCE.I
+	ImmArith OpCode = 0x13
+	AUIPC    OpCode = 0x17
+	Store    OpCode = 0x23
+	StoreFP  OpCode = 0x27 // FSW and FSD
+	AMO      OpCode = 0x2F // atomic memory operations
+	RegArith OpCode = 0x33
+	LUI      OpCode = 0x37
+	FMAdd    OpCode = 0x43 // the fused multiply-add instructions, which have the R4 layout
+	FMSub    OpCode = 0x47
+	FNMSub   OpCode = 0x4B
+	FNMAdd   OpCode = 0x4F
+	OpFP     OpCode = 0x53 // floating-point arithmetic, comparisons, conversions and moves
+	Branch   OpCode = 0x63
+	JALR     OpCode = 0x67
+	JAL      OpCode = 0x6F
+	System   OpCode = 0x73
 )
 
 /*Parse will take a 32 bit instruction and parse its
 fields into the relevant values BY INSTRUCTION TYPE,
 and then return the results in a Struct.
+
+Floating-point instructions are parsed as F-type when their `fmt` field selects single precision,
+and as D-type when it selects double precision. FLW, FLD, FSW and FSD keep the I-type and
+S-type layouts of the loads and stores.
+
+The immediates of B-type and J-type instructions are offsets in multiples of 2 bytes,
+because the spec leaves out their lowest bit, which is always 0. They are returned
+as they are encoded: bits 12 to 1 and bits 20 to 1 of the offset, respectively.
+
+When the lowest 16 bits of `instruction` are a compressed instruction, the upper 16 bits are ignored,
+and the result is that of the 32-bit instruction it expands to, marked as Compressed. The C type
+is not used, since every compressed instruction keeps the type of the instruction it stands for.
 */
 func (parser *RiscVBinaryInstructionParser) Parse(instruction uint32) RiscVBinaryParseResult {
+	if IsCompressed(instruction) {
+		result := parser.Parse(ExpandCompressed(uint16(instruction)))
+		result.Compressed = true
+		return result
+	}
+
 	opcode := OpCode(((1 << 7) - 1) & instruction)
 	var result RiscVBinaryParseResult
-
-	if opcode < ImmArith+1 { // immediate arithmetic
+	switch opcode {
+	case ImmArith, JALR, Load, LoadFP, MiscMem, System:
 		result = parseAsI(instruction)
-	} else if opcode < AUIPC+1 {
+	case LUI, AUIPC:
 		result = parseAsU(instruction)
-	} else if opcode < RegArith+1 { // register-register arithmetic
+	case RegArith:
 		result = parseAsR(instruction)
-	} else if opcode < JAL+1 { // jump and link
+	case JAL:
 		result = parseAsJ(instruction)
-	} else if opcode < JALR+1 { // jump and link register
-		result = parseAsI(instruction)
-	} else if opcode < Branch+1 { // branch
+	case Branch:
 		result = parseAsB(instruction)
-	} else if opcode < Load+1 { // loads
-		result = parseAsI(instruction)
-	} else if opcode < Store+1 { // stores
+	case Store, StoreFP:
 		result = parseAsS(instruction)
-	} else if opcode < System+1 { // system (csr)
-		result = parseAsI(instruction)
-	} else {
-		panic(fmt.Sprintf("unrecognized opcode %d", opcode))
+	case AMO:
+		result = parseAsA(instruction)
+	case OpFP:
+		result = parseAsFloat(instruction)
+	case FMAdd, FMSub, FNMSub, FNMAdd:
+		result = parseAsR4(instruction)
+	default:
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("unrecognized opcode %d", opcode)))
 	}
+
 	result.errorIfInvalid()
 
 	// Tag with the opcode, which was already calculated
 	result.OpCode = opcode
-
 	return result
 }
 
@@ -110,6 +139,50 @@ func parseAsR(instruction uint32) RiscVBinaryParseResult {
 	return result
 }
 
+/*parseAsA parses an atomic instruction, which has the R-type layout, except that the upper
+5 bits of its Funct7 select the operation, and the lower 2 bits are its acquire and release bits*/
+func parseAsA(instruction uint32) RiscVBinaryParseResult {
+	result := parseAsR(instruction)
+	result.InstructionType = A
+	result.Funct5 = result.Funct7 >> 2
+	result.Acquire = (result.Funct7>>1)&1 == 1
+	result.Release = result.Funct7&1 == 1
+
+	return result
+}
+
+/*parseAsFloat parses an OP-FP instruction, which has the R-type layout, except that the upper
+5 bits of its Funct7 select the operation, and the lower 2 bits are its `fmt`. The Funct3
+holds the rounding mode of the operations that round*/
+func parseAsFloat(instruction uint32) RiscVBinaryParseResult {
+	result := parseAsR(instruction)
+	result.Funct5 = result.Funct7 >> 2
+	result.Funct2 = result.Funct7 & 3
+	result.InstructionType = floatType(result.Funct2)
+
+	return result
+}
+
+/*parseAsR4 parses a fused multiply-add instruction, which has the R-type layout, except that
+the upper 5 bits of its Funct7 are a third source register, and the lower 2 bits are its `fmt`*/
+func parseAsR4(instruction uint32) RiscVBinaryParseResult {
+	result := parseAsR(instruction)
+	result.FiveBitRegister3 = result.Funct7 >> 2
+	result.Funct2 = result.Funct7 & 3
+	result.InstructionType = floatType(result.Funct2)
+
+	return result
+}
+
+/*floatType returns the instruction type of a floating-point instruction with the `fmt` field `format`.
+The half and quad precision formats are parsed as F-type, and left to the executor to reject*/
+func floatType(format uint8) InstructionType {
+	if format == 1 {
+		return D
+	}
+	return F
+}
+
 func parseAsJ(instruction uint32) RiscVBinaryParseResult {
 	result := RiscVBinaryParseResult{InstructionType: J}
 	var uintInstruction = uint(instruction)
@@ -199,6 +272,12 @@ type RiscVBinaryParseResult struct {
 	Funct3             uint8
 	Funct7             uint8
 	TwentyBitImmediate uint32
+	Funct5             uint8 // the operation of an atomic instruction
+	Acquire            bool  // whether an atomic instruction has acquire ordering
+	Release            bool  // whether an atomic instruction has release ordering
+	FiveBitRegister3   uint8 // the third source register of a fused multiply-add instruction
+	Funct2             uint8 // the `fmt` of a floating-point instruction: 0 for single and 1 for double precision
+	Compressed         bool  // whether the instruction was expanded from a 16-bit compressed instruction
 }
 
 // the purpose of this function is to check that all values that populate
@@ -217,6 +296,12 @@ func (result RiscVBinaryParseResult) errorIfInvalid() {
 		panic(fmt.Sprintf("invalid binary parse result Funct3 %d", result.Funct3))
 	} else if xIsGreaterThanYBits(uint(result.Funct7), 7) {
 		panic(fmt.Sprintf("invalid binary parse result Funct7 %d", result.Funct7))
+	} else if xIsGreaterThanYBits(uint(result.Funct5), 5) {
+		panic(fmt.Sprintf("invalid binary parse result Funct5 %d", result.Funct5))
+	} else if xIsGreaterThanYBits(uint(result.FiveBitRegister3), 5) {
+		panic(fmt.Sprintf("invalid binary parse result FiveBitRegister3 %d", result.FiveBitRegister3))
+	} else if xIsGreaterThanYBits(uint(result.Funct2), 2) {
+		panic(fmt.Sprintf("invalid binary parse result Funct2 %d", result.Funct2))
 	} else if xIsGreaterThanYBits(uint(result.TwentyBitImmediate), 20) {
 		panic(fmt.Sprintf("invalid binary parse result TwentyBitImmediate %d", result.TwentyBitImmediate))
 	}
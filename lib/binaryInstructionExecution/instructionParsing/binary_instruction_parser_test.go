@@ -277,3 +277,118 @@ func (suite *ParseSuite) TestParseSystem() {
 
 	assert.Equal(expected, actual)
 }
+
+func (suite *ParseSuite) TestParseMiscMem() {
+	assert := assert.New(suite.T())
+
+	// fence iorw, iorw
+	actual := suite.parser.Parse(0x0FF0000F)
+
+	expected := RiscVBinaryParseResult{
+		InstructionType:    I,
+		OpCode:             MiscMem,
+		TwelveBitImmediate: 0xFF,
+	}
+
+	assert.Equal(expected, actual)
+}
+
+func (suite *ParseSuite) TestParseToolchainEncoding() {
+	assert := assert.New(suite.T())
+
+	// addi a0, zero, 5 as encoded by a standard RISC-V assembler
+	actual := suite.parser.Parse(0x00500513)
+
+	expected := RiscVBinaryParseResult{
+		InstructionType:    I,
+		OpCode:             ImmArith,
+		FiveBitDestination: 10,
+		TwelveBitImmediate: 5,
+	}
+
+	assert.Equal(expected, actual)
+}
+
+func (suite *ParseSuite) TestParseAtomic() {
+	assert := assert.New(suite.T())
+
+	// amoswap.w.aq x1, x2, (x3)
+	actual := suite.parser.Parse(0x0C21A0AF)
+
+	expected := RiscVBinaryParseResult{
+		InstructionType:    A,
+		OpCode:             AMO,
+		FiveBitDestination: 1,
+		FiveBitRegister1:   3,
+		FiveBitRegister2:   2,
+		Funct3:             2,
+		Funct7:             0x06,
+		Funct5:             0x01,
+		Acquire:            true,
+		Release:            false,
+	}
+
+	assert.Equal(expected, actual)
+}
+
+func (suite *ParseSuite) TestParseFloat() {
+	assert := assert.New(suite.T())
+
+	// fadd.s f1, f2, f3 with the dynamic rounding mode
+	actual := suite.parser.Parse(0x003170D3)
+
+	expected := RiscVBinaryParseResult{
+		InstructionType:    F,
+		OpCode:             OpFP,
+		FiveBitDestination: 1,
+		FiveBitRegister1:   2,
+		FiveBitRegister2:   3,
+		Funct3:             7,
+	}
+
+	assert.Equal(expected, actual)
+
+	// fsqrt.d f1, f2 rounding towards zero
+	actual = suite.parser.Parse(0x5A0110D3)
+
+	expected = RiscVBinaryParseResult{
+		InstructionType:    D,
+		OpCode:             OpFP,
+		FiveBitDestination: 1,
+		FiveBitRegister1:   2,
+		Funct3:             1,
+		Funct7:             0x2D,
+		Funct5:             0x0B,
+		Funct2:             1,
+	}
+
+	assert.Equal(expected, actual)
+}
+
+func (suite *ParseSuite) TestParseR4() {
+	assert := assert.New(suite.T())
+
+	// fmadd.d f1, f2, f3, f4 with the dynamic rounding mode
+	actual := suite.parser.Parse(0x223170C3)
+
+	expected := RiscVBinaryParseResult{
+		InstructionType:    D,
+		OpCode:             FMAdd,
+		FiveBitDestination: 1,
+		FiveBitRegister1:   2,
+		FiveBitRegister2:   3,
+		FiveBitRegister3:   4,
+		Funct3:             7,
+		Funct7:             0x11,
+		Funct2:             1,
+	}
+
+	assert.Equal(expected, actual)
+}
+
+func (suite *ParseSuite) TestParseUnrecognizedOpcode() {
+	assert := assert.New(suite.T())
+
+	assert.Panics(func() { suite.parser.Parse(0) })
+	assert.Panics(func() { suite.parser.Parse(0x7F) })
+}
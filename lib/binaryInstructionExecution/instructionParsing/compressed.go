@@ -0,0 +1,254 @@
+package instructionParsing
+
+import "fmt"
+import Traps "github.com/chenhowa/computer/lib/traps"
+
+/*IsCompressed returns whether `instruction` is a 16-bit compressed instruction. Compressed instructions
+are the ones whose lowest two bits are not both set; those bits are 0b11 for every 32-bit instruction*/
+func IsCompressed(instruction uint32) bool {
+	return instruction&3 != 3
+}
+
+/*These constants are the Funct3 values of the base instructions that compressed instructions expand to.
+The Funct3 of a load or store is the width it accesses. These mirror the constants the executors use,
+which cannot be imported here*/
+const (
+	addFunct3     = 0
+	shiftLeft     = 1
+	wordWidth     = 2
+	doubleWidth   = 3
+	xorFunct3     = 4
+	shiftRight    = 5
+	orFunct3      = 6
+	andFunct3     = 7
+	branchEqual   = 0
+	branchNotEq   = 1
+	subtractFlag  = 0x20  // the Funct7 of SUB
+	arithmeticBit = 0x400 // the immediate bit that makes a right shift arithmetic
+	linkRegister  = 1
+	stackPointer  = 2
+)
+
+/*ExpandCompressed returns the 32-bit instruction that the 16-bit compressed `instruction` stands for.
+All of RV32C is supported, including the loads and stores of the F and D extensions.
+Illegal and reserved encodings, including the all-zero instruction, panic with an illegal instruction
+exception, just as unrecognized opcodes do*/
+func ExpandCompressed(instruction uint16) uint32 {
+	c := uint(instruction)
+	quadrant := c & 3
+	funct3 := field(c, 13, 15)
+
+	var expanded uint32
+	switch quadrant {
+	case 0:
+		expanded = expandQuadrant0(c, funct3)
+	case 1:
+		expanded = expandQuadrant1(c, funct3)
+	case 2:
+		expanded = expandQuadrant2(c, funct3)
+	default:
+		panic(fmt.Sprintf("ExpandCompressed: %#04x is not a compressed instruction", instruction))
+	}
+
+	if expanded == 0 {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0,
+			fmt.Sprintf("ExpandCompressed: %#04x is a reserved or illegal compressed instruction", instruction)))
+	}
+	return expanded
+}
+
+/*expandQuadrant0 expands the loads and stores whose registers are among x8 to x15, and C.ADDI4SPN.
+It returns 0 for reserved encodings*/
+func expandQuadrant0(c uint, funct3 uint) uint32 {
+	low := compactRegister(c, 2)
+	high := compactRegister(c, 7)
+	wordOffset := place(c, 10, 12, 3) | place(c, 6, 6, 2) | place(c, 5, 5, 6)
+	doubleOffset := place(c, 10, 12, 3) | place(c, 5, 6, 6)
+
+	switch funct3 {
+	case 0: // C.ADDI4SPN
+		immediate := place(c, 11, 12, 4) | place(c, 7, 10, 6) | place(c, 6, 6, 2) | place(c, 5, 5, 3)
+		if immediate == 0 {
+			return 0
+		}
+		return BuildInstructionI(uint(ImmArith), low, addFunct3, stackPointer, immediate)
+	case 1: // C.FLD
+		return BuildInstructionI(uint(LoadFP), low, doubleWidth, high, doubleOffset)
+	case 2: // C.LW
+		return BuildInstructionI(uint(Load), low, wordWidth, high, wordOffset)
+	case 3: // C.FLW
+		return BuildInstructionI(uint(LoadFP), low, wordWidth, high, wordOffset)
+	case 5: // C.FSD
+		return BuildInstructionS(uint(StoreFP), doubleWidth, high, low, doubleOffset)
+	case 6: // C.SW
+		return BuildInstructionS(uint(Store), wordWidth, high, low, wordOffset)
+	case 7: // C.FSW
+		return BuildInstructionS(uint(StoreFP), wordWidth, high, low, wordOffset)
+	}
+	return 0
+}
+
+/*expandQuadrant1 expands the arithmetic with immediates, the jumps and the branches.
+It returns 0 for reserved encodings*/
+func expandQuadrant1(c uint, funct3 uint) uint32 {
+	rd := field(c, 7, 11)
+	immediate := signExtend(place(c, 12, 12, 5)|field(c, 2, 6), 6)
+	jumpOffset := signExtend(place(c, 12, 12, 11)|place(c, 11, 11, 4)|place(c, 9, 10, 8)|place(c, 8, 8, 10)|
+		place(c, 7, 7, 6)|place(c, 6, 6, 7)|place(c, 3, 5, 1)|place(c, 2, 2, 5), 12)
+	branchOffset := signExtend(place(c, 12, 12, 8)|place(c, 10, 11, 3)|place(c, 5, 6, 6)|
+		place(c, 3, 4, 1)|place(c, 2, 2, 5), 9)
+
+	switch funct3 {
+	case 0: // C.ADDI, or C.NOP when rd is x0
+		return BuildInstructionI(uint(ImmArith), rd, addFunct3, rd, immediate)
+	case 1: // C.JAL
+		return BuildInstructionJ(uint(JAL), linkRegister, jumpOffset)
+	case 2: // C.LI
+		return BuildInstructionI(uint(ImmArith), rd, addFunct3, 0, immediate)
+	case 3:
+		if rd == stackPointer { // C.ADDI16SP
+			offset := signExtend(place(c, 12, 12, 9)|place(c, 6, 6, 4)|place(c, 5, 5, 6)|
+				place(c, 3, 4, 7)|place(c, 2, 2, 5), 10)
+			if offset == 0 {
+				return 0
+			}
+			return BuildInstructionI(uint(ImmArith), stackPointer, addFunct3, stackPointer, offset)
+		}
+		if immediate == 0 { // C.LUI
+			return 0
+		}
+		return BuildInstructionU(uint(LUI), rd, immediate)
+	case 4:
+		return expandArithmetic(c)
+	case 5: // C.J
+		return BuildInstructionJ(uint(JAL), 0, jumpOffset)
+	case 6: // C.BEQZ
+		return BuildInstructionB(uint(Branch), branchEqual, compactRegister(c, 7), 0, branchOffset)
+	case 7: // C.BNEZ
+		return BuildInstructionB(uint(Branch), branchNotEq, compactRegister(c, 7), 0, branchOffset)
+	}
+	return 0
+}
+
+/*expandArithmetic expands the arithmetic whose registers are among x8 to x15.
+It returns 0 for reserved encodings, which include the shifts by more than 31*/
+func expandArithmetic(c uint) uint32 {
+	rd := compactRegister(c, 7)
+	shamt := field(c, 2, 6)
+	shiftTooFar := field(c, 12, 12) == 1
+
+	switch field(c, 10, 11) {
+	case 0: // C.SRLI
+		if shiftTooFar {
+			return 0
+		}
+		return BuildInstructionI(uint(ImmArith), rd, shiftRight, rd, shamt)
+	case 1: // C.SRAI
+		if shiftTooFar {
+			return 0
+		}
+		return BuildInstructionI(uint(ImmArith), rd, shiftRight, rd, shamt|arithmeticBit)
+	case 2: // C.ANDI
+		return BuildInstructionI(uint(ImmArith), rd, andFunct3, rd, signExtend(place(c, 12, 12, 5)|shamt, 6))
+	}
+
+	if field(c, 12, 12) == 1 { // the RV64C word arithmetic
+		return 0
+	}
+	rs2 := compactRegister(c, 2)
+	switch field(c, 5, 6) {
+	case 0: // C.SUB
+		return BuildInstructionR(uint(RegArith), rd, addFunct3, rd, rs2, subtractFlag)
+	case 1: // C.XOR
+		return BuildInstructionR(uint(RegArith), rd, xorFunct3, rd, rs2, 0)
+	case 2: // C.OR
+		return BuildInstructionR(uint(RegArith), rd, orFunct3, rd, rs2, 0)
+	default: // C.AND
+		return BuildInstructionR(uint(RegArith), rd, andFunct3, rd, rs2, 0)
+	}
+}
+
+/*expandQuadrant2 expands the instructions that address the stack, C.SLLI, and the register moves, jumps
+and additions. It returns 0 for reserved encodings*/
+func expandQuadrant2(c uint, funct3 uint) uint32 {
+	rd := field(c, 7, 11)
+	rs2 := field(c, 2, 6)
+	bit12 := field(c, 12, 12)
+
+	switch funct3 {
+	case 0: // C.SLLI
+		if bit12 == 1 {
+			return 0
+		}
+		return BuildInstructionI(uint(ImmArith), rd, shiftLeft, rd, rs2)
+	case 1: // C.FLDSP
+		offset := place(c, 12, 12, 5) | place(c, 5, 6, 3) | place(c, 2, 4, 6)
+		return BuildInstructionI(uint(LoadFP), rd, doubleWidth, stackPointer, offset)
+	case 2, 3: // C.LWSP and C.FLWSP
+		offset := place(c, 12, 12, 5) | place(c, 4, 6, 2) | place(c, 2, 3, 6)
+		if funct3 == 3 {
+			return BuildInstructionI(uint(LoadFP), rd, wordWidth, stackPointer, offset)
+		}
+		if rd == 0 {
+			return 0
+		}
+		return BuildInstructionI(uint(Load), rd, wordWidth, stackPointer, offset)
+	case 4:
+		return expandRegisterOperation(rd, rs2, bit12 == 1)
+	case 5: // C.FSDSP
+		offset := place(c, 10, 12, 3) | place(c, 7, 9, 6)
+		return BuildInstructionS(uint(StoreFP), doubleWidth, stackPointer, rs2, offset)
+	case 6, 7: // C.SWSP and C.FSWSP
+		offset := place(c, 9, 12, 2) | place(c, 7, 8, 6)
+		opcode := Store
+		if funct3 == 7 {
+			opcode = StoreFP
+		}
+		return BuildInstructionS(uint(opcode), wordWidth, stackPointer, rs2, offset)
+	}
+	return 0
+}
+
+/*expandRegisterOperation expands C.JR, C.MV, C.EBREAK, C.JALR and C.ADD, which all share a Funct3*/
+func expandRegisterOperation(rd uint, rs2 uint, link bool) uint32 {
+	switch {
+	case !link && rs2 == 0: // C.JR
+		if rd == 0 {
+			return 0
+		}
+		return BuildInstructionI(uint(JALR), 0, 0, rd, 0)
+	case !link: // C.MV
+		return BuildInstructionR(uint(RegArith), rd, addFunct3, 0, rs2, 0)
+	case rd == 0 && rs2 == 0: // C.EBREAK
+		return BuildInstructionI(uint(System), 0, 0, 0, 1)
+	case rs2 == 0: // C.JALR
+		return BuildInstructionI(uint(JALR), linkRegister, 0, rd, 0)
+	default: // C.ADD
+		return BuildInstructionR(uint(RegArith), rd, addFunct3, rd, rs2, 0)
+	}
+}
+
+/*field returns bits `start` to `end` of the compressed instruction `c`*/
+func field(c uint, start uint, end uint) uint {
+	return getBitsInInclusiveRange(c, start, end)
+}
+
+/*place returns bits `start` to `end` of `c`, moved so that bit `start` lands at bit `to`. Compressed
+instructions scatter the bits of their immediates, so each immediate is put back together out of these*/
+func place(c uint, start uint, end uint, to uint) uint {
+	return field(c, start, end) << to
+}
+
+/*compactRegister returns the register named by the 3 bits of `c` starting at `start`,
+which can only name the registers x8 to x15*/
+func compactRegister(c uint, start uint) uint {
+	return field(c, start, start+2) + 8
+}
+
+/*signExtend extends the `width`-bit immediate `value` to all the bits of a uint*/
+func signExtend(value uint, width uint) uint {
+	if value&(1<<(width-1)) != 0 {
+		return value | ^uint(0)<<width
+	}
+	return value
+}
@@ -0,0 +1,88 @@
+package instructionParsing
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+/*compressedEncodings pairs each compressed instruction with the 32-bit instruction that it expands to,
+as the GNU and LLVM toolchains encode them*/
+var compressedEncodings = []struct {
+	compressed uint16
+	expanded   uint32
+}{
+	{0x1fe0, 0x3fc10413}, // C.ADDI4SPN x8, x2, 1020
+	{0x3fe4, 0x0f87b487}, // C.FLD f9, 248(x15)
+	{0x5de8, 0x07c5a503}, // C.LW x10, 124(x11)
+	{0x62b0, 0x0406a607}, // C.FLW f12, 64(x13)
+	{0xa418, 0x00e43427}, // C.FSD f14, 8(x8)
+	{0xc0dc, 0x00f4a223}, // C.SW x15, 4(x9)
+	{0xffe0, 0x0687ae27}, // C.FSW f8, 124(x15)
+	{0x0001, 0x00000013}, // C.NOP
+	{0x1281, 0xfe028293}, // C.ADDI x5, -32
+	{0x3001, 0x801ff0ef}, // C.JAL -2048
+	{0x437d, 0x01f00313}, // C.LI x6, 31
+	{0x7101, 0xe0010113}, // C.ADDI16SP x2, -512
+	{0x7381, 0xfffe03b7}, // C.LUI x7, 0xfffe0
+	{0x807d, 0x01f45413}, // C.SRLI x8, 31
+	{0x8485, 0x4014d493}, // C.SRAI x9, 1
+	{0x997d, 0xfff57513}, // C.ANDI x10, -1
+	{0x8d91, 0x40c585b3}, // C.SUB x11, x12
+	{0x8eb9, 0x00e6c6b3}, // C.XOR x13, x14
+	{0x8fc1, 0x0087e7b3}, // C.OR x15, x8
+	{0x8c65, 0x00947433}, // C.AND x8, x9
+	{0xaffd, 0x7fe0006f}, // C.J 2046
+	{0xd081, 0xf00480e3}, // C.BEQZ x9, -256
+	{0xed7d, 0x0e051f63}, // C.BNEZ x10, 254
+	{0x0fc6, 0x011f9f93}, // C.SLLI x31, 17
+	{0x30fe, 0x1f813087}, // C.FLDSP f1, 504(x2)
+	{0x517e, 0x0fc12103}, // C.LWSP x2, 252(x2)
+	{0x6192, 0x00412187}, // C.FLWSP f3, 4(x2)
+	{0x8082, 0x00008067}, // C.JR x1
+	{0x8216, 0x00500233}, // C.MV x4, x5
+	{0x9002, 0x00100073}, // C.EBREAK
+	{0x9302, 0x000300e7}, // C.JALR x6
+	{0x93a2, 0x008383b3}, // C.ADD x7, x8
+	{0xbfa6, 0x1e913c27}, // C.FSDSP f9, 504(x2)
+	{0xdfaa, 0x0ea12e23}, // C.SWSP x10, 252(x2)
+	{0xe02e, 0x00b12027}, // C.FSWSP f11, 0(x2)
+}
+
+func (suite *ParseSuite) TestExpandCompressed() {
+	assert := assert.New(suite.T())
+
+	for _, encoding := range compressedEncodings {
+		assert.True(IsCompressed(uint32(encoding.compressed)))
+		assert.Equal(encoding.expanded, ExpandCompressed(encoding.compressed), "%#04x", encoding.compressed)
+	}
+	assert.False(IsCompressed(0x00000013))
+}
+
+func (suite *ParseSuite) TestExpandCompressed_Reserved() {
+	assert := assert.New(suite.T())
+
+	for _, instruction := range []uint16{
+		0x0000, // the all-zero instruction
+		0x8000, // reserved in quadrant 0
+		0x6101, // C.ADDI16SP by 0
+		0x6081, // C.LUI with an immediate of 0
+		0x9005, // C.SRLI by more than 31
+		0x9C01, // C.SUBW, which is RV64 only
+		0x4002, // C.LWSP to x0
+		0x8002, // C.JR x0
+		0x1006, // C.SLLI by more than 31
+	} {
+		assert.Panics(func() { ExpandCompressed(instruction) }, "%#04x", instruction)
+	}
+}
+
+func (suite *ParseSuite) TestParseCompressed() {
+	assert := assert.New(suite.T())
+
+	// c.beqz x9, -256, with another instruction in the upper 16 bits
+	actual := suite.parser.Parse(0x1234D081)
+	expected := suite.parser.Parse(0xF00480E3)
+	expected.Compressed = true
+
+	assert.Equal(expected, actual)
+	assert.Equal(B, actual.InstructionType)
+}
@@ -19,14 +19,21 @@ func MakeClock(waiter waiter) Clock {
 }
 
 type waiter interface {
-	wait()
+	Delay()
 }
 
-func (c *Clock) waitForNextCycle() {
-	c.waiter.wait()
-	c.count += 1
+/*Tick waits until the next clock cycle begins, and then counts that cycle*/
+func (c *Clock) Tick() {
+	c.waiter.Delay()
+	c.count++
 }
 
-func (c *Clock) reset() {
+/*GetCount returns the number of cycles that have ticked since the clock was constructed or last reset*/
+func (c *Clock) GetCount() uint {
+	return c.count
+}
+
+/*Reset sets the count of cycles back to 0*/
+func (c *Clock) Reset() {
 	c.count = 0
 }
@@ -0,0 +1,11 @@
+package delay
+
+/*NoDelay represents a delay that does not delay at all, so that
+a clock using it ticks as fast as the simulation can run*/
+type NoDelay struct {
+}
+
+/*Delay returns immediately*/
+func (d *NoDelay) Delay() {
+	return
+}
@@ -7,6 +7,20 @@ import (
 /*TimeDelay will delay for a given amount of time by waiting on a go channel.
  */
 type TimeDelay struct {
+	period time.Duration
+}
+
+/*MakeTimeDelay is a constructor for a TimeDelay whose Delay waits for `period`, so that a clock
+using it ticks at wall-clock rate, once every `period`*/
+func MakeTimeDelay(period time.Duration) TimeDelay {
+	return TimeDelay{
+		period: period,
+	}
+}
+
+/*Delay pauses the executing goroutine for the period of the TimeDelay*/
+func (d *TimeDelay) Delay() {
+	time.Sleep(d.period)
 }
 
 /*DelayForMilliseconds pauses the executing goroutine for specified number of `milliseconds`
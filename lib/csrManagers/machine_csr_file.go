@@ -0,0 +1,660 @@
+package csrManagers
+
+import (
+	"errors"
+	"fmt"
+
+	Traps "github.com/chenhowa/computer/lib/traps"
+)
+
+/*ErrIllegalCsrAccess is the error that MachineCsrFile panics with, wrapped, when a CSR is accessed in a way
+that the spec makes an illegal instruction: the CSR does not exist, the hart does not have the privilege
+that the CSR needs, or the CSR is read-only and is being written*/
+var ErrIllegalCsrAccess = errors.New("illegal CSR access")
+
+/*Privilege is a privilege level that a hart runs at. Its values are those of the spec,
+which are also the ones that the bits 9 to 8 of a CSR number hold*/
+type Privilege uint8
+
+/*These constants are the privilege levels*/
+const (
+	User       Privilege = 0
+	Supervisor Privilege = 1
+	Machine    Privilege = 3
+)
+
+/*These constants are the numbers of the CSRs that MachineCsrFile holds*/
+const (
+	Sstatus       uint = 0x100
+	Sie           uint = 0x104
+	Stvec         uint = 0x105
+	Scounteren    uint = 0x106
+	Sscratch      uint = 0x140
+	Sepc          uint = 0x141
+	Scause        uint = 0x142
+	Stval         uint = 0x143
+	Sip           uint = 0x144
+	Satp          uint = 0x180
+	Mstatus       uint = 0x300
+	Misa          uint = 0x301
+	Medeleg       uint = 0x302
+	Mideleg       uint = 0x303
+	Mie           uint = 0x304
+	Mtvec         uint = 0x305
+	Mcounteren    uint = 0x306
+	Mstatush      uint = 0x310
+	Mhpmevent3    uint = 0x323 // the first of the events of mhpmcounter3 to mhpmcounter31
+	Mscratch      uint = 0x340
+	Mepc          uint = 0x341
+	Mcause        uint = 0x342
+	Mtval         uint = 0x343
+	Mip           uint = 0x344
+	Pmpcfg0       uint = 0x3A0 // the first of pmpcfg0 to pmpcfg3
+	Pmpaddr0      uint = 0x3B0 // the first of pmpaddr0 to pmpaddr15
+	Mcycle        uint = 0xB00
+	Minstret      uint = 0xB02
+	Mhpmcounter3  uint = 0xB03
+	Mcycleh       uint = 0xB80
+	Minstreth     uint = 0xB82
+	Mhpmcounter3h uint = 0xB83
+	Cycle         uint = 0xC00
+	Time          uint = 0xC01
+	Instret       uint = 0xC02
+	Hpmcounter3   uint = 0xC03
+	Cycleh        uint = 0xC80
+	Timeh         uint = 0xC81
+	Instreth      uint = 0xC82
+	Hpmcounter3h  uint = 0xC83
+	Mvendorid     uint = 0xF11
+	Marchid       uint = 0xF12
+	Mimpid        uint = 0xF13
+	Mhartid       uint = 0xF14
+)
+
+/*hpmCounters is the number of the hardware performance-monitoring counters, mhpmcounter3 to mhpmcounter31*/
+const hpmCounters = 29
+
+/*These constants are the fields of mstatus. SD is read-only, and summarizes whether FS is dirty.
+MPRV, SUM and MXR change how loads and stores are translated by the MMU.
+TVM, TW and TSR make supervisor mode trap on satp accesses and SFENCE.VMA, WFI and SRET, respectively*/
+const (
+	MstatusSIE  uint32 = 1 << 1
+	MstatusMIE  uint32 = 1 << 3
+	MstatusSPIE uint32 = 1 << 5
+	MstatusMPIE uint32 = 1 << 7
+	MstatusSPP  uint32 = 1 << 8
+	MstatusMPP  uint32 = 3 << 11
+	MstatusFS   uint32 = 3 << 13
+	MstatusMPRV uint32 = 1 << 17
+	MstatusSUM  uint32 = 1 << 18
+	MstatusMXR  uint32 = 1 << 19
+	MstatusTVM  uint32 = 1 << 20
+	MstatusTW   uint32 = 1 << 21
+	MstatusTSR  uint32 = 1 << 22
+	MstatusSD   uint32 = 1 << 31
+)
+
+/*These constants are the positions of the lowest bits of mstatus.SPP and mstatus.MPP*/
+const (
+	MstatusSPPShift = 8
+	MstatusMPPShift = 11
+)
+
+/*sstatusFields are the fields of mstatus that sstatus shows to supervisor mode*/
+const sstatusFields = MstatusSIE | MstatusSPIE | MstatusSPP | MstatusFS | MstatusSUM | MstatusMXR | MstatusSD
+
+/*These constants are the interrupts, as bits of mie and mip, and of sie and sip for the supervisor-level ones*/
+const (
+	SupervisorSoftwareInterrupt uint32 = 1 << 1
+	MachineSoftwareInterrupt    uint32 = 1 << 3
+	SupervisorTimerInterrupt    uint32 = 1 << 5
+	MachineTimerInterrupt       uint32 = 1 << 7
+	SupervisorExternalInterrupt uint32 = 1 << 9
+	MachineExternalInterrupt    uint32 = 1 << 11
+)
+
+/*These constants are the interrupts that can be enabled, those that machine mode can make pending
+by writing to mip, and those that can be delegated to supervisor mode*/
+const (
+	supervisorInterrupts = SupervisorSoftwareInterrupt | SupervisorTimerInterrupt | SupervisorExternalInterrupt
+	machineInterrupts    = MachineSoftwareInterrupt | MachineTimerInterrupt | MachineExternalInterrupt
+)
+
+/*interruptPriorities are the codes of the interrupts in the order in which they are taken when
+several of them are pending at once*/
+var interruptPriorities = []uint32{11, 3, 7, 9, 1, 5}
+
+/*delegableExceptions are the exceptions that medeleg can delegate to supervisor mode: all of them,
+except for the environment call from machine mode, which can only be raised in machine mode*/
+const delegableExceptions uint32 = 0xB3FF
+
+/*These constants are the fields of satp. The mode is either Bare (0), which does not translate
+addresses, or Sv32 (1), which translates them with the page table at the physical page PPN*/
+const (
+	SatpMode      uint32 = 1 << 31
+	SatpASID      uint32 = 0x1FF << 22
+	SatpPPN       uint32 = 0x3FFFFF
+	SatpASIDShift        = 22
+)
+
+/*InterruptBit is the bit of mcause that is set when a trap was caused by an interrupt rather than an exception*/
+const InterruptBit uint32 = 1 << 31
+
+/*These constants are the modes of mtvec and stvec, which are held in their lowest 2 bits*/
+const (
+	DirectMode   uint32 = 0
+	VectoredMode uint32 = 1
+	mtvecMode    uint32 = 3
+)
+
+/*misaValue describes the hart: a 32-bit base (MXL of 1) with the I, M, A, F, D and C extensions, and
+supervisor and user modes, each of which is the bit of its letter*/
+const misaValue uint32 = 1<<30 | 1<<('I'-'A') | 1<<('M'-'A') | 1<<('A'-'A') | 1<<('F'-'A') | 1<<('D'-'A') | 1<<('C'-'A') |
+	1<<('S'-'A') | 1<<('U'-'A')
+
+/*MachineCsrFile holds the machine-mode and supervisor-mode CSRs of a hart, and the privilege that
+it runs at. Unlike NoOpManager, every CSR is its own register, and each of them only lets through
+the writes that the spec allows:
+	- Read-only CSRs and fields, and the fields that are hardwired, ignore writes, as do WARL fields
+	  that are written with a value they do not support.
+	- Accessing a CSR that does not exist, or that needs more privilege than the hart has, or writing
+	  to a read-only CSR, panics with an error that wraps ErrIllegalCsrAccess. So does reading a counter
+	  that mcounteren (or scounteren, in user mode) does not enable, and accessing satp in supervisor
+	  mode while mstatus.TVM is set.
+
+Physical memory protection has 16 entries, which AllowsPhysicalAccess checks accesses against.
+
+The hart supports machine, supervisor and user privilege. sstatus, sie and sip are views of
+mstatus, mie and mip, restricted to the supervisor fields and the delegated interrupts.
+Devices raise interrupts with SetInterruptLines, which make them pending in mip until they are lowered again,
+and PendingInterrupt returns the interrupt that the hart should take before its next instruction.
+Traps are taken with TakeTrap, which delegates them to supervisor mode as medeleg and mideleg ask,
+and returned from with ReturnFromTrap and ReturnFromSupervisorTrap.
+mcycle counts the ticks of the clock, and minstret counts the calls to Retire, from whatever values
+they were last written with. The instruction that writes one of them is not counted in it. The time CSR counts the ticks of the clock as well, as there is no real-time clock.
+The performance-monitoring counters are hardwired to 0, and mstatus.FS is not enforced by the executor.
+The floating-point CSRs are held by the executor, and never reach MachineCsrFile.
+*/
+type MachineCsrFile struct {
+	privilege  Privilege
+	hartID     uint32
+	mstatus    uint32
+	medeleg    uint32
+	mideleg    uint32
+	mie        uint32
+	mip        uint32
+	lines      uint32 // the interrupts that devices are raising, which are pending on top of those written to mip
+	mtvec      uint32
+	mcounteren uint32
+	mscratch   uint32
+	mepc       uint32
+	mcause     uint32
+	mtval      uint32
+	stvec      uint32
+	scounteren uint32
+	sscratch   uint32
+	sepc       uint32
+	scause     uint32
+	stval      uint32
+	satp       uint32
+	pmpcfg     [PmpEntries]uint8
+	pmpaddr    [PmpEntries]uint32
+	clock      counterClock
+	cycleBase  uint64 // what mcycle was written with, less the clock count at the time
+	minstret   uint64
+
+	// whether mcycle or minstret has been written since the last call to Retire
+	cyclesWritten              bool
+	instructionsRetiredWritten bool
+}
+
+/*counterClock is the clock whose ticks are counted by mcycle and time*/
+type counterClock interface {
+	GetCount() uint
+}
+
+/*MakeMachineCsrFile constructs a MachineCsrFile for the hart `hartID`, at machine privilege,
+with all of its writable CSRs set to 0, except for mcycle, which counts from the current count of `clock`*/
+func MakeMachineCsrFile(hartID uint32, clock counterClock) MachineCsrFile {
+	file := MachineCsrFile{
+		privilege: Machine,
+		hartID:    hartID,
+		clock:     clock,
+		mstatus:   uint32(Machine) << MstatusMPPShift,
+	}
+
+	return file
+}
+
+/*GetPrivilege returns the privilege that the hart is running at*/
+func (f *MachineCsrFile) GetPrivilege() Privilege {
+	return f.privilege
+}
+
+/*SetPrivilege changes the privilege that the hart is running at*/
+func (f *MachineCsrFile) SetPrivilege(privilege Privilege) {
+	f.privilege = privilege
+}
+
+/*Retire counts one retired instruction in minstret. It is called after the clock has ticked for
+that instruction. If the instruction wrote mcycle or minstret, that counter keeps the value written,
+so that it is what the next instruction reads*/
+func (f *MachineCsrFile) Retire() {
+	if f.cyclesWritten {
+		f.cycleBase--
+	}
+	if !f.instructionsRetiredWritten {
+		f.minstret++
+	}
+	f.cyclesWritten = false
+	f.instructionsRetiredWritten = false
+}
+
+/*SetInterruptLines sets the interrupts that devices are raising, as bits of mip. They are pending for as long as
+they are raised, whatever software writes to mip*/
+func (f *MachineCsrFile) SetInterruptLines(lines uint32) {
+	f.lines = lines & (supervisorInterrupts | machineInterrupts)
+}
+
+/*PendingInterrupt returns the mcause of the interrupt that the hart takes before its next instruction, if there is one.
+An interrupt is taken when it is both pending and enabled in mie, and interrupts are enabled at its level: those taken
+in machine mode are always enabled below machine privilege, and enabled by mstatus.MIE in machine mode. Those that
+mideleg delegates are always enabled in user mode, enabled by mstatus.SIE in supervisor mode, and never taken in
+machine mode. Machine external interrupts come first, then software, then timer interrupts, with the machine-level
+ones before the supervisor-level ones*/
+func (f *MachineCsrFile) PendingInterrupt() (uint32, bool) {
+	pending := (f.mip | f.lines) & f.mie
+	if pending == 0 {
+		return 0, false
+	}
+
+	machineEnabled := f.privilege < Machine || f.mstatus&MstatusMIE != 0
+	supervisorEnabled := f.privilege < Supervisor || (f.privilege == Supervisor && f.mstatus&MstatusSIE != 0)
+	for _, code := range interruptPriorities {
+		interrupt := uint32(1) << code
+		if pending&interrupt == 0 {
+			continue
+		}
+		if (f.mideleg&interrupt == 0 && machineEnabled) || (f.mideleg&interrupt != 0 && supervisorEnabled) {
+			return InterruptBit | code, true
+		}
+	}
+	return 0, false
+}
+
+/*GetInstructionsRetired returns the value of minstret*/
+func (f *MachineCsrFile) GetInstructionsRetired() uint64 {
+	return f.minstret
+}
+
+/*TakeTrap enters the trap handler for a trap of `cause` (an mcause value), taken by the instruction at `pc`,
+and returns the address of that handler. It saves `pc` in mepc, `cause` in mcause and `value` in mtval,
+disables interrupts while keeping whether they were enabled in MPIE, and moves the hart to machine privilege
+while keeping the privilege that it trapped from in MPP. In vectored mode, interrupts go to the mtvec base
+plus 4 times their code, but exceptions always go to the base.
+
+Traps that medeleg or mideleg delegate are taken in supervisor mode instead, with sepc, scause, stval, SPIE,
+SPP and stvec, unless the hart is running at machine privilege, since a trap never lowers the privilege*/
+func (f *MachineCsrFile) TakeTrap(cause uint32, value uint32, pc uint32) uint32 {
+	if f.delegated(cause) {
+		f.sepc = pc &^ 1
+		f.scause = cause
+		f.stval = value
+
+		mstatus := f.mstatus &^ (MstatusSIE | MstatusSPIE | MstatusSPP)
+		if f.mstatus&MstatusSIE != 0 {
+			mstatus |= MstatusSPIE
+		}
+		f.mstatus = mstatus | uint32(f.privilege)<<MstatusSPPShift
+		f.privilege = Supervisor
+
+		return trapVector(f.stvec, cause)
+	}
+
+	f.mepc = pc &^ 1
+	f.mcause = cause
+	f.mtval = value
+
+	mstatus := f.mstatus &^ (MstatusMIE | MstatusMPIE | MstatusMPP)
+	if f.mstatus&MstatusMIE != 0 {
+		mstatus |= MstatusMPIE
+	}
+	f.mstatus = mstatus | uint32(f.privilege)<<MstatusMPPShift
+	f.privilege = Machine
+
+	return trapVector(f.mtvec, cause)
+}
+
+/*delegated returns whether a trap of `cause` is taken in supervisor mode*/
+func (f *MachineCsrFile) delegated(cause uint32) bool {
+	if f.privilege == Machine {
+		return false
+	}
+
+	delegation := f.medeleg
+	if cause&InterruptBit != 0 {
+		delegation = f.mideleg
+	}
+	code := cause &^ InterruptBit
+	return code < 32 && delegation&(1<<code) != 0
+}
+
+/*trapVector returns the address of the handler of a trap of `cause`, given the mtvec or stvec `tvec`*/
+func trapVector(tvec uint32, cause uint32) uint32 {
+	base := tvec &^ mtvecMode
+	if tvec&mtvecMode == VectoredMode && cause&InterruptBit != 0 {
+		return base + 4*(cause&^InterruptBit)
+	}
+	return base
+}
+
+/*ReturnFromTrap leaves a machine-mode trap handler, as MRET does, and returns the address in mepc to
+return to. It restores the privilege in MPP and whether interrupts were enabled from MPIE, then sets MPIE
+and sets MPP to the least privilege that the hart supports. MPRV is cleared when MRET leaves machine mode.
+Below machine privilege, MRET is illegal*/
+func (f *MachineCsrFile) ReturnFromTrap() uint32 {
+	if f.privilege < Machine {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("MachineCsrFile: MRET needs more privilege than %d", f.privilege)))
+	}
+
+	mstatus := f.mstatus &^ (MstatusMIE | MstatusMPP)
+	if f.mstatus&MstatusMPIE != 0 {
+		mstatus |= MstatusMIE
+	}
+	f.privilege = Privilege((f.mstatus & MstatusMPP) >> MstatusMPPShift)
+	if f.privilege != Machine {
+		mstatus &^= MstatusMPRV
+	}
+	f.mstatus = mstatus | MstatusMPIE | uint32(f.leastPrivilege())<<MstatusMPPShift
+
+	return f.mepc
+}
+
+/*ReturnFromSupervisorTrap leaves a supervisor-mode trap handler, as SRET does, and returns the address in
+sepc to return to. It restores the privilege in SPP and whether interrupts were enabled from SPIE, then sets
+SPIE, sets SPP to user privilege and clears MPRV. SRET is illegal in user mode, and in supervisor mode when
+mstatus.TSR is set*/
+func (f *MachineCsrFile) ReturnFromSupervisorTrap() uint32 {
+	if f.privilege < Supervisor || (f.privilege == Supervisor && f.mstatus&MstatusTSR != 0) {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("MachineCsrFile: SRET is not allowed at privilege %d", f.privilege)))
+	}
+
+	mstatus := f.mstatus &^ (MstatusSIE | MstatusSPP | MstatusMPRV)
+	if f.mstatus&MstatusSPIE != 0 {
+		mstatus |= MstatusSIE
+	}
+	f.privilege = Privilege((f.mstatus & MstatusSPP) >> MstatusSPPShift)
+	f.mstatus = mstatus | MstatusSPIE
+
+	return f.sepc
+}
+
+/*WaitForInterrupt is WFI. It returns at once, as the spec allows, since the hart checks for
+pending interrupts before every instruction anyway. WFI is illegal in user mode, and in supervisor mode when mstatus.TW is set*/
+func (f *MachineCsrFile) WaitForInterrupt() {
+	if f.privilege == User || (f.privilege == Supervisor && f.mstatus&MstatusTW != 0) {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("MachineCsrFile: WFI is not allowed at privilege %d", f.privilege)))
+	}
+}
+
+/*Get returns the value of CSR `register`*/
+func (f *MachineCsrFile) Get(register uint) uint32 {
+	return f.access(register, false).read(f)
+}
+
+/*Inspect returns the value of CSR `register` whatever the privilege of the hart, as a debugger sees it.
+It still panics if the CSR does not exist*/
+func (f *MachineCsrFile) Inspect(register uint) uint32 {
+	csr, ok := machineCsrs[register]
+	if !ok {
+		panic(fmt.Errorf("MachineCsrFile: CSR %#03x does not exist: %w", register, ErrIllegalCsrAccess))
+	}
+	return csr.read(f)
+}
+
+/*Set writes `val` to CSR `register`, as far as the CSR lets it*/
+func (f *MachineCsrFile) Set(register uint, val uint32) {
+	if write := f.access(register, true).write; write != nil {
+		write(f, val)
+	}
+}
+
+/*access returns how to access CSR `register`, and panics if the access is illegal*/
+func (f *MachineCsrFile) access(register uint, write bool) csrAccess {
+	csr, ok := machineCsrs[register]
+	if !ok {
+		panic(fmt.Errorf("MachineCsrFile: CSR %#03x does not exist: %w", register, ErrIllegalCsrAccess))
+	}
+	if Privilege((register>>8)&3) > f.privilege {
+		panic(fmt.Errorf("MachineCsrFile: CSR %#03x needs more privilege than %d: %w", register, f.privilege, ErrIllegalCsrAccess))
+	}
+	if write && (register>>10)&3 == 3 {
+		panic(fmt.Errorf("MachineCsrFile: CSR %#03x is read-only: %w", register, ErrIllegalCsrAccess))
+	}
+	if !f.counterEnabled(register) {
+		panic(fmt.Errorf("MachineCsrFile: counter %#03x is not enabled at privilege %d: %w", register, f.privilege, ErrIllegalCsrAccess))
+	}
+	if register == Satp && f.privilege == Supervisor && f.mstatus&MstatusTVM != 0 {
+		panic(fmt.Errorf("MachineCsrFile: satp is trapped by mstatus.TVM: %w", ErrIllegalCsrAccess))
+	}
+
+	return csr
+}
+
+/*counterEnabled returns whether the hart can read `register`, if it is one of the unprivileged counters.
+Below machine privilege, the bit of the counter must be set in mcounteren, and in user mode, in scounteren too*/
+func (f *MachineCsrFile) counterEnabled(register uint) bool {
+	if (register&^0x1F != Cycle && register&^0x1F != Cycleh) || f.privilege == Machine {
+		return true
+	}
+
+	bit := uint32(1) << (register & 0x1F)
+	return f.mcounteren&bit != 0 && (f.privilege == Supervisor || f.scounteren&bit != 0)
+}
+
+/*supports returns whether the hart can run at `privilege`*/
+func (f *MachineCsrFile) supports(privilege Privilege) bool {
+	return privilege == User || privilege == Supervisor || privilege == Machine
+}
+
+/*leastPrivilege returns the lowest privilege that the hart can run at*/
+func (f *MachineCsrFile) leastPrivilege() Privilege {
+	for _, privilege := range []Privilege{User, Supervisor} {
+		if f.supports(privilege) {
+			return privilege
+		}
+	}
+	return Machine
+}
+
+/*csrAccess reads and writes one CSR. A CSR without a `write` ignores all writes*/
+type csrAccess struct {
+	read  func(f *MachineCsrFile) uint32
+	write func(f *MachineCsrFile, val uint32)
+}
+
+/*machineCsrs maps the number of each CSR that MachineCsrFile holds to how it is accessed*/
+var machineCsrs = makeMachineCsrs()
+
+func makeMachineCsrs() map[uint]csrAccess {
+	csrs := map[uint]csrAccess{
+		Mstatus:    {read: readMstatus, write: writeMstatus},
+		Misa:       constant(misaValue), // writes are ignored, so no extension can be turned off
+		Medeleg:    masked(func(f *MachineCsrFile) *uint32 { return &f.medeleg }, delegableExceptions),
+		Mideleg:    masked(func(f *MachineCsrFile) *uint32 { return &f.mideleg }, supervisorInterrupts),
+		Mie:        masked(func(f *MachineCsrFile) *uint32 { return &f.mie }, supervisorInterrupts|machineInterrupts),
+		Mip:        {read: func(f *MachineCsrFile) uint32 { return f.mip | f.lines }, write: writeMip},
+		Mtvec:      trapVectorBase(func(f *MachineCsrFile) *uint32 { return &f.mtvec }),
+		Mcounteren: masked(func(f *MachineCsrFile) *uint32 { return &f.mcounteren }, ^uint32(0)),
+		Mstatush:   constant(0), // the hart is little-endian at every privilege
+		Mscratch:   masked(func(f *MachineCsrFile) *uint32 { return &f.mscratch }, ^uint32(0)),
+		Mepc:       masked(func(f *MachineCsrFile) *uint32 { return &f.mepc }, ^uint32(1)), // instructions can be 2-byte aligned, as RVC is supported
+		Mcause:     masked(func(f *MachineCsrFile) *uint32 { return &f.mcause }, ^uint32(0)),
+		Mtval:      masked(func(f *MachineCsrFile) *uint32 { return &f.mtval }, ^uint32(0)),
+
+		Sstatus:    {read: func(f *MachineCsrFile) uint32 { return readMstatus(f) & sstatusFields }, write: writeSstatus},
+		Sie:        {read: func(f *MachineCsrFile) uint32 { return f.mie & f.mideleg }, write: writeSie},
+		Sip:        {read: func(f *MachineCsrFile) uint32 { return (f.mip | f.lines) & f.mideleg }, write: writeSip},
+		Stvec:      trapVectorBase(func(f *MachineCsrFile) *uint32 { return &f.stvec }),
+		Scounteren: masked(func(f *MachineCsrFile) *uint32 { return &f.scounteren }, ^uint32(0)),
+		Sscratch:   masked(func(f *MachineCsrFile) *uint32 { return &f.sscratch }, ^uint32(0)),
+		Sepc:       masked(func(f *MachineCsrFile) *uint32 { return &f.sepc }, ^uint32(1)),
+		Scause:     masked(func(f *MachineCsrFile) *uint32 { return &f.scause }, ^uint32(0)),
+		Stval:      masked(func(f *MachineCsrFile) *uint32 { return &f.stval }, ^uint32(0)),
+		Satp:       {read: func(f *MachineCsrFile) uint32 { return f.satp }, write: writeSatp},
+
+		Mcycle:    lowHalf(getCycles, setCycles),
+		Mcycleh:   highHalf(getCycles, setCycles),
+		Minstret:  lowHalf(getInstructionsRetired, setInstructionsRetired),
+		Minstreth: highHalf(getInstructionsRetired, setInstructionsRetired),
+		Time:      lowHalf(getTime, nil),
+		Timeh:     highHalf(getTime, nil),
+
+		Mvendorid: constant(0), // this is a non-commercial implementation
+		Marchid:   constant(0),
+		Mimpid:    constant(0),
+		Mhartid:   {read: func(f *MachineCsrFile) uint32 { return f.hartID }},
+	}
+
+	// the unprivileged counters are read-only shadows of the machine counters
+	csrs[Cycle] = csrAccess{read: csrs[Mcycle].read}
+	csrs[Cycleh] = csrAccess{read: csrs[Mcycleh].read}
+	csrs[Instret] = csrAccess{read: csrs[Minstret].read}
+	csrs[Instreth] = csrAccess{read: csrs[Minstreth].read}
+
+	for i := uint(0); i < hpmCounters; i++ {
+		for _, register := range []uint{Mhpmevent3, Mhpmcounter3, Mhpmcounter3h, Hpmcounter3, Hpmcounter3h} {
+			csrs[register+i] = constant(0)
+		}
+	}
+
+	for i := uint(0); i < PmpEntries/pmpEntriesPerCfg; i++ {
+		csrs[Pmpcfg0+i] = pmpConfiguration(i)
+	}
+	for i := uint(0); i < PmpEntries; i++ {
+		csrs[Pmpaddr0+i] = pmpAddress(i)
+	}
+
+	return csrs
+}
+
+/*constant returns the access of a CSR that always reads as `val`, and ignores writes*/
+func constant(val uint32) csrAccess {
+	return csrAccess{read: func(f *MachineCsrFile) uint32 { return val }}
+}
+
+/*masked returns the access of a CSR that is held in the field returned by `field`,
+where only the bits in `writable` can be written, and the others are always 0*/
+func masked(field func(f *MachineCsrFile) *uint32, writable uint32) csrAccess {
+	return csrAccess{
+		read:  func(f *MachineCsrFile) uint32 { return *field(f) },
+		write: func(f *MachineCsrFile, val uint32) { *field(f) = val & writable },
+	}
+}
+
+/*lowHalf returns the access of a CSR that is the low 32 bits of the 64-bit counter that `get` reads
+and `set` writes. The CSR ignores writes if there is no `set`*/
+func lowHalf(get func(f *MachineCsrFile) uint64, set func(f *MachineCsrFile, val uint64)) csrAccess {
+	access := csrAccess{read: func(f *MachineCsrFile) uint32 { return uint32(get(f)) }}
+	if set != nil {
+		access.write = func(f *MachineCsrFile, val uint32) { set(f, get(f)&^0xFFFFFFFF|uint64(val)) }
+	}
+	return access
+}
+
+/*highHalf returns the access of a CSR that is the high 32 bits of the 64-bit counter that `get` reads
+and `set` writes. The CSR ignores writes if there is no `set`*/
+func highHalf(get func(f *MachineCsrFile) uint64, set func(f *MachineCsrFile, val uint64)) csrAccess {
+	access := csrAccess{read: func(f *MachineCsrFile) uint32 { return uint32(get(f) >> 32) }}
+	if set != nil {
+		access.write = func(f *MachineCsrFile, val uint32) { set(f, get(f)&0xFFFFFFFF|uint64(val)<<32) }
+	}
+	return access
+}
+
+func getCycles(f *MachineCsrFile) uint64 {
+	return uint64(f.clock.GetCount()) + f.cycleBase
+}
+
+func setCycles(f *MachineCsrFile, val uint64) {
+	f.cycleBase = val - uint64(f.clock.GetCount())
+	f.cyclesWritten = true
+}
+
+func getTime(f *MachineCsrFile) uint64 {
+	return uint64(f.clock.GetCount())
+}
+
+func getInstructionsRetired(f *MachineCsrFile) uint64 {
+	return f.minstret
+}
+
+func setInstructionsRetired(f *MachineCsrFile, val uint64) {
+	f.minstret = val
+	f.instructionsRetiredWritten = true
+}
+
+func readMstatus(f *MachineCsrFile) uint32 {
+	if f.mstatus&MstatusFS == MstatusFS {
+		return f.mstatus | MstatusSD
+	}
+	return f.mstatus
+}
+
+/*writeMstatus writes the fields of mstatus that can be written. MPP is WARL, and keeps its value
+when it is written with a privilege that the hart does not support*/
+func writeMstatus(f *MachineCsrFile, val uint32) {
+	mpp := f.mstatus & MstatusMPP
+	if f.supports(Privilege((val & MstatusMPP) >> MstatusMPPShift)) {
+		mpp = val & MstatusMPP
+	}
+
+	writable := MstatusSIE | MstatusMIE | MstatusSPIE | MstatusMPIE | MstatusSPP | MstatusFS | MstatusMPRV | MstatusSUM | MstatusMXR |
+		MstatusTVM | MstatusTW | MstatusTSR
+	f.mstatus = val&writable | mpp
+}
+
+/*writeSstatus writes the supervisor fields of mstatus, and leaves the others as they are*/
+func writeSstatus(f *MachineCsrFile, val uint32) {
+	writable := MstatusSIE | MstatusSPIE | MstatusSPP | MstatusFS | MstatusSUM | MstatusMXR
+	f.mstatus = f.mstatus&^writable | val&writable
+}
+
+/*writeSie enables or disables the interrupts that are delegated to supervisor mode*/
+func writeSie(f *MachineCsrFile, val uint32) {
+	f.mie = f.mie&^f.mideleg | val&f.mideleg
+}
+
+/*writeMip writes the supervisor-level interrupts of mip. The machine-level interrupts are only pending
+by way of the devices that raise them*/
+func writeMip(f *MachineCsrFile, val uint32) {
+	f.mip = f.mip&^supervisorInterrupts | val&supervisorInterrupts
+}
+
+/*writeSip writes the supervisor software interrupt, if it is delegated. The other interrupts
+in sip are only pending by way of machine mode, or of the devices that raise them*/
+func writeSip(f *MachineCsrFile, val uint32) {
+	writable := f.mideleg & SupervisorSoftwareInterrupt
+	f.mip = f.mip&^writable | val&writable
+}
+
+/*writeSatp writes satp. Every field is writable, as both modes and all 9 bits of the ASID are supported*/
+func writeSatp(f *MachineCsrFile, val uint32) {
+	f.satp = val
+}
+
+/*trapVectorBase returns the access of mtvec or stvec, which is held in the field returned by `field`.
+Its mode is WARL, and keeps its value when it is written with a reserved mode*/
+func trapVectorBase(field func(f *MachineCsrFile) *uint32) csrAccess {
+	return csrAccess{
+		read: func(f *MachineCsrFile) uint32 { return *field(f) },
+		write: func(f *MachineCsrFile, val uint32) {
+			mode := val & mtvecMode
+			if mode != DirectMode && mode != VectoredMode {
+				mode = *field(f) & mtvecMode
+			}
+
+			*field(f) = val&^mtvecMode | mode
+		},
+	}
+}
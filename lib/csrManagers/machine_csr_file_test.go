@@ -0,0 +1,384 @@
+package csrManagers
+
+import (
+	"errors"
+	"testing"
+
+	Traps "github.com/chenhowa/computer/lib/traps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MachineCsrFileSuite struct {
+	suite.Suite
+	clock *fakeClock
+	file  *MachineCsrFile
+}
+
+type fakeClock struct {
+	count uint
+}
+
+func (c *fakeClock) GetCount() uint {
+	return c.count
+}
+
+func TestMachineCsrFileSuite(t *testing.T) {
+	suite.Run(t, new(MachineCsrFileSuite))
+}
+
+func (suite *MachineCsrFileSuite) SetupTest() {
+	suite.clock = &fakeClock{}
+	file := MakeMachineCsrFile(7, suite.clock)
+	suite.file = &file
+}
+
+/*assertIllegal asserts that `access` panics with an error that wraps ErrIllegalCsrAccess*/
+func (suite *MachineCsrFileSuite) assertIllegal(access func()) {
+	defer func() {
+		err, ok := recover().(error)
+		assert.True(suite.T(), ok)
+		assert.True(suite.T(), errors.Is(err, ErrIllegalCsrAccess))
+	}()
+	access()
+}
+
+func (suite *MachineCsrFileSuite) TestRegistersDoNotAlias() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Mscratch, 0xDEADBEEF)
+	suite.file.Set(Mtval, 5)
+	suite.file.Set(Mcause, 0x8000000B)
+	assert.Equal(uint32(0xDEADBEEF), suite.file.Get(Mscratch))
+	assert.Equal(uint32(5), suite.file.Get(Mtval))
+	assert.Equal(uint32(0x8000000B), suite.file.Get(Mcause))
+	assert.Equal(uint32(0), suite.file.Get(Mepc))
+}
+
+func (suite *MachineCsrFileSuite) TestReadOnlyInformation() {
+	assert := assert.New(suite.T())
+
+	assert.Equal(uint32(7), suite.file.Get(Mhartid))
+	assert.Equal(uint32(0), suite.file.Get(Mvendorid))
+	assert.Equal(uint32(0x4014112D), suite.file.Get(Misa)) // RV32ACDFIM, with S and U modes
+
+	suite.file.Set(Misa, 0)
+	assert.Equal(uint32(0x4014112D), suite.file.Get(Misa))
+	suite.assertIllegal(func() { suite.file.Set(Mhartid, 0) })
+	suite.assertIllegal(func() { suite.file.Set(Cycle, 0) })
+}
+
+func (suite *MachineCsrFileSuite) TestWarlFields() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Mstatus, 0xFFFFFFFF)
+	assert.Equal(MstatusSD|MstatusTSR|MstatusTW|MstatusTVM|MstatusMXR|MstatusSUM|MstatusMPRV|MstatusFS|MstatusMPP|MstatusSPP|
+		MstatusMPIE|MstatusSPIE|MstatusMIE|MstatusSIE, suite.file.Get(Mstatus))
+	suite.file.Set(Mstatus, 2<<MstatusMPPShift) // MPP does not support the reserved privilege 2
+	assert.Equal(MstatusMPP, suite.file.Get(Mstatus))
+	suite.file.Set(Mstatus, 0)
+	assert.Equal(uint32(0), suite.file.Get(Mstatus))
+
+	suite.file.Set(Mtvec, 0x1001)
+	assert.Equal(uint32(0x1001), suite.file.Get(Mtvec))
+	suite.file.Set(Mtvec, 0x2002) // a reserved mode
+	assert.Equal(uint32(0x2001), suite.file.Get(Mtvec))
+
+	suite.file.Set(Mepc, 0x103)
+	assert.Equal(uint32(0x102), suite.file.Get(Mepc))
+
+	suite.file.Set(Mie, 0xFFFFFFFF)
+	assert.Equal(uint32(0xAAA), suite.file.Get(Mie))
+	suite.file.Set(Mip, 0xFFFFFFFF) // only the supervisor-level interrupts can be made pending
+	assert.Equal(uint32(0x222), suite.file.Get(Mip))
+	suite.file.Set(Medeleg, 0xFFFFFFFF)
+	assert.Equal(uint32(0xB3FF), suite.file.Get(Medeleg))
+	suite.file.Set(Mideleg, 0xFFFFFFFF)
+	assert.Equal(uint32(0x222), suite.file.Get(Mideleg))
+}
+
+func (suite *MachineCsrFileSuite) TestSupervisorCsrsAreViewsOfMachineCsrs() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Mstatus, MstatusMIE|MstatusSIE|MstatusSPP|MstatusTSR)
+	assert.Equal(MstatusSIE|MstatusSPP, suite.file.Get(Sstatus))
+	suite.file.Set(Sstatus, 0xFFFFFFFF)
+	assert.Equal(MstatusSD|MstatusTSR|MstatusMXR|MstatusSUM|MstatusFS|MstatusSPP|MstatusSPIE|MstatusMIE|MstatusSIE,
+		suite.file.Get(Mstatus))
+
+	// sie and sip only show the interrupts that are delegated
+	suite.file.Set(Mie, MachineTimerInterrupt|SupervisorTimerInterrupt)
+	suite.file.Set(Mip, SupervisorTimerInterrupt|SupervisorExternalInterrupt)
+	assert.Equal(uint32(0), suite.file.Get(Sie))
+	suite.file.Set(Mideleg, SupervisorTimerInterrupt|SupervisorSoftwareInterrupt)
+	assert.Equal(SupervisorTimerInterrupt, suite.file.Get(Sie))
+	assert.Equal(SupervisorTimerInterrupt, suite.file.Get(Sip))
+
+	suite.file.Set(Sie, 0)
+	assert.Equal(MachineTimerInterrupt, suite.file.Get(Mie))
+	suite.file.Set(Sip, 0xFFFFFFFF) // only the software interrupt can be made pending from sip
+	assert.Equal(SupervisorSoftwareInterrupt|SupervisorTimerInterrupt|SupervisorExternalInterrupt, suite.file.Get(Mip))
+
+	suite.file.Set(Sscratch, 5)
+	suite.file.Set(Sepc, 0x103)
+	assert.Equal(uint32(5), suite.file.Get(Sscratch))
+	assert.Equal(uint32(0x102), suite.file.Get(Sepc))
+	assert.Equal(uint32(0), suite.file.Get(Mscratch))
+}
+
+func (suite *MachineCsrFileSuite) TestSatpSupportsBareAndSv32Modes() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Satp, SatpMode|0x456)
+	assert.Equal(SatpMode|0x456, suite.file.Get(Satp))
+	suite.file.Set(Satp, 0x00C00123)
+	assert.Equal(uint32(0x00C00123), suite.file.Get(Satp))
+
+	suite.file.SetPrivilege(Supervisor)
+	assert.Equal(uint32(0x00C00123), suite.file.Get(Satp))
+	suite.file.SetPrivilege(Machine)
+	suite.file.Set(Mstatus, MstatusTVM)
+	suite.file.SetPrivilege(Supervisor)
+	suite.assertIllegal(func() { suite.file.Get(Satp) })
+}
+
+func (suite *MachineCsrFileSuite) TestCounters() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Mcycle, 0xFFFFFFFF)
+	suite.file.Set(Mcycleh, 1)
+	assert.Equal(uint32(0xFFFFFFFF), suite.file.Get(Cycle))
+	assert.Equal(uint32(1), suite.file.Get(Cycleh))
+
+	suite.file.Set(Minstret, 3)
+	assert.Equal(uint32(3), suite.file.Get(Instret))
+	assert.Equal(uint32(0), suite.file.Get(Instreth))
+
+	suite.file.Set(Mhpmcounter3+28, 5)
+	assert.Equal(uint32(0), suite.file.Get(Mhpmcounter3+28))
+	assert.Equal(uint32(0), suite.file.Get(Hpmcounter3h))
+}
+
+func (suite *MachineCsrFileSuite) TestCountersFollowTheClockAndRetiredInstructions() {
+	assert := assert.New(suite.T())
+
+	suite.clock.count = 10
+	suite.file.Retire()
+	suite.file.Retire()
+	assert.Equal(uint32(10), suite.file.Get(Cycle))
+	assert.Equal(uint32(10), suite.file.Get(Time))
+	assert.Equal(uint32(2), suite.file.Get(Instret))
+
+	// the instruction that writes a counter is not counted in it
+	suite.file.Set(Mcycle, 100)
+	suite.file.Set(Minstret, 0xFFFFFFFF)
+	suite.clock.count = 11
+	suite.file.Retire()
+	assert.Equal(uint32(100), suite.file.Get(Mcycle))
+	assert.Equal(uint32(0xFFFFFFFF), suite.file.Get(Minstret))
+
+	suite.clock.count = 15
+	suite.file.Retire()
+	assert.Equal(uint32(104), suite.file.Get(Mcycle))
+	assert.Equal(uint32(0), suite.file.Get(Mcycleh))
+	assert.Equal(uint32(0), suite.file.Get(Instret))
+	assert.Equal(uint32(1), suite.file.Get(Instreth))
+
+	suite.clock.count = 1 << 32
+	assert.Equal(uint32(0), suite.file.Get(Time))
+	assert.Equal(uint32(1), suite.file.Get(Timeh))
+	suite.assertIllegal(func() { suite.file.Set(Time, 0) })
+	suite.assertIllegal(func() { suite.file.Set(Timeh, 0) })
+}
+
+func (suite *MachineCsrFileSuite) TestTakeTrapAndReturn() {
+	assert := assert.New(suite.T())
+	suite.file.Set(Mtvec, 0x100)
+	suite.file.Set(Mstatus, MstatusMIE)
+
+	assert.Equal(uint32(0x100), suite.file.TakeTrap(2, 0x7F, 0x42))
+	assert.Equal(uint32(0x42), suite.file.Get(Mepc))
+	assert.Equal(uint32(2), suite.file.Get(Mcause))
+	assert.Equal(uint32(0x7F), suite.file.Get(Mtval))
+	assert.Equal(MstatusMPIE|MstatusMPP, suite.file.Get(Mstatus))
+	assert.Equal(Machine, suite.file.GetPrivilege())
+
+	suite.file.Set(Mepc, 0x46)
+	assert.Equal(uint32(0x46), suite.file.ReturnFromTrap())
+	assert.Equal(MstatusMIE|MstatusMPIE, suite.file.Get(Mstatus)) // MPP is left at user privilege
+
+	suite.file.SetPrivilege(User)
+	assert.PanicsWithError("MachineCsrFile: MRET needs more privilege than 0", func() { suite.file.ReturnFromTrap() })
+}
+
+func (suite *MachineCsrFileSuite) TestTrapReturnsClearMprvWhenLeavingMachineMode() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Mstatus, MstatusMPRV|MstatusMPP)
+	suite.file.ReturnFromTrap()
+	assert.Equal(MstatusMPRV, suite.file.Get(Mstatus)&MstatusMPRV)
+
+	suite.file.Set(Mstatus, MstatusMPRV|uint32(Supervisor)<<MstatusMPPShift)
+	suite.file.ReturnFromTrap()
+	assert.Equal(uint32(0), suite.file.Inspect(Mstatus)&MstatusMPRV)
+}
+
+func (suite *MachineCsrFileSuite) TestVectoredTraps() {
+	assert := assert.New(suite.T())
+	suite.file.Set(Mtvec, 0x200|VectoredMode)
+
+	assert.Equal(uint32(0x200), suite.file.TakeTrap(11, 0, 0))
+	assert.Equal(uint32(0x200+4*7), suite.file.TakeTrap(InterruptBit|7, 0, 0))
+	assert.Equal(InterruptBit|7, suite.file.Get(Mcause))
+
+	suite.file.Set(Mtvec, 0x200|DirectMode)
+	assert.Equal(uint32(0x200), suite.file.TakeTrap(InterruptBit|7, 0, 0))
+}
+
+func (suite *MachineCsrFileSuite) TestDelegatedTraps() {
+	assert := assert.New(suite.T())
+	suite.file.Set(Mtvec, 0x100)
+	suite.file.Set(Stvec, 0x200|VectoredMode)
+	suite.file.Set(Medeleg, 1<<uint(Traps.UserEnvironmentCall))
+	suite.file.Set(Mideleg, SupervisorTimerInterrupt)
+	suite.file.Set(Mstatus, MstatusSIE)
+
+	// traps are never delegated away from machine mode
+	assert.Equal(uint32(0x100), suite.file.TakeTrap(uint32(Traps.UserEnvironmentCall), 0, 0x10))
+	assert.Equal(Machine, suite.file.GetPrivilege())
+
+	suite.file.SetPrivilege(User)
+	assert.Equal(uint32(0x200), suite.file.TakeTrap(uint32(Traps.UserEnvironmentCall), 0, 0x20))
+	assert.Equal(Supervisor, suite.file.GetPrivilege())
+	assert.Equal(uint32(0x20), suite.file.Get(Sepc))
+	assert.Equal(uint32(Traps.UserEnvironmentCall), suite.file.Get(Scause))
+	assert.Equal(MstatusSPIE, suite.file.Get(Sstatus)) // SPP holds user privilege
+	assert.Equal(uint32(0x10), suite.file.Inspect(Mepc))
+
+	assert.Equal(uint32(0x200+4*5), suite.file.TakeTrap(InterruptBit|5, 0, 0x30))
+	assert.Equal(MstatusSPP, suite.file.Get(Sstatus))
+
+	// exceptions that are not delegated still go to machine mode
+	assert.Equal(uint32(0x100), suite.file.TakeTrap(uint32(Traps.IllegalInstruction), 0, 0x200))
+	assert.Equal(uint32(Supervisor)<<MstatusMPPShift, suite.file.Get(Mstatus)&MstatusMPP)
+	suite.file.SetPrivilege(Supervisor)
+
+	suite.file.Set(Sepc, 0x24)
+	suite.file.Set(Sstatus, MstatusSPIE) // return to user mode, with interrupts enabled
+	assert.Equal(uint32(0x24), suite.file.ReturnFromSupervisorTrap())
+	assert.Equal(User, suite.file.GetPrivilege())
+	assert.Equal(MstatusSIE|MstatusSPIE, suite.file.Inspect(Sstatus))
+
+	assert.PanicsWithError("MachineCsrFile: SRET is not allowed at privilege 0", func() { suite.file.ReturnFromSupervisorTrap() })
+	suite.file.SetPrivilege(Machine)
+	suite.file.Set(Mstatus, MstatusTSR)
+	suite.file.SetPrivilege(Supervisor)
+	assert.PanicsWithError("MachineCsrFile: SRET is not allowed at privilege 1", func() { suite.file.ReturnFromSupervisorTrap() })
+}
+
+func (suite *MachineCsrFileSuite) TestWaitForInterrupt() {
+	assert := assert.New(suite.T())
+
+	assert.NotPanics(func() { suite.file.WaitForInterrupt() })
+	suite.file.Set(Mstatus, MstatusTW)
+	assert.NotPanics(func() { suite.file.WaitForInterrupt() })
+	suite.file.SetPrivilege(Supervisor)
+	assert.Panics(func() { suite.file.WaitForInterrupt() })
+	suite.file.SetPrivilege(Machine)
+	suite.file.Set(Mstatus, 0)
+	suite.file.SetPrivilege(Supervisor)
+	assert.NotPanics(func() { suite.file.WaitForInterrupt() })
+	suite.file.SetPrivilege(User)
+	assert.Panics(func() { suite.file.WaitForInterrupt() })
+}
+
+func (suite *MachineCsrFileSuite) TestInterruptLinesArePending() {
+	assert := assert.New(suite.T())
+
+	suite.file.SetInterruptLines(MachineTimerInterrupt | SupervisorExternalInterrupt | 1<<16)
+	assert.Equal(MachineTimerInterrupt|SupervisorExternalInterrupt, suite.file.Get(Mip))
+	suite.file.Set(Mip, 0) // a raised line cannot be cleared by software
+	assert.Equal(MachineTimerInterrupt|SupervisorExternalInterrupt, suite.file.Get(Mip))
+	suite.file.Set(Mideleg, SupervisorExternalInterrupt)
+	assert.Equal(SupervisorExternalInterrupt, suite.file.Get(Sip))
+
+	suite.file.SetInterruptLines(0)
+	assert.Equal(uint32(0), suite.file.Get(Mip))
+}
+
+func (suite *MachineCsrFileSuite) TestPendingInterrupt() {
+	assert := assert.New(suite.T())
+
+	suite.file.SetInterruptLines(MachineTimerInterrupt | MachineSoftwareInterrupt)
+	_, ok := suite.file.PendingInterrupt()
+	assert.False(ok) // not enabled in mie
+	suite.file.Set(Mie, MachineTimerInterrupt|MachineSoftwareInterrupt|SupervisorSoftwareInterrupt)
+	_, ok = suite.file.PendingInterrupt()
+	assert.False(ok) // not enabled in mstatus while in machine mode
+
+	suite.file.Set(Mstatus, MstatusMIE)
+	cause, ok := suite.file.PendingInterrupt()
+	assert.True(ok)
+	assert.Equal(InterruptBit|3, cause) // software interrupts come before timer interrupts
+	suite.file.SetInterruptLines(MachineTimerInterrupt)
+	cause, _ = suite.file.PendingInterrupt()
+	assert.Equal(InterruptBit|7, cause)
+
+	suite.file.Set(Mstatus, 0)
+	suite.file.SetPrivilege(User) // machine-level interrupts are always enabled below machine mode
+	cause, ok = suite.file.PendingInterrupt()
+	assert.True(ok)
+	assert.Equal(InterruptBit|7, cause)
+}
+
+func (suite *MachineCsrFileSuite) TestPendingDelegatedInterrupt() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Mideleg, SupervisorSoftwareInterrupt)
+	suite.file.Set(Mie, SupervisorSoftwareInterrupt)
+	suite.file.Set(Mip, SupervisorSoftwareInterrupt)
+	suite.file.Set(Mstatus, MstatusMIE|MstatusSIE)
+	_, ok := suite.file.PendingInterrupt()
+	assert.False(ok) // delegated interrupts are never taken in machine mode
+
+	suite.file.SetPrivilege(Supervisor)
+	cause, ok := suite.file.PendingInterrupt()
+	assert.True(ok)
+	assert.Equal(InterruptBit|1, cause)
+	suite.file.Set(Sstatus, 0)
+	_, ok = suite.file.PendingInterrupt()
+	assert.False(ok)
+
+	suite.file.SetPrivilege(User)
+	_, ok = suite.file.PendingInterrupt()
+	assert.True(ok)
+}
+
+func (suite *MachineCsrFileSuite) TestCountersMustBeEnabledBelowMachineMode() {
+	assert := assert.New(suite.T())
+	suite.file.Set(Mcounteren, 1<<(Instret-Cycle))
+	suite.file.Set(Scounteren, 1<<(Cycle-Cycle)|1<<(Instret-Cycle))
+
+	suite.file.SetPrivilege(Supervisor)
+	assert.Equal(uint32(0), suite.file.Get(Instreth))
+	suite.assertIllegal(func() { suite.file.Get(Cycle) })
+
+	suite.file.SetPrivilege(User)
+	assert.Equal(uint32(0), suite.file.Get(Instret))
+	suite.assertIllegal(func() { suite.file.Get(Cycle) })
+	suite.assertIllegal(func() { suite.file.Get(Time) })
+}
+
+func (suite *MachineCsrFileSuite) TestIllegalAccesses() {
+	suite.assertIllegal(func() { suite.file.Get(0x7C0) })
+	suite.assertIllegal(func() { suite.file.Get(Mhpmcounter3 + 29) })
+
+	suite.file.SetPrivilege(Supervisor)
+	suite.assertIllegal(func() { suite.file.Get(Mscratch) })
+	assert.Equal(suite.T(), uint32(0), suite.file.Get(Sscratch))
+	assert.Equal(suite.T(), uint32(0), suite.file.Inspect(Mscratch))
+
+	suite.file.SetPrivilege(User)
+	suite.assertIllegal(func() { suite.file.Get(Sscratch) })
+	suite.assertIllegal(func() { suite.file.Get(Cycle) })
+}
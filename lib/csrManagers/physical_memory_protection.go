@@ -0,0 +1,124 @@
+package csrManagers
+
+import "math/bits"
+
+/*PmpEntries is the number of physical memory protection entries, each of which has a pmpaddr CSR
+and a byte of a pmpcfg CSR*/
+const PmpEntries = 16
+
+/*pmpEntriesPerCfg is the number of entries whose configuration a pmpcfg CSR holds, one in each byte*/
+const pmpEntriesPerCfg = 4
+
+/*These constants are the fields of the configuration of a PMP entry. R, W and X are also
+the permissions that an access needs, as AllowsPhysicalAccess takes them*/
+const (
+	PmpRead    uint8 = 1 << 0
+	PmpWrite   uint8 = 1 << 1
+	PmpExecute uint8 = 1 << 2
+	PmpMatch   uint8 = 3 << 3
+	PmpLock    uint8 = 1 << 7
+)
+
+/*These constants are the ways that a PMP entry can match addresses, as the values of its PmpMatch field.
+An entry that is OFF matches nothing. A TOR entry matches from the address of the entry before it, up to
+its own address. NA4 and NAPOT entries match a naturally aligned region of 4 bytes, or of a power of 2 bytes*/
+const (
+	PmpOff   uint8 = 0 << 3
+	PmpTor   uint8 = 1 << 3
+	PmpNa4   uint8 = 2 << 3
+	PmpNapot uint8 = 3 << 3
+)
+
+/*pmpWritable are the fields of the configuration of a PMP entry that can be written. The other bits are 0*/
+const pmpWritable = PmpRead | PmpWrite | PmpExecute | PmpMatch | PmpLock
+
+/*pmpConfiguration returns the access of pmpcfg `index`, which holds the configurations of 4 entries.
+The configuration of a locked entry ignores writes, and so does one that is written with the reserved
+permissions that allow writes but not reads*/
+func pmpConfiguration(index uint) csrAccess {
+	first := index * pmpEntriesPerCfg
+	return csrAccess{
+		read: func(f *MachineCsrFile) uint32 {
+			var val uint32
+			for i := uint(0); i < pmpEntriesPerCfg; i++ {
+				val |= uint32(f.pmpcfg[first+i]) << (8 * i)
+			}
+			return val
+		},
+		write: func(f *MachineCsrFile, val uint32) {
+			for i := uint(0); i < pmpEntriesPerCfg; i++ {
+				cfg := uint8(val>>(8*i)) & pmpWritable
+				if f.pmpcfg[first+i]&PmpLock != 0 || (cfg&PmpWrite != 0 && cfg&PmpRead == 0) {
+					continue
+				}
+				f.pmpcfg[first+i] = cfg
+			}
+		},
+	}
+}
+
+/*pmpAddress returns the access of pmpaddr `index`, which holds bits 33 to 2 of the address of the entry.
+It ignores writes while its entry is locked, or while the next entry is a locked TOR entry, whose range
+starts at this address*/
+func pmpAddress(index uint) csrAccess {
+	return csrAccess{
+		read: func(f *MachineCsrFile) uint32 { return f.pmpaddr[index] },
+		write: func(f *MachineCsrFile, val uint32) {
+			if f.pmpcfg[index]&PmpLock != 0 {
+				return
+			}
+			if index+1 < PmpEntries && f.pmpcfg[index+1]&PmpLock != 0 && f.pmpcfg[index+1]&PmpMatch == PmpTor {
+				return
+			}
+			f.pmpaddr[index] = val
+		},
+	}
+}
+
+/*AllowsPhysicalAccess returns whether physical memory protection allows an access of `size` bytes at the physical
+`address` at `privilege`, which needs the `permission` PmpRead, PmpWrite or PmpExecute.
+The entry with the lowest index that matches any byte of the access decides: the access must lie within it as a whole,
+and the entry must grant the permission, although machine mode is only held to the entries that are locked.
+When no entry matches, machine mode is allowed the access, and supervisor and user mode are not*/
+func (f *MachineCsrFile) AllowsPhysicalAccess(address uint32, size uint32, permission uint8, privilege Privilege) bool {
+	start, end := uint64(address), uint64(address)+uint64(size)
+	for i := 0; i < PmpEntries; i++ {
+		low, high, ok := f.pmpRange(i)
+		if !ok || end <= low || start >= high {
+			continue
+		}
+
+		cfg := f.pmpcfg[i]
+		if start < low || end > high {
+			return false
+		}
+		if privilege == Machine && cfg&PmpLock == 0 {
+			return true
+		}
+		return cfg&permission == permission
+	}
+
+	return privilege == Machine
+}
+
+/*pmpRange returns the range of the physical addresses, from `low` up to but not including `high`,
+that PMP entry `index` matches. It returns false if the entry is OFF*/
+func (f *MachineCsrFile) pmpRange(index int) (low uint64, high uint64, ok bool) {
+	address := uint64(f.pmpaddr[index]) << 2
+	switch f.pmpcfg[index] & PmpMatch {
+	case PmpTor:
+		if index > 0 {
+			low = uint64(f.pmpaddr[index-1]) << 2
+		}
+		return low, address, true
+	case PmpNa4:
+		return address, address + 4, true
+	case PmpNapot:
+		// the trailing 1s of pmpaddr give the size of the region: 8 bytes, doubled for each of them
+		ones := uint(bits.TrailingZeros32(^f.pmpaddr[index]))
+		size := uint64(8) << ones
+		low = address &^ (size - 1)
+		return low, low + size, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,74 @@
+package csrManagers
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+const readWrite = PmpRead | PmpWrite
+
+func (suite *MachineCsrFileSuite) TestPmpCsrs() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Pmpaddr0+15, 0x12345678)
+	assert.Equal(uint32(0x12345678), suite.file.Get(Pmpaddr0+15))
+
+	suite.file.Set(Pmpcfg0+3, 0xFFFFFFFF)
+	assert.Equal(uint32(0x9F9F9F9F), suite.file.Get(Pmpcfg0+3))
+	assert.Equal(uint32(0), suite.file.Get(Pmpcfg0+2))
+
+	suite.file.Set(Pmpcfg0, uint32(PmpWrite)<<8|uint32(PmpRead)) // writable but not readable is reserved
+	assert.Equal(uint32(PmpRead), suite.file.Get(Pmpcfg0))
+
+	suite.file.SetPrivilege(Supervisor)
+	suite.assertIllegal(func() { suite.file.Get(Pmpcfg0) })
+}
+
+func (suite *MachineCsrFileSuite) TestLockedPmpEntriesIgnoreWrites() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Pmpaddr0, 0x100)
+	suite.file.Set(Pmpaddr0+1, 0x200)
+	suite.file.Set(Pmpcfg0, uint32(PmpLock|PmpTor|PmpRead)<<8)
+
+	suite.file.Set(Pmpcfg0, 0)
+	assert.Equal(uint32(PmpLock|PmpTor|PmpRead)<<8, suite.file.Get(Pmpcfg0))
+	suite.file.Set(Pmpaddr0+1, 0)
+	assert.Equal(uint32(0x200), suite.file.Get(Pmpaddr0+1))
+	suite.file.Set(Pmpaddr0, 0) // it is the bottom of the locked TOR region
+	assert.Equal(uint32(0x100), suite.file.Get(Pmpaddr0))
+}
+
+func (suite *MachineCsrFileSuite) TestPmpMatchesAddresses() {
+	assert := assert.New(suite.T())
+
+	suite.file.Set(Pmpaddr0, 0x1000>>2)           // TOR from 0 to 0x1000
+	suite.file.Set(Pmpaddr0+1, 0x2000>>2)         // NA4 at 0x2000
+	suite.file.Set(Pmpaddr0+2, (0x3000|0x3FF)>>2) // NAPOT of 2 KiB at 0x3000
+	suite.file.Set(Pmpaddr0+3, 0xFFFFFFFF)        // NAPOT of all memory
+	suite.file.Set(Pmpcfg0, uint32(PmpNapot|PmpRead)<<24|uint32(PmpNapot|readWrite)<<16|
+		uint32(PmpNa4|PmpExecute)<<8|uint32(PmpTor|readWrite))
+
+	assert.True(suite.file.AllowsPhysicalAccess(0xFFC, 4, PmpWrite, User))
+	assert.False(suite.file.AllowsPhysicalAccess(0xFFE, 4, PmpRead, User)) // only part of the access is in the region
+	assert.True(suite.file.AllowsPhysicalAccess(0x2000, 2, PmpExecute, Supervisor))
+	assert.False(suite.file.AllowsPhysicalAccess(0x2000, 4, PmpRead, Supervisor))
+	assert.True(suite.file.AllowsPhysicalAccess(0x37FC, 4, PmpWrite, User))
+	assert.True(suite.file.AllowsPhysicalAccess(0x3800, 4, PmpRead, User))
+	assert.False(suite.file.AllowsPhysicalAccess(0x3800, 4, PmpWrite, User))
+	assert.True(suite.file.AllowsPhysicalAccess(0xFFFFFFFC, 4, PmpRead, User))
+}
+
+func (suite *MachineCsrFileSuite) TestPmpOnlyHoldsMachineModeToLockedEntries() {
+	assert := assert.New(suite.T())
+
+	// without a matching entry, only machine mode is allowed
+	assert.True(suite.file.AllowsPhysicalAccess(0x100, 4, PmpWrite, Machine))
+	assert.False(suite.file.AllowsPhysicalAccess(0x100, 4, PmpRead, Supervisor))
+
+	suite.file.Set(Pmpaddr0, 0x100>>2)
+	suite.file.Set(Pmpcfg0, uint32(PmpNa4))
+	assert.True(suite.file.AllowsPhysicalAccess(0x100, 4, PmpWrite, Machine))
+	suite.file.Set(Pmpcfg0, uint32(PmpNa4|PmpLock|PmpRead))
+	assert.False(suite.file.AllowsPhysicalAccess(0x100, 4, PmpWrite, Machine))
+	assert.True(suite.file.AllowsPhysicalAccess(0x100, 4, PmpRead, Machine))
+}
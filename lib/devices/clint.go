@@ -0,0 +1,146 @@
+package devices
+
+import (
+	"math"
+
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	Memory "github.com/chenhowa/computer/lib/memory"
+)
+
+/*These constants are the layout of the CLINT: the offsets of its registers from the address it is
+mapped at, the size of the address space it takes up, and the address that it is usually mapped at.
+There is a single hart, so there is one msip and one mtimecmp*/
+const (
+	ClintMsip     uint32 = 0x0
+	ClintMtimecmp uint32 = 0x4000
+	ClintMtime    uint32 = 0xBFF8
+	ClintSize     uint32 = 0x10000
+	ClintBase     uint32 = 0x2000000
+)
+
+/*Clint is a core-local interruptor, the device that raises the machine timer and software interrupts of a hart.
+Its registers are memory-mapped, and can be read and written a byte at a time, in little-endian order:
+	- msip, at ClintMsip, raises the machine software interrupt while its lowest bit is set. Its other bits are 0.
+	- mtime, at ClintMtime, is the 64-bit real-time counter. It counts one for every `ticksPerIncrement`
+	  ticks of its clock, so it follows wall time when that clock ticks with a TimeDelay.
+	- mtimecmp, at ClintMtimecmp, raises the machine timer interrupt while mtime is at least its 64-bit value.
+	  It starts at its largest value, so that no timer interrupt is raised until software sets it.
+The other addresses read as 0 and ignore writes*/
+type Clint struct {
+	clock             counterClock
+	ticksPerIncrement uint
+	msip              bool
+	mtimecmp          uint64
+	mtimeBase         uint64 // what mtime was last written with
+	countBase         uint   // the count of the clock when mtime was last written
+}
+
+/*counterClock is the clock whose ticks advance mtime*/
+type counterClock interface {
+	GetCount() uint
+}
+
+/*MakeClint is a constructor for a Clint whose mtime starts at 0, and advances once every `ticksPerIncrement`
+ticks of `clock`. A `ticksPerIncrement` of 0 is taken as 1*/
+func MakeClint(clock counterClock, ticksPerIncrement uint) Clint {
+	if ticksPerIncrement == 0 {
+		ticksPerIncrement = 1
+	}
+
+	return Clint{
+		clock:             clock,
+		ticksPerIncrement: ticksPerIncrement,
+		mtimecmp:          math.MaxUint64,
+		countBase:         clock.GetCount(),
+	}
+}
+
+/*GetInterrupts returns the interrupts that the CLINT is raising, as bits of mip*/
+func (c *Clint) GetInterrupts() uint32 {
+	var interrupts uint32
+	if c.msip {
+		interrupts |= CsrManagers.MachineSoftwareInterrupt
+	}
+	if c.GetMtime() >= c.mtimecmp {
+		interrupts |= CsrManagers.MachineTimerInterrupt
+	}
+	return interrupts
+}
+
+/*GetMtime returns the value of mtime*/
+func (c *Clint) GetMtime() uint64 {
+	count := c.clock.GetCount()
+	if count < c.countBase { // the clock was reset, so mtime counts on from the value it was last written with
+		c.countBase = count
+	}
+	return c.mtimeBase + uint64((count-c.countBase)/c.ticksPerIncrement)
+}
+
+/*setMtime makes mtime `val`, from which it goes on counting*/
+func (c *Clint) setMtime(val uint64) {
+	c.mtimeBase = val
+	c.countBase = c.clock.GetCount()
+}
+
+/*Read returns the `width` bytes starting at `address`, in little-endian order. It returns an error
+if any of them is not within the CLINT*/
+func (c *Clint) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	if err := Memory.CheckAccess(address, width, c.GetAddressSpaceSize()); err != nil {
+		return 0, err
+	}
+
+	var val uint64
+	for i := uint32(0); i < uint32(width); i++ {
+		val |= uint64(c.getByte(address+i)) << (8 * i)
+	}
+	return val, nil
+}
+
+/*Write writes the lowest `width` bytes of `val`, starting at `address`, in little-endian order. It returns
+an error, and writes nothing, if any of them is not within the CLINT*/
+func (c *Clint) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	if err := Memory.CheckAccess(address, width, c.GetAddressSpaceSize()); err != nil {
+		return err
+	}
+
+	for i := uint32(0); i < uint32(width); i++ {
+		c.setByte(address+i, uint8(val>>(8*i)))
+	}
+	return nil
+}
+
+/*GetAddressSpaceSize returns the size of the address space that the CLINT takes up*/
+func (c *Clint) GetAddressSpaceSize() uint {
+	return uint(ClintSize)
+}
+
+/*getByte returns the byte of the registers at `address`*/
+func (c *Clint) getByte(address uint32) uint8 {
+	switch {
+	case address == ClintMsip && c.msip:
+		return 1
+	case address >= ClintMtimecmp && address < ClintMtimecmp+8:
+		return uint8(c.mtimecmp >> (8 * (address - ClintMtimecmp)))
+	case address >= ClintMtime && address < ClintMtime+8:
+		return uint8(c.GetMtime() >> (8 * (address - ClintMtime)))
+	}
+	return 0
+}
+
+/*setByte writes `val` to the byte of the registers at `address`*/
+func (c *Clint) setByte(address uint32, val uint8) {
+	switch {
+	case address == ClintMsip:
+		c.msip = val&1 != 0
+	case address >= ClintMtimecmp && address < ClintMtimecmp+8:
+		c.mtimecmp = replaceByte(c.mtimecmp, address-ClintMtimecmp, val)
+	case address >= ClintMtime && address < ClintMtime+8:
+		c.setMtime(replaceByte(c.GetMtime(), address-ClintMtime, val))
+	}
+}
+
+/*replaceByte returns `val` with its byte `index` replaced by `b`*/
+func replaceByte(val uint64, index uint32, b uint8) uint64 {
+	shift := 8 * index
+	return val&^(0xFF<<shift) | uint64(b)<<shift
+}
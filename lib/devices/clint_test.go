@@ -0,0 +1,95 @@
+package devices
+
+import (
+	"errors"
+	"testing"
+
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ClintSuite struct {
+	suite.Suite
+	clock *fakeClock
+	clint *Clint
+}
+
+type fakeClock struct {
+	count uint
+}
+
+func (c *fakeClock) GetCount() uint {
+	return c.count
+}
+
+func TestClintSuite(t *testing.T) {
+	suite.Run(t, new(ClintSuite))
+}
+
+/*readWord returns the word at `address` of `device`, or 0 if it cannot be read*/
+func readWord(device Memory.Reader, address uint32) uint32 {
+	val, _ := device.Read(address, Memory.Word)
+	return uint32(val)
+}
+
+func (suite *ClintSuite) SetupTest() {
+	suite.clock = &fakeClock{count: 5}
+	clint := MakeClint(suite.clock, 10)
+	suite.clint = &clint
+}
+
+func (suite *ClintSuite) TestMtimeFollowsTheClock() {
+	assert := assert.New(suite.T())
+
+	assert.Equal(uint64(0), suite.clint.GetMtime())
+	suite.clock.count = 34
+	assert.Equal(uint64(2), suite.clint.GetMtime())
+	assert.Equal(uint32(2), readWord(suite.clint, ClintMtime))
+
+	suite.clint.Write(ClintMtime+4, Memory.Word, 1)
+	assert.Equal(uint64(1<<32|2), suite.clint.GetMtime())
+	suite.clock.count = 44
+	assert.Equal(uint32(3), readWord(suite.clint, ClintMtime))
+	assert.Equal(uint32(1), readWord(suite.clint, ClintMtime+4))
+}
+
+func (suite *ClintSuite) TestTimerInterrupt() {
+	assert := assert.New(suite.T())
+
+	assert.Equal(uint32(0xFFFFFFFF), readWord(suite.clint, ClintMtimecmp+4))
+	assert.Equal(uint32(0), suite.clint.GetInterrupts())
+
+	suite.clint.Write(ClintMtimecmp+4, Memory.Word, 0)
+	suite.clint.Write(ClintMtimecmp, Memory.Word, 3)
+	suite.clock.count = 34
+	assert.Equal(uint32(0), suite.clint.GetInterrupts())
+	suite.clock.count = 35
+	assert.Equal(CsrManagers.MachineTimerInterrupt, suite.clint.GetInterrupts())
+
+	suite.clint.Write(ClintMtimecmp, Memory.Byte, 4) // writing mtimecmp lowers the interrupt again
+	assert.Equal(uint32(0), suite.clint.GetInterrupts())
+}
+
+func (suite *ClintSuite) TestSoftwareInterrupt() {
+	assert := assert.New(suite.T())
+
+	assert.NoError(suite.clint.Write(ClintMsip, Memory.Word, 0xFFFFFFFF))
+	assert.Equal(uint32(1), readWord(suite.clint, ClintMsip))
+	assert.Equal(CsrManagers.MachineSoftwareInterrupt, suite.clint.GetInterrupts())
+
+	suite.clint.Write(ClintMsip, Memory.Byte, 0)
+	assert.Equal(uint32(0), suite.clint.GetInterrupts())
+}
+
+func (suite *ClintSuite) TestUnusedAddresses() {
+	assert := assert.New(suite.T())
+
+	suite.clint.Write(0x100, Memory.Word, 0xFFFFFFFF)
+	assert.Equal(uint32(0), readWord(suite.clint, 0x100))
+	assert.NoError(suite.clint.Write(ClintSize-2, Memory.HalfWord, 0xFFFF))
+	assert.True(errors.Is(suite.clint.Write(ClintSize-2, Memory.Word, 0xFFFFFFFF), Memory.ErrAddressOutOfRange))
+	_, err := suite.clint.Read(ClintSize, Memory.Byte)
+	assert.True(errors.Is(err, Memory.ErrAddressOutOfRange))
+}
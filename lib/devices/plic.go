@@ -0,0 +1,255 @@
+package devices
+
+import (
+	"fmt"
+
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	Memory "github.com/chenhowa/computer/lib/memory"
+)
+
+/*These constants are the layout of the PLIC: the offsets of its registers from the address it is mapped at,
+the distance between the registers of consecutive contexts, the size of the address space it takes up,
+and the address that it is usually mapped at*/
+const (
+	PlicPriority      uint32 = 0x0
+	PlicPending       uint32 = 0x1000
+	PlicEnable        uint32 = 0x2000
+	PlicEnableStride  uint32 = 0x80
+	PlicThreshold     uint32 = 0x200000
+	PlicClaim         uint32 = 0x200004
+	PlicContextStride uint32 = 0x1000
+	PlicSize          uint32 = 0x4000000
+	PlicBase          uint32 = 0xC000000
+)
+
+/*PlicSources is the number of interrupt sources of the PLIC. Source 0 means "no interrupt",
+so devices raise sources 1 to 31*/
+const PlicSources = 32
+
+/*PlicMaxPriority is the highest priority that a source can have. Priorities are WARL, so a larger one
+is cut down to its lowest bits*/
+const PlicMaxPriority uint32 = 7
+
+/*These constants are the contexts of the PLIC, each of which is the interrupt that it drives on the hart*/
+const (
+	PlicMachineContext    = 0
+	PlicSupervisorContext = 1
+	plicContexts          = 2
+)
+
+/*plicContextInterrupts are the interrupts of mip that the contexts drive, in the order of the contexts*/
+var plicContextInterrupts = [plicContexts]uint32{CsrManagers.MachineExternalInterrupt, CsrManagers.SupervisorExternalInterrupt}
+
+/*Plic is a platform-level interrupt controller, which routes the interrupts of devices to the external interrupts
+of a hart. Devices raise and lower their interrupt sources with SetInterruptLine, or are attached to a source with
+AttachDevice, so that the PLIC samples whether they are asking for an interrupt. A source that is raised becomes
+pending, and stays pending until a context claims it, even if its line is lowered in the meantime.
+
+Each context has its own enable bits and threshold, and raises its external interrupt on the hart, machine or
+supervisor, while one of the sources that it enables is pending with a priority above its threshold. Reading the
+claim register of a context claims the pending source with the highest priority, or the lowest number among those
+of equal priority, and returns its number, or 0 if there is none. A claimed source does not become pending again
+until its number is written to the claim register, which completes it. Its registers are 32 bits wide:
+	- the priority of source N, at PlicPriority + 4*N. Source 0 has none.
+	- the pending bits of the sources, at PlicPending. They are read-only.
+	- the enable bits of context C, at PlicEnable + PlicEnableStride*C.
+	- the threshold of context C, at PlicThreshold + PlicContextStride*C.
+	- the claim and complete register of context C, at PlicClaim + PlicContextStride*C.
+The other addresses read as 0 and ignore writes*/
+type Plic struct {
+	priorities [PlicSources]uint32
+	lines      uint32 // the sources that are being raised
+	pending    uint32
+	claimed    uint32
+	enables    [plicContexts]uint32
+	thresholds [plicContexts]uint32
+	devices    [PlicSources]interruptingDevice
+}
+
+/*interruptingDevice is a device whose interrupt line is a source of the PLIC, such as a UART*/
+type interruptingDevice interface {
+	IsInterruptPending() bool
+}
+
+/*MakePlic is a constructor for a Plic with no pending sources, in which every source has priority 0,
+and so is never taken, until software gives it a priority*/
+func MakePlic() Plic {
+	return Plic{}
+}
+
+/*SetInterruptLine raises or lowers the interrupt line of `source`, as the device that owns it does*/
+func (p *Plic) SetInterruptLine(source uint32, raised bool) {
+	if source == 0 || source >= PlicSources {
+		panic(fmt.Sprintf("Plic: there is no interrupt source %d", source))
+	}
+
+	bit := uint32(1) << source
+	if !raised {
+		p.lines &^= bit
+		return
+	}
+	p.lines |= bit
+	if p.claimed&bit == 0 {
+		p.pending |= bit
+	}
+}
+
+/*AttachDevice makes `device` the owner of `source`, which is raised while the device is asking for an interrupt.
+The PLIC samples its attached devices whenever the hart checks for interrupts, and when a source is completed*/
+func (p *Plic) AttachDevice(source uint32, device interruptingDevice) {
+	if source == 0 || source >= PlicSources {
+		panic(fmt.Sprintf("Plic: there is no interrupt source %d", source))
+	}
+	p.devices[source] = device
+}
+
+/*sample sets the interrupt lines of the attached devices to whether they are asking for an interrupt*/
+func (p *Plic) sample() {
+	for source, device := range p.devices {
+		if device != nil {
+			p.SetInterruptLine(uint32(source), device.IsInterruptPending())
+		}
+	}
+}
+
+/*GetInterrupts returns the external interrupts that the PLIC is raising on the hart, as bits of mip*/
+func (p *Plic) GetInterrupts() uint32 {
+	p.sample()
+	var interrupts uint32
+	for context, interrupt := range plicContextInterrupts {
+		if p.highestPending(context) != 0 {
+			interrupts |= interrupt
+		}
+	}
+	return interrupts
+}
+
+/*highestPending returns the number of the source that `context` would claim, or 0 if there is none*/
+func (p *Plic) highestPending(context int) uint32 {
+	candidates := p.pending & p.enables[context]
+	var best uint32
+	for source := uint32(1); source < PlicSources; source++ {
+		if candidates&(1<<source) == 0 || p.priorities[source] <= p.thresholds[context] {
+			continue
+		}
+		if best == 0 || p.priorities[source] > p.priorities[best] {
+			best = source
+		}
+	}
+	return best
+}
+
+/*claim claims the source with the highest priority that is pending for `context`, and returns its number*/
+func (p *Plic) claim(context int) uint32 {
+	source := p.highestPending(context)
+	if source != 0 {
+		p.pending &^= 1 << source
+		p.claimed |= 1 << source
+	}
+	return source
+}
+
+/*complete finishes the handling of the claimed `source`, which becomes pending again if its line is still raised.
+Numbers of sources that are not claimed, or that `context` does not enable, are ignored*/
+func (p *Plic) complete(context int, source uint32) {
+	if source >= PlicSources {
+		return
+	}
+	bit := uint32(1) << source
+	if p.claimed&bit == 0 || p.enables[context]&bit == 0 {
+		return
+	}
+	p.claimed &^= bit
+	p.sample()
+	if p.lines&bit != 0 {
+		p.pending |= bit
+	}
+}
+
+/*Read returns the `width` bytes starting at `address`, in little-endian order. Each register that the bytes
+are in is read once, so reading any bytes of a claim register claims one source. Read returns an error if
+any of the bytes is not within the PLIC*/
+func (p *Plic) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	if err := Memory.CheckAccess(address, width, p.GetAddressSpaceSize()); err != nil {
+		return 0, err
+	}
+
+	var val uint64
+	for bytesRead := uint32(0); bytesRead < uint32(width); {
+		next := address + bytesRead
+		val |= uint64(p.read(next&^3, true)>>(8*(next&3))) << (8 * bytesRead)
+		bytesRead += 4 - next&3
+	}
+	return val & width.Mask(), nil
+}
+
+/*Write writes the lowest `width` bytes of `val`, starting at `address`, in little-endian order.
+The bytes of a register that are not written keep their value. Write returns an error, and writes nothing,
+if any of the bytes is not within the PLIC*/
+func (p *Plic) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	if err := Memory.CheckAccess(address, width, p.GetAddressSpaceSize()); err != nil {
+		return err
+	}
+
+	for bytesWritten := uint32(0); bytesWritten < uint32(width); {
+		next := address + bytesWritten
+		register, shift := next&^3, 8*(next&3)
+		count := 4 - next&3
+		if left := uint32(width) - bytesWritten; count > left {
+			count = left
+		}
+		mask := uint32(uint64(1)<<(8*count)-1) << shift
+		p.write(register, p.read(register, false)&^mask|uint32(val>>(8*bytesWritten))<<shift&mask)
+		bytesWritten += count
+	}
+	return nil
+}
+
+/*GetAddressSpaceSize returns the size of the address space that the PLIC takes up*/
+func (p *Plic) GetAddressSpaceSize() uint {
+	return uint(PlicSize)
+}
+
+/*read returns the register at the 4-byte aligned `register`. It only claims a source when asked to `claim`,
+so that the register can be read without side effects before part of it is written*/
+func (p *Plic) read(register uint32, claim bool) uint32 {
+	switch {
+	case register < PlicPending && register/4 < PlicSources:
+		return p.priorities[register/4]
+	case register == PlicPending:
+		return p.pending
+	case register >= PlicEnable && register < PlicEnable+PlicEnableStride*plicContexts && register%PlicEnableStride == 0:
+		return p.enables[(register-PlicEnable)/PlicEnableStride]
+	case register >= PlicThreshold && register < PlicThreshold+PlicContextStride*plicContexts:
+		context := int((register - PlicThreshold) / PlicContextStride)
+		switch (register - PlicThreshold) % PlicContextStride {
+		case 0:
+			return p.thresholds[context]
+		case PlicClaim - PlicThreshold:
+			if claim {
+				return p.claim(context)
+			}
+		}
+	}
+	return 0
+}
+
+/*write writes `val` to the register at the 4-byte aligned `register`*/
+func (p *Plic) write(register uint32, val uint32) {
+	switch {
+	case register < PlicPending && register/4 < PlicSources:
+		if register != PlicPriority { // source 0 has no priority
+			p.priorities[register/4] = val & PlicMaxPriority
+		}
+	case register >= PlicEnable && register < PlicEnable+PlicEnableStride*plicContexts && register%PlicEnableStride == 0:
+		p.enables[(register-PlicEnable)/PlicEnableStride] = val &^ 1 // source 0 cannot be enabled
+	case register >= PlicThreshold && register < PlicThreshold+PlicContextStride*plicContexts:
+		context := int((register - PlicThreshold) / PlicContextStride)
+		switch (register - PlicThreshold) % PlicContextStride {
+		case 0:
+			p.thresholds[context] = val & PlicMaxPriority
+		case PlicClaim - PlicThreshold:
+			p.complete(context, val)
+		}
+	}
+}
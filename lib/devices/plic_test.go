@@ -0,0 +1,92 @@
+package devices
+
+import (
+	"errors"
+	"testing"
+
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PlicSuite struct {
+	suite.Suite
+	plic *Plic
+}
+
+func TestPlicSuite(t *testing.T) {
+	suite.Run(t, new(PlicSuite))
+}
+
+func (suite *PlicSuite) SetupTest() {
+	plic := MakePlic()
+	suite.plic = &plic
+}
+
+func (suite *PlicSuite) TestRegisters() {
+	assert := assert.New(suite.T())
+
+	suite.plic.Write(PlicPriority+4*3, Memory.Word, 0xFF)
+	assert.Equal(PlicMaxPriority, readWord(suite.plic, PlicPriority+4*3))
+	suite.plic.Write(PlicPriority, Memory.Word, 5)
+	assert.Equal(uint32(0), readWord(suite.plic, PlicPriority)) // source 0 has no priority
+
+	suite.plic.Write(PlicEnable+PlicEnableStride, Memory.Word, 0xFFFFFFFF)
+	assert.Equal(uint32(0xFFFFFFFE), readWord(suite.plic, PlicEnable+PlicEnableStride))
+	suite.plic.Write(PlicEnable+PlicEnableStride+1, Memory.Byte, 0) // the other bytes keep their value
+	assert.Equal(uint32(0xFFFF00FE), readWord(suite.plic, PlicEnable+PlicEnableStride))
+
+	suite.plic.Write(PlicThreshold+PlicContextStride, Memory.Word, 2)
+	assert.Equal(uint32(2), readWord(suite.plic, PlicThreshold+PlicContextStride))
+	assert.Equal(uint32(0), readWord(suite.plic, PlicThreshold))
+	_, err := suite.plic.Read(PlicSize-2, Memory.Word)
+	assert.True(errors.Is(err, Memory.ErrAddressOutOfRange))
+}
+
+func (suite *PlicSuite) TestClaimAndComplete() {
+	assert := assert.New(suite.T())
+
+	suite.plic.Write(PlicPriority+4*2, Memory.Word, 1)
+	suite.plic.Write(PlicPriority+4*5, Memory.Word, 3)
+	suite.plic.Write(PlicPriority+4*6, Memory.Word, 3)
+	suite.plic.Write(PlicEnable, Memory.Word, 1<<2|1<<5|1<<6)
+	suite.plic.SetInterruptLine(2, true)
+	suite.plic.SetInterruptLine(5, true)
+	suite.plic.SetInterruptLine(6, true)
+	suite.plic.SetInterruptLine(6, false) // it stays pending until it is claimed
+	assert.Equal(uint32(1<<2|1<<5|1<<6), readWord(suite.plic, PlicPending))
+	assert.Equal(CsrManagers.MachineExternalInterrupt, suite.plic.GetInterrupts())
+
+	assert.Equal(uint32(5), readWord(suite.plic, PlicClaim)) // of equal priorities, the lowest source comes first
+	assert.Equal(uint32(6), readWord(suite.plic, PlicClaim))
+	assert.Equal(uint32(2), readWord(suite.plic, PlicClaim))
+	assert.Equal(uint32(0), readWord(suite.plic, PlicClaim))
+	assert.Equal(uint32(0), suite.plic.GetInterrupts())
+
+	suite.plic.SetInterruptLine(5, true) // a claimed source does not become pending again
+	assert.Equal(uint32(0), readWord(suite.plic, PlicPending))
+	suite.plic.Write(PlicClaim, Memory.Word, 6)
+	assert.Equal(uint32(0), readWord(suite.plic, PlicPending))
+	suite.plic.Write(PlicClaim, Memory.Word, 5) // its line is still raised
+	assert.Equal(uint32(1<<5), readWord(suite.plic, PlicPending))
+}
+
+func (suite *PlicSuite) TestContextsHaveTheirOwnEnablesAndThresholds() {
+	assert := assert.New(suite.T())
+
+	suite.plic.Write(PlicPriority+4*1, Memory.Word, 2)
+	suite.plic.Write(PlicPriority+4*4, Memory.Word, 4)
+	suite.plic.Write(PlicEnable, Memory.Word, 1<<1)
+	suite.plic.Write(PlicEnable+PlicEnableStride, Memory.Word, 1<<4)
+	suite.plic.Write(PlicThreshold, Memory.Word, 2)
+	suite.plic.SetInterruptLine(1, true)
+	suite.plic.SetInterruptLine(4, true)
+	assert.Equal(CsrManagers.SupervisorExternalInterrupt, suite.plic.GetInterrupts())
+	assert.Equal(uint32(0), readWord(suite.plic, PlicClaim)) // the priority of source 1 is not above the threshold
+
+	suite.plic.Write(PlicThreshold, Memory.Word, 1)
+	assert.Equal(CsrManagers.MachineExternalInterrupt|CsrManagers.SupervisorExternalInterrupt, suite.plic.GetInterrupts())
+	assert.Equal(uint32(4), readWord(suite.plic, PlicClaim+PlicContextStride))
+	assert.Equal(uint32(1), readWord(suite.plic, PlicClaim))
+}
@@ -0,0 +1,274 @@
+package devices
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	Memory "github.com/chenhowa/computer/lib/memory"
+)
+
+/*These constants are the numbers of the registers of the UART. Register N is at N times the register spacing
+from the address the UART is mapped at. The spacing is 1 byte, as in a 16550, unless the UART is made with a
+wider one, like a 16550 whose register shift gives each register a word of its own.
+RBR and THR, and IIR and FCR, share a number: the first is read, and the second written. While LCR.DLAB
+is set, RBR and IER hold the divisor latch instead*/
+const (
+	UartRbr uint32 = 0
+	UartThr uint32 = 0
+	UartIer uint32 = 1
+	UartIir uint32 = 2
+	UartFcr uint32 = 2
+	UartLcr uint32 = 3
+	UartMcr uint32 = 4
+	UartLsr uint32 = 5
+	UartMsr uint32 = 6
+	UartScr uint32 = 7
+)
+
+/*uartRegisters is the number of registers of the UART*/
+const uartRegisters = 8
+
+/*UartBase is the address that the UART is usually mapped at*/
+const UartBase uint32 = 0x10000000
+
+/*ErrNotARegister is what the reads and writes of the UART wrap when they do not access exactly one of its registers*/
+var ErrNotARegister = errors.New("Uart: access is not to a register")
+
+/*These constants are the fields of the registers of the UART*/
+const (
+	UartIerReceivedData     uint8 = 1 << 0
+	UartIerTransmitterEmpty uint8 = 1 << 1
+	uartIerWritable         uint8 = 0x0F
+
+	UartIirNoInterrupt      uint8 = 0x01
+	UartIirTransmitterEmpty uint8 = 0x02
+	UartIirReceivedData     uint8 = 0x04
+	uartIirFifosEnabled     uint8 = 0xC0
+
+	UartFcrEnableFifos uint8 = 1 << 0
+
+	UartLcrDlab uint8 = 1 << 7
+
+	UartLsrDataReady        uint8 = 1 << 0
+	UartLsrTransmitterEmpty uint8 = 1 << 5 // THR is empty
+	UartLsrTransmitterIdle  uint8 = 1 << 6 // THR and the shift register are empty
+
+	uartMcrWritable uint8 = 0x1F
+)
+
+/*uartFifoSize is the number of received bytes that the UART holds before its input stops being read*/
+const uartFifoSize = 16
+
+/*Uart is a memory-mapped UART that is compatible with the NS16550. Like UserInputReadMemory, the reads of its
+receive buffer are satisfied by an external source: the bytes that arrive from its input. They are read from
+the input in the background, so that LSR shows whether one has arrived without blocking the hart. The bytes
+written to THR are written to its output at once, so the transmitter is always empty.
+
+The UART asks for an interrupt, through IsInterruptPending, while IER enables the received data interrupt and a
+byte has arrived, or while IER enables the transmitter empty interrupt and THR has emptied since IIR last
+reported it. The interrupt only reaches the hart if the UART is attached to a source of a PLIC.
+The divisor latch, MCR and SCR hold what is written to them, but have no effect, and MSR reads as 0*/
+type Uart struct {
+	received    <-chan byte
+	output      io.Writer
+	spacing     Memory.AccessWidth
+	rbr         uint8
+	dataReady   bool
+	ier         uint8
+	lcr         uint8
+	mcr         uint8
+	scr         uint8
+	dll         uint8
+	dlm         uint8
+	fifoEnabled bool
+
+	// whether THR has emptied since IIR last reported it, which is what the transmitter empty interrupt asks for
+	transmitterEmptied bool
+}
+
+/*MakeUart is a constructor for a Uart that receives the bytes of `input`, and transmits to `output`.
+Its registers are 1 byte apart. It starts reading `input` in the background, and stops once `input` ends or fails*/
+func MakeUart(input io.Reader, output io.Writer) Uart {
+	return MakeUartWithRegisterSpacing(input, output, Memory.Byte)
+}
+
+/*MakeUartWithRegisterSpacing is a constructor for a Uart like MakeUart, but whose registers are `spacing` bytes
+apart. Each register can then be accessed with any width up to `spacing`*/
+func MakeUartWithRegisterSpacing(input io.Reader, output io.Writer, spacing Memory.AccessWidth) Uart {
+	received := make(chan byte, uartFifoSize)
+	go receive(input, received)
+
+	return Uart{
+		received: received,
+		output:   output,
+		spacing:  spacing,
+	}
+}
+
+/*receive sends the bytes of `input` to `received`, one at a time, until `input` ends or fails*/
+func receive(input io.Reader, received chan<- byte) {
+	buffer := make([]byte, 1)
+	for {
+		n, err := input.Read(buffer)
+		if n > 0 {
+			received <- buffer[0]
+		}
+		if err != nil {
+			close(received)
+			return
+		}
+	}
+}
+
+/*IsInterruptPending returns whether the UART is asking for an interrupt*/
+func (u *Uart) IsInterruptPending() bool {
+	return u.interruptIdentification() != UartIirNoInterrupt
+}
+
+/*interruptIdentification returns the interrupt that the UART is asking for, in the format of IIR.
+Received data comes before an empty transmitter*/
+func (u *Uart) interruptIdentification() uint8 {
+	if u.ier&UartIerReceivedData != 0 && u.poll() {
+		return UartIirReceivedData
+	}
+	if u.ier&UartIerTransmitterEmpty != 0 && u.transmitterEmptied {
+		return UartIirTransmitterEmpty
+	}
+	return UartIirNoInterrupt
+}
+
+/*poll moves the next byte that has arrived into RBR, if RBR is empty, and returns whether RBR holds a byte*/
+func (u *Uart) poll() bool {
+	if u.dataReady {
+		return true
+	}
+
+	select {
+	case b, ok := <-u.received:
+		if ok {
+			u.rbr = b
+			u.dataReady = true
+		}
+	default:
+	}
+	return u.dataReady
+}
+
+/*Read reads the register at `address`, whose value is its lowest byte, as `width` bytes. Read returns an error
+if `address` is not where a register is, or if `width` is wider than the spacing of the registers*/
+func (u *Uart) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	register, err := u.register(address, width)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(u.read(register)), nil
+}
+
+/*Write writes the lowest byte of `val` to the register at `address`, and ignores the rest of the `width` bytes.
+Write returns an error, and writes nothing, if `address` is not where a register is, or if `width` is wider than
+the spacing of the registers. It also returns an error if transmitting a byte to the output fails*/
+func (u *Uart) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	register, err := u.register(address, width)
+	if err != nil {
+		return err
+	}
+
+	return u.write(register, uint8(val))
+}
+
+/*register returns the number of the register that an access of `width` bytes at `address` accesses,
+or an error if the access is not within the UART, or is not to exactly one register*/
+func (u *Uart) register(address uint32, width Memory.AccessWidth) (uint32, error) {
+	if err := Memory.CheckAccess(address, width, u.GetAddressSpaceSize()); err != nil {
+		return 0, err
+	}
+	if address%uint32(u.spacing) != 0 || width > u.spacing {
+		return 0, fmt.Errorf("%w: %d bytes at %#x", ErrNotARegister, width, address)
+	}
+	return address / uint32(u.spacing), nil
+}
+
+/*GetAddressSpaceSize returns the size of the address space that the UART takes up*/
+func (u *Uart) GetAddressSpaceSize() uint {
+	return uartRegisters * uint(u.spacing)
+}
+
+/*read returns the value of `register`. Reading RBR takes its byte, and reading IIR clears
+the transmitter empty interrupt, if that is the interrupt that it reports*/
+func (u *Uart) read(register uint32) uint8 {
+	dlab := u.lcr&UartLcrDlab != 0
+	switch register {
+	case UartRbr:
+		if dlab {
+			return u.dll
+		}
+		if !u.poll() {
+			return 0
+		}
+		u.dataReady = false
+		return u.rbr
+	case UartIer:
+		if dlab {
+			return u.dlm
+		}
+		return u.ier
+	case UartIir:
+		iir := u.interruptIdentification()
+		if iir == UartIirTransmitterEmpty {
+			u.transmitterEmptied = false
+		}
+		if u.fifoEnabled {
+			iir |= uartIirFifosEnabled
+		}
+		return iir
+	case UartLcr:
+		return u.lcr
+	case UartMcr:
+		return u.mcr
+	case UartLsr:
+		lsr := UartLsrTransmitterEmpty | UartLsrTransmitterIdle
+		if u.poll() {
+			lsr |= UartLsrDataReady
+		}
+		return lsr
+	case UartScr:
+		return u.scr
+	}
+	return 0
+}
+
+/*write writes `val` to `register`. Writing to THR transmits `val`, and returns an error if the output fails*/
+func (u *Uart) write(register uint32, val uint8) error {
+	dlab := u.lcr&UartLcrDlab != 0
+	switch register {
+	case UartThr:
+		if dlab {
+			u.dll = val
+			return nil
+		}
+		if _, err := u.output.Write([]byte{val}); err != nil {
+			return fmt.Errorf("Uart: transmitting %#x: %w", val, err)
+		}
+		u.transmitterEmptied = true
+	case UartIer:
+		if dlab {
+			u.dlm = val
+			return nil
+		}
+		if u.ier&UartIerTransmitterEmpty == 0 && val&UartIerTransmitterEmpty != 0 {
+			u.transmitterEmptied = true // THR is already empty
+		}
+		u.ier = val & uartIerWritable
+	case UartFcr:
+		u.fifoEnabled = val&UartFcrEnableFifos != 0
+	case UartLcr:
+		u.lcr = val
+	case UartMcr:
+		u.mcr = val & uartMcrWritable
+	case UartScr:
+		u.scr = val
+	}
+	return nil
+}
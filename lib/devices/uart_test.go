@@ -0,0 +1,153 @@
+package devices
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	Memory "github.com/chenhowa/computer/lib/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UartSuite struct {
+	suite.Suite
+	output *bytes.Buffer
+}
+
+func TestUartSuite(t *testing.T) {
+	suite.Run(t, new(UartSuite))
+}
+
+func (suite *UartSuite) SetupTest() {
+	suite.output = &bytes.Buffer{}
+}
+
+/*readRegister reads `register` of `uart`, whose registers are 1 byte apart*/
+func readRegister(uart *Uart, register uint32) uint32 {
+	val, _ := uart.Read(register, Memory.Byte)
+	return uint32(val)
+}
+
+/*failingWriter is an output whose writes always fail*/
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("the output is closed")
+}
+
+/*waitForData waits until a byte has arrived at `uart`*/
+func (suite *UartSuite) waitForData(uart *Uart) {
+	assert.Eventually(suite.T(), func() bool { return readRegister(uart, UartLsr)&uint32(UartLsrDataReady) != 0 },
+		time.Second, time.Millisecond)
+}
+
+func (suite *UartSuite) TestTransmitsToOutput() {
+	assert := assert.New(suite.T())
+	uart := MakeUart(strings.NewReader(""), suite.output)
+
+	assert.Equal(uint32(UartLsrTransmitterEmpty|UartLsrTransmitterIdle), readRegister(&uart, UartLsr))
+	assert.Nil(uart.Write(UartThr, Memory.Byte, 'h'))
+	assert.Nil(uart.Write(UartThr, Memory.Byte, 'i'))
+	assert.Equal("hi", suite.output.String())
+
+	failing := MakeUart(strings.NewReader(""), failingWriter{})
+	assert.NotNil(failing.Write(UartThr, Memory.Byte, '!'))
+}
+
+func (suite *UartSuite) TestAccessesExactlyOneRegister() {
+	assert := assert.New(suite.T())
+	uart := MakeUart(strings.NewReader(""), suite.output)
+
+	assert.Equal(uint(8), uart.GetAddressSpaceSize())
+	assert.ErrorIs(uart.Write(UartThr, Memory.Word, 'x'), ErrNotARegister)
+	_, err := uart.Read(UartLsr, Memory.HalfWord)
+	assert.ErrorIs(err, ErrNotARegister)
+	_, err = uart.Read(8, Memory.Byte)
+	assert.ErrorIs(err, Memory.ErrAddressOutOfRange)
+	assert.Equal("", suite.output.String())
+}
+
+func (suite *UartSuite) TestRegisterSpacing() {
+	assert := assert.New(suite.T())
+	uart := MakeUartWithRegisterSpacing(strings.NewReader(""), suite.output, Memory.Word)
+
+	assert.Equal(uint(32), uart.GetAddressSpaceSize())
+	assert.Nil(uart.Write(4*UartScr, Memory.Word, 0x1234))
+	val, err := uart.Read(4*UartScr, Memory.Word)
+	assert.Nil(err)
+	assert.Equal(uint64(0x34), val)
+	val, err = uart.Read(4*UartLsr, Memory.Byte)
+	assert.Nil(err)
+	assert.Equal(uint64(UartLsrTransmitterEmpty|UartLsrTransmitterIdle), val)
+
+	assert.ErrorIs(uart.Write(4*UartThr+1, Memory.Byte, 'x'), ErrNotARegister)
+	assert.ErrorIs(uart.Write(4*UartThr, Memory.DoubleWord, 'x'), ErrNotARegister)
+	assert.Nil(uart.Write(4*UartThr, Memory.HalfWord, 'x'))
+	assert.Equal("x", suite.output.String())
+}
+
+func (suite *UartSuite) TestReceivesFromInput() {
+	assert := assert.New(suite.T())
+	uart := MakeUart(strings.NewReader("ok"), suite.output)
+
+	suite.waitForData(&uart)
+	assert.Equal(uint32('o'), readRegister(&uart, UartRbr))
+	suite.waitForData(&uart)
+	assert.Equal(uint32('k'), readRegister(&uart, UartRbr))
+	assert.Never(func() bool { return readRegister(&uart, UartLsr)&uint32(UartLsrDataReady) != 0 }, 20*time.Millisecond, time.Millisecond)
+	assert.Equal(uint32(0), readRegister(&uart, UartRbr))
+}
+
+func (suite *UartSuite) TestDivisorLatch() {
+	assert := assert.New(suite.T())
+	uart := MakeUart(strings.NewReader(""), suite.output)
+
+	uart.Write(UartLcr, Memory.Byte, uint64(UartLcrDlab|3))
+	uart.Write(UartThr, Memory.Byte, 0x0C)
+	uart.Write(UartIer, Memory.Byte, 0x01)
+	assert.Equal(uint32(0x0C), readRegister(&uart, UartRbr))
+	assert.Equal(uint32(0x01), readRegister(&uart, UartIer))
+	uart.Write(UartLcr, Memory.Byte, 3)
+	assert.Equal(uint32(0), readRegister(&uart, UartIer))
+	assert.Equal("", suite.output.String())
+}
+
+func (suite *UartSuite) TestInterrupts() {
+	assert := assert.New(suite.T())
+	uart := MakeUart(strings.NewReader("x"), suite.output)
+
+	suite.waitForData(&uart)
+	assert.False(uart.IsInterruptPending())
+	assert.Equal(uint32(UartIirNoInterrupt), readRegister(&uart, UartIir))
+
+	uart.Write(UartIer, Memory.Byte, uint64(UartIerReceivedData|UartIerTransmitterEmpty))
+	assert.True(uart.IsInterruptPending())
+	assert.Equal(uint32(UartIirReceivedData), readRegister(&uart, UartIir))
+	readRegister(&uart, UartRbr)
+
+	// the transmitter has been empty since the interrupt was enabled, until IIR reports it
+	assert.Equal(uint32(UartIirTransmitterEmpty), readRegister(&uart, UartIir))
+	assert.False(uart.IsInterruptPending())
+	uart.Write(UartThr, Memory.Byte, 'y')
+	assert.True(uart.IsInterruptPending())
+}
+
+func (suite *UartSuite) TestRaisesPlicSource() {
+	assert := assert.New(suite.T())
+	uart := MakeUart(strings.NewReader("x"), suite.output)
+	plic := MakePlic()
+	plic.AttachDevice(10, &uart)
+	plic.Write(PlicPriority+4*10, Memory.Word, 1)
+	plic.Write(PlicEnable, Memory.Word, 1<<10)
+	uart.Write(UartIer, Memory.Byte, uint64(UartIerReceivedData))
+
+	suite.waitForData(&uart)
+	assert.NotEqual(uint32(0), plic.GetInterrupts())
+	assert.Equal(uint32(10), readWord(&plic, PlicClaim))
+	assert.Equal(uint32('x'), readRegister(&uart, UartRbr))
+	plic.Write(PlicClaim, Memory.Word, 10) // the UART no longer asks for an interrupt, so the source does not become pending again
+	assert.Equal(uint32(0), plic.GetInterrupts())
+}
@@ -0,0 +1,111 @@
+package instructionmanagers
+
+import (
+	"fmt"
+
+	Traps "github.com/chenhowa/computer/lib/traps"
+)
+
+/*These constants are the lengths, in bytes, that an instruction can have*/
+const (
+	CompressedInstructionLength uint32 = 2
+	InstructionLength           uint32 = 4
+)
+
+/*PCInstructionManager32 is a program counter with 32-bit addresses, which starts at a configurable reset vector.
+Instructions are 4 bytes long unless the manager is told otherwise through SetInstructionLength. Jumps and branches must land on an address that is aligned to the instruction alignment,
+which is 2 bytes by default, as compressed instructions are supported. One that does not panics with an
+instruction-address-misaligned exception, and leaves the program counter where it was*/
+type PCInstructionManager32 struct {
+	resetVector        uint32
+	instructionAddress uint32
+	instructionLength  uint32
+	alignment          uint32
+	jumped             bool   // whether the next instruction is at `target`, rather than after the current one
+	target             uint32 // the address that the program counter jumps to, if it `jumped`
+}
+
+/*MakePCInstructionManager32 initializes a PCInstructionManager32 whose NEXT instruction address is `resetVector`.
+The current instruction address is not valid until manager.IncrementInstructionAddress() is called*/
+func MakePCInstructionManager32(resetVector uint32) PCInstructionManager32 {
+	manager := PCInstructionManager32{
+		resetVector:       resetVector,
+		instructionLength: InstructionLength,
+		alignment:         CompressedInstructionLength,
+	}
+	manager.Reset()
+
+	return manager
+}
+
+/*Reset makes the reset vector the NEXT instruction address again*/
+func (manager *PCInstructionManager32) Reset() {
+	manager.jumped = true
+	manager.target = manager.resetVector
+}
+
+/*GetResetVector returns the address of the first instruction after a reset*/
+func (manager *PCInstructionManager32) GetResetVector() uint32 {
+	return manager.resetVector
+}
+
+/*SetInstructionAlignment makes `alignment` the number of bytes that jump and branch targets must be aligned to.
+It is 2 while compressed instructions are supported, and 4 otherwise*/
+func (manager *PCInstructionManager32) SetInstructionAlignment(alignment uint32) {
+	manager.alignment = alignment
+}
+
+/*GetCurrentInstructionAddress returns the address where the current instruction is stored*/
+func (manager *PCInstructionManager32) GetCurrentInstructionAddress() uint32 {
+	return manager.instructionAddress
+}
+
+/*GetNextInstructionAddress gets the address of the instruction that runs after the current one: the
+address that the program counter jumps to, if it jumped, or the address immediately AFTER the current instruction*/
+func (manager *PCInstructionManager32) GetNextInstructionAddress() uint32 {
+	if manager.jumped {
+		return manager.target
+	}
+	return manager.instructionAddress + manager.instructionLength
+}
+
+/*SetInstructionLength tells the manager that the current instruction is `length` bytes long,
+so that the next instruction starts `length` bytes after it*/
+func (manager *PCInstructionManager32) SetInstructionLength(length uint32) {
+	manager.instructionLength = length
+}
+
+/*IncrementInstructionAddress updates the manager to point at the NextInstructionAddress*/
+func (manager *PCInstructionManager32) IncrementInstructionAddress() {
+	manager.instructionAddress = manager.GetNextInstructionAddress()
+	manager.jumped = false
+}
+
+/*AddOffsetForNextAddress updates the manager so the next Instruction Address is
+<current instruction address> + `offset`. It panics if that address is misaligned*/
+func (manager *PCInstructionManager32) AddOffsetForNextAddress(offset uint32) {
+	manager.jump(manager.instructionAddress + offset)
+}
+
+/*LoadInstructionAddressForNextAddress updates the manager so that the next Instruction Address
+is `newAddress`. It panics if `newAddress` is misaligned*/
+func (manager *PCInstructionManager32) LoadInstructionAddressForNextAddress(newAddress uint32) {
+	manager.jump(newAddress)
+}
+
+/*SetNextInstructionAddress makes `address` the next instruction address without checking its alignment,
+as a debugger or a loader does. The instruction at a misaligned address cannot be fetched*/
+func (manager *PCInstructionManager32) SetNextInstructionAddress(address uint32) {
+	manager.jumped = true
+	manager.target = address
+}
+
+/*jump makes `target` the next instruction address, unless it is misaligned*/
+func (manager *PCInstructionManager32) jump(target uint32) {
+	if target%manager.alignment != 0 {
+		panic(Traps.MakeException(Traps.InstructionAddressMisaligned, target,
+			fmt.Sprintf("PCInstructionManager32: target %#x is not aligned to %d bytes", target, manager.alignment)))
+	}
+
+	manager.SetNextInstructionAddress(target)
+}
@@ -0,0 +1,88 @@
+package instructionmanagers
+
+import (
+	"testing"
+
+	Traps "github.com/chenhowa/computer/lib/traps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InstructionManager32Suite struct {
+	suite.Suite
+	manager *PCInstructionManager32
+}
+
+func TestInstructionManager32Suite(t *testing.T) {
+	suite.Run(t, new(InstructionManager32Suite))
+}
+
+func (suite *InstructionManager32Suite) SetupTest() {
+	manager := MakePCInstructionManager32(0x80000000)
+	suite.manager = &manager
+}
+
+/*assertMisaligned asserts that `jump` panics with an instruction-address-misaligned exception for `target`*/
+func (suite *InstructionManager32Suite) assertMisaligned(target uint32, jump func()) {
+	defer func() {
+		exception, ok := recover().(Traps.Exception)
+		assert.True(suite.T(), ok)
+		assert.Equal(suite.T(), Traps.InstructionAddressMisaligned, exception.Cause)
+		assert.Equal(suite.T(), target, exception.Value)
+	}()
+	jump()
+}
+
+func (suite *InstructionManager32Suite) TestStartsAtTheResetVector() {
+	assert := assert.New(suite.T())
+
+	assert.Equal(uint32(0x80000000), suite.manager.GetNextInstructionAddress())
+	suite.manager.IncrementInstructionAddress()
+	assert.Equal(uint32(0x80000000), suite.manager.GetCurrentInstructionAddress())
+	assert.Equal(uint32(0x80000004), suite.manager.GetNextInstructionAddress())
+
+	suite.manager.IncrementInstructionAddress()
+	suite.manager.Reset()
+	assert.Equal(suite.manager.GetResetVector(), suite.manager.GetNextInstructionAddress())
+}
+
+func (suite *InstructionManager32Suite) TestJumpsDoNotWrapAbove64KiB() {
+	assert := assert.New(suite.T())
+
+	suite.manager.IncrementInstructionAddress()
+	suite.manager.AddOffsetForNextAddress(0x20000)
+	assert.Equal(uint32(0x80000000), suite.manager.GetCurrentInstructionAddress())
+	assert.Equal(uint32(0x80020000), suite.manager.GetNextInstructionAddress())
+	suite.manager.IncrementInstructionAddress()
+	suite.manager.AddOffsetForNextAddress(0xFFFFFFF0) // -16
+	suite.manager.IncrementInstructionAddress()
+	assert.Equal(uint32(0x8001FFF0), suite.manager.GetCurrentInstructionAddress())
+
+	suite.manager.LoadInstructionAddressForNextAddress(0x10000)
+	suite.manager.IncrementInstructionAddress()
+	assert.Equal(uint32(0x10000), suite.manager.GetCurrentInstructionAddress())
+}
+
+func (suite *InstructionManager32Suite) TestCompressedInstructions() {
+	assert := assert.New(suite.T())
+
+	suite.manager.IncrementInstructionAddress()
+	suite.manager.SetInstructionLength(CompressedInstructionLength)
+	assert.Equal(uint32(0x80000002), suite.manager.GetNextInstructionAddress())
+	suite.manager.AddOffsetForNextAddress(6)
+	suite.manager.IncrementInstructionAddress()
+	assert.Equal(uint32(0x80000006), suite.manager.GetCurrentInstructionAddress())
+}
+
+func (suite *InstructionManager32Suite) TestMisalignedTargets() {
+	assert := assert.New(suite.T())
+
+	suite.manager.IncrementInstructionAddress()
+	suite.assertMisaligned(0x80000003, func() { suite.manager.AddOffsetForNextAddress(3) })
+	assert.Equal(uint32(0x80000004), suite.manager.GetNextInstructionAddress())
+
+	suite.manager.SetInstructionAlignment(InstructionLength)
+	suite.assertMisaligned(0x102, func() { suite.manager.LoadInstructionAddressForNextAddress(0x102) })
+	suite.manager.LoadInstructionAddressForNextAddress(0x104)
+	assert.Equal(uint32(0x104), suite.manager.GetNextInstructionAddress())
+}
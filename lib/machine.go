@@ -0,0 +1,384 @@
+package computer
+
+import (
+	"errors"
+	"fmt"
+
+	Binary "github.com/chenhowa/computer/lib/binaryInstructionExecution"
+	Execution "github.com/chenhowa/computer/lib/binaryInstructionExecution/execution"
+	Producer "github.com/chenhowa/computer/lib/binaryInstructionExecution/executionFactoryProducers"
+	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	Clocks "github.com/chenhowa/computer/lib/clocks"
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	InstructionManagers "github.com/chenhowa/computer/lib/instructionManagers"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
+	VirtualMemory "github.com/chenhowa/computer/lib/virtualMemory"
+)
+
+/*Machine is a single RISC-V hart. It owns the registers, the program counter, the CSRs
+and the clock, and it runs the fetch/decode/execute loop against the memory it is given.
+
+The Machine halts when it executes an EBREAK instruction (unless breakpoints trap), when the caller
+asks it to through Halt, or when an instruction fails for a reason that is not an exception.
+Exceptions trap to the handler in mtvec instead, or to the one in stvec when medeleg delegates them.
+The machine starts at machine privilege, and can drop to supervisor or user privilege with MRET and SRET.
+Below machine privilege, fetches, loads and stores are translated by an Sv32 MMU while satp selects Sv32.
+Its page tables are read from the memory that loads and stores use. Physical memory protection then checks
+every access, so supervisor and user mode can only access the memory that a PMP entry allows them to.
+Between instructions, the machine takes the interrupts that its interrupt sources raise, once they are
+enabled in mie and mstatus.
+*/
+type Machine struct {
+	executor          *Execution.RiscVInstructionExecutor
+	manager           *InstructionManagers.PCInstructionManager32
+	instructionMemory Memory.Reader
+	csr               *Execution.AdaptedCsrOperator
+	csrFile           *CsrManagers.MachineCsrFile
+	mmu               *VirtualMemory.Sv32Mmu
+	clock             *Clocks.Clock
+	factory           *Binary.RiscVBinaryInstructionExecutionFactory
+	halter            *breakpointHalter
+	lastTrap          *Traps.Exception
+	interruptSources  []interruptSource
+}
+
+/*interruptSource is a device that raises interrupts, such as the CLINT. GetInterrupts returns
+the interrupts that it is raising, as bits of mip*/
+type interruptSource interface {
+	GetInterrupts() uint32
+}
+
+/*MakeMachine constructs a Machine whose registers and CSRs are all 0, that executes instructions
+from `memory`, starting with the instruction at `resetAddress`. Each executed instruction ticks the `clock`
+*/
+func MakeMachine(memory Memory.ReadWriter, resetAddress uint32, clock *Clocks.Clock) Machine {
+	return MakeMachineWithInstructionMemory(memory, memory, resetAddress, clock)
+}
+
+/*MakeMachineWithInstructionMemory constructs a Machine like MakeMachine does, except that
+instructions are fetched from `instructionMemory`, while loads and stores still use `memory`
+*/
+func MakeMachineWithInstructionMemory(memory Memory.ReadWriter, instructionMemory Memory.Reader,
+	resetAddress uint32, clock *Clocks.Clock) Machine {
+	executor := Execution.MakeRiscVInstructionExecutor([32]uint32{})
+	manager := InstructionManagers.MakePCInstructionManager32(resetAddress)
+	csr := CsrManagers.MakeMachineCsrFile(0, clock)
+	halter := breakpointHalter{}
+	mmu := VirtualMemory.MakeSv32Mmu(&csr, memory)
+	adaptedTranslator := Execution.MakeAdaptedAddressTranslator(&mmu)
+	executor.UseAddressTranslator(&adaptedTranslator)
+
+	adaptedManager := Execution.MakeAdaptedInstructionManager(&manager)
+	adaptedCsr := Execution.MakeAdaptedCsrOperator(&csr)
+	adaptedTrapEnv := Execution.MakeAdaptedTrapEnvManager(&csr)
+	adaptedExecEnv := Execution.MakeAdaptedExecutionEnvManager(&environmentCaller{csr: &csr})
+	adaptedDebugEnv := Execution.MakeAdaptedDebugEnvManager(&halter)
+	adaptedExecutor := Producer.MakeAdaptedRiscVExecutor(&executor, memory,
+		&adaptedManager, &adaptedCsr, &adaptedTrapEnv, &adaptedExecEnv, &adaptedDebugEnv)
+	factory := Binary.MakeRiscVInstructionExecutionFactory(&adaptedExecutor)
+
+	machine := Machine{
+		executor:          &executor,
+		manager:           &manager,
+		instructionMemory: instructionMemory,
+		csr:               &adaptedCsr,
+		csrFile:           &csr,
+		mmu:               &mmu,
+		clock:             clock,
+		factory:           &factory,
+		halter:            &halter,
+	}
+
+	return machine
+}
+
+/*Step fetches the instruction at the program counter, decodes it, and executes it.
+Compressed instructions are 2 bytes long, so the program counter only has to be aligned to 2 bytes.
+If an interrupt is pending and enabled, Step takes it instead, so that the machine continues at its handler
+and the instruction runs once the handler returns to it.
+
+An instruction that raises an exception (because it is illegal, accesses memory that does not exist,
+or is an ECALL, for example) does not retire. Instead, the machine traps to the handler in mtvec, and Step
+still succeeds. If the machine is already halted, or the instruction failed for any other reason,
+Step returns an error and the machine is left halted*/
+func (m *Machine) Step() (err error) {
+	if m.IsHalted() {
+		return errors.New("Step: machine is halted")
+	}
+
+	m.lastTrap = nil
+	m.manager.IncrementInstructionAddress()
+	address := m.manager.GetCurrentInstructionAddress()
+	if m.interrupt(address) {
+		return nil
+	}
+
+	var instruction uint32
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if exception, ok := exceptionOf(r, instruction); ok {
+			m.trap(exception, address)
+			return
+		}
+
+		m.Halt()
+		if rErr, ok := r.(error); ok {
+			err = fmt.Errorf("Step: instruction at address %d failed: %w", address, rErr)
+		} else {
+			err = fmt.Errorf("Step: instruction at address %d failed: %v", address, r)
+		}
+	}()
+
+	if address%InstructionManagers.CompressedInstructionLength != 0 {
+		panic(Traps.MakeException(Traps.InstructionAddressMisaligned, address, "Step: misaligned program counter"))
+	}
+	instruction = m.fetch(address)
+	if Parser.IsCompressed(instruction) {
+		m.manager.SetInstructionLength(InstructionManagers.CompressedInstructionLength)
+	} else {
+		m.manager.SetInstructionLength(InstructionManagers.InstructionLength)
+	}
+	m.factory.Produce(instruction).Execute()
+
+	m.clock.Tick()
+	m.csrFile.Retire()
+	return nil
+}
+
+/*fetch reads the instruction at the virtual `address`. The two halves of an instruction that is not compressed
+are checked against physical memory protection separately, and they are translated, and read, separately
+when the instruction crosses into another page. A compressed instruction
+can end where the instruction memory does, so a word that cannot be read is read again as a halfword.
+An error that the instruction memory returns, because nothing is mapped there or because it cannot be executed,
+is an instruction access fault*/
+func (m *Machine) fetch(address uint32) uint32 {
+	const halfLength = InstructionManagers.CompressedInstructionLength
+	crossesPage := (address+halfLength)%VirtualMemory.PageSize == 0
+	physicalAddress := m.mmu.TranslateFetch(address, halfLength)
+	instruction, err := Memory.Fetch(m.instructionMemory, physicalAddress, Memory.Word)
+	if err != nil {
+		lowerHalf, halfErr := Memory.Fetch(m.instructionMemory, physicalAddress, Memory.HalfWord)
+		if halfErr != nil || !(Parser.IsCompressed(uint32(lowerHalf)) || crossesPage) {
+			panicIfFetchFailed(err, physicalAddress)
+		}
+		instruction = lowerHalf
+	}
+	if Parser.IsCompressed(uint32(instruction)) {
+		return uint32(instruction)
+	}
+	if !crossesPage {
+		m.mmu.CheckPhysicalAccess(address+halfLength, physicalAddress+halfLength, halfLength, VirtualMemory.Fetch)
+		return uint32(instruction)
+	}
+
+	physicalAddress = m.mmu.TranslateFetch(address+halfLength, halfLength)
+	upperHalf, err := Memory.Fetch(m.instructionMemory, physicalAddress, Memory.HalfWord)
+	panicIfFetchFailed(err, physicalAddress)
+	return uint32(instruction)&0xFFFF | uint32(upperHalf)<<16
+}
+
+/*panicIfFetchFailed raises an instruction access fault if reading the instruction
+at the physical `address` failed with `err`*/
+func panicIfFetchFailed(err error, address uint32) {
+	if err != nil {
+		panic(Traps.MakeException(Traps.InstructionAccessFault, address, err.Error()))
+	}
+}
+
+/*trap takes the trap for `exception`, raised by the instruction at `address`, so that the machine
+continues at the trap handler. The instruction does not retire, but it still takes a clock cycle*/
+func (m *Machine) trap(exception Traps.Exception, address uint32) {
+	handler := m.csrFile.TakeTrap(uint32(exception.Cause), exception.Value, address)
+	m.manager.LoadInstructionAddressForNextAddress(handler)
+	m.clock.Tick()
+	m.lastTrap = &exception
+}
+
+/*interrupt takes the trap for the interrupt that is pending and enabled before the instruction at `address`,
+if there is one, and returns whether it took it. Interrupts are raised by the interrupt sources of the machine,
+or by software writing to mip. Taking an interrupt takes a clock cycle*/
+func (m *Machine) interrupt(address uint32) bool {
+	var lines uint32
+	for _, source := range m.interruptSources {
+		lines |= source.GetInterrupts()
+	}
+	m.csrFile.SetInterruptLines(lines)
+
+	cause, ok := m.csrFile.PendingInterrupt()
+	if !ok {
+		return false
+	}
+	handler := m.csrFile.TakeTrap(cause, 0, address)
+	m.manager.LoadInstructionAddressForNextAddress(handler)
+	m.clock.Tick()
+	return true
+}
+
+/*exceptionOf returns the exception that a panic with `r` raised, if it was one. Illegal CSR accesses are
+illegal instructions, and illegal instructions report the bits of the `instruction` that raised them*/
+func exceptionOf(r interface{}, instruction uint32) (Traps.Exception, bool) {
+	err, ok := r.(error)
+	if !ok {
+		return Traps.Exception{}, false
+	}
+
+	var exception Traps.Exception
+	if errors.Is(err, CsrManagers.ErrIllegalCsrAccess) {
+		exception = Traps.MakeException(Traps.IllegalInstruction, 0, err.Error())
+	} else if !errors.As(err, &exception) {
+		return Traps.Exception{}, false
+	}
+
+	if exception.Cause == Traps.IllegalInstruction {
+		exception.Value = instruction
+		if Parser.IsCompressed(instruction) {
+			exception.Value &= 0xFFFF
+		}
+	}
+	return exception, true
+}
+
+/*Run steps the machine until it halts, or until `maxSteps` instructions have been executed.
+If `maxSteps` is 0, the number of steps is not limited. Run returns the number of
+instructions executed, and the error that halted the machine, if there was one*/
+func (m *Machine) Run(maxSteps uint) (uint, error) {
+	var steps uint
+	for !m.IsHalted() && (maxSteps == 0 || steps < maxSteps) {
+		if err := m.Step(); err != nil {
+			return steps, err
+		}
+		steps++
+	}
+
+	return steps, nil
+}
+
+/*Halt stops the machine, so that it will not execute any more instructions*/
+func (m *Machine) Halt() {
+	m.halter.halted = true
+}
+
+/*IsHalted returns whether the machine has halted*/
+func (m *Machine) IsHalted() bool {
+	return m.halter.halted
+}
+
+/*GetRegister returns the value of register `reg`*/
+func (m *Machine) GetRegister(reg uint) uint32 {
+	return m.executor.Get(reg)
+}
+
+/*SetRegister writes `val` into the register `reg`. Writes to register 0 are ignored*/
+func (m *Machine) SetRegister(reg uint, val uint32) {
+	m.executor.Set(reg, val)
+}
+
+/*GetProgramCounter returns the address of the next instruction that the machine will execute*/
+func (m *Machine) GetProgramCounter() uint32 {
+	return m.manager.GetNextInstructionAddress()
+}
+
+/*SetProgramCounter makes `address` the address of the next instruction that the machine will execute*/
+func (m *Machine) SetProgramCounter(address uint32) {
+	m.manager.SetNextInstructionAddress(address)
+}
+
+/*GetFloatRegister returns the raw 64 bits of floating-point register `reg`*/
+func (m *Machine) GetFloatRegister(reg uint) uint64 {
+	return m.executor.GetFloat(reg)
+}
+
+/*GetCsr returns the value of the control and status register `csr`, as a debugger sees it: whatever
+the privilege that the machine is running at. It panics if the machine does not have that CSR*/
+func (m *Machine) GetCsr(csr uint) uint32 {
+	inspector := Execution.MakeAdaptedCsrOperator(&csrInspector{file: m.csrFile})
+	return m.executor.GetCsr(csr, &inspector)
+}
+
+/*AddInterruptSource connects `source` to the machine, which takes the interrupts that it raises*/
+func (m *Machine) AddInterruptSource(source interruptSource) {
+	m.interruptSources = append(m.interruptSources, source)
+}
+
+/*GetPrivilege returns the privilege that the machine is running at*/
+func (m *Machine) GetPrivilege() CsrManagers.Privilege {
+	return m.csrFile.GetPrivilege()
+}
+
+/*GetLastTrap returns the exception that the last step trapped on, if it trapped*/
+func (m *Machine) GetLastTrap() (Traps.Exception, bool) {
+	if m.lastTrap == nil {
+		return Traps.Exception{}, false
+	}
+	return *m.lastTrap, true
+}
+
+/*GetTlbStatistics returns the statistics of the TLB of the MMU*/
+func (m *Machine) GetTlbStatistics() VirtualMemory.TlbStatistics {
+	return m.mmu.GetTlbStatistics()
+}
+
+/*SetMisalignedAccessPolicy chooses what loads and stores at misaligned addresses do. By default, they are allowed*/
+func (m *Machine) SetMisalignedAccessPolicy(policy Execution.MisalignedAccessPolicy) {
+	m.executor.SetMisalignedAccessPolicy(policy)
+}
+
+/*GetMisalignedAccessStatistics returns the number of loads and stores that the machine has executed
+at misaligned addresses, including the ones that trapped*/
+func (m *Machine) GetMisalignedAccessStatistics() Execution.MisalignedAccessStatistics {
+	return m.executor.GetMisalignedAccessStatistics()
+}
+
+/*GetInstructionsRetired returns the number of instructions that the machine has finished executing,
+which is the value of minstret. If the program wrote minstret, the count starts from the value written*/
+func (m *Machine) GetInstructionsRetired() uint {
+	return uint(m.csrFile.GetInstructionsRetired())
+}
+
+/*csrInspector reads the CSRs of the Machine for GetCsr. It cannot write them*/
+type csrInspector struct {
+	file *CsrManagers.MachineCsrFile
+}
+
+func (i *csrInspector) Get(register uint) uint32 {
+	return i.file.Inspect(register)
+}
+
+func (i *csrInspector) Set(register uint, val uint32) {
+	panic("csrInspector: CSRs cannot be written while they are inspected")
+}
+
+/*SetTrapOnBreakpoint chooses what EBREAK does. By default, it halts the machine, as if a debugger
+had taken over. If `trap` is true, it raises a breakpoint exception for the trap handler instead*/
+func (m *Machine) SetTrapOnBreakpoint(trap bool) {
+	m.halter.trap = trap
+}
+
+/*breakpointHalter is the debugging environment of the Machine. It halts the machine whenever
+an EBREAK instruction is executed, unless breakpoints are set to trap*/
+type breakpointHalter struct {
+	halted bool
+	trap   bool
+}
+
+func (h *breakpointHalter) DebugBreak() {
+	if h.trap {
+		panic(Traps.MakeException(Traps.Breakpoint, 0, "EBREAK"))
+	}
+	h.halted = true
+}
+
+/*environmentCaller is the execution environment of the Machine. Every ECALL raises the environment
+call exception of the privilege that the hart is running at*/
+type environmentCaller struct {
+	csr *CsrManagers.MachineCsrFile
+}
+
+func (c *environmentCaller) ExecuteCall() {
+	cause := Traps.UserEnvironmentCall + Traps.Cause(c.csr.GetPrivilege())
+	panic(Traps.MakeException(cause, 0, "ECALL"))
+}
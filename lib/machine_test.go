@@ -0,0 +1,620 @@
+package computer
+
+import (
+	"testing"
+
+	Execution "github.com/chenhowa/computer/lib/binaryInstructionExecution/execution"
+	Producer "github.com/chenhowa/computer/lib/binaryInstructionExecution/executionFactoryProducers"
+	Parser "github.com/chenhowa/computer/lib/binaryInstructionExecution/instructionParsing"
+	Clocks "github.com/chenhowa/computer/lib/clocks"
+	Delay "github.com/chenhowa/computer/lib/clocks/delay"
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	Devices "github.com/chenhowa/computer/lib/devices"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
+	VirtualMemory "github.com/chenhowa/computer/lib/virtualMemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MachineSuite struct {
+	suite.Suite
+	memory  *MachineMemoryMock
+	clock   *Clocks.Clock
+	machine *Machine
+}
+
+func TestMachineSuite(t *testing.T) {
+	suite.Run(t, new(MachineSuite))
+}
+
+/*MachineMemoryMock is a small, little-endian, byte-addressable memory*/
+type MachineMemoryMock struct {
+	bytes [256]uint8
+}
+
+func (m *MachineMemoryMock) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	if err := Memory.CheckAccess(address, width, uint(len(m.bytes))); err != nil {
+		return 0, err
+	}
+	var val uint64
+	for i := uint32(0); i < uint32(width); i++ {
+		val |= uint64(m.bytes[address+i]) << (8 * i)
+	}
+	return val, nil
+}
+
+func (m *MachineMemoryMock) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	if err := Memory.CheckAccess(address, width, uint(len(m.bytes))); err != nil {
+		return err
+	}
+	for i := uint32(0); i < uint32(width); i++ {
+		m.bytes[address+i] = uint8(val >> (8 * i))
+	}
+	return nil
+}
+
+/*readWord reads the word at `address` of the memory of the suite*/
+func (suite *MachineSuite) readWord(address uint32) uint32 {
+	val, err := suite.memory.Read(address, Memory.Word)
+	suite.Nil(err)
+	return uint32(val)
+}
+
+func (suite *MachineSuite) SetupTest() {
+	memory := MachineMemoryMock{}
+	suite.memory = &memory
+
+	clock := Clocks.MakeClock(&Delay.NoDelay{})
+	suite.clock = &clock
+
+	machine := MakeMachine(suite.memory, 0, suite.clock)
+	suite.machine = &machine
+}
+
+func (suite *MachineSuite) loadProgram(instructions []uint32) {
+	for i, instruction := range instructions {
+		suite.memory.Write(uint32(4*i), Memory.Word, uint64(instruction))
+	}
+}
+
+func addImmediate(dest uint, reg uint, immediate uint) uint32 {
+	return Parser.BuildInstructionI(uint(Parser.ImmArith), dest, uint(Producer.AddI), reg, immediate)
+}
+
+func ebreak() uint32 {
+	return Parser.BuildInstructionI(uint(Parser.System), 0, uint(Producer.Private), 0, uint(Producer.EBREAK))
+}
+
+func ecall() uint32 {
+	return Parser.BuildInstructionI(uint(Parser.System), 0, uint(Producer.Private), 0, uint(Producer.ECALL))
+}
+
+func mret() uint32 {
+	return Parser.BuildInstructionI(uint(Parser.System), 0, uint(Producer.Private), 0, uint(Producer.MRET))
+}
+
+func sret() uint32 {
+	return Parser.BuildInstructionI(uint(Parser.System), 0, uint(Producer.Private), 0, uint(Producer.SRET))
+}
+
+/*allowAllPhysicalMemory returns the instructions that let every privilege access all of physical memory,
+by making PMP entry 0 a NAPOT region that covers it*/
+func allowAllPhysicalMemory() []uint32 {
+	napot := CsrManagers.PmpNapot | CsrManagers.PmpRead | CsrManagers.PmpWrite | CsrManagers.PmpExecute
+	return []uint32{
+		addImmediate(1, 0, 0xFFF), // -1
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Pmpaddr0, 1),
+		addImmediate(1, 0, uint(napot)),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Pmpcfg0, 1),
+	}
+}
+
+func (suite *MachineSuite) TestRun_HaltsOnBreakpoint() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{
+		addImmediate(1, 0, 5),
+		addImmediate(2, 0, 7),
+		Parser.BuildInstructionR(uint(Parser.RegArith), 3, uint(Producer.Add), 1, 2, uint(Producer.F0)),
+		Parser.BuildInstructionS(uint(Parser.Store), uint(Producer.StoreWord), 0, 3, 100),
+		Parser.BuildInstructionI(uint(Parser.Load), 4, uint(Producer.LoadWord), 0, 100),
+		ebreak(),
+		addImmediate(5, 0, 1),
+	})
+
+	steps, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint(6), steps)
+	assert.True(suite.machine.IsHalted())
+	assert.Equal(uint32(12), suite.machine.GetRegister(3))
+	assert.Equal(uint32(12), suite.machine.GetRegister(4))
+	assert.Equal(uint32(12), suite.readWord(100))
+	assert.Equal(uint32(0), suite.machine.GetRegister(5))
+	assert.Equal(uint32(24), suite.machine.GetProgramCounter())
+	assert.Equal(uint(6), suite.machine.GetInstructionsRetired())
+	assert.Equal(uint(6), suite.clock.GetCount())
+}
+
+func (suite *MachineSuite) TestRun_TakesBranch() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{
+		addImmediate(1, 0, 5),
+		Parser.BuildInstructionB(uint(Parser.Branch), uint(Producer.Bneq), 1, 0, 8),
+		addImmediate(2, 0, 1),
+		ebreak(),
+	})
+
+	_, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint32(0), suite.machine.GetRegister(2))
+	assert.Equal(uint32(16), suite.machine.GetProgramCounter())
+}
+
+func (suite *MachineSuite) TestRun_BranchesBackward() {
+	assert := assert.New(suite.T())
+	// addi x1, x0, 3; loop: addi x1, x1, -1; bne x1, x0, loop; ebreak
+	suite.loadProgram([]uint32{0x00300093, 0xFFF08093, 0xFE009EE3, 0x00100073})
+
+	steps, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint(8), steps)
+	assert.Equal(uint32(0), suite.machine.GetRegister(1))
+	assert.Equal(uint32(16), suite.machine.GetProgramCounter())
+}
+
+func (suite *MachineSuite) TestRun_MultipliesAndDivides() {
+	assert := assert.New(suite.T())
+	// addi x1, x0, -7; addi x2, x0, 2; mul x3, x1, x2; div x4, x1, x0; rem x5, x1, x2; ebreak
+	suite.loadProgram([]uint32{0xFF900093, 0x00200113, 0x022081B3, 0x0200C233, 0x0220E2B3, 0x00100073})
+
+	_, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint32(0xFFFFFFF2), suite.machine.GetRegister(3))
+	assert.Equal(uint32(0xFFFFFFFF), suite.machine.GetRegister(4))
+	assert.Equal(uint32(0xFFFFFFFF), suite.machine.GetRegister(5))
+}
+
+func (suite *MachineSuite) TestRun_AtomicIncrement() {
+	assert := assert.New(suite.T())
+	// addi x1, x0, 64; retry: lr.w x2, (x1); addi x2, x2, 1; sc.w x3, x2, (x1); bne x3, x0, retry
+	// amoswap.w x4, x0, (x1); ebreak
+	suite.loadProgram([]uint32{0x04000093, 0x1000A12F, 0x00110113, 0x1820A1AF, 0xFE019AE3, 0x0800A22F, 0x00100073})
+
+	steps, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint(7), steps)
+	assert.Equal(uint32(0), suite.machine.GetRegister(3))
+	assert.Equal(uint32(1), suite.machine.GetRegister(4))
+	assert.Equal(uint32(0), suite.readWord(64))
+}
+
+func floatOperation(funct5 uint, format uint, dest uint, reg1 uint, reg2 uint, rm uint) uint32 {
+	return Parser.BuildInstructionR(uint(Parser.OpFP), dest, rm, reg1, reg2, funct5<<2|format)
+}
+
+func (suite *MachineSuite) TestRun_FloatingPoint() {
+	assert := assert.New(suite.T())
+	single, double := Execution.SinglePrecision, Execution.DoublePrecision
+	suite.loadProgram([]uint32{
+		addImmediate(1, 0, 1),
+		floatOperation(uint(Producer.FConvertFromInt), single, 1, 1, uint(Producer.FCvtWord), 0),
+		addImmediate(2, 0, 3),
+		floatOperation(uint(Producer.FConvertFromInt), single, 2, 2, uint(Producer.FCvtWord), 0),
+		floatOperation(uint(Producer.FDiv), single, 3, 1, 2, 7),
+		Parser.BuildInstructionS(uint(Parser.StoreFP), uint(Producer.StoreFloat), 0, 3, 200),
+		Parser.BuildInstructionI(uint(Parser.LoadFP), 4, uint(Producer.LoadFloat), 0, 200),
+		floatOperation(uint(Producer.FCompare), single, 3, 3, 4, uint(Producer.FEq)),
+		floatOperation(uint(Producer.FConvertFormat), double, 5, 3, single, 7),
+		Parser.BuildInstructionR4(uint(Parser.FMAdd), 6, 7, 5, 5, 5, double),
+		Parser.BuildInstructionS(uint(Parser.StoreFP), uint(Producer.StoreDouble), 0, 6, 208),
+		ebreak(),
+	})
+
+	_, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint32(0x3EAAAAAB), suite.readWord(200))
+	assert.Equal(uint64(0xFFFFFFFF3EAAAAAB), suite.machine.GetFloatRegister(4))
+	assert.Equal(uint32(1), suite.machine.GetRegister(3))
+	assert.Equal(uint64(0x3FD5555560000000), suite.machine.GetFloatRegister(5))
+	assert.Equal(suite.machine.GetFloatRegister(6), uint64(suite.readWord(212))<<32|uint64(suite.readWord(208)))
+	assert.Equal(uint32(1), suite.machine.GetCsr(Execution.FflagsCsr)) // only inexact
+}
+
+func (suite *MachineSuite) TestRun_CompressedInstructions() {
+	assert := assert.New(suite.T())
+	// c.li x8, 3; loop: c.addi x8, -1; addi x9, x9, 100; c.bnez x8, loop; c.jal f; c.ebreak; f: c.mv x10, x9; c.jr x1
+	for i, halfword := range []uint16{0x440D, 0x147D, 0x8493, 0x0644, 0xFC6D, 0x2011, 0x9002, 0x8526, 0x8082} {
+		suite.memory.Write(uint32(2*i), Memory.HalfWord, uint64(halfword))
+	}
+
+	steps, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint(14), steps)
+	assert.Equal(uint32(300), suite.machine.GetRegister(10))
+	assert.Equal(uint32(12), suite.machine.GetRegister(1))
+	assert.Equal(uint32(14), suite.machine.GetProgramCounter())
+}
+
+func csrOperation(funct3 uint, dest uint, csr uint, src uint) uint32 {
+	return Parser.BuildInstructionI(uint(Parser.System), dest, funct3, src, csr)
+}
+
+func (suite *MachineSuite) TestRun_MachineCsrs() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{
+		addImmediate(1, 0, 42),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mscratch, 1),
+		csrOperation(uint(Producer.CSRRS), 2, CsrManagers.Mepc, 0),
+		csrOperation(uint(Producer.CSRRS), 3, CsrManagers.Mscratch, 0),
+		csrOperation(uint(Producer.CSRRS), 4, CsrManagers.Mhartid, 0),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mhartid, 1),
+	})
+
+	_, err := suite.machine.Run(6)
+	assert.Nil(err)
+	assert.Equal(uint32(0), suite.machine.GetRegister(2))
+	assert.Equal(uint32(42), suite.machine.GetRegister(3))
+	assert.Equal(uint32(0), suite.machine.GetRegister(4))
+	assert.Equal(uint32(42), suite.machine.GetCsr(CsrManagers.Mscratch))
+
+	// writing the read-only mhartid is an illegal instruction, which traps to mtvec
+	assert.Equal(uint32(Traps.IllegalInstruction), suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(20), suite.machine.GetCsr(CsrManagers.Mepc))
+	assert.Equal(csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mhartid, 1), suite.machine.GetCsr(CsrManagers.Mtval))
+	assert.Equal(uint32(0), suite.machine.GetProgramCounter())
+}
+
+func (suite *MachineSuite) TestRun_TrapHandlerReturnsWithMret() {
+	assert := assert.New(suite.T())
+	nop := addImmediate(0, 0, 0)
+	suite.loadProgram([]uint32{
+		addImmediate(1, 0, 32),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mtvec, 1),
+		ecall(),
+		addImmediate(5, 0, 9),
+		ebreak(),
+		nop,
+		nop,
+		nop,
+		// the handler at 32 skips over the ECALL that trapped
+		csrOperation(uint(Producer.CSRRS), 6, CsrManagers.Mepc, 0),
+		addImmediate(6, 6, 4),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mepc, 6),
+		mret(),
+	})
+
+	steps, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint(9), steps)
+	assert.True(suite.machine.IsHalted())
+	assert.Equal(uint32(9), suite.machine.GetRegister(5))
+	assert.Equal(uint32(Traps.MachineEnvironmentCall), suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(12), suite.machine.GetCsr(CsrManagers.Mepc))
+	assert.Equal(CsrManagers.MstatusMPIE, suite.machine.GetCsr(CsrManagers.Mstatus)&(CsrManagers.MstatusMIE|CsrManagers.MstatusMPIE))
+	assert.Equal(uint(8), suite.machine.GetInstructionsRetired()) // the ECALL does not retire
+}
+
+func (suite *MachineSuite) TestRun_UserModeIsSeparatedFromTheKernel() {
+	assert := assert.New(suite.T())
+	const supervisorHandler, user, machineHandler = 56, 76, 92
+	suite.loadProgram(append(allowAllPhysicalMemory(),
+		addImmediate(1, 0, user),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mepc, 1),
+		addImmediate(1, 0, supervisorHandler),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Stvec, 1),
+		addImmediate(1, 0, machineHandler),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mtvec, 1),
+		addImmediate(1, 0, 1<<uint(Traps.UserEnvironmentCall)),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Medeleg, 1),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mstatus, 0), // MPP is user privilege
+		mret(),
+		// the supervisor handler skips over the ECALL that trapped
+		csrOperation(uint(Producer.CSRRS), 2, CsrManagers.Sepc, 0),
+		addImmediate(2, 2, 4),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Sepc, 2),
+		addImmediate(3, 0, 1),
+		sret(),
+		// the user program
+		ecall(),
+		addImmediate(4, 0, 2),
+		csrOperation(uint(Producer.CSRRS), 5, CsrManagers.Mstatus, 0),
+		addImmediate(6, 0, 3),
+		// the machine handler
+		ebreak(),
+	))
+
+	_, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.True(suite.machine.IsHalted())
+	assert.Equal(uint32(1), suite.machine.GetRegister(3))
+	assert.Equal(uint32(2), suite.machine.GetRegister(4))
+	assert.Equal(uint32(0), suite.machine.GetRegister(5))
+	assert.Equal(uint32(0), suite.machine.GetRegister(6))
+
+	assert.Equal(uint32(Traps.UserEnvironmentCall), suite.machine.GetCsr(CsrManagers.Scause))
+	assert.Equal(uint32(Traps.IllegalInstruction), suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(user+8), suite.machine.GetCsr(CsrManagers.Mepc))
+	assert.Equal(uint32(0), suite.machine.GetCsr(CsrManagers.Mstatus)&CsrManagers.MstatusMPP) // trapped from user mode
+	assert.Equal(CsrManagers.Machine, suite.machine.GetPrivilege())
+}
+
+func (suite *MachineSuite) TestRun_SupervisorModeTranslatesAddresses() {
+	assert := assert.New(suite.T())
+	const supervisor, machineHandler = 60, 68
+	// the root page table is at address 0, and maps the first megapage to itself, without write permission
+	const pte = VirtualMemory.PteValid | VirtualMemory.PteRead | VirtualMemory.PteExecute | VirtualMemory.PteAccessed
+	suite.memory.Write(0, Memory.Word, uint64(pte))
+	for i, instruction := range append(allowAllPhysicalMemory(),
+		addImmediate(1, 0, supervisor),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mepc, 1),
+		addImmediate(1, 0, machineHandler),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mtvec, 1),
+		Parser.BuildInstructionU(uint(Parser.LUI), 1, 0x80000), // Sv32, with the root page table at PPN 0
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Satp, 1),
+		addImmediate(1, 0, 1<<(CsrManagers.MstatusMPPShift-1)),
+		Parser.BuildInstructionR(uint(Parser.RegArith), 1, uint(Producer.Add), 1, 1, uint(Producer.F0)), // MPP is supervisor privilege
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mstatus, 1),
+		mret(),
+		// the supervisor program
+		Parser.BuildInstructionI(uint(Parser.Load), 2, uint(Producer.LoadWord), 0, 0),
+		Parser.BuildInstructionS(uint(Parser.Store), uint(Producer.StoreWord), 0, 2, 200),
+		// the machine handler
+		ebreak(),
+	) {
+		suite.memory.Write(uint32(4*(i+1)), Memory.Word, uint64(instruction))
+	}
+	machine := MakeMachine(suite.memory, 4, suite.clock)
+
+	_, err := machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(pte, machine.GetRegister(2))
+	assert.Equal(uint32(Traps.StorePageFault), machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(200), machine.GetCsr(CsrManagers.Mtval))
+	assert.Equal(uint32(supervisor+4), machine.GetCsr(CsrManagers.Mepc))
+	assert.Equal(uint32(0), suite.readWord(200))
+
+	// only the first fetch in supervisor mode walked the page table
+	assert.Equal(VirtualMemory.TlbStatistics{Hits: 3, Misses: 1}, machine.GetTlbStatistics())
+}
+
+func (suite *MachineSuite) TestRun_TakesTimerInterrupt() {
+	assert := assert.New(suite.T())
+	const handler = 32
+	clint := Devices.MakeClint(suite.clock, 1)
+	clint.Write(Devices.ClintMtimecmp, Memory.DoubleWord, 10)
+	suite.machine.AddInterruptSource(&clint)
+	suite.loadProgram([]uint32{
+		addImmediate(1, 0, handler),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mtvec, 1),
+		addImmediate(1, 0, uint(CsrManagers.MachineTimerInterrupt)),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mie, 1),
+		addImmediate(1, 0, uint(CsrManagers.MstatusMIE)),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mstatus, 1),
+		// loop: addi x2, x2, 1; jal x0, loop
+		addImmediate(2, 2, 1),
+		0xFFDFF06F,
+		// the handler
+		ebreak(),
+	})
+
+	steps, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint(12), steps) // the interrupt is taken once mtime reaches 10, before the eleventh instruction
+	assert.Equal(uint32(2), suite.machine.GetRegister(2))
+	assert.Equal(CsrManagers.InterruptBit|7, suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(24), suite.machine.GetCsr(CsrManagers.Mepc))
+	assert.Equal(CsrManagers.MstatusMPIE, suite.machine.GetCsr(CsrManagers.Mstatus)&(CsrManagers.MstatusMIE|CsrManagers.MstatusMPIE))
+	assert.Equal(CsrManagers.MachineTimerInterrupt, suite.machine.GetCsr(CsrManagers.Mip))
+	_, trapped := suite.machine.GetLastTrap()
+	assert.False(trapped) // the last step was the EBREAK
+}
+
+func (suite *MachineSuite) TestStep_TrapsOnAccessFault() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{
+		Parser.BuildInstructionI(uint(Parser.Load), 2, uint(Producer.LoadWord), 1, 4),
+	})
+	suite.machine.SetRegister(1, 0xFFFE)
+	suite.machine.SetRegister(2, 7)
+
+	assert.Nil(suite.machine.Step())
+	exception, ok := suite.machine.GetLastTrap()
+	assert.True(ok)
+	assert.Equal(Traps.LoadAccessFault, exception.Cause)
+	assert.Equal(uint32(Traps.LoadAccessFault), suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(0x10002), suite.machine.GetCsr(CsrManagers.Mtval))
+	assert.Equal(uint32(7), suite.machine.GetRegister(2))
+	assert.False(suite.machine.IsHalted())
+}
+
+func (suite *MachineSuite) TestStep_ChecksBothHalvesOfAnInstructionAgainstPmp() {
+	assert := assert.New(suite.T())
+	// locked entries hold machine mode too: the first 64 bytes are executable, and the next 64 are not
+	suite.loadProgram([]uint32{
+		addImmediate(1, 0, 64>>2),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Pmpaddr0, 1),
+		addImmediate(1, 0, 128>>2),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Pmpaddr0+1, 1),
+		Parser.BuildInstructionU(uint(Parser.LUI), 1, 0x9),
+		addImmediate(1, 1, 0x98F), // 0x898F, the configurations of both entries
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Pmpcfg0, 1),
+	})
+	// a C.NOP leaves the next instruction straddling the boundary
+	suite.memory.Write(60, Memory.HalfWord, 0x0001)
+	suite.memory.Write(62, Memory.Word, uint64(addImmediate(2, 0, 1)))
+
+	for i := 0; i < 7; i++ {
+		assert.Nil(suite.machine.Step())
+	}
+	assert.Equal(uint32(0x898F), suite.machine.GetCsr(CsrManagers.Pmpcfg0))
+	suite.machine.SetProgramCounter(60)
+	assert.Nil(suite.machine.Step())
+	assert.Nil(suite.machine.Step())
+
+	exception, ok := suite.machine.GetLastTrap()
+	assert.True(ok)
+	assert.Equal(Traps.InstructionAccessFault, exception.Cause)
+	assert.Equal(uint32(62), suite.machine.GetCsr(CsrManagers.Mepc))
+	assert.Equal(uint32(64), suite.machine.GetCsr(CsrManagers.Mtval))
+	assert.Equal(uint32(0), suite.machine.GetRegister(2))
+}
+
+func (suite *MachineSuite) TestStep_TrapsOnUnmappedAddresses() {
+	assert := assert.New(suite.T())
+	bus := Memory.MakeBus()
+	ram := Memory.MakeBasicMemory(0xFF)
+	assert.Nil(bus.Map(0x100, &ram))
+	machine := MakeMachine(&bus, 0x100, suite.clock)
+	ram.Write(0, Memory.Word, uint64(Parser.BuildInstructionI(uint(Parser.Load), 2, uint(Producer.LoadWord), 1, 4)))
+	machine.SetRegister(1, 0x200)
+
+	assert.Nil(machine.Step())
+	assert.Equal(uint32(Traps.LoadAccessFault), machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(0x204), machine.GetCsr(CsrManagers.Mtval))
+
+	assert.Nil(machine.Step()) // mtvec is 0, which is not mapped
+	assert.Equal(uint32(Traps.InstructionAccessFault), machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(0), machine.GetCsr(CsrManagers.Mepc))
+}
+
+func (suite *MachineSuite) TestRun_JumpsAbove64KiB() {
+	assert := assert.New(suite.T())
+	bus := Memory.MakeBus()
+	ram := Memory.MakeSparseMemory32(0x7FFFFFFF)
+	assert.Nil(bus.Map(0x80000000, &ram))
+	machine := MakeMachine(&bus, 0x80000000, suite.clock)
+	ram.Write(0, Memory.Word, uint64(Parser.BuildInstructionJ(uint(Parser.JAL), 1, 0x20000)))
+	ram.Write(0x20000, Memory.Word, uint64(ebreak()))
+
+	steps, err := machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint(2), steps)
+	assert.Equal(uint32(0x80000004), machine.GetRegister(1))
+	assert.Equal(uint32(0x80020004), machine.GetProgramCounter())
+}
+
+func (suite *MachineSuite) TestStep_TrapsOnRomWritesAndNoExecuteFetches() {
+	assert := assert.New(suite.T())
+	rom := Memory.MakeBasicMemory(0xFF)
+	ram := Memory.MakeBasicMemory(0xFF)
+	further := Memory.MakeCompositeMemory32WithAttributes(&ram, Memory.NoExecute, nil)
+	composite := Memory.MakeCompositeMemory32WithAttributes(&rom, Memory.ROM, &further)
+	machine := MakeMachine(&composite, 0, suite.clock)
+	assert.Nil(rom.Write(0, Memory.Word, uint64(Parser.BuildInstructionS(uint(Parser.Store), uint(Producer.StoreWord), 0, 1, 0))))
+	machine.SetRegister(1, 0xFFFFFFFF)
+
+	assert.Nil(machine.Step()) // the boot ROM cannot overwrite itself
+	assert.Equal(uint32(Traps.StoreAccessFault), machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(0), machine.GetCsr(CsrManagers.Mtval))
+	instruction, err := rom.Read(0, Memory.Word)
+	assert.Nil(err)
+	assert.NotEqual(uint64(0xFFFFFFFF), instruction)
+
+	machine.SetProgramCounter(0x100)
+	assert.Nil(machine.Step()) // the RAM holds data, not instructions
+	assert.Equal(uint32(Traps.InstructionAccessFault), machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(0x100), machine.GetCsr(CsrManagers.Mepc))
+}
+
+func (suite *MachineSuite) TestStep_FollowsTheMisalignedAccessPolicy() {
+	assert := assert.New(suite.T())
+	loadWord := Parser.BuildInstructionI(uint(Parser.Load), 2, uint(Producer.LoadWord), 1, 0)
+	suite.loadProgram([]uint32{addImmediate(1, 0, 101), loadWord, loadWord, loadWord})
+
+	assert.Nil(suite.machine.Step())
+	assert.Nil(suite.machine.Step())
+	_, trapped := suite.machine.GetLastTrap()
+	assert.False(trapped)
+
+	suite.machine.SetMisalignedAccessPolicy(Execution.TrapMisaligned)
+	assert.Nil(suite.machine.Step())
+	assert.Equal(uint32(Traps.LoadAddressMisaligned), suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(101), suite.machine.GetCsr(CsrManagers.Mtval))
+
+	suite.machine.SetMisalignedAccessPolicy(Execution.FaultMisaligned)
+	suite.machine.SetProgramCounter(12)
+	assert.Nil(suite.machine.Step())
+	assert.Equal(uint32(Traps.LoadAccessFault), suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(Execution.MisalignedAccessStatistics{Loads: 3}, suite.machine.GetMisalignedAccessStatistics())
+}
+
+func (suite *MachineSuite) TestStep_TrapsOnBreakpointWhenAsked() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{addImmediate(1, 0, 16), csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mtvec, 1), ebreak()})
+	suite.machine.SetTrapOnBreakpoint(true)
+
+	_, err := suite.machine.Run(3)
+	assert.Nil(err)
+	assert.False(suite.machine.IsHalted())
+	assert.Equal(uint32(Traps.Breakpoint), suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(8), suite.machine.GetCsr(CsrManagers.Mepc))
+	assert.Equal(uint32(16), suite.machine.GetProgramCounter())
+}
+
+func (suite *MachineSuite) TestRun_CountersCountCyclesAndRetiredInstructions() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{
+		addImmediate(1, 0, 1),
+		csrOperation(uint(Producer.CSRRS), 2, CsrManagers.Cycle, 0),
+		csrOperation(uint(Producer.CSRRS), 3, CsrManagers.Instret, 0),
+		csrOperation(uint(Producer.CSRRS), 4, CsrManagers.Time, 0),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Minstret, 0),
+		csrOperation(uint(Producer.CSRRS), 5, CsrManagers.Instret, 0),
+		csrOperation(uint(Producer.CSRRW), 0, CsrManagers.Mcycle, 0),
+		csrOperation(uint(Producer.CSRRS), 6, CsrManagers.Cycle, 0),
+	})
+
+	_, err := suite.machine.Run(8)
+	assert.Nil(err)
+	assert.Equal(uint32(1), suite.machine.GetRegister(2))
+	assert.Equal(uint32(2), suite.machine.GetRegister(3))
+	assert.Equal(uint32(3), suite.machine.GetRegister(4))
+	// the next instruction reads what was written, without the writing instruction counted in it
+	assert.Equal(uint32(0), suite.machine.GetRegister(5))
+	assert.Equal(uint32(0), suite.machine.GetRegister(6))
+	assert.Equal(uint32(3), suite.machine.GetCsr(CsrManagers.Minstret))
+	assert.Equal(uint32(1), suite.machine.GetCsr(CsrManagers.Mcycle))
+	assert.Equal(uint(3), suite.machine.GetInstructionsRetired())
+}
+
+func (suite *MachineSuite) TestRun_StopsAfterMaxSteps() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{
+		Parser.BuildInstructionJ(uint(Parser.JAL), 0, 0),
+	})
+
+	steps, err := suite.machine.Run(10)
+	assert.Nil(err)
+	assert.Equal(uint(10), steps)
+	assert.False(suite.machine.IsHalted())
+	assert.Equal(uint32(0), suite.machine.GetProgramCounter())
+}
+
+func (suite *MachineSuite) TestStep_TrapsOnUnknownInstruction() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{0x7F})
+
+	assert.Nil(suite.machine.Step())
+	assert.False(suite.machine.IsHalted())
+	assert.Equal(uint32(Traps.IllegalInstruction), suite.machine.GetCsr(CsrManagers.Mcause))
+	assert.Equal(uint32(0x7F), suite.machine.GetCsr(CsrManagers.Mtval))
+	assert.Equal(uint32(0), suite.machine.GetCsr(CsrManagers.Mepc))
+	assert.Equal(uint(0), suite.machine.GetInstructionsRetired())
+}
+
+func (suite *MachineSuite) TestSetProgramCounterAndRegister() {
+	assert := assert.New(suite.T())
+	suite.loadProgram([]uint32{
+		ebreak(),
+		addImmediate(1, 1, 3),
+		ebreak(),
+	})
+
+	suite.machine.SetProgramCounter(4)
+	suite.machine.SetRegister(1, 10)
+	suite.machine.SetRegister(0, 10)
+	_, err := suite.machine.Run(0)
+	assert.Nil(err)
+	assert.Equal(uint32(13), suite.machine.GetRegister(1))
+	assert.Equal(uint32(0), suite.machine.GetRegister(0))
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*AccessWidth is the number of bytes that a read or a write accesses*/
+type AccessWidth uint
+
+/*These constants are the widths of the accesses that memories support*/
+const (
+	Byte       AccessWidth = 1
+	HalfWord   AccessWidth = 2
+	Word       AccessWidth = 4
+	DoubleWord AccessWidth = 8
+)
+
+/*Reader is a memory that can be read. Read returns the `width` bytes starting at `address`, in little-endian
+order, in the lowest bytes of the result. It returns an error instead of a value if the access is not supported*/
+type Reader interface {
+	Read(address uint32, width AccessWidth) (uint64, error)
+}
+
+/*Writer is a memory that can be written. Write writes the lowest `width` bytes of `val` to memory, starting
+at `address`, in little-endian order. It returns an error, and writes nothing, if the access is not supported*/
+type Writer interface {
+	Write(address uint32, width AccessWidth, val uint64) error
+}
+
+/*ReadWriter is a memory that can be read and written*/
+type ReadWriter interface {
+	Reader
+	Writer
+}
+
+/*Fetcher is a memory that knows the difference between reading data and fetching instructions, such as a memory
+whose regions can be made Readable but not Executable, or the other way around. Fetch is Read, for instructions*/
+type Fetcher interface {
+	Fetch(address uint32, width AccessWidth) (uint64, error)
+}
+
+/*Fetch fetches the `width` bytes of instructions starting at `address` from `memory`. Memories that are not
+Fetchers do not tell instructions from data, so the instructions are read from them*/
+func Fetch(memory Reader, address uint32, width AccessWidth) (uint64, error) {
+	if fetcher, ok := memory.(Fetcher); ok {
+		return fetcher.Fetch(address, width)
+	}
+	return memory.Read(address, width)
+}
+
+/*Interface is the interface that every memory, adapter and memory-mapped device implements. Addresses start
+from 0, and GetAddressSpaceSize returns the first address that the memory does not support;
+that is, it returns (MaxAddress + 1)*/
+type Interface interface {
+	ReadWriter
+	GetAddressSpaceSize() uint
+}
+
+/*ErrInvalidWidth is what reads and writes wrap when they are asked for a width that is not 1, 2, 4 or 8 bytes*/
+var ErrInvalidWidth = errors.New("memory: access width is not 1, 2, 4 or 8 bytes")
+
+/*ErrAddressOutOfRange is what reads and writes wrap when some of the bytes that they access are not in memory*/
+var ErrAddressOutOfRange = errors.New("memory: address is out of range")
+
+/*isValid returns whether `w` is one of the supported widths*/
+func (w AccessWidth) isValid() bool {
+	return w == Byte || w == HalfWord || w == Word || w == DoubleWord
+}
+
+/*Mask returns the bits of a value that an access of width `w` holds*/
+func (w AccessWidth) Mask() uint64 {
+	if w >= DoubleWord {
+		return ^uint64(0)
+	}
+	return 1<<(8*w) - 1
+}
+
+/*CheckAccess returns an error if `width` is not supported, or if any of the `width` bytes from `address`
+are at or above `size`, the size of the address space of the memory*/
+func CheckAccess(address uint32, width AccessWidth, size uint) error {
+	if !width.isValid() {
+		return fmt.Errorf("%w: %d bytes at %#x", ErrInvalidWidth, width, address)
+	}
+	if uint64(address)+uint64(width) > uint64(size) {
+		return fmt.Errorf("%w: %d bytes at %#x", ErrAddressOutOfRange, width, address)
+	}
+	return nil
+}
+
+/*readBytes assembles `width` bytes, starting at `address`, from `getByte`, in little-endian order*/
+func readBytes(address uint32, width AccessWidth, getByte func(address uint32) uint8) uint64 {
+	var val uint64
+	for i := uint32(0); i < uint32(width); i++ {
+		val |= uint64(getByte(address+i)) << (8 * i)
+	}
+	return val
+}
+
+/*writeBytes hands the lowest `width` bytes of `val`, starting at `address`, to `setByte`, in little-endian order*/
+func writeBytes(address uint32, width AccessWidth, val uint64, setByte func(address uint32, b uint8)) {
+	for i := uint32(0); i < uint32(width); i++ {
+		setByte(address+i, uint8(val>>(8*i)))
+	}
+}
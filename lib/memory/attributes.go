@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*Attributes are the permissions and properties of a region of memory, as bits*/
+type Attributes uint8
+
+/*These constants are the bits of Attributes. A region can be read, written and executed only if it is
+Readable, Writable and Executable. Cacheable regions may be cached, and Idempotent regions can be accessed
+more than once, or speculatively, with the same effect as accessing them once. A region that is not Writable
+faults on writes, unless it IgnoresWrites, in which case the writes succeed without writing anything*/
+const (
+	Readable Attributes = 1 << iota
+	Writable
+	Executable
+	Cacheable
+	Idempotent
+	IgnoresWrites
+)
+
+/*These constants are the Attributes of the kinds of regions that machines are usually built from.
+A ROM that ignores writes, like a boot ROM often does, is ROM | IgnoresWrites*/
+const (
+	RAM         = Readable | Writable | Executable | Cacheable | Idempotent
+	ROM         = Readable | Executable | Cacheable | Idempotent
+	ReadOnly    = Readable | Cacheable | Idempotent
+	ExecuteOnly = Executable | Cacheable | Idempotent
+	NoExecute   = Readable | Writable | Cacheable | Idempotent
+	IO          = Readable | Writable
+)
+
+/*ErrAccessDenied is what reads, writes and fetches wrap when the attributes of a region do not permit them*/
+var ErrAccessDenied = errors.New("memory: access is denied")
+
+/*accessNames name the accesses that need each permission, for errors*/
+var accessNames = map[Attributes]string{
+	Readable:   "read",
+	Writable:   "written",
+	Executable: "executed",
+}
+
+/*permits returns whether a region with the attributes `a` permits an access that needs `permission`,
+which is Readable, Writable or Executable. Writes are permitted where they are ignored*/
+func (a Attributes) permits(permission Attributes) bool {
+	return a&permission != 0 || (permission == Writable && a&IgnoresWrites != 0)
+}
+
+/*deniedError returns the error for an access of `width` bytes at `address` that is not permitted to need `permission`*/
+func deniedError(address uint32, width AccessWidth, permission Attributes) error {
+	return fmt.Errorf("%w: the %d bytes at %#x cannot all be %s", ErrAccessDenied, width, address, accessNames[permission])
+}
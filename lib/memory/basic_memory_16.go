@@ -1,14 +1,8 @@
 package memory
 
-import (
-	"math"
-
-	Utils "github.com/chenhowa/computer/lib/binaryInstructionExecution/bitUtils"
-)
-
-/*BasicMemory implements basic reading from and writing to bits in memory. It
-supports a 16-bit address space and is byte-addressable. Reads are up to 32 bits,
-as are writes
+/*BasicMemory implements basic reading from and writing to bytes in memory. It
+supports a 16-bit address space and is byte-addressable. Reads and writes are
+1, 2, 4 or 8 bytes wide
 */
 type BasicMemory struct {
 	memory     [65536]uint8
@@ -24,57 +18,29 @@ func MakeBasicMemory(maxAddress uint16) BasicMemory {
 	return memory
 }
 
-/*Get reads a doubleword from `address` in memory
-If reading that doubleword involves reading from some invalid addresses,
-the invalid addresses are regarded as having value `0`*/
-func (m *BasicMemory) Get(address uint16) uint32 {
-	if !(uint(address) < m.GetAddressSpaceSize()) {
-		panic("Get: address was outside addressable memory")
-	}
-
-	var byteCount uint16
-	var val uint32
-	for ; byteCount < 4; byteCount++ {
-		if uint(address)+uint(byteCount) < m.GetAddressSpaceSize() {
-			val += uint32(m.memory[address+byteCount]) << (8 * byteCount)
-		} else {
-			break
-		}
+/*Read returns the `width` bytes starting at `address` in memory. It returns an error
+if any of them are outside of addressable memory*/
+func (m *BasicMemory) Read(address uint32, width AccessWidth) (uint64, error) {
+	if err := CheckAccess(address, width, m.GetAddressSpaceSize()); err != nil {
+		return 0, err
 	}
 
-	return val
+	return readBytes(address, width, func(address uint32) uint8 {
+		return m.memory[address]
+	}), nil
 }
 
-/*Set writes the lowest `bitsToWrite` bits of `val` to memory at `address`. It starts writing
-from the LSB of `val`, and it returns the number of bits successfully written. Attempting to
-write more than 32 bits will write at most 32 bits. If memory runs out before `bitsToWrite` bits
-are written, no explicit error occurs. The caller can detect this through the return value, which is the number
-of bits successfully written*/
-func (m *BasicMemory) Set(address uint16, val uint32, bitsToWrite uint) NumberOfBitsWritten {
-	if !(uint(address) < m.GetAddressSpaceSize()) {
-		panic("Set: address was outside addressable memory")
-	}
-
-	var bitsWritten NumberOfBitsWritten
-
-	var trackedAddress = address
-	for uint(bitsWritten) < bitsToWrite {
-		if !(uint(trackedAddress) < m.GetAddressSpaceSize()) {
-			break
-		} else if bitsLeft := bitsToWrite - uint(bitsWritten); bitsLeft < 8 {
-			keepTopBitsMask := uint8(Utils.KeepBitsInInclusiveRange(uint32(math.MaxUint32), bitsLeft, 7))
-			m.memory[trackedAddress] = (m.memory[trackedAddress] & keepTopBitsMask) |
-				uint8(Utils.GetBitsInInclusiveRange(uint(val), uint(bitsWritten), uint(bitsWritten)+bitsLeft-1))
-			bitsWritten += NumberOfBitsWritten(bitsLeft)
-			break
-		} else {
-			m.memory[trackedAddress] = uint8(Utils.GetBitsInInclusiveRange(uint(val), uint(bitsWritten), uint(bitsWritten)+7))
-			bitsWritten += 8
-			trackedAddress++
-		}
+/*Write writes the lowest `width` bytes of `val` to memory at `address`. It returns an error,
+and writes nothing, if any of them are outside of addressable memory*/
+func (m *BasicMemory) Write(address uint32, width AccessWidth, val uint64) error {
+	if err := CheckAccess(address, width, m.GetAddressSpaceSize()); err != nil {
+		return err
 	}
 
-	return bitsWritten
+	writeBytes(address, width, val, func(address uint32, b uint8) {
+		m.memory[address] = b
+	})
+	return nil
 }
 
 /*GetAddressSpaceSize returns the size of the address space. That is,
@@ -1,11 +1,10 @@
 package memory
 
 import (
+	"errors"
 	"math"
 	"testing"
 
-	Utils "github.com/chenhowa/computer/lib/binaryInstructionExecution/bitUtils"
-
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -22,100 +21,78 @@ func (suite *BasicMemorySuite) SetupTest() {
 
 }
 
-func (suite *BasicMemorySuite) TestSet_Basic_1() {
-	memory := MakeBasicMemory(19)
-	assert := assert.New(suite.T())
-
-	memory.Set(0, math.MaxUint32, 8)
-	assert.Equal(uint32(math.MaxUint8), memory.Get(0))
-	assert.Equal(uint32(0), memory.Get(1))
+func (suite *BasicMemorySuite) assertRead(memory *BasicMemory, address uint32, width AccessWidth, val uint64) {
+	actual, err := memory.Read(address, width)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), val, actual)
 }
 
-func (suite *BasicMemorySuite) TestSet_Basic_2() {
+func (suite *BasicMemorySuite) TestWrite_Byte() {
 	memory := MakeBasicMemory(19)
 	assert := assert.New(suite.T())
 
-	memory.Set(0, math.MaxUint32, 32)
-	assert.Equal(uint32(math.MaxUint32), memory.Get(0))
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 0, 23), memory.Get(1))
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 0, 15), memory.Get(2))
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 0, 7), memory.Get(3))
-	assert.Equal(uint32(0), memory.Get(4))
+	assert.NoError(memory.Write(0, Byte, math.MaxUint64))
+	suite.assertRead(&memory, 0, Word, math.MaxUint8)
+	suite.assertRead(&memory, 1, Word, 0)
 }
 
-func (suite *BasicMemorySuite) TestSet_Basic_3() {
+func (suite *BasicMemorySuite) TestWrite_Word() {
 	memory := MakeBasicMemory(19)
 	assert := assert.New(suite.T())
 
-	memory.Set(0, math.MaxUint32, 10)
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 0, 9), memory.Get(0))
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 0, 1), memory.Get(1))
-	assert.Equal(uint32(0), memory.Get(2))
+	assert.NoError(memory.Write(0, Word, math.MaxUint64))
+	suite.assertRead(&memory, 0, Word, math.MaxUint32)
+	suite.assertRead(&memory, 1, Word, 0x00FFFFFF)
+	suite.assertRead(&memory, 2, HalfWord, math.MaxUint16)
+	suite.assertRead(&memory, 3, Byte, math.MaxUint8)
+	suite.assertRead(&memory, 4, Word, 0)
 }
 
-func (suite *BasicMemorySuite) TestSet_Basic_4() {
+func (suite *BasicMemorySuite) TestWrite_LittleEndian() {
 	memory := MakeBasicMemory(19)
 	assert := assert.New(suite.T())
 
-	memory.Set(4, uint32(math.MaxUint8-1), 2)
-	assert.Equal(uint32(2), memory.Get(4))
+	assert.NoError(memory.Write(4, DoubleWord, 0x0807060504030201))
+	suite.assertRead(&memory, 4, DoubleWord, 0x0807060504030201)
+	suite.assertRead(&memory, 5, Byte, 0x02)
+	suite.assertRead(&memory, 6, HalfWord, 0x0403)
+	suite.assertRead(&memory, 8, Word, 0x08070605)
 
-	memory.Set(0, math.MaxUint32, 33)
-	assert.Equal(uint32(math.MaxUint32), memory.Get(0))
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 0, 23)+Utils.KeepBitsInInclusiveRange(math.MaxUint32, 25, 25), memory.Get(1))
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 0, 15)+Utils.KeepBitsInInclusiveRange(math.MaxUint32, 17, 17), memory.Get(2))
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 0, 7)+Utils.KeepBitsInInclusiveRange(math.MaxUint32, 9, 9), memory.Get(3))
-	assert.Equal(uint32(2), memory.Get(4))
+	assert.NoError(memory.Write(6, HalfWord, 0xAAAA))
+	suite.assertRead(&memory, 4, DoubleWord, 0x08070605AAAA0201)
 }
 
-func (suite *BasicMemorySuite) TestSet_Near_Max_Address_1() {
+func (suite *BasicMemorySuite) TestWrite_Near_Max_Address() {
 	memory := MakeBasicMemory(19)
 	assert := assert.New(suite.T())
 
-	memory.Set(19, Utils.KeepBitsInInclusiveRange(math.MaxUint32, 4, 31), 16)
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 4, 7), memory.Get(19))
+	assert.NoError(memory.Write(18, HalfWord, 0xFFF0))
+	suite.assertRead(&memory, 18, HalfWord, 0xFFF0)
+	suite.assertRead(&memory, 19, Byte, 0xFF)
 }
 
-func (suite *BasicMemorySuite) TestSet_Near_Max_Address_2() {
+func (suite *BasicMemorySuite) TestWrite_Errors() {
 	memory := MakeBasicMemory(19)
 	assert := assert.New(suite.T())
 
-	memory.Set(18, Utils.KeepBitsInInclusiveRange(math.MaxUint32, 4, 31), 16)
-	assert.Equal(Utils.KeepBitsInInclusiveRange(math.MaxUint32, 4, 15), memory.Get(18))
+	assert.True(errors.Is(memory.Write(20, Byte, 100), ErrAddressOutOfRange))
+	assert.True(errors.Is(memory.Write(19, HalfWord, 0xFFFF), ErrAddressOutOfRange))
+	suite.assertRead(&memory, 19, Byte, 0) // nothing was written
+	assert.True(errors.Is(memory.Write(0, 3, 100), ErrInvalidWidth))
 }
 
-func (suite *BasicMemorySuite) TestSet_Panic() {
-	memory := MakeBasicMemory(19)
-	assert := assert.New(suite.T())
-
-	assert.Panics(func() {
-		memory.Set(20, 100, 5)
-	})
-	assert.Panics(func() {
-		memory.Set(21, 100, 5)
-	})
-}
-
-func (suite *BasicMemorySuite) TestGet() {
-	memory := MakeBasicMemory(19)
-	assert := assert.New(suite.T())
-
-	memory.Set(4, uint32(math.MaxUint8-1), 2)
-	assert.Equal(uint32(2), memory.Get(4))
-}
-
-func (suite *BasicMemorySuite) TestGet_Panic() {
+func (suite *BasicMemorySuite) TestRead_Errors() {
 	memory := MakeBasicMemory(19)
 	assert := assert.New(suite.T())
 
 	assert.Equal(uint(20), memory.GetAddressSpaceSize())
-	assert.Equal(uint32(0), memory.Get(0))
-	assert.Equal(uint32(0), memory.Get(19))
-	assert.Panics(func() {
-		memory.Get(20)
-	})
-	assert.Panics(func() {
-		memory.Get(21)
-	})
-
+	suite.assertRead(&memory, 0, Word, 0)
+	suite.assertRead(&memory, 19, Byte, 0)
+
+	_, err := memory.Read(20, Byte)
+	assert.True(errors.Is(err, ErrAddressOutOfRange))
+	_, err = memory.Read(18, Word)
+	assert.True(errors.Is(err, ErrAddressOutOfRange))
+	_, err = memory.Read(0, 0)
+	assert.True(errors.Is(err, ErrInvalidWidth))
 }
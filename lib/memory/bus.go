@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+/*Bus maps regions of the 32-bit physical address space to the memories and devices that serve them.
+Each region is mapped at its own base address, and takes up as many addresses as its memory has, so that
+RAM, ROM and devices can be placed anywhere, with unmapped holes between them. The memory of a region sees
+addresses that start from 0 at its base.
+
+Addresses are decoded with a binary search of the regions, which are kept sorted, and the region that served
+the last access is tried first. Accessing an address that no region maps returns an error that wraps
+ErrAddressOutOfRange, which the Machine traps on as an access fault. An access that runs past the end of
+a region into the region mapped right after it is split into bytes, and every byte must be mapped*/
+type Bus struct {
+	regions []busRegion
+	last    int
+}
+
+/*busRegion is the memory mapped at the addresses from `base` up to, but not including, `end`*/
+type busRegion struct {
+	base   uint64
+	end    uint64
+	memory Interface
+}
+
+/*ErrRegionNotMappable is what Map wraps when a memory cannot be mapped*/
+var ErrRegionNotMappable = errors.New("Bus: region cannot be mapped")
+
+/*busAddressSpaceSize is the size of the physical address space that the bus decodes*/
+const busAddressSpaceSize uint64 = 1 << 32
+
+/*MakeBus is a constructor for a Bus that maps no regions yet*/
+func MakeBus() Bus {
+	return Bus{}
+}
+
+/*Map maps `memory` at the addresses from `base` up to base plus the size of its address space.
+It returns an error that wraps ErrRegionNotMappable if the memory is empty, if it does not fit
+below 4 GiB, or if it overlaps a region that is already mapped*/
+func (b *Bus) Map(base uint32, memory Interface) error {
+	region := busRegion{
+		base:   uint64(base),
+		end:    uint64(base) + uint64(memory.GetAddressSpaceSize()),
+		memory: memory,
+	}
+	if region.end == region.base || region.end > busAddressSpaceSize {
+		return fmt.Errorf("%w: %#x bytes at %#x do not fit in the address space", ErrRegionNotMappable,
+			memory.GetAddressSpaceSize(), base)
+	}
+
+	i := sort.Search(len(b.regions), func(i int) bool { return b.regions[i].end > region.base })
+	if i < len(b.regions) && b.regions[i].base < region.end {
+		return fmt.Errorf("%w: the region at %#x overlaps the region at %#x", ErrRegionNotMappable,
+			base, b.regions[i].base)
+	}
+
+	b.regions = append(b.regions, busRegion{})
+	copy(b.regions[i+1:], b.regions[i:])
+	b.regions[i] = region
+	b.last = i
+	return nil
+}
+
+/*find returns the index of the region that maps `address`, if there is one*/
+func (b *Bus) find(address uint32) (int, bool) {
+	if b.last < len(b.regions) && b.regions[b.last].contains(address) {
+		return b.last, true
+	}
+
+	i := sort.Search(len(b.regions), func(i int) bool { return b.regions[i].end > uint64(address) })
+	if i < len(b.regions) && b.regions[i].contains(address) {
+		b.last = i
+		return i, true
+	}
+	return 0, false
+}
+
+func (r *busRegion) contains(address uint32) bool {
+	return uint64(address) >= r.base && uint64(address) < r.end
+}
+
+/*covers returns whether every one of the `size` bytes from `address` is mapped*/
+func (b *Bus) covers(address uint32, size uint64) bool {
+	next := uint64(address)
+	for next < uint64(address)+size {
+		if next >= busAddressSpaceSize {
+			return false
+		}
+		i, ok := b.find(uint32(next))
+		if !ok {
+			return false
+		}
+		next = b.regions[i].end
+	}
+	return true
+}
+
+/*region returns the region that maps all of the `width` bytes from `address`. It returns false if
+they span more than one region, and an error if `width` is not supported or any of the bytes is not mapped*/
+func (b *Bus) region(address uint32, width AccessWidth) (busRegion, bool, error) {
+	if err := CheckAccess(address, width, b.GetAddressSpaceSize()); err != nil {
+		return busRegion{}, false, err
+	}
+	if !b.covers(address, uint64(width)) {
+		return busRegion{}, false, fmt.Errorf("%w: Bus: the %d bytes at %#x are not all mapped", ErrAddressOutOfRange,
+			width, address)
+	}
+
+	i, _ := b.find(address)
+	region := b.regions[i]
+	return region, uint64(address)+uint64(width) <= region.end, nil
+}
+
+/*Read returns the `width` bytes starting at `address`, from the regions that map them. It returns an error
+if any of them is not mapped*/
+func (b *Bus) Read(address uint32, width AccessWidth) (uint64, error) {
+	return b.read(address, width, false)
+}
+
+/*Fetch fetches the `width` bytes of instructions starting at `address` like Read reads them, except that
+the regions that map them are asked to Fetch them, so that regions that cannot be executed can refuse*/
+func (b *Bus) Fetch(address uint32, width AccessWidth) (uint64, error) {
+	return b.read(address, width, true)
+}
+
+/*read reads, or fetches if `fetch` is true, the `width` bytes starting at `address`*/
+func (b *Bus) read(address uint32, width AccessWidth, fetch bool) (uint64, error) {
+	region, whole, err := b.region(address, width)
+	if err != nil {
+		return 0, err
+	}
+	if whole && fetch {
+		return Fetch(region.memory, uint32(uint64(address)-region.base), width)
+	}
+	if whole {
+		return region.memory.Read(uint32(uint64(address)-region.base), width)
+	}
+
+	var val uint64
+	for i := uint32(0); i < uint32(width); i++ {
+		part, err := b.read(address+i, Byte, fetch)
+		if err != nil {
+			return 0, err
+		}
+		val |= part << (8 * i)
+	}
+	return val, nil
+}
+
+/*Write writes the lowest `width` bytes of `val`, starting at `address`, across as many regions as they span.
+It returns an error, and writes nothing, if any of the bytes is not mapped*/
+func (b *Bus) Write(address uint32, width AccessWidth, val uint64) error {
+	region, whole, err := b.region(address, width)
+	if err != nil {
+		return err
+	}
+	if whole {
+		return region.memory.Write(uint32(uint64(address)-region.base), width, val)
+	}
+
+	for i := uint32(0); i < uint32(width); i++ {
+		if err := b.Write(address+i, Byte, val>>(8*i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*GetAddressSpaceSize returns the size of the address space that the bus decodes, which is all of 4 GiB,
+whether it is mapped or not*/
+func (b *Bus) GetAddressSpaceSize() uint {
+	return uint(busAddressSpaceSize)
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BusSuite struct {
+	suite.Suite
+	bus  *Bus
+	low  *BasicMemory
+	high *BasicMemory
+}
+
+func TestBusSuite(t *testing.T) {
+	suite.Run(t, new(BusSuite))
+}
+
+func (suite *BusSuite) SetupTest() {
+	bus := MakeBus()
+	suite.bus = &bus
+
+	low := MakeBasicMemory(0xFF)
+	suite.low = &low
+	high := MakeBasicMemory(0xF)
+	suite.high = &high
+
+	assert.Nil(suite.T(), suite.bus.Map(0x80000000, suite.high))
+	assert.Nil(suite.T(), suite.bus.Map(0x1000, suite.low))
+}
+
+/*assertRead asserts that reading `width` bytes of `memory` at `address` gives `val`*/
+func (suite *BusSuite) assertRead(memory Reader, address uint32, width AccessWidth, val uint64) {
+	actual, err := memory.Read(address, width)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), val, actual)
+}
+
+/*assertUnmapped asserts that reading `width` bytes of the bus at `address` fails because they are not mapped*/
+func (suite *BusSuite) assertUnmapped(address uint32, width AccessWidth) {
+	_, err := suite.bus.Read(address, width)
+	assert.True(suite.T(), errors.Is(err, ErrAddressOutOfRange))
+}
+
+func (suite *BusSuite) TestAccessesGoToTheirRegions() {
+	assert := assert.New(suite.T())
+
+	assert.NoError(suite.bus.Write(0x1004, Word, 0x12345678))
+	suite.assertRead(suite.low, 4, Word, 0x12345678)
+	suite.assertRead(suite.bus, 0x1004, Word, 0x12345678)
+
+	assert.NoError(suite.bus.Write(0x80000000, HalfWord, 0xABCD))
+	suite.assertRead(suite.high, 0, HalfWord, 0xABCD)
+	suite.assertRead(suite.bus, 0x80000000, Word, 0xABCD)
+	suite.assertRead(suite.bus, 0x1004, Word, 0x12345678)
+}
+
+func (suite *BusSuite) TestUnmappedAccessesFail() {
+	suite.assertUnmapped(0xFFF, Byte)
+	suite.assertUnmapped(0x1100, Word)
+	suite.assertUnmapped(0x10FE, Word) // the read runs past the end of a region
+	assert.True(suite.T(), errors.Is(suite.bus.Write(0x90000000, Byte, 1), ErrAddressOutOfRange))
+
+	// a write that runs past the end of a region writes nothing
+	assert.True(suite.T(), errors.Is(suite.bus.Write(0x10FE, Word, 0xFFFFFFFF), ErrAddressOutOfRange))
+	suite.assertRead(suite.low, 0xFE, HalfWord, 0)
+}
+
+func (suite *BusSuite) TestAccessesSpanAdjacentRegions() {
+	assert := assert.New(suite.T())
+
+	assert.NoError(suite.low.Write(0xFE, HalfWord, 0xBEEF))
+	suite.assertRead(suite.bus, 0x10FE, HalfWord, 0xBEEF)
+
+	ram := MakeBasicMemory(0xF)
+	assert.Nil(suite.bus.Map(0x1100, &ram))
+	assert.NoError(suite.bus.Write(0x10FF, Word, 0x44332211)) // the regions are next to each other, so accesses can span them
+	suite.assertRead(&ram, 0, Word, 0x443322)
+	suite.assertRead(suite.bus, 0x10FE, Word, 0x332211EF)
+}
+
+func (suite *BusSuite) TestOverlappingRegionsCannotBeMapped() {
+	assert := assert.New(suite.T())
+	ram := MakeBasicMemory(0xF)
+
+	assert.True(errors.Is(suite.bus.Map(0x10F0, &ram), ErrRegionNotMappable))
+	assert.True(errors.Is(suite.bus.Map(0xFF8, &ram), ErrRegionNotMappable))
+	assert.True(errors.Is(suite.bus.Map(0xFFFFFFF8, &ram), ErrRegionNotMappable))
+	assert.Nil(suite.bus.Map(0xFF0, &ram))
+}
+
+func (suite *BusSuite) TestOtherMemoriesCanBeMapped() {
+	assert := assert.New(suite.T())
+	rom := MakeBasicMemory(0xF)
+	assert.NoError(rom.Write(0, HalfWord, 0x1234))
+	input := MakeUserInputReadMemory(&rom, &existingValueSource{})
+	ram := MakeBasicMemory(0xF)
+	composite := MakeCompositeMemory32(&ram, nil)
+
+	assert.Nil(suite.bus.Map(0x2000, &input))
+	assert.Nil(suite.bus.Map(0x3000, &composite))
+
+	suite.assertRead(suite.bus, 0x2000, Word, 0x1234)
+	assert.NoError(suite.bus.Write(0x3008, Word, 0x55667788))
+	suite.assertRead(&ram, 8, Word, 0x55667788)
+}
+
+func (suite *BusSuite) TestFetchesGoToRegionsThatCanRefuseThem() {
+	assert := assert.New(suite.T())
+	data := MakeBasicMemory(0xF)
+	composite := MakeCompositeMemory32WithAttributes(&data, NoExecute, nil)
+	assert.Nil(suite.bus.Map(0x1100, &composite))
+	assert.NoError(suite.bus.Write(0x1000, Word, 0x13))
+
+	val, err := suite.bus.Fetch(0x1000, Word)
+	assert.NoError(err)
+	assert.Equal(uint64(0x13), val)
+	_, err = suite.bus.Fetch(0x1100, Word)
+	assert.True(errors.Is(err, ErrAccessDenied))
+	_, err = suite.bus.Fetch(0x10FE, Word)
+	assert.True(errors.Is(err, ErrAccessDenied))
+	suite.assertRead(suite.bus, 0x10FE, Word, 0)
+}
+
+type existingValueSource struct {
+}
+
+func (s *existingValueSource) Get(address uint32, existingVal uint32) uint32 {
+	return existingVal
+}
@@ -1,67 +1,148 @@
 package memory
 
-import "errors"
-
 /*CompositeMemory32 presents methods for reading and writing from a 32-bit address
 memory space. It does not enforce the idea that each consecutive address addresses
 consecutive bytes. This struct supports reading up to a doubleword from memory, and no more.
+Each of its memories has Attributes, so that a composite can be made of RAM, ROM and regions that cannot be executed.
 */
 type CompositeMemory32 struct {
-	memory        basicMemory
+	memory        Interface
+	attributes    Attributes
 	furtherMemory *CompositeMemory32
 }
 
-/*NumberOfBitsWritten represents the number of bits written by a call to Set*/
-type NumberOfBitsWritten uint
+/*MakeCompositeMemory32 is a constructor for CompositeMemory32. Addresses from 0 up to the size of `memory`
+are served by `memory`, and the addresses after them by `furtherMemory`, whose own addresses start from 0 again.
+`furtherMemory` may be nil, in which case `memory` is the last memory of the composite.
+`memory` is RAM, which can be read, written and executed*/
+func MakeCompositeMemory32(memory Interface, furtherMemory *CompositeMemory32) CompositeMemory32 {
+	return MakeCompositeMemory32WithAttributes(memory, RAM, furtherMemory)
+}
+
+/*MakeCompositeMemory32WithAttributes constructs a CompositeMemory32 like MakeCompositeMemory32 does, except that
+`memory` has the `attributes`, which decide whether its addresses can be read, written and executed*/
+func MakeCompositeMemory32WithAttributes(memory Interface, attributes Attributes,
+	furtherMemory *CompositeMemory32) CompositeMemory32 {
+	composite := CompositeMemory32{
+		memory:        memory,
+		attributes:    attributes,
+		furtherMemory: furtherMemory,
+	}
 
-type basicMemory interface {
-	Get(address uint32) uint32
-	Set(address uint32, val uint32, bitsToWrite uint) NumberOfBitsWritten
-	GetAddressSpaceSize() uint
+	return composite
 }
 
 /*GetAddressSpaceSize returns the first address that this memory
 does not support; that is, it returns (MaxAddress + 1). Note that
-CompositeMemory32 will always start its address support from 0*/
+CompositeMemory32 will always start its address support from 0, and that
+a nil CompositeMemory32 supports no addresses at all*/
 func (m *CompositeMemory32) GetAddressSpaceSize() uint {
+	if m == nil {
+		return 0
+	}
 	return m.memory.GetAddressSpaceSize() + m.furtherMemory.GetAddressSpaceSize()
 }
 
-/*Get attempts to retrieve the doubleword value from `address` in memory.
-However, if `address` is outside the range of addressable memory,
-th call to Get will return an error, and it is up to the caller to handle what happens.
+/*GetAttributes returns the attributes of the memory that serves `address`, or no attributes at all
+if no memory does*/
+func (m *CompositeMemory32) GetAttributes(address uint32) Attributes {
+	for node, base := m, uint64(0); node != nil; node = node.furtherMemory {
+		size := uint64(node.memory.GetAddressSpaceSize())
+		if uint64(address) < base+size {
+			return node.attributes
+		}
+		base += size
+	}
+	return 0
+}
+
+/*checkPermission returns an error that wraps ErrAccessDenied if any of the memories that serve
+the `width` bytes at `address` does not permit an access that needs `permission`*/
+func (m *CompositeMemory32) checkPermission(address uint32, width AccessWidth, permission Attributes) error {
+	end := uint64(address) + uint64(width)
+	for node, base := m, uint64(0); node != nil && base < end; node = node.furtherMemory {
+		size := uint64(node.memory.GetAddressSpaceSize())
+		if base+size > uint64(address) && !node.attributes.permits(permission) {
+			return deniedError(address, width, permission)
+		}
+		base += size
+	}
+	return nil
+}
+
+/*Read attempts to read `width` bytes from `address` in memory. An access that starts in one memory
+and ends in the next is read a byte at a time. If any of the bytes are outside the range of addressable memory,
+or are in a memory that is not Readable, the call to Read will return an error, and it is up to the caller
+to handle what happens.
 */
-func (m *CompositeMemory32) Get(address uint32) (uint32, error) {
-	currentMemorySize := uint32(m.memory.GetAddressSpaceSize())
-	if !(address < uint32(m.GetAddressSpaceSize())) {
-		return 0, errors.New("Get: Requested address is not addressable in memory")
-	} else if address < currentMemorySize {
-		return m.memory.Get(address), nil
-	} else {
-		return m.furtherMemory.Get(address - currentMemorySize)
+func (m *CompositeMemory32) Read(address uint32, width AccessWidth) (uint64, error) {
+	return m.read(address, width, Readable)
+}
+
+/*Fetch reads instructions like Read reads data, except that the bytes must be in memories that are Executable,
+rather than Readable*/
+func (m *CompositeMemory32) Fetch(address uint32, width AccessWidth) (uint64, error) {
+	return m.read(address, width, Executable)
+}
+
+/*read reads `width` bytes from `address`, for an access that needs `permission`*/
+func (m *CompositeMemory32) read(address uint32, width AccessWidth, permission Attributes) (uint64, error) {
+	if err := CheckAccess(address, width, m.GetAddressSpaceSize()); err != nil {
+		return 0, err
+	}
+	if err := m.checkPermission(address, width, permission); err != nil {
+		return 0, err
+	}
+
+	currentMemorySize := uint64(m.memory.GetAddressSpaceSize())
+	switch {
+	case uint64(address)+uint64(width) <= currentMemorySize && permission == Executable:
+		return Fetch(m.memory, address, width)
+	case uint64(address)+uint64(width) <= currentMemorySize:
+		return m.memory.Read(address, width)
+	case uint64(address) >= currentMemorySize:
+		return m.furtherMemory.read(address-uint32(currentMemorySize), width, permission)
 	}
+
+	var val uint64
+	for i := uint32(0); i < uint32(width); i++ {
+		part, err := m.read(address+i, Byte, permission)
+		if err != nil {
+			return 0, err
+		}
+		val |= part << (8 * i)
+	}
+	return val, nil
 }
 
-/*Set attempts to write the lowest `bitsToWrite` bits of `val` to memory, starting at `address`,
-and starting with the LSB of `val`. Attempting to write more than 32 bits will still write only 32 bits. If writing
-these bits to memory causes attempted writes to invalid addresses, Set will return an error
-to the caller to handle. */
-func (m *CompositeMemory32) Set(address uint32, val uint32, bitsToWrite uint) error {
-	if bitsToWrite == 0 {
-		return nil
+/*Write attempts to write the lowest `width` bytes of `val` to memory, starting at `address`. An access that
+starts in one memory and ends in the next is written a byte at a time. If any of the bytes are outside the range
+of addressable memory, or are in a memory that is not Writable, Write will return an error to the caller to handle,
+and write nothing. The bytes in memories that ignore writes are not written, but they are not an error either*/
+func (m *CompositeMemory32) Write(address uint32, width AccessWidth, val uint64) error {
+	if err := CheckAccess(address, width, m.GetAddressSpaceSize()); err != nil {
+		return err
+	}
+	if err := m.checkPermission(address, width, Writable); err != nil {
+		return err
 	}
 
-	currentMemorySize := uint32(m.memory.GetAddressSpaceSize())
-	if !(address < uint32(m.GetAddressSpaceSize())) {
-		return errors.New("Set: Requested address is not addressable in memory")
-	} else if address < currentMemorySize {
-		/*If the write did not complete within current memory, we will attempt to complete it
-		in the `furtherMemory`, if possible*/
-		bitsWritten := m.memory.Set(address, val, bitsToWrite)
-		return m.furtherMemory.Set(0, val>>bitsWritten, bitsToWrite-uint(bitsWritten))
-	} else {
+	currentMemorySize := uint64(m.memory.GetAddressSpaceSize())
+	switch {
+	case uint64(address)+uint64(width) <= currentMemorySize && m.attributes&Writable == 0:
+		return nil // the memory ignores writes
+	case uint64(address)+uint64(width) <= currentMemorySize:
+		return m.memory.Write(address, width, val)
+	case uint64(address) >= currentMemorySize:
 		/*If the write is not supposed to be done within this node, we'll try to
 		do it in the next node*/
-		return m.furtherMemory.Set(address-currentMemorySize, val, bitsToWrite)
+		return m.furtherMemory.Write(address-uint32(currentMemorySize), width, val)
+	}
+
+	for i := uint32(0); i < uint32(width); i++ {
+		if err := m.Write(address+i, Byte, val>>(8*i)); err != nil {
+			return err
+		}
 	}
+	return nil
 }
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompositeMemorySuite struct {
+	suite.Suite
+	rom       *BasicMemory
+	ram       *BasicMemory
+	composite *CompositeMemory32
+}
+
+func TestCompositeMemorySuite(t *testing.T) {
+	suite.Run(t, new(CompositeMemorySuite))
+}
+
+/*SetupTest builds a composite of 16 bytes of ROM, followed by 16 bytes of RAM*/
+func (suite *CompositeMemorySuite) SetupTest() {
+	rom := MakeBasicMemory(0xF)
+	suite.rom = &rom
+	ram := MakeBasicMemory(0xF)
+	suite.ram = &ram
+
+	assert.NoError(suite.T(), rom.Write(0, Word, 0x12345678))
+	assert.NoError(suite.T(), rom.Write(12, Word, 0xAABBCCDD))
+	suite.setAttributes(ROM, RAM)
+}
+
+func (suite *CompositeMemorySuite) setAttributes(romAttributes Attributes, ramAttributes Attributes) {
+	further := MakeCompositeMemory32WithAttributes(suite.ram, ramAttributes, nil)
+	composite := MakeCompositeMemory32WithAttributes(suite.rom, romAttributes, &further)
+	suite.composite = &composite
+}
+
+func (suite *CompositeMemorySuite) assertDenied(err error) {
+	assert.True(suite.T(), errors.Is(err, ErrAccessDenied), "%v", err)
+}
+
+func (suite *CompositeMemorySuite) TestAccessesSpanMemories() {
+	assert := assert.New(suite.T())
+	suite.setAttributes(RAM, RAM)
+
+	assert.NoError(suite.composite.Write(14, Word, 0x11223344))
+	val, err := suite.composite.Read(14, Word)
+	assert.NoError(err)
+	assert.Equal(uint64(0x11223344), val)
+	val, err = suite.ram.Read(0, HalfWord)
+	assert.NoError(err)
+	assert.Equal(uint64(0x1122), val)
+
+	_, err = suite.composite.Read(30, Word)
+	assert.True(errors.Is(err, ErrAddressOutOfRange))
+}
+
+func (suite *CompositeMemorySuite) TestWritesToRomFault() {
+	assert := assert.New(suite.T())
+
+	suite.assertDenied(suite.composite.Write(0, Word, 0))
+	suite.assertDenied(suite.composite.Write(14, Word, 0)) // half of it is RAM, which is left alone
+	val, err := suite.composite.Read(12, DoubleWord)
+	assert.NoError(err)
+	assert.Equal(uint64(0xAABBCCDD), val)
+
+	assert.NoError(suite.composite.Write(16, Word, 0x55))
+	val, err = suite.ram.Read(0, Word)
+	assert.NoError(err)
+	assert.Equal(uint64(0x55), val)
+}
+
+func (suite *CompositeMemorySuite) TestWritesToRomCanBeIgnored() {
+	assert := assert.New(suite.T())
+	suite.setAttributes(ROM|IgnoresWrites, RAM)
+
+	assert.NoError(suite.composite.Write(0, Word, 0))
+	assert.NoError(suite.composite.Write(14, Word, 0x11223344))
+	val, err := suite.composite.Read(12, DoubleWord)
+	assert.NoError(err)
+	assert.Equal(uint64(0x1122AABBCCDD), val)
+}
+
+func (suite *CompositeMemorySuite) TestFetchesNeedExecutableMemory() {
+	assert := assert.New(suite.T())
+	suite.setAttributes(ExecuteOnly, NoExecute)
+
+	val, err := suite.composite.Fetch(0, Word)
+	assert.NoError(err)
+	assert.Equal(uint64(0x12345678), val)
+	_, err = suite.composite.Read(0, Word)
+	suite.assertDenied(err)
+
+	_, err = suite.composite.Fetch(16, Word)
+	suite.assertDenied(err)
+	_, err = suite.composite.Fetch(14, Word)
+	suite.assertDenied(err)
+	_, err = suite.composite.Read(16, Word)
+	assert.NoError(err)
+}
+
+func (suite *CompositeMemorySuite) TestGetAttributes() {
+	assert := assert.New(suite.T())
+	suite.setAttributes(ROM, IO)
+
+	assert.Equal(ROM, suite.composite.GetAttributes(15))
+	assert.Equal(IO, suite.composite.GetAttributes(16))
+	assert.Zero(suite.composite.GetAttributes(16) & (Cacheable | Idempotent))
+	assert.Equal(Attributes(0), suite.composite.GetAttributes(32))
+}
+
+func (suite *CompositeMemorySuite) TestMemoriesCanBeNested() {
+	assert := assert.New(suite.T())
+	inner := MakeCompositeMemory32WithAttributes(suite.rom, NoExecute, nil)
+	composite := MakeCompositeMemory32(&inner, nil)
+
+	_, err := Fetch(&composite, 0, Word)
+	suite.assertDenied(err)
+	val, err := Fetch(suite.rom, 0, Word) // not a Fetcher, so it is read
+	assert.NoError(err)
+	assert.Equal(uint64(0x12345678), val)
+}
@@ -1,14 +1,18 @@
 package messages
 
+import "fmt"
+
 /*InstructionMessages has methods for returning messages to give to command line user
 when requesting instructions from them*/
 type InstructionMessages struct{}
 
-func (m *InstructionMessages) getInputPromptMessage(address string, value string) string {
-	return "Instruction " + value + " at address: " + address + "\n" +
+/*GetInputPromptMessage returns the prompt that shows the user the instruction `value` at `address`*/
+func (m *InstructionMessages) GetInputPromptMessage(address uint32, value string) string {
+	return "Instruction " + value + " at address: " + fmt.Sprintf("%d", address) + "\n" +
 		"Press <Enter> to keep this instruction, or enter your own: "
 }
 
-func (m *InstructionMessages) getInputErrorMessage() string {
-	return "Invalid instruction. Please enter a valid instruction according to the RiscV assembly language"
+/*GetInputErrorMessage returns the message that tells the user that their instruction was invalid*/
+func (m *InstructionMessages) GetInputErrorMessage() string {
+	return "Invalid instruction. Please enter a valid instruction according to the RiscV assembly language\n"
 }
@@ -1,9 +1,12 @@
 package memory
 
+import "fmt"
+
 /*PanicMemory32 assumes that the underlying memory may panic, and as a side effect,
-it handles it by informing an error sink of the error*/
+it handles it by informing an error sink of the error. Errors that the underlying
+memory returns are returned to the caller as they are*/
 type PanicMemory32 struct {
-	memory    basicMemory
+	memory    Interface
 	errorSink errorSink
 }
 
@@ -12,7 +15,7 @@ type errorSink interface {
 }
 
 /*MakePanicMemory32 is a constructor for PanicMemory32*/
-func MakePanicMemory32(memory basicMemory, sink errorSink) PanicMemory32 {
+func MakePanicMemory32(memory Interface, sink errorSink) PanicMemory32 {
 	panicMemory := PanicMemory32{
 		memory:    memory,
 		errorSink: sink,
@@ -21,32 +24,47 @@ func MakePanicMemory32(memory basicMemory, sink errorSink) PanicMemory32 {
 	return panicMemory
 }
 
-/*Get attempts to get the value from memory at `address`
-If the attempt panics, the errorSink will be informed*/
-func (m *PanicMemory32) Get(address uint32) (v uint32) {
+/*Read attempts to read `width` bytes from memory at `address`.
+If the attempt panics, the errorSink will be informed, and an error is returned*/
+func (m *PanicMemory32) Read(address uint32, width AccessWidth) (v uint64, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			v = 0
+			v, err = 0, fmt.Errorf("PanicMemory32: reading %d bytes at %#x panicked: %v", width, address, r)
 			m.errorSink.Handle(r)
 		}
 	}()
 
-	val := m.memory.Get(address)
+	return m.memory.Read(address, width)
+}
 
-	return val
+/*Fetch attempts to fetch `width` bytes of instructions from memory at `address`, which the underlying memory
+refuses if it cannot be executed. If the attempt panics, the errorSink will be informed, and an error is returned*/
+func (m *PanicMemory32) Fetch(address uint32, width AccessWidth) (v uint64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = 0, fmt.Errorf("PanicMemory32: fetching %d bytes at %#x panicked: %v", width, address, r)
+			m.errorSink.Handle(r)
+		}
+	}()
+
+	return Fetch(m.memory, address, width)
 }
 
-/*Set attempts to write `bitsToWrite` bits of `val` to memory at `address`
-If the attempt panics, the errorSink will be informed*/
-func (m *PanicMemory32) Set(address uint32, val uint32, bitsToWrite uint) (n NumberOfBitsWritten) {
+/*Write attempts to write the lowest `width` bytes of `val` to memory at `address`.
+If the attempt panics, the errorSink will be informed, and an error is returned*/
+func (m *PanicMemory32) Write(address uint32, width AccessWidth, val uint64) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			n = 0
+			err = fmt.Errorf("PanicMemory32: writing %d bytes at %#x panicked: %v", width, address, r)
 			m.errorSink.Handle(r)
 		}
 	}()
 
-	numBits := m.memory.Set(address, val, bitsToWrite)
+	return m.memory.Write(address, width, val)
+}
 
-	return numBits
+/*GetAddressSpaceSize returns the size of the address space of the underlying memory.
+It is not expected to panic*/
+func (m *PanicMemory32) GetAddressSpaceSize() uint {
+	return m.memory.GetAddressSpaceSize()
 }
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -41,19 +42,23 @@ func (es *MockErrorSink) Handle(message interface{}) {
 type MockBasicMemory struct {
 }
 
-func (m *MockBasicMemory) Get(address uint32) uint32 {
+func (m *MockBasicMemory) Read(address uint32, width AccessWidth) (uint64, error) {
 	if address > 10 {
 		panic(1)
+	} else if address == 10 {
+		return 0, ErrAddressOutOfRange
 	} else {
-		return 5
+		return 5, nil
 	}
 }
 
-func (m *MockBasicMemory) Set(address uint32, val uint32, bitsToWrite uint) NumberOfBitsWritten {
+func (m *MockBasicMemory) Write(address uint32, width AccessWidth, val uint64) error {
 	if address > 10 {
 		panic(1)
+	} else if address == 10 {
+		return ErrAddressOutOfRange
 	} else {
-		return 20
+		return nil
 	}
 }
 
@@ -61,26 +66,50 @@ func (m *MockBasicMemory) GetAddressSpaceSize() uint {
 	return 11
 }
 
-func (suite *PanicMemorySuite) TestSet() {
+func (suite *PanicMemorySuite) TestWrite() {
 	assert := assert.New(suite.T())
-	result := suite.memory.Set(5, 5, 5)
-	assert.Equal(NumberOfBitsWritten(20), result)
+	assert.NoError(suite.memory.Write(5, Word, 5))
+	assert.Equal(ErrAddressOutOfRange, suite.memory.Write(10, Word, 5))
 	suite.sink.AssertNotCalled(suite.T(), "Handle", mock.Anything)
 
 	suite.sink.On("Handle", mock.Anything)
-	result = suite.memory.Set(11, 5, 5)
-	assert.Equal(NumberOfBitsWritten(0), result)
+	assert.Error(suite.memory.Write(11, Word, 5))
 	suite.sink.AssertCalled(suite.T(), "Handle", mock.Anything)
 }
 
-func (suite *PanicMemorySuite) TestGet() {
+func (suite *PanicMemorySuite) TestRead() {
 	assert := assert.New(suite.T())
-	result := suite.memory.Get(5)
-	assert.Equal(uint32(5), result)
+	result, err := suite.memory.Read(5, Word)
+	assert.NoError(err)
+	assert.Equal(uint64(5), result)
+	_, err = suite.memory.Read(10, Word)
+	assert.Equal(ErrAddressOutOfRange, err)
 	suite.sink.AssertNotCalled(suite.T(), "Handle", mock.Anything)
 
 	suite.sink.On("Handle", mock.Anything)
-	result = suite.memory.Get(11)
-	assert.Equal(uint32(0), result)
+	result, err = suite.memory.Read(11, Word)
+	assert.Error(err)
+	assert.Equal(uint64(0), result)
+	suite.sink.AssertCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *PanicMemorySuite) TestFetch() {
+	assert := assert.New(suite.T())
+	rom := MakeBasicMemory(0xF)
+	assert.NoError(rom.Write(0, Word, 7))
+	composite := MakeCompositeMemory32WithAttributes(&rom, ReadOnly, nil)
+	suite.memory.memory = &composite
+
+	_, err := suite.memory.Fetch(0, Word)
+	assert.True(errors.Is(err, ErrAccessDenied))
+	result, err := suite.memory.Read(0, Word)
+	assert.NoError(err)
+	assert.Equal(uint64(7), result)
+	suite.sink.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+
+	suite.memory.memory = &MockBasicMemory{}
+	suite.sink.On("Handle", mock.Anything)
+	_, err = suite.memory.Fetch(11, Word)
+	assert.Error(err)
 	suite.sink.AssertCalled(suite.T(), "Handle", mock.Anything)
 }
@@ -1,64 +1,84 @@
 package sources
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 )
 
 /*CommandLineSource is a source that takes the value from the user at
 the command line*/
 type CommandLineSource struct {
+	input             *bufio.Reader
+	output            io.Writer
 	inputTransformer  inputTransformer
 	outputTransformer outputTransformer
 	messageSource     messageSource
 }
 
-/*MakeCommandLineSource is a constructor for CommandLineSource*/
-func MakeCommandLineSource(transformer inputTransformer) CommandLineSource {
+/*ErrInputEnded is what CommandLineSource panics with when the user's input ends before they
+have entered a value, as there is then no value that it can return*/
+var ErrInputEnded = errors.New("CommandLineSource: input ended")
+
+/*MakeCommandLineSource is a constructor for CommandLineSource. The user's values are read line by line
+from `input`, and the prompts are written to `output`*/
+func MakeCommandLineSource(input io.Reader, output io.Writer, inTransformer inputTransformer,
+	outTransformer outputTransformer, messages messageSource) CommandLineSource {
 	source := CommandLineSource{
-		inputTransformer: transformer,
+		input:             bufio.NewReader(input),
+		output:            output,
+		inputTransformer:  inTransformer,
+		outputTransformer: outTransformer,
+		messageSource:     messages,
 	}
 
 	return source
 }
 
 type inputTransformer interface {
-	transform(input string) (uint32, error)
+	Transform(input string) (uint32, error)
 }
 
 type outputTransformer interface {
-	transform(output uint32) string
+	Transform(output uint32) string
 }
 
 type messageSource interface {
-	getInputErrorMessage() string
-	getInputPromptMessage(address uint16, value string) string
+	GetInputErrorMessage() string
+	GetInputPromptMessage(address uint32, value string) string
 }
 
-/*Get prints a prompt to stdout asking whether the user wants to accept
-the existing value for this address, or to enter their own. If they want to enter their own,
+/*Get prints a prompt asking whether the user wants to accept
+the existing value for this address, or to enter their own. If they want to accept the existing value,
 they can simply press <Enter>. If they want to enter their own, they can write their own,
 and press <Enter> to submit it. If the value they entered is valid, then it is returned, otherwise
 the user will be prompted again until they enter a valid value.
+
+If the input ends before a value is chosen, Get panics with ErrInputEnded.
 */
-func (s *CommandLineSource) Get(address uint16, existingVal uint32) uint32 {
-	var input string
-	var result uint32
-	done := false
+func (s *CommandLineSource) Get(address uint32, existingVal uint32) uint32 {
+	inputMessage := s.messageSource.GetInputPromptMessage(address, s.outputTransformer.Transform(existingVal))
+	errorMessage := s.messageSource.GetInputErrorMessage()
+
+	for {
+		fmt.Fprint(s.output, inputMessage)
+		line, readErr := s.input.ReadString('\n')
+		if readErr != nil && line == "" {
+			panic(ErrInputEnded)
+		}
 
-	inputMessage := s.messageSource.getInputPromptMessage(address, s.outputTransformer.transform(existingVal))
-	errorMessage := s.messageSource.getInputErrorMessage()
+		input := strings.TrimSpace(line)
+		if input == "" {
+			return existingVal
+		}
 
-	for !done {
-		fmt.Printf(inputMessage)
-		_, readErr := fmt.Scanln(&input)
-		transformed, tErr := s.inputTransformer.transform(input)
-		if (readErr != nil) || (tErr != nil) {
-			fmt.Printf(errorMessage)
+		transformed, tErr := s.inputTransformer.Transform(input)
+		if tErr != nil {
+			fmt.Fprint(s.output, errorMessage)
 		} else {
-			result = transformed
-			done = true
+			return transformed
 		}
 	}
-
-	return result
 }
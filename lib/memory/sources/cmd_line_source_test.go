@@ -0,0 +1,92 @@
+package sources
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CommandLineSourceSuite struct {
+	suite.Suite
+	output *bytes.Buffer
+}
+
+func TestCommandLineSourceSuite(t *testing.T) {
+	suite.Run(t, new(CommandLineSourceSuite))
+}
+
+func (suite *CommandLineSourceSuite) SetupTest() {
+	suite.output = &bytes.Buffer{}
+}
+
+func (suite *CommandLineSourceSuite) makeSource(input string) CommandLineSource {
+	return MakeCommandLineSource(strings.NewReader(input), suite.output,
+		&decimalInputTransformer{}, &decimalOutputTransformer{}, &messageSourceStub{})
+}
+
+func (suite *CommandLineSourceSuite) TestGet_KeepsExistingValue() {
+	assert := assert.New(suite.T())
+	source := suite.makeSource("\n")
+
+	assert.Equal(uint32(7), source.Get(4, 7))
+	assert.Equal("4=7? ", suite.output.String())
+}
+
+func (suite *CommandLineSourceSuite) TestGet_ReplacesValue() {
+	assert := assert.New(suite.T())
+	source := suite.makeSource("  12  \n")
+
+	assert.Equal(uint32(12), source.Get(4, 7))
+}
+
+func (suite *CommandLineSourceSuite) TestGet_LastLineWithoutNewline() {
+	assert := assert.New(suite.T())
+	source := suite.makeSource("12")
+
+	assert.Equal(uint32(12), source.Get(4, 7))
+}
+
+func (suite *CommandLineSourceSuite) TestGet_RepromptsOnInvalidInput() {
+	assert := assert.New(suite.T())
+	source := suite.makeSource("abc\n13\n")
+
+	assert.Equal(uint32(13), source.Get(4, 7))
+	assert.Equal("4=7? invalid\n4=7? ", suite.output.String())
+}
+
+func (suite *CommandLineSourceSuite) TestGet_PanicsWhenInputEnds() {
+	assert := assert.New(suite.T())
+	source := suite.makeSource("abc\n")
+
+	assert.PanicsWithValue(ErrInputEnded, func() { source.Get(4, 7) })
+}
+
+type decimalInputTransformer struct {
+}
+
+func (t *decimalInputTransformer) Transform(input string) (uint32, error) {
+	value, err := strconv.ParseUint(input, 10, 32)
+	return uint32(value), err
+}
+
+type decimalOutputTransformer struct {
+}
+
+func (t *decimalOutputTransformer) Transform(output uint32) string {
+	return strconv.FormatUint(uint64(output), 10)
+}
+
+type messageSourceStub struct {
+}
+
+func (m *messageSourceStub) GetInputErrorMessage() string {
+	return "invalid\n"
+}
+
+func (m *messageSourceStub) GetInputPromptMessage(address uint32, value string) string {
+	return strconv.Itoa(int(address)) + "=" + value + "? "
+}
@@ -7,6 +7,6 @@ type MemorySource struct {
 }
 
 /*Get simply returns the existing memory value as the value in memory*/
-func (s *MemorySource) Get(address uint16, existingVal uint32) uint32 {
+func (s *MemorySource) Get(address uint32, existingVal uint32) uint32 {
 	return existingVal
 }
@@ -0,0 +1,94 @@
+package memory
+
+import "sort"
+
+/*SparsePageSize is the size, in bytes, of the pages that SparseMemory32 allocates*/
+const SparsePageSize = 4096
+
+/*sparsePage is one page of the bytes of a SparseMemory32*/
+type sparsePage [SparsePageSize]uint8
+
+/*SparseMemory32 is a byte-addressable memory with 32-bit addresses, which can cover the whole 4 GiB address space.
+It only allocates a 4 KiB page once one of its bytes is written, so the memory it uses grows with the pages that
+are written to, rather than with its size. Bytes that have never been written are 0. Like BasicMemory,
+reads and writes are 1, 2, 4 or 8 bytes wide
+*/
+type SparseMemory32 struct {
+	pages      map[uint32]*sparsePage
+	maxAddress uint32
+}
+
+/*MakeSparseMemory32 constructs a SparseMemory32 whose addresses go from 0 up to and including `maxAddress`,
+with no pages allocated yet*/
+func MakeSparseMemory32(maxAddress uint32) SparseMemory32 {
+	return SparseMemory32{
+		pages:      map[uint32]*sparsePage{},
+		maxAddress: maxAddress,
+	}
+}
+
+/*Read returns the `width` bytes starting at `address` in memory, without allocating their pages.
+It returns an error if any of them are outside of addressable memory*/
+func (m *SparseMemory32) Read(address uint32, width AccessWidth) (uint64, error) {
+	if err := CheckAccess(address, width, m.GetAddressSpaceSize()); err != nil {
+		return 0, err
+	}
+
+	return readBytes(address, width, m.getByte), nil
+}
+
+/*Write writes the lowest `width` bytes of `val` to memory at `address`, allocating the pages that
+they are in. It returns an error, and writes nothing, if any of them are outside of addressable memory*/
+func (m *SparseMemory32) Write(address uint32, width AccessWidth, val uint64) error {
+	if err := CheckAccess(address, width, m.GetAddressSpaceSize()); err != nil {
+		return err
+	}
+
+	writeBytes(address, width, val, func(address uint32, b uint8) {
+		m.page(address)[address%SparsePageSize] = b
+	})
+	return nil
+}
+
+/*GetAddressSpaceSize returns the size of the address space. That is,
+it returns <maximum valid address + 1>
+*/
+func (m *SparseMemory32) GetAddressSpaceSize() uint {
+	return uint(m.maxAddress) + 1
+}
+
+/*GetPageCount returns the number of pages that the memory has allocated*/
+func (m *SparseMemory32) GetPageCount() uint {
+	return uint(len(m.pages))
+}
+
+/*GetPageAddresses returns the addresses of the pages that the memory has allocated, in order. Every byte
+outside of them is 0*/
+func (m *SparseMemory32) GetPageAddresses() []uint32 {
+	addresses := make([]uint32, 0, len(m.pages))
+	for number := range m.pages {
+		addresses = append(addresses, number*SparsePageSize)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i] < addresses[j] })
+	return addresses
+}
+
+/*getByte returns the byte at `address`, without allocating its page*/
+func (m *SparseMemory32) getByte(address uint32) uint8 {
+	page, ok := m.pages[address/SparsePageSize]
+	if !ok {
+		return 0
+	}
+	return page[address%SparsePageSize]
+}
+
+/*page returns the page that holds `address`, allocating it if it has not been yet*/
+func (m *SparseMemory32) page(address uint32) *sparsePage {
+	number := address / SparsePageSize
+	page, ok := m.pages[number]
+	if !ok {
+		page = &sparsePage{}
+		m.pages[number] = page
+	}
+	return page
+}
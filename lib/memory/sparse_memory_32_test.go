@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SparseMemorySuite struct {
+	suite.Suite
+	memory *SparseMemory32
+}
+
+func TestSparseMemorySuite(t *testing.T) {
+	suite.Run(t, new(SparseMemorySuite))
+}
+
+func (suite *SparseMemorySuite) SetupTest() {
+	memory := MakeSparseMemory32(math.MaxUint32)
+	suite.memory = &memory
+}
+
+func (suite *SparseMemorySuite) assertRead(address uint32, width AccessWidth, val uint64) {
+	actual, err := suite.memory.Read(address, width)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), val, actual)
+}
+
+func (suite *SparseMemorySuite) TestCoversTheWholeAddressSpace() {
+	assert := assert.New(suite.T())
+
+	assert.Equal(uint(1)<<32, suite.memory.GetAddressSpaceSize())
+	assert.NoError(suite.memory.Write(0x80000000, Word, 0xDEADBEEF))
+	suite.assertRead(0x80000000, Word, 0xDEADBEEF)
+	suite.assertRead(0x80000001, HalfWord, 0xADBE)
+	assert.NoError(suite.memory.Write(math.MaxUint32-1, HalfWord, 0xFFFF))
+	suite.assertRead(math.MaxUint32-1, HalfWord, 0xFFFF)
+	assert.True(errors.Is(suite.memory.Write(math.MaxUint32-1, Word, 0), ErrAddressOutOfRange))
+}
+
+func (suite *SparseMemorySuite) TestOnlyAllocatesWrittenPages() {
+	assert := assert.New(suite.T())
+
+	suite.assertRead(0x12345678, DoubleWord, 0)
+	assert.Equal(uint(0), suite.memory.GetPageCount())
+
+	assert.NoError(suite.memory.Write(0x0FFE, Word, 0x44332211)) // the write spans two pages
+	assert.Equal(uint(2), suite.memory.GetPageCount())
+	suite.assertRead(0x1000, HalfWord, 0x4433)
+	assert.NoError(suite.memory.Write(0x1004, Byte, 1))
+	assert.Equal(uint(2), suite.memory.GetPageCount())
+	assert.Equal([]uint32{0, 0x1000}, suite.memory.GetPageAddresses())
+}
+
+func (suite *SparseMemorySuite) TestSmallerAddressSpace() {
+	assert := assert.New(suite.T())
+	memory := MakeSparseMemory32(0xFF)
+
+	assert.Equal(uint(0x100), memory.GetAddressSpaceSize())
+	assert.True(errors.Is(memory.Write(0xFF, HalfWord, 0xFFFF), ErrAddressOutOfRange))
+	assert.Equal(uint(0), memory.GetPageCount())
+	assert.NoError(memory.Write(0xFF, Byte, 0xFF))
+	_, err := memory.Read(0x100, Byte)
+	assert.True(errors.Is(err, ErrAddressOutOfRange))
+}
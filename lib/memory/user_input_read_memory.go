@@ -6,27 +6,49 @@ and returned to the caller, and the value is then recorded in memory. If necessa
 the memory will block while waiting for the external value to be sent.
 */
 type UserInputReadMemory struct {
-	memory memory
+	memory Interface
 	source source
 }
 
-type memory interface {
-	Set(address uint16, value uint32)
-	Get(address uint16) uint32
+/*MakeUserInputReadMemory is a constructor for UserInputReadMemory*/
+func MakeUserInputReadMemory(memory Interface, source source) UserInputReadMemory {
+	inputMemory := UserInputReadMemory{
+		memory: memory,
+		source: source,
+	}
+
+	return inputMemory
 }
 
 type source interface {
-	Get(address uint16, existingVal uint32) uint32
+	Get(address uint32, existingVal uint32) uint32
 }
 
-/*Get will attempt to get a value from an external `source`, while passing it the existing value at
-the address, in case the external wants to choose the existing value. If necessary, Get will
-block as it waits for the value. Once the value is obtained,
-it will write the value to memory at the 16-bit `address`, and return the value
-to the caller, so that we can pretend the value was always there in memory.
+/*Read will attempt to get a value from an external `source`, while passing it the existing value at
+the address, in case the external wants to choose the existing value. If necessary, Read will
+block as it waits for the value. Once the value is obtained, it will write the lowest `width` bytes
+of the value to memory at `address`, and return them to the caller, so that we can pretend the value
+was always there in memory. The source is not asked for a value if the access is not supported by memory.
 */
-func (m *UserInputReadMemory) Get(address uint16) uint32 {
-	val := m.source.Get(address, m.memory.Get(address))
-	m.memory.Set(address, val)
-	return val
+func (m *UserInputReadMemory) Read(address uint32, width AccessWidth) (uint64, error) {
+	existingVal, err := m.memory.Read(address, width)
+	if err != nil {
+		return 0, err
+	}
+
+	val := uint64(m.source.Get(address, uint32(existingVal))) & width.Mask()
+	if err := m.memory.Write(address, width, val); err != nil {
+		return 0, err
+	}
+	return val, nil
+}
+
+/*Write writes the lowest `width` bytes of `val` to the underlying memory at `address`*/
+func (m *UserInputReadMemory) Write(address uint32, width AccessWidth, val uint64) error {
+	return m.memory.Write(address, width, val)
+}
+
+/*GetAddressSpaceSize returns the size of the address space of the underlying memory*/
+func (m *UserInputReadMemory) GetAddressSpaceSize() uint {
+	return m.memory.GetAddressSpaceSize()
 }
@@ -0,0 +1,32 @@
+package programLoaders
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+/*LoadBinary copies the flat binary image read from `image` into memory, byte for byte, starting at `base`.
+It returns the number of bytes that were loaded*/
+func (l *ImageLoader) LoadBinary(image io.Reader, base uint32) (uint32, error) {
+	data, err := ioutil.ReadAll(image)
+	if err != nil {
+		return 0, fmt.Errorf("LoadBinary: image could not be read: %v", err)
+	}
+
+	if err := l.setBytes(base, data); err != nil {
+		return 0, err
+	}
+	return uint32(len(data)), nil
+}
+
+/*WriteBinary writes the `length` bytes of memory starting at `start` to `out` as a flat binary image*/
+func (w *ImageWriter) WriteBinary(out io.Writer, start uint32, length uint32) error {
+	data, err := w.getBytes(start, length)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(data)
+	return err
+}
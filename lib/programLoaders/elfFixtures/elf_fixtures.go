@@ -0,0 +1,94 @@
+package elfFixtures
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+)
+
+/*Code is the code of the executables that Build builds: addi a0, zero, 5; ebreak*/
+var Code = []byte{0x13, 0x05, 0x50, 0x00, 0x73, 0x00, 0x10, 0x00}
+
+/*Options changes the ELF file that Build builds. Fields that are left as 0 keep their defaults*/
+type Options struct {
+	Machine  elf.Machine
+	FileType elf.Type
+	Address  uint32
+	Entry    uint32
+}
+
+/*Build builds a RISC-V executable with one PT_LOAD segment of 8 bytes of Code followed by 8 bytes of
+.bss, and a symbol table with the symbols _start, for the code, and buffer, for the .bss.
+The segment is at 0x40 unless overridden, and the program starts at the start of the segment*/
+func Build(options Options) []byte {
+	if options.Machine == 0 {
+		options.Machine = elf.EM_RISCV
+	}
+	if options.FileType == 0 {
+		options.FileType = elf.ET_EXEC
+	}
+	if options.Address == 0 {
+		options.Address = 0x40
+	}
+	if options.Entry == 0 {
+		options.Entry = options.Address
+	}
+
+	code := Code
+	strtab := []byte("\x00_start\x00buffer\x00")
+	shstrtab := []byte("\x00.symtab\x00.strtab\x00.shstrtab\x00")
+	symbols := []elf.Sym32{
+		{},
+		{Name: 1, Value: options.Address, Size: 8, Info: elf.ST_INFO(elf.STB_GLOBAL, elf.STT_FUNC), Shndx: uint16(elf.SHN_ABS)},
+		{Name: 8, Value: options.Address + 8, Size: 8, Info: elf.ST_INFO(elf.STB_GLOBAL, elf.STT_OBJECT), Shndx: uint16(elf.SHN_ABS)},
+	}
+
+	const headerSize, progSize, sectionSize, symbolSize = 52, 32, 40, 16
+	codeOffset := uint32(headerSize + progSize)
+	symtabOffset := codeOffset + uint32(len(code))
+	strtabOffset := symtabOffset + uint32(len(symbols)*symbolSize)
+	shstrtabOffset := strtabOffset + uint32(len(strtab))
+	sectionsOffset := shstrtabOffset + uint32(len(shstrtab))
+
+	header := elf.Header32{
+		Type:      uint16(options.FileType),
+		Machine:   uint16(options.Machine),
+		Version:   uint32(elf.EV_CURRENT),
+		Entry:     options.Entry,
+		Phoff:     headerSize,
+		Shoff:     sectionsOffset,
+		Ehsize:    headerSize,
+		Phentsize: progSize,
+		Phnum:     1,
+		Shentsize: sectionSize,
+		Shnum:     4,
+		Shstrndx:  3,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS32)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	prog := elf.Prog32{
+		Type:   uint32(elf.PT_LOAD),
+		Off:    codeOffset,
+		Vaddr:  options.Address,
+		Paddr:  options.Address,
+		Filesz: uint32(len(code)),
+		Memsz:  uint32(len(code)) + 8,
+		Flags:  uint32(elf.PF_R | elf.PF_W | elf.PF_X),
+	}
+
+	sections := []elf.Section32{
+		{},
+		{Name: 1, Type: uint32(elf.SHT_SYMTAB), Off: symtabOffset, Size: uint32(len(symbols) * symbolSize), Link: 2, Info: 1, Entsize: symbolSize},
+		{Name: 9, Type: uint32(elf.SHT_STRTAB), Off: strtabOffset, Size: uint32(len(strtab))},
+		{Name: 17, Type: uint32(elf.SHT_STRTAB), Off: shstrtabOffset, Size: uint32(len(shstrtab))},
+	}
+
+	buffer := &bytes.Buffer{}
+	for _, part := range []interface{}{header, prog, code, symbols, strtab, shstrtab, sections} {
+		binary.Write(buffer, binary.LittleEndian, part)
+	}
+	return buffer.Bytes()
+}
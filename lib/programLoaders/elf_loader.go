@@ -0,0 +1,217 @@
+package programLoaders
+
+import (
+	"debug/elf"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+
+	Memory "github.com/chenhowa/computer/lib/memory"
+)
+
+/*ElfLoader loads 32-bit RISC-V ELF executables, such as those built by a standard GCC or LLVM
+toolchain, into memory. Each PT_LOAD segment is copied to its physical address, and the part of
+the segment that is not in the file (such as .bss) is filled with zeros*/
+type ElfLoader struct {
+	memory loaderMemory
+	pc     programCounter
+}
+
+type programCounter interface {
+	SetProgramCounter(address uint32)
+}
+
+/*MakeElfLoader is a constructor for ElfLoader. Programs are loaded into `memory`, and `pc`
+is pointed at the entry point of each program that is loaded*/
+func MakeElfLoader(memory loaderMemory, pc programCounter) ElfLoader {
+	loader := ElfLoader{
+		memory: memory,
+		pc:     pc,
+	}
+
+	return loader
+}
+
+/*ElfSymbol is an entry of the symbol table of an ELF file*/
+type ElfSymbol struct {
+	Name    string
+	Address uint32
+	Size    uint32
+}
+
+/*ElfProgram describes an ELF executable that has been loaded*/
+type ElfProgram struct {
+	entry   uint32
+	symbols []ElfSymbol
+}
+
+/*GetEntry returns the address of the first instruction of the program*/
+func (p *ElfProgram) GetEntry() uint32 {
+	return p.entry
+}
+
+/*GetSymbols returns the named symbols of the program, in the order of its symbol table*/
+func (p *ElfProgram) GetSymbols() []ElfSymbol {
+	return p.symbols
+}
+
+/*GetSymbolAddress returns the address of the first symbol named `name`, if the program has one*/
+func (p *ElfProgram) GetSymbolAddress(name string) (uint32, bool) {
+	for _, symbol := range p.symbols {
+		if symbol.Name == name {
+			return symbol.Address, true
+		}
+	}
+	return 0, false
+}
+
+/*Load validates the ELF file read from `file`, loads its segments into memory, and sets the
+program counter to its entry point. If the file is not a little-endian, 32-bit RISC-V executable,
+or any of its segments does not fit in memory, Load returns an error without writing to memory*/
+func (l *ElfLoader) Load(file io.ReaderAt) (ElfProgram, error) {
+	f, err := elf.NewFile(file)
+	if err != nil {
+		return ElfProgram{}, fmt.Errorf("Load: not a valid ELF file: %v", err)
+	}
+	defer f.Close()
+
+	if err := validate(f); err != nil {
+		return ElfProgram{}, err
+	}
+
+	segments, err := readSegments(f)
+	if err != nil {
+		return ElfProgram{}, err
+	}
+
+	symbols, err := readSymbols(f)
+	if err != nil {
+		return ElfProgram{}, err
+	}
+
+	size := uint64(l.memory.GetAddressSpaceSize())
+	for _, s := range segments {
+		if end := s.end(); end > size {
+			return ElfProgram{}, fmt.Errorf("Load: segment at 0x%08x ends at 0x%x, but memory ends at 0x%x", s.address, end, size)
+		}
+	}
+
+	for _, s := range segments {
+		if err := l.loadSegment(s); err != nil {
+			return ElfProgram{}, err
+		}
+	}
+
+	program := ElfProgram{
+		entry:   uint32(f.Entry),
+		symbols: symbols,
+	}
+	l.pc.SetProgramCounter(program.entry)
+	return program, nil
+}
+
+func validate(f *elf.File) error {
+	if f.Class != elf.ELFCLASS32 {
+		return fmt.Errorf("Load: ELF class is %v, but only ELFCLASS32 is supported", f.Class)
+	}
+	if f.Data != elf.ELFDATA2LSB {
+		return fmt.Errorf("Load: ELF data encoding is %v, but only little-endian is supported", f.Data)
+	}
+	if f.Machine != elf.EM_RISCV {
+		return fmt.Errorf("Load: ELF machine is %v, but only EM_RISCV is supported", f.Machine)
+	}
+	if f.Type != elf.ET_EXEC {
+		return fmt.Errorf("Load: ELF type is %v, but only executables (ET_EXEC) are supported", f.Type)
+	}
+	return nil
+}
+
+/*segment is the contents of a PT_LOAD segment, which are followed in memory by `zeros` bytes of 0*/
+type segment struct {
+	address uint32
+	data    []byte
+	zeros   uint32
+}
+
+/*end returns the first address after the segment*/
+func (s *segment) end() uint64 {
+	return uint64(s.address) + uint64(len(s.data)) + uint64(s.zeros)
+}
+
+/*readSegments reads every PT_LOAD segment of the file before any of them is loaded,
+so that a bad file leaves memory untouched*/
+func readSegments(f *elf.File) ([]segment, error) {
+	segments := []segment{}
+	for i, prog := range f.Progs {
+		if prog.Type != elf.PT_LOAD {
+			continue
+		}
+
+		if prog.Filesz > prog.Memsz {
+			return nil, fmt.Errorf("Load: segment %d has more bytes in the file (%d) than in memory (%d)", i, prog.Filesz, prog.Memsz)
+		}
+		if prog.Paddr+prog.Memsz > math.MaxUint32+1 {
+			return nil, fmt.Errorf("Load: segment %d at 0x%08x does not fit in a 32-bit address space", i, prog.Paddr)
+		}
+
+		data, err := ioutil.ReadAll(prog.Open())
+		if err != nil {
+			return nil, fmt.Errorf("Load: segment %d could not be read: %v", i, err)
+		}
+		if uint64(len(data)) != prog.Filesz {
+			return nil, fmt.Errorf("Load: segment %d is cut short: read %d of %d bytes", i, len(data), prog.Filesz)
+		}
+
+		segments = append(segments, segment{
+			address: uint32(prog.Paddr),
+			data:    data,
+			zeros:   uint32(prog.Memsz - prog.Filesz),
+		})
+	}
+
+	return segments, nil
+}
+
+/*readSymbols returns the named symbols of the file. A file without a symbol table has no symbols*/
+func readSymbols(f *elf.File) ([]ElfSymbol, error) {
+	elfSymbols, err := f.Symbols()
+	if err == elf.ErrNoSymbols {
+		return []ElfSymbol{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Load: symbol table could not be read: %v", err)
+	}
+
+	symbols := []ElfSymbol{}
+	for _, s := range elfSymbols {
+		if s.Name == "" {
+			continue
+		}
+		symbols = append(symbols, ElfSymbol{
+			Name:    s.Name,
+			Address: uint32(s.Value),
+			Size:    uint32(s.Size),
+		})
+	}
+
+	return symbols, nil
+}
+
+func (l *ElfLoader) loadSegment(s segment) error {
+	address := s.address
+	for _, b := range s.data {
+		if err := l.memory.Write(address, Memory.Byte, uint64(b)); err != nil {
+			return fmt.Errorf("Load: segment at 0x%08x does not fit in memory: %v", s.address, err)
+		}
+		address++
+	}
+
+	for i := uint32(0); i < s.zeros; i++ {
+		if err := l.memory.Write(address, Memory.Byte, 0); err != nil {
+			return fmt.Errorf("Load: segment at 0x%08x does not fit in memory: %v", s.address, err)
+		}
+		address++
+	}
+
+	return nil
+}
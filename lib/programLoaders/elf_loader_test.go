@@ -0,0 +1,127 @@
+package programLoaders
+
+import (
+	"bytes"
+	"debug/elf"
+	"testing"
+
+	Memory "github.com/chenhowa/computer/lib/memory"
+	ElfFixtures "github.com/chenhowa/computer/lib/programLoaders/elfFixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ElfLoaderSuite struct {
+	suite.Suite
+	bytes  *byteMemory
+	memory *Memory.CompositeMemory32
+	pc     *programCounterStub
+	loader *ElfLoader
+}
+
+func TestElfLoaderSuite(t *testing.T) {
+	suite.Run(t, new(ElfLoaderSuite))
+}
+
+func (suite *ElfLoaderSuite) SetupTest() {
+	suite.bytes = &byteMemory{data: make([]uint8, 256)}
+	memory := Memory.MakeCompositeMemory32(suite.bytes, nil)
+	suite.memory = &memory
+	suite.pc = &programCounterStub{}
+	loader := MakeElfLoader(suite.memory, suite.pc)
+	suite.loader = &loader
+}
+
+func (suite *ElfLoaderSuite) TestLoad() {
+	assert := assert.New(suite.T())
+	for i := range suite.bytes.data {
+		suite.bytes.data[i] = 0xAA
+	}
+
+	program, err := suite.loader.Load(bytes.NewReader(ElfFixtures.Build(ElfFixtures.Options{Entry: 0x44})))
+
+	assert.Nil(err)
+	assert.Equal(uint32(0x44), program.GetEntry())
+	assert.Equal(uint32(0x44), suite.pc.address)
+	assert.Equal([]uint8{0x13, 0x05, 0x50, 0x00, 0x73, 0x00, 0x10, 0x00}, suite.bytes.data[0x40:0x48])
+	assert.Equal(make([]uint8, 8), suite.bytes.data[0x48:0x50], ".bss must be zero-filled")
+	assert.Equal(uint8(0xAA), suite.bytes.data[0x3F])
+	assert.Equal(uint8(0xAA), suite.bytes.data[0x50])
+
+	assert.Equal([]ElfSymbol{{Name: "_start", Address: 0x40, Size: 8}, {Name: "buffer", Address: 0x48, Size: 8}}, program.GetSymbols())
+	address, ok := program.GetSymbolAddress("buffer")
+	assert.True(ok)
+	assert.Equal(uint32(0x48), address)
+	_, ok = program.GetSymbolAddress("main")
+	assert.False(ok)
+}
+
+func (suite *ElfLoaderSuite) TestLoad_InvalidFiles() {
+	assert := assert.New(suite.T())
+
+	_, err := suite.loader.Load(bytes.NewReader([]byte("ADDI x1 x0 1\n")))
+	assert.Contains(err.Error(), "Load: not a valid ELF file")
+
+	_, err = suite.loader.Load(bytes.NewReader(ElfFixtures.Build(ElfFixtures.Options{Machine: elf.EM_ARM})))
+	assert.EqualError(err, "Load: ELF machine is EM_ARM, but only EM_RISCV is supported")
+
+	_, err = suite.loader.Load(bytes.NewReader(ElfFixtures.Build(ElfFixtures.Options{FileType: elf.ET_REL})))
+	assert.EqualError(err, "Load: ELF type is ET_REL, but only executables (ET_EXEC) are supported")
+
+	elf64 := ElfFixtures.Build(ElfFixtures.Options{Entry: 0x44})
+	elf64[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	_, err = suite.loader.Load(bytes.NewReader(elf64))
+	assert.NotNil(err)
+
+	assert.Equal(make([]uint8, 256), suite.bytes.data, "memory must be untouched")
+	assert.Equal(uint32(0), suite.pc.address)
+}
+
+func (suite *ElfLoaderSuite) TestLoad_SegmentDoesNotFit() {
+	assert := assert.New(suite.T())
+
+	_, err := suite.loader.Load(bytes.NewReader(ElfFixtures.Build(ElfFixtures.Options{Address: 0xFC})))
+	assert.EqualError(err, "Load: segment at 0x000000fc ends at 0x10c, but memory ends at 0x100")
+	assert.Equal(make([]uint8, 256), suite.bytes.data, "memory must be untouched")
+	assert.Equal(uint32(0), suite.pc.address)
+}
+
+/*byteMemory is a little-endian, byte-addressable memory that is backed by a slice*/
+type byteMemory struct {
+	data []uint8
+}
+
+func (m *byteMemory) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	if err := Memory.CheckAccess(address, width, m.GetAddressSpaceSize()); err != nil {
+		return 0, err
+	}
+
+	val := uint64(0)
+	for i := uint32(0); i < uint32(width); i++ {
+		val |= uint64(m.data[address+i]) << (8 * i)
+	}
+	return val, nil
+}
+
+func (m *byteMemory) Write(address uint32, width Memory.AccessWidth, val uint64) error {
+	if err := Memory.CheckAccess(address, width, m.GetAddressSpaceSize()); err != nil {
+		return err
+	}
+
+	for i := uint32(0); i < uint32(width); i++ {
+		m.data[address+i] = uint8(val >> (8 * i))
+	}
+	return nil
+}
+
+func (m *byteMemory) GetAddressSpaceSize() uint {
+	return uint(len(m.data))
+}
+
+type programCounterStub struct {
+	address uint32
+}
+
+func (pc *programCounterStub) SetProgramCounter(address uint32) {
+	pc.address = address
+}
@@ -0,0 +1,67 @@
+package programLoaders
+
+import (
+	"fmt"
+
+	Memory "github.com/chenhowa/computer/lib/memory"
+)
+
+/*ImageLoader loads memory images, which are plain copies of a range of memory, in the formats
+that are also used to initialize memories on an FPGA: flat binary, Intel HEX, and Verilog hex*/
+type ImageLoader struct {
+	memory imageMemory
+}
+
+/*MakeImageLoader is a constructor for ImageLoader. Images are loaded into `memory`*/
+func MakeImageLoader(memory imageMemory) ImageLoader {
+	loader := ImageLoader{
+		memory: memory,
+	}
+
+	return loader
+}
+
+/*ImageWriter exports ranges of memory as memory images, in the same formats that ImageLoader loads*/
+type ImageWriter struct {
+	memory imageMemory
+}
+
+/*MakeImageWriter is a constructor for ImageWriter. Images are exported from `memory`*/
+func MakeImageWriter(memory imageMemory) ImageWriter {
+	writer := ImageWriter{
+		memory: memory,
+	}
+
+	return writer
+}
+
+/*setBytes writes `data` to memory, starting at `address`*/
+func (l *ImageLoader) setBytes(address uint32, data []byte) error {
+	for i, b := range data {
+		byteAddress := address + uint32(i)
+		if byteAddress < address {
+			return fmt.Errorf("Load: image at 0x%08x runs past the end of the 32-bit address space", address)
+		}
+		if err := l.memory.Write(byteAddress, Memory.Byte, uint64(b)); err != nil {
+			return fmt.Errorf("Load: image does not fit in memory: %v", err)
+		}
+	}
+	return nil
+}
+
+/*getBytes reads `length` bytes of memory, starting at `address`*/
+func (w *ImageWriter) getBytes(address uint32, length uint32) ([]byte, error) {
+	if uint64(address)+uint64(length) > uint64(1)<<32 {
+		return nil, fmt.Errorf("Write: range of %d bytes at 0x%08x does not fit in a 32-bit address space", length, address)
+	}
+
+	data := make([]byte, length)
+	for i := range data {
+		b, err := w.memory.Read(address+uint32(i), Memory.Byte)
+		if err != nil {
+			return nil, fmt.Errorf("Write: memory cannot be read: %v", err)
+		}
+		data[i] = uint8(b)
+	}
+	return data, nil
+}
@@ -0,0 +1,156 @@
+package programLoaders
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	Memory "github.com/chenhowa/computer/lib/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImageSuite struct {
+	suite.Suite
+	memory *Memory.BasicMemory
+	loader *ImageLoader
+	writer *ImageWriter
+}
+
+func TestImageSuite(t *testing.T) {
+	suite.Run(t, new(ImageSuite))
+}
+
+func (suite *ImageSuite) SetupTest() {
+	memory := Memory.MakeBasicMemory(0x1FF)
+	suite.memory = &memory
+	loader := MakeImageLoader(suite.memory)
+	suite.loader = &loader
+	writer := MakeImageWriter(suite.memory)
+	suite.writer = &writer
+}
+
+func (suite *ImageSuite) bytesAt(address uint32, length int) []byte {
+	data := make([]byte, length)
+	for i := range data {
+		b, err := suite.memory.Read(address+uint32(i), Memory.Byte)
+		assert.NoError(suite.T(), err)
+		data[i] = uint8(b)
+	}
+	return data
+}
+
+func (suite *ImageSuite) TestBinary() {
+	assert := assert.New(suite.T())
+
+	count, err := suite.loader.LoadBinary(bytes.NewReader([]byte{1, 2, 3, 4, 5}), 0x10)
+	assert.Nil(err)
+	assert.Equal(uint32(5), count)
+	assert.Equal([]byte{0, 1, 2, 3, 4, 5, 0}, suite.bytesAt(0xF, 7))
+
+	out := &bytes.Buffer{}
+	assert.Nil(suite.writer.WriteBinary(out, 0x11, 3))
+	assert.Equal([]byte{2, 3, 4}, out.Bytes())
+
+	_, err = suite.loader.LoadBinary(bytes.NewReader([]byte{1, 2}), 0x1FF)
+	assert.EqualError(err, "Load: image does not fit in memory: memory: address is out of range: 1 bytes at 0x200")
+}
+
+func (suite *ImageSuite) TestIntelHex() {
+	assert := assert.New(suite.T())
+	image := ":10010000214601360121470136007EFE09D2190140\n" +
+		":0400000500000100F6\n" +
+		":00000001FF\n"
+
+	assert.Nil(suite.loader.LoadIntelHex(strings.NewReader(image), 0))
+	assert.Equal([]byte{0x21, 0x46, 0x01, 0x36, 0x01, 0x21, 0x47, 0x01, 0x36, 0x00, 0x7E, 0xFE, 0x09, 0xD2, 0x19, 0x01},
+		suite.bytesAt(0x100, 16))
+
+	out := &bytes.Buffer{}
+	assert.Nil(suite.writer.WriteIntelHex(out, 0x100, 16))
+	assert.Equal(":10010000214601360121470136007EFE09D2190140\n:00000001FF\n", out.String())
+}
+
+func (suite *ImageSuite) TestIntelHex_BaseAndExtendedAddresses() {
+	assert := assert.New(suite.T())
+	image := ":020000020001FB\n" + // segment 0x0001, which starts at 0x10
+		":02000200AABB97\n" +
+		":00000001FF\n"
+
+	assert.Nil(suite.loader.LoadIntelHex(strings.NewReader(image), 0x100))
+	assert.Equal([]byte{0xAA, 0xBB}, suite.bytesAt(0x112, 2))
+}
+
+func (suite *ImageSuite) TestIntelHex_WritesExtendedLinearAddresses() {
+	assert := assert.New(suite.T())
+	memory := byteMemory{data: make([]uint8, 0x10004)}
+	memory.data[0xFFFF] = 0x11
+	memory.data[0x10000] = 0x22
+	writer := MakeImageWriter(&memory)
+
+	out := &bytes.Buffer{}
+	assert.Nil(writer.WriteIntelHex(out, 0xFFFF, 2))
+	assert.Equal(":01FFFF0011F0\n:020000040001F9\n:0100000022DD\n:00000001FF\n", out.String())
+}
+
+func (suite *ImageSuite) TestIntelHex_Errors() {
+	assert := assert.New(suite.T())
+
+	err := suite.loader.LoadIntelHex(strings.NewReader(":0100000022DE\n:00000001FF\n"), 0)
+	assert.EqualError(err, "LoadIntelHex: line 1: record checksum is wrong")
+
+	err = suite.loader.LoadIntelHex(strings.NewReader("\n0100000022DD\n"), 0)
+	assert.EqualError(err, "LoadIntelHex: line 2: record must start with ':'")
+
+	err = suite.loader.LoadIntelHex(strings.NewReader(":0200000022DD\n"), 0)
+	assert.EqualError(err, "LoadIntelHex: line 1: record length does not match its byte count")
+
+	err = suite.loader.LoadIntelHex(strings.NewReader(":0100000022DD\n"), 0)
+	assert.EqualError(err, "LoadIntelHex: image has no end-of-file record")
+}
+
+func (suite *ImageSuite) TestVerilogHex() {
+	assert := assert.New(suite.T())
+	image := "// program\n" +
+		"@4 00500513 /* addi a0, zero, 5 */\n" +
+		"0010_0073 /* ebreak\n" +
+		"   still a comment */ DEADBEEF\n"
+
+	assert.Nil(suite.loader.LoadVerilogHex(strings.NewReader(image), 0x20, 4))
+	assert.Equal([]byte{0x13, 0x05, 0x50, 0x00, 0x73, 0x00, 0x10, 0x00, 0xEF, 0xBE, 0xAD, 0xDE}, suite.bytesAt(0x30, 12))
+
+	out := &bytes.Buffer{}
+	assert.Nil(suite.writer.WriteVerilogHex(out, 0x30, 12, 4))
+	assert.Equal("@0000000C\n00500513 00100073 DEADBEEF\n", out.String())
+
+	out.Reset()
+	assert.Nil(suite.writer.WriteVerilogHex(out, 0x30, 18, 1))
+	assert.Equal("@00000030\n13 05 50 00 73 00 10 00 EF BE AD DE 00 00 00 00\n00 00\n", out.String())
+}
+
+func (suite *ImageSuite) TestVerilogHex_RoundTrip() {
+	assert := assert.New(suite.T())
+	_, err := suite.loader.LoadBinary(bytes.NewReader([]byte{1, 2, 3, 4}), 0x40)
+	assert.Nil(err)
+
+	out := &bytes.Buffer{}
+	assert.Nil(suite.writer.WriteVerilogHex(out, 0x40, 4, 2))
+	assert.Nil(suite.loader.LoadVerilogHex(out, 0x100, 2))
+	assert.Equal([]byte{1, 2, 3, 4}, suite.bytesAt(0x140, 4))
+}
+
+func (suite *ImageSuite) TestVerilogHex_Errors() {
+	assert := assert.New(suite.T())
+
+	err := suite.loader.LoadVerilogHex(strings.NewReader("00\n1FF\n"), 0, 1)
+	assert.EqualError(err, "LoadVerilogHex: line 2: 1FF is not a valid 1-byte word")
+
+	err = suite.loader.LoadVerilogHex(strings.NewReader("@xyz\n"), 0, 1)
+	assert.EqualError(err, "LoadVerilogHex: line 1: @xyz is not a valid address")
+
+	err = suite.loader.LoadVerilogHex(strings.NewReader("00\n"), 0, 3)
+	assert.EqualError(err, "LoadVerilogHex: word size must be 1, 2 or 4 bytes, not 3")
+
+	err = suite.writer.WriteVerilogHex(&bytes.Buffer{}, 2, 4, 4)
+	assert.EqualError(err, "WriteVerilogHex: start 0x00000002 and length 4 must be multiples of the word size 4")
+}
@@ -0,0 +1,145 @@
+package programLoaders
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*These constants are the record types of Intel HEX*/
+const (
+	hexData uint8 = iota
+	hexEndOfFile
+	hexExtendedSegmentAddress
+	hexStartSegmentAddress
+	hexExtendedLinearAddress
+	hexStartLinearAddress
+)
+
+/*hexRecordLength is the number of data bytes in each data record that WriteIntelHex writes*/
+const hexRecordLength = 16
+
+/*LoadIntelHex loads the Intel HEX image read from `image` into memory. The addresses of the image,
+including those set by extended segment and extended linear address records, are offsets from `base`.
+Start address records are accepted, but ignored. The image must end with an end-of-file record*/
+func (l *ImageLoader) LoadIntelHex(image io.Reader, base uint32) error {
+	scanner := bufio.NewScanner(image)
+	upperAddress := uint32(0)
+	for lineCount := 1; scanner.Scan(); lineCount++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		recordType, address, data, err := parseHexRecord(line)
+		if err != nil {
+			return fmt.Errorf("LoadIntelHex: line %d: %v", lineCount, err)
+		}
+
+		switch recordType {
+		case hexData:
+			if err := l.setBytes(base+upperAddress+uint32(address), data); err != nil {
+				return fmt.Errorf("LoadIntelHex: line %d: %v", lineCount, err)
+			}
+		case hexEndOfFile:
+			return nil
+		case hexExtendedSegmentAddress, hexExtendedLinearAddress:
+			if len(data) != 2 {
+				return fmt.Errorf("LoadIntelHex: line %d: address record must have 2 data bytes, but has %d", lineCount, len(data))
+			}
+			upperAddress = uint32(data[0])<<8 | uint32(data[1])
+			if recordType == hexExtendedSegmentAddress {
+				upperAddress <<= 4
+			} else {
+				upperAddress <<= 16
+			}
+		case hexStartSegmentAddress, hexStartLinearAddress:
+			if len(data) != 4 {
+				return fmt.Errorf("LoadIntelHex: line %d: start address record must have 4 data bytes, but has %d", lineCount, len(data))
+			}
+		default:
+			return fmt.Errorf("LoadIntelHex: line %d: unknown record type %d", lineCount, recordType)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("LoadIntelHex: image could not be read: %v", err)
+	}
+	return fmt.Errorf("LoadIntelHex: image has no end-of-file record")
+}
+
+/*parseHexRecord parses the record `line`, which has the form `:LLAAAATT<data>CC`, and checks its checksum*/
+func parseHexRecord(line string) (uint8, uint16, []byte, error) {
+	if !strings.HasPrefix(line, ":") {
+		return 0, 0, nil, fmt.Errorf("record must start with ':'")
+	}
+
+	record, err := hex.DecodeString(line[1:])
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("record is not hexadecimal: %v", err)
+	}
+	if len(record) < 5 || len(record) != 5+int(record[0]) {
+		return 0, 0, nil, fmt.Errorf("record length does not match its byte count")
+	}
+
+	sum := uint8(0)
+	for _, b := range record {
+		sum += b
+	}
+	if sum != 0 {
+		return 0, 0, nil, fmt.Errorf("record checksum is wrong")
+	}
+
+	address := uint16(record[1])<<8 | uint16(record[2])
+	return record[3], address, record[4 : len(record)-1], nil
+}
+
+/*WriteIntelHex writes the `length` bytes of memory starting at `start` to `out` as an Intel HEX image.
+Addresses above 16 bits are written with extended linear address records*/
+func (w *ImageWriter) WriteIntelHex(out io.Writer, start uint32, length uint32) error {
+	data, err := w.getBytes(start, length)
+	if err != nil {
+		return err
+	}
+
+	upperAddress := uint32(0)
+	for offset := uint32(0); offset < length; {
+		address := start + offset
+		if address>>16 != upperAddress {
+			upperAddress = address >> 16
+			if err := writeHexRecord(out, hexExtendedLinearAddress, 0, []byte{uint8(upperAddress >> 8), uint8(upperAddress)}); err != nil {
+				return err
+			}
+		}
+
+		// A record may not run past the end of its 64 KiB block, as its address is only 16 bits
+		recordLength := uint32(hexRecordLength)
+		if left := length - offset; left < recordLength {
+			recordLength = left
+		}
+		if toBlockEnd := 0x10000 - address&0xFFFF; toBlockEnd < recordLength {
+			recordLength = toBlockEnd
+		}
+
+		if err := writeHexRecord(out, hexData, uint16(address), data[offset:offset+recordLength]); err != nil {
+			return err
+		}
+		offset += recordLength
+	}
+
+	return writeHexRecord(out, hexEndOfFile, 0, nil)
+}
+
+func writeHexRecord(out io.Writer, recordType uint8, address uint16, data []byte) error {
+	record := append([]byte{uint8(len(data)), uint8(address >> 8), uint8(address), recordType}, data...)
+	sum := uint8(0)
+	for _, b := range record {
+		sum += b
+	}
+	record = append(record, -sum)
+
+	_, err := fmt.Fprintf(out, ":%s\n", strings.ToUpper(hex.EncodeToString(record)))
+	return err
+}
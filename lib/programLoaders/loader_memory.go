@@ -0,0 +1,18 @@
+package programLoaders
+
+import (
+	Memory "github.com/chenhowa/computer/lib/memory"
+)
+
+/*loaderMemory is the memory that programs are loaded into. Every memory.Interface, such as
+memory.CompositeMemory32 or the command line's Memory32, satisfies it. GetAddressSpaceSize returns
+the first address that the memory does not have*/
+type loaderMemory interface {
+	Memory.Writer
+	GetAddressSpaceSize() uint
+}
+
+/*imageMemory is the memory that images are loaded into, and exported from*/
+type imageMemory interface {
+	Memory.ReadWriter
+}
@@ -0,0 +1,130 @@
+package programLoaders
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*verilogLineLength is the number of bytes that WriteVerilogHex writes on each line*/
+const verilogLineLength = 16
+
+/*LoadVerilogHex loads the Verilog hex image read from `image`, as $readmemh would read it, into memory.
+Each value of the image is a word of `wordSize` bytes (1, 2 or 4), and is stored in little-endian order.
+`@address` directives move to another word address, which, like the first word of the image, is an offset
+from `base`. Both `//` and block comments are allowed, and so are underscores inside values*/
+func (l *ImageLoader) LoadVerilogHex(image io.Reader, base uint32, wordSize uint) error {
+	if err := checkWordSize(wordSize); err != nil {
+		return fmt.Errorf("LoadVerilogHex: %v", err)
+	}
+
+	scanner := bufio.NewScanner(image)
+	address := base
+	inComment := false
+	for lineCount := 1; scanner.Scan(); lineCount++ {
+		var line string
+		line, inComment = stripVerilogComments(scanner.Text(), inComment)
+
+		for _, field := range strings.Fields(line) {
+			if strings.HasPrefix(field, "@") {
+				wordAddress, err := strconv.ParseUint(field[1:], 16, 32)
+				if err != nil {
+					return fmt.Errorf("LoadVerilogHex: line %d: %s is not a valid address", lineCount, field)
+				}
+				address = base + uint32(wordAddress)*uint32(wordSize)
+				continue
+			}
+
+			word, err := strconv.ParseUint(strings.Replace(field, "_", "", -1), 16, int(wordSize)*8)
+			if err != nil {
+				return fmt.Errorf("LoadVerilogHex: line %d: %s is not a valid %d-byte word", lineCount, field, wordSize)
+			}
+
+			data := make([]byte, wordSize)
+			for i := range data {
+				data[i] = uint8(word >> (8 * uint(i)))
+			}
+			if err := l.setBytes(address, data); err != nil {
+				return fmt.Errorf("LoadVerilogHex: line %d: %v", lineCount, err)
+			}
+			address += uint32(wordSize)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("LoadVerilogHex: image could not be read: %v", err)
+	}
+	return nil
+}
+
+/*stripVerilogComments removes the comments from `line`. `inComment` tells whether the line starts inside
+a block comment, and the returned bool whether the next line does*/
+func stripVerilogComments(line string, inComment bool) (string, bool) {
+	var stripped strings.Builder
+	for len(line) > 0 {
+		if inComment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				return stripped.String(), true
+			}
+			line = line[end+2:]
+			inComment = false
+		} else if strings.HasPrefix(line, "//") {
+			break
+		} else if strings.HasPrefix(line, "/*") {
+			line = line[2:]
+			inComment = true
+			stripped.WriteByte(' ')
+		} else {
+			stripped.WriteByte(line[0])
+			line = line[1:]
+		}
+	}
+	return stripped.String(), inComment
+}
+
+/*WriteVerilogHex writes the `length` bytes of memory starting at `start` to `out` as a Verilog hex image
+of `wordSize`-byte words, which begins with the word address of `start`. Both `start` and `length`
+must be multiples of the word size*/
+func (w *ImageWriter) WriteVerilogHex(out io.Writer, start uint32, length uint32, wordSize uint) error {
+	if err := checkWordSize(wordSize); err != nil {
+		return fmt.Errorf("WriteVerilogHex: %v", err)
+	}
+	if start%uint32(wordSize) != 0 || length%uint32(wordSize) != 0 {
+		return fmt.Errorf("WriteVerilogHex: start 0x%08x and length %d must be multiples of the word size %d", start, length, wordSize)
+	}
+
+	data, err := w.getBytes(start, length)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(out, "@%08X\n", start/uint32(wordSize)); err != nil {
+		return err
+	}
+	for lineStart := 0; lineStart < len(data); lineStart += verilogLineLength {
+		words := []string{}
+		for i := lineStart; i < len(data) && i < lineStart+verilogLineLength; i += int(wordSize) {
+			word := uint32(0)
+			for b := int(wordSize) - 1; b >= 0; b-- {
+				word = word<<8 | uint32(data[i+b])
+			}
+			words = append(words, fmt.Sprintf("%0*X", wordSize*2, word))
+		}
+
+		if _, err := fmt.Fprintln(out, strings.Join(words, " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkWordSize(wordSize uint) error {
+	if wordSize != 1 && wordSize != 2 && wordSize != 4 {
+		return fmt.Errorf("word size must be 1, 2 or 4 bytes, not %d", wordSize)
+	}
+	return nil
+}
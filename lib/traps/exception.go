@@ -0,0 +1,46 @@
+package traps
+
+/*Cause is the exception code that a trap writes to mcause*/
+type Cause uint32
+
+/*These constants are the causes of the synchronous exceptions that an instruction can raise*/
+const (
+	InstructionAddressMisaligned Cause = 0
+	InstructionAccessFault       Cause = 1
+	IllegalInstruction           Cause = 2
+	Breakpoint                   Cause = 3
+	LoadAddressMisaligned        Cause = 4
+	LoadAccessFault              Cause = 5
+	StoreAddressMisaligned       Cause = 6
+	StoreAccessFault             Cause = 7
+	UserEnvironmentCall          Cause = 8
+	SupervisorEnvironmentCall    Cause = 9
+	MachineEnvironmentCall       Cause = 11
+	InstructionPageFault         Cause = 12
+	LoadPageFault                Cause = 13
+	StorePageFault               Cause = 15
+)
+
+/*Exception is an error that an instruction panics with when it cannot complete, and that the
+machine turns into a trap instead of a failure. `Value` is written to mtval: the address that
+could not be accessed, or 0 when the exception has no such address*/
+type Exception struct {
+	Cause  Cause
+	Value  uint32
+	reason string
+}
+
+/*MakeException is a constructor for Exception. The `reason` is only used as the message of the error*/
+func MakeException(cause Cause, value uint32, reason string) Exception {
+	exception := Exception{
+		Cause:  cause,
+		Value:  value,
+		reason: reason,
+	}
+
+	return exception
+}
+
+func (e Exception) Error() string {
+	return e.reason
+}
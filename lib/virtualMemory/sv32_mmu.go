@@ -0,0 +1,285 @@
+package virtualMemory
+
+import (
+	"fmt"
+
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
+)
+
+/*Access is the kind of memory access that an address is translated for*/
+type Access uint8
+
+/*These constants are the kinds of memory accesses. AMOs and store-conditionals are stores*/
+const (
+	Fetch Access = iota
+	Load
+	Store
+)
+
+/*These constants are the bits of a page table entry*/
+const (
+	PteValid      uint32 = 1 << 0
+	PteRead       uint32 = 1 << 1
+	PteWrite      uint32 = 1 << 2
+	PteExecute    uint32 = 1 << 3
+	PteUser       uint32 = 1 << 4
+	PteGlobal     uint32 = 1 << 5
+	PteAccessed   uint32 = 1 << 6
+	PteDirty      uint32 = 1 << 7
+	ptePPNShift          = 10
+	pteLowPPNMask uint32 = 0x3FF << ptePPNShift // the bits of PPN[0], which are 0 in a superpage
+)
+
+/*These constants describe the Sv32 virtual address: a 4 KiB page, selected by two 10-bit virtual page numbers*/
+const (
+	PageShift        = 12
+	PageSize  uint32 = 1 << PageShift
+	vpnBits          = 10
+	vpnMask   uint32 = 1<<vpnBits - 1
+	pteSize   uint32 = 4
+)
+
+var pageFaults = map[Access]Traps.Cause{
+	Fetch: Traps.InstructionPageFault,
+	Load:  Traps.LoadPageFault,
+	Store: Traps.StorePageFault,
+}
+
+var accessFaults = map[Access]Traps.Cause{
+	Fetch: Traps.InstructionAccessFault,
+	Load:  Traps.LoadAccessFault,
+	Store: Traps.StoreAccessFault,
+}
+
+/*pmpPermissions are the physical memory protection permissions that each kind of access needs*/
+var pmpPermissions = map[Access]uint8{
+	Fetch: CsrManagers.PmpExecute,
+	Load:  CsrManagers.PmpRead,
+	Store: CsrManagers.PmpWrite,
+}
+
+var misalignedAccesses = map[Access]Traps.Cause{
+	Fetch: Traps.InstructionAddressMisaligned,
+	Load:  Traps.LoadAddressMisaligned,
+	Store: Traps.StoreAddressMisaligned,
+}
+
+/*Sv32Mmu translates the virtual addresses of a hart into physical addresses, with the Sv32 page tables that
+satp selects. Addresses are not translated in machine mode, nor while satp is in the Bare mode.
+Loads and stores in machine mode are translated at the privilege in mstatus.MPP while mstatus.MPRV is set.
+
+Translations are cached in a Tlb, which SFENCE.VMA flushes with Fence. The accessed and dirty bits are never
+written by the MMU: an access to a page whose A bit is clear, or a store to a page whose D bit is clear, raises
+a page fault, so that software can set them. An access that crosses into another page raises a misaligned
+exception, since its bytes may not be next to each other in physical memory. An executor that allows misaligned
+accesses translates their bytes one at a time instead.
+
+Every physical address, translated or not, is then checked by physical memory protection, and so is every page
+table entry that a walk reads, at supervisor privilege. An access that it does not allow raises an access fault.
+*/
+type Sv32Mmu struct {
+	control translationControl
+	memory  Memory.Reader
+	tlb     Tlb
+}
+
+/*translationControl is the CSR file that holds satp, mstatus, the physical memory protection entries
+and the privilege of the hart*/
+type translationControl interface {
+	GetPrivilege() CsrManagers.Privilege
+	Inspect(register uint) uint32
+	AllowsPhysicalAccess(address uint32, size uint32, permission uint8, privilege CsrManagers.Privilege) bool
+}
+
+/*MakeSv32Mmu is a constructor for Sv32Mmu, whose page tables are read from `memory`, and which
+is controlled by the CSRs in `control`. Its Tlb starts empty*/
+func MakeSv32Mmu(control translationControl, memory Memory.Reader) Sv32Mmu {
+	mmu := Sv32Mmu{
+		control: control,
+		memory:  memory,
+		tlb:     MakeTlb(),
+	}
+
+	return mmu
+}
+
+/*TranslateFetch translates the address of an instruction fetch of `size` bytes*/
+func (m *Sv32Mmu) TranslateFetch(address uint32, size uint32) uint32 {
+	return m.Translate(address, size, Fetch)
+}
+
+/*TranslateLoad translates the address of a load of `size` bytes*/
+func (m *Sv32Mmu) TranslateLoad(address uint32, size uint32) uint32 {
+	return m.Translate(address, size, Load)
+}
+
+/*TranslateStore translates the address of a store or AMO of `size` bytes*/
+func (m *Sv32Mmu) TranslateStore(address uint32, size uint32) uint32 {
+	return m.Translate(address, size, Store)
+}
+
+/*Translate returns the physical address of the virtual `address`, for an `access` of `size` bytes.
+It panics with a page fault when the page table does not allow the access, and with an access fault
+when physical memory protection does not*/
+func (m *Sv32Mmu) Translate(address uint32, size uint32, access Access) uint32 {
+	physical := m.translate(address, size, access, m.effectivePrivilege(access))
+	m.CheckPhysicalAccess(address, physical, size, access)
+	return physical
+}
+
+/*CheckPhysicalAccess panics with an access fault when physical memory protection does not allow an `access`
+of `size` bytes at the `physical` address, which the virtual `address` translates to. It checks the part of
+an access that lies in a page that has already been translated, without translating it again*/
+func (m *Sv32Mmu) CheckPhysicalAccess(address uint32, physical uint32, size uint32, access Access) {
+	if !m.control.AllowsPhysicalAccess(physical, size, pmpPermissions[access], m.effectivePrivilege(access)) {
+		panic(Traps.MakeException(accessFaults[access], address,
+			fmt.Sprintf("Sv32Mmu: physical memory protection does not allow the access at %#x", physical)))
+	}
+}
+
+/*translate returns the physical address of the virtual `address`, for an `access` of `size` bytes at `privilege`*/
+func (m *Sv32Mmu) translate(address uint32, size uint32, access Access, privilege CsrManagers.Privilege) uint32 {
+	satp := m.control.Inspect(CsrManagers.Satp)
+	if satp&CsrManagers.SatpMode == 0 || privilege == CsrManagers.Machine {
+		return address
+	}
+
+	if address%PageSize+size > PageSize {
+		panic(Traps.MakeException(misalignedAccesses[access], address,
+			fmt.Sprintf("Sv32Mmu: access of %d bytes at %#x crosses a page boundary", size, address)))
+	}
+
+	asid := (satp & CsrManagers.SatpASID) >> CsrManagers.SatpASIDShift
+	vpn := address >> PageShift
+	entry, ok := m.tlb.lookup(asid, vpn)
+	if !ok {
+		entry = m.walk(satp, address, access)
+		entry.asid = asid
+		m.tlb.insert(entry)
+	}
+
+	m.checkPermissions(entry.pte, address, access, privilege)
+	physical := physicalAddress(entry, address)
+	if physical > uint64(^uint32(0)) {
+		panic(Traps.MakeException(accessFaults[access], address,
+			fmt.Sprintf("Sv32Mmu: %#x translates to %#x, which is outside of physical memory", address, physical)))
+	}
+	return uint32(physical)
+}
+
+/*effectivePrivilege returns the privilege that an `access` is translated at*/
+func (m *Sv32Mmu) effectivePrivilege(access Access) CsrManagers.Privilege {
+	privilege := m.control.GetPrivilege()
+	mstatus := m.control.Inspect(CsrManagers.Mstatus)
+	if access != Fetch && privilege == CsrManagers.Machine && mstatus&CsrManagers.MstatusMPRV != 0 {
+		return CsrManagers.Privilege((mstatus & CsrManagers.MstatusMPP) >> CsrManagers.MstatusMPPShift)
+	}
+	return privilege
+}
+
+/*walk finds the leaf page table entry of `address`, starting from the root page table in `satp`*/
+func (m *Sv32Mmu) walk(satp uint32, address uint32, access Access) tlbEntry {
+	table := uint64(satp&CsrManagers.SatpPPN) << PageShift
+	for level := 1; level >= 0; level-- {
+		vpn := (address >> (PageShift + vpnBits*uint(level))) & vpnMask
+		pteAddress := table + uint64(vpn*pteSize)
+		if pteAddress > uint64(^uint32(0)) ||
+			!m.control.AllowsPhysicalAccess(uint32(pteAddress), pteSize, CsrManagers.PmpRead, CsrManagers.Supervisor) {
+			panic(Traps.MakeException(accessFaults[access], address,
+				fmt.Sprintf("Sv32Mmu: page table entry at %#x cannot be read", pteAddress)))
+		}
+
+		pte := m.readPte(uint32(pteAddress), address, access)
+		if pte&PteValid == 0 || (pte&PteRead == 0 && pte&PteWrite != 0) {
+			panic(m.pageFault(address, access, fmt.Sprintf("page table entry %#x is not valid", pte)))
+		}
+		if pte&(PteRead|PteExecute) != 0 {
+			if level == 1 && pte&pteLowPPNMask != 0 {
+				panic(m.pageFault(address, access, fmt.Sprintf("superpage entry %#x is misaligned", pte)))
+			}
+			return tlbEntry{vpn: address >> PageShift, pte: pte, superpage: level == 1}
+		}
+
+		table = uint64(pte>>ptePPNShift) << PageShift
+	}
+
+	panic(m.pageFault(address, access, "the last level of the page table does not hold a leaf entry"))
+}
+
+/*readPte reads the page table entry at `pteAddress`. If the memory returns an error, because nothing is
+mapped there, the `access` at `address` that needed the entry raises an access fault*/
+func (m *Sv32Mmu) readPte(pteAddress uint32, address uint32, access Access) uint32 {
+	pte, err := m.memory.Read(pteAddress, Memory.Word)
+	if err != nil {
+		panic(Traps.MakeException(accessFaults[access], address,
+			fmt.Sprintf("Sv32Mmu: page table entry at %#x cannot be read: %v", pteAddress, err)))
+	}
+	return uint32(pte)
+}
+
+/*checkPermissions panics with a page fault if the leaf entry `pte` does not allow an `access` at `privilege`*/
+func (m *Sv32Mmu) checkPermissions(pte uint32, address uint32, access Access, privilege CsrManagers.Privilege) {
+	mstatus := m.control.Inspect(CsrManagers.Mstatus)
+	if pte&PteUser != 0 && privilege == CsrManagers.Supervisor && (access == Fetch || mstatus&CsrManagers.MstatusSUM == 0) {
+		panic(m.pageFault(address, access, "supervisor mode cannot access a user page"))
+	}
+	if pte&PteUser == 0 && privilege == CsrManagers.User {
+		panic(m.pageFault(address, access, "user mode cannot access a supervisor page"))
+	}
+
+	var allowed bool
+	switch access {
+	case Fetch:
+		allowed = pte&PteExecute != 0
+	case Load:
+		allowed = pte&PteRead != 0 || (mstatus&CsrManagers.MstatusMXR != 0 && pte&PteExecute != 0)
+	case Store:
+		allowed = pte&PteWrite != 0
+	}
+	if !allowed {
+		panic(m.pageFault(address, access, fmt.Sprintf("page table entry %#x does not allow the access", pte)))
+	}
+
+	if pte&PteAccessed == 0 || (access == Store && pte&PteDirty == 0) {
+		panic(m.pageFault(address, access, fmt.Sprintf("page table entry %#x needs its A or D bit set", pte)))
+	}
+}
+
+/*pageFault returns the page fault of an `access` to `address`*/
+func (m *Sv32Mmu) pageFault(address uint32, access Access, reason string) Traps.Exception {
+	return Traps.MakeException(pageFaults[access], address, fmt.Sprintf("Sv32Mmu: page fault at %#x: %s", address, reason))
+}
+
+/*physicalAddress returns the physical address that `entry` translates `address` to, which has 34 bits in Sv32*/
+func physicalAddress(entry tlbEntry, address uint32) uint64 {
+	ppn := uint64(entry.pte >> ptePPNShift)
+	if entry.superpage {
+		return ppn<<PageShift | uint64(address&(vpnMask<<PageShift|(PageSize-1)))
+	}
+	return ppn<<PageShift | uint64(address&(PageSize-1))
+}
+
+/*isGlobal returns whether the page table entry `pte` maps its page into every address space*/
+func isGlobal(pte uint32) bool {
+	return pte&PteGlobal != 0
+}
+
+/*Fence orders the updates to the page tables before the translations that follow, as SFENCE.VMA does, by flushing
+the Tlb. `allAddresses` and `allAsids` are set when the instruction's rs1 and rs2, respectively, are x0.
+SFENCE.VMA is illegal in user mode, and in supervisor mode when mstatus.TVM is set*/
+func (m *Sv32Mmu) Fence(address uint32, asid uint32, allAddresses bool, allAsids bool) {
+	privilege := m.control.GetPrivilege()
+	tvm := m.control.Inspect(CsrManagers.Mstatus)&CsrManagers.MstatusTVM != 0
+	if privilege == CsrManagers.User || (privilege == CsrManagers.Supervisor && tvm) {
+		panic(Traps.MakeException(Traps.IllegalInstruction, 0, fmt.Sprintf("Sv32Mmu: SFENCE.VMA is not allowed at privilege %d", privilege)))
+	}
+
+	m.tlb.Flush(address, asid, allAddresses, allAsids)
+}
+
+/*GetTlbStatistics returns the statistics of the Tlb*/
+func (m *Sv32Mmu) GetTlbStatistics() TlbStatistics {
+	return m.tlb.GetStatistics()
+}
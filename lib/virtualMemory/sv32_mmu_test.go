@@ -0,0 +1,251 @@
+package virtualMemory
+
+import (
+	"testing"
+
+	CsrManagers "github.com/chenhowa/computer/lib/csrManagers"
+	Memory "github.com/chenhowa/computer/lib/memory"
+	Traps "github.com/chenhowa/computer/lib/traps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type Sv32MmuSuite struct {
+	suite.Suite
+	control *translationControlMock
+	memory  *pageTableMemoryMock
+	mmu     *Sv32Mmu
+}
+
+func TestSv32MmuSuite(t *testing.T) {
+	suite.Run(t, new(Sv32MmuSuite))
+}
+
+type translationControlMock struct {
+	privilege CsrManagers.Privilege
+	mstatus   uint32
+	satp      uint32
+	protected uint32 // the physical page that physical memory protection does not allow any access to
+}
+
+func (c *translationControlMock) GetPrivilege() CsrManagers.Privilege {
+	return c.privilege
+}
+
+func (c *translationControlMock) Inspect(register uint) uint32 {
+	switch register {
+	case CsrManagers.Mstatus:
+		return c.mstatus
+	case CsrManagers.Satp:
+		return c.satp
+	}
+	panic("translationControlMock: only mstatus and satp are inspected")
+}
+
+func (c *translationControlMock) AllowsPhysicalAccess(address uint32, size uint32, permission uint8, privilege CsrManagers.Privilege) bool {
+	return address>>PageShift != c.protected>>PageShift
+}
+
+/*pageTableMemoryMock is a word-addressable memory, which is all that the page table walk needs.
+Nothing is mapped from `unmapped` up*/
+type pageTableMemoryMock struct {
+	words    map[uint32]uint32
+	unmapped uint32
+}
+
+func (m *pageTableMemoryMock) Read(address uint32, width Memory.AccessWidth) (uint64, error) {
+	if address >= m.unmapped {
+		return 0, Memory.ErrAddressOutOfRange
+	}
+	return uint64(m.words[address]), nil
+}
+
+const (
+	rootTable   = 0x1000
+	secondTable = 0x2000
+	asid        = 3
+	leafFlags   = PteValid | PteAccessed | PteDirty
+)
+
+func pageTableEntry(physicalAddress uint32, flags uint32) uint32 {
+	return physicalAddress>>PageShift<<ptePPNShift | flags
+}
+
+func (suite *Sv32MmuSuite) SetupTest() {
+	suite.control = &translationControlMock{
+		privilege: CsrManagers.Supervisor,
+		satp:      CsrManagers.SatpMode | asid<<CsrManagers.SatpASIDShift | rootTable>>PageShift,
+		protected: 0xF000,
+	}
+	suite.memory = &pageTableMemoryMock{words: map[uint32]uint32{}, unmapped: 0x80000000}
+
+	// 0x00400000 is a megapage at 0x00800000, and 0x00001000 is in the page table at 0x2000
+	suite.memory.words[rootTable+4*1] = pageTableEntry(0x00800000, leafFlags|PteRead|PteWrite)
+	suite.memory.words[rootTable+4*0] = pageTableEntry(secondTable, PteValid)
+	suite.memory.words[secondTable+4*1] = pageTableEntry(0x5000, leafFlags|PteRead|PteWrite|PteExecute)
+
+	mmu := MakeSv32Mmu(suite.control, suite.memory)
+	suite.mmu = &mmu
+}
+
+func (suite *Sv32MmuSuite) setLeaf(flags uint32) {
+	suite.memory.words[secondTable+4*1] = pageTableEntry(0x5000, flags)
+	suite.mmu.tlb.Flush(0, 0, true, true)
+}
+
+func (suite *Sv32MmuSuite) assertFault(cause Traps.Cause, address uint32, translate func()) {
+	defer func() {
+		exception, ok := recover().(Traps.Exception)
+		assert.True(suite.T(), ok, "no exception was raised")
+		assert.Equal(suite.T(), cause, exception.Cause)
+		assert.Equal(suite.T(), address, exception.Value)
+	}()
+	translate()
+}
+
+func (suite *Sv32MmuSuite) TestAddressesAreNotTranslatedInBareOrMachineMode() {
+	assert := assert.New(suite.T())
+	suite.control.privilege = CsrManagers.Machine
+	assert.Equal(uint32(0x1234), suite.mmu.TranslateStore(0x1234, 4))
+	assert.Equal(uint32(0x1234), suite.mmu.TranslateFetch(0x1234, 2))
+
+	suite.control.privilege = CsrManagers.User
+	suite.control.satp = 0
+	assert.Equal(uint32(0x1234), suite.mmu.TranslateLoad(0x1234, 4))
+	assert.Equal(TlbStatistics{}, suite.mmu.GetTlbStatistics())
+}
+
+func (suite *Sv32MmuSuite) TestPagesAndMegapagesAreTranslated() {
+	assert := assert.New(suite.T())
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateFetch(0x1234, 2))
+	assert.Equal(uint32(0x00812345), suite.mmu.TranslateStore(0x00412345, 1))
+
+	suite.assertFault(Traps.LoadPageFault, 0x3000, func() { suite.mmu.TranslateLoad(0x3000, 4) })
+	suite.assertFault(Traps.InstructionPageFault, 0x00C00000, func() { suite.mmu.TranslateFetch(0x00C00000, 2) })
+}
+
+func (suite *Sv32MmuSuite) TestMachineModeLoadsAndStoresUseMprv() {
+	assert := assert.New(suite.T())
+	suite.control.privilege = CsrManagers.Machine
+	suite.control.mstatus = CsrManagers.MstatusMPRV | uint32(CsrManagers.Supervisor)<<CsrManagers.MstatusMPPShift
+
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+	assert.Equal(uint32(0x1234), suite.mmu.TranslateFetch(0x1234, 4))
+}
+
+func (suite *Sv32MmuSuite) TestPermissions() {
+	assert := assert.New(suite.T())
+
+	suite.setLeaf(leafFlags | PteExecute)
+	suite.assertFault(Traps.LoadPageFault, 0x1234, func() { suite.mmu.TranslateLoad(0x1234, 4) })
+	suite.assertFault(Traps.StorePageFault, 0x1234, func() { suite.mmu.TranslateStore(0x1234, 4) })
+	suite.control.mstatus = CsrManagers.MstatusMXR // executable pages become readable
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+
+	suite.setLeaf(leafFlags | PteRead)
+	suite.assertFault(Traps.InstructionPageFault, 0x1234, func() { suite.mmu.TranslateFetch(0x1234, 2) })
+
+	suite.setLeaf(PteValid | PteRead | PteWrite | PteAccessed)
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+	suite.assertFault(Traps.StorePageFault, 0x1234, func() { suite.mmu.TranslateStore(0x1234, 4) })
+	suite.setLeaf(PteValid | PteRead | PteDirty)
+	suite.assertFault(Traps.LoadPageFault, 0x1234, func() { suite.mmu.TranslateLoad(0x1234, 4) })
+}
+
+func (suite *Sv32MmuSuite) TestUserPages() {
+	assert := assert.New(suite.T())
+
+	suite.control.privilege = CsrManagers.User
+	suite.assertFault(Traps.LoadPageFault, 0x1234, func() { suite.mmu.TranslateLoad(0x1234, 4) })
+
+	suite.setLeaf(leafFlags | PteRead | PteExecute | PteUser)
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+
+	suite.control.privilege = CsrManagers.Supervisor
+	suite.assertFault(Traps.LoadPageFault, 0x1234, func() { suite.mmu.TranslateLoad(0x1234, 4) })
+	suite.control.mstatus = CsrManagers.MstatusSUM
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+	suite.assertFault(Traps.InstructionPageFault, 0x1234, func() { suite.mmu.TranslateFetch(0x1234, 2) })
+}
+
+func (suite *Sv32MmuSuite) TestInvalidPageTables() {
+	suite.setLeaf(leafFlags | PteWrite) // writable, but not readable, is reserved
+	suite.assertFault(Traps.StorePageFault, 0x1234, func() { suite.mmu.TranslateStore(0x1234, 4) })
+
+	suite.memory.words[rootTable+4*2] = pageTableEntry(0x00801000, leafFlags|PteRead)
+	suite.assertFault(Traps.LoadPageFault, 0x00800000, func() { suite.mmu.TranslateLoad(0x00800000, 4) })
+
+	suite.memory.words[rootTable+4*3] = pageTableEntry(secondTable, PteValid)
+	suite.memory.words[secondTable] = pageTableEntry(secondTable, PteValid) // not a leaf
+	suite.assertFault(Traps.LoadPageFault, 0x00C00000, func() { suite.mmu.TranslateLoad(0x00C00000, 4) })
+}
+
+func (suite *Sv32MmuSuite) TestPhysicalMemoryProtection() {
+	suite.control.privilege = CsrManagers.Machine
+	suite.assertFault(Traps.StoreAccessFault, 0xF004, func() { suite.mmu.TranslateStore(0xF004, 4) })
+
+	suite.control.privilege = CsrManagers.Supervisor
+	suite.memory.words[secondTable+4*2] = pageTableEntry(0xF000, leafFlags|PteExecute)
+	suite.assertFault(Traps.InstructionAccessFault, 0x2000, func() { suite.mmu.TranslateFetch(0x2000, 2) })
+
+	// the page table itself is protected
+	suite.control.protected = secondTable
+	suite.assertFault(Traps.LoadAccessFault, 0x1234, func() { suite.mmu.TranslateLoad(0x1234, 4) })
+}
+
+func (suite *Sv32MmuSuite) TestUnmappedPageTablesAreAccessFaults() {
+	suite.memory.unmapped = secondTable
+	suite.assertFault(Traps.StoreAccessFault, 0x1234, func() { suite.mmu.TranslateStore(0x1234, 4) })
+}
+
+func (suite *Sv32MmuSuite) TestAccessesCannotCrossPages() {
+	assert := assert.New(suite.T())
+	assert.Equal(uint32(0x5FFC), suite.mmu.TranslateLoad(0x1FFC, 4))
+	suite.assertFault(Traps.LoadAddressMisaligned, 0x1FFE, func() { suite.mmu.TranslateLoad(0x1FFE, 4) })
+	suite.assertFault(Traps.StoreAddressMisaligned, 0x1FFC, func() { suite.mmu.TranslateStore(0x1FFC, 8) })
+}
+
+func (suite *Sv32MmuSuite) TestTlbCachesTranslationsUntilFenced() {
+	assert := assert.New(suite.T())
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+	assert.Equal(uint32(0x5678), suite.mmu.TranslateLoad(0x1678, 4))
+	assert.Equal(TlbStatistics{Hits: 1, Misses: 1}, suite.mmu.GetTlbStatistics())
+
+	suite.memory.words[secondTable+4*1] = pageTableEntry(0x7000, leafFlags|PteRead)
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+
+	// fencing another address, or another address space, keeps the translation
+	suite.mmu.Fence(0x2000, 0, false, true)
+	suite.mmu.Fence(0, asid+1, true, false)
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+
+	suite.mmu.Fence(0x1FFF, asid, false, false)
+	assert.Equal(uint32(0x7234), suite.mmu.TranslateLoad(0x1234, 4))
+	assert.Equal(TlbStatistics{Hits: 3, Misses: 2, Flushes: 3}, suite.mmu.GetTlbStatistics())
+}
+
+func (suite *Sv32MmuSuite) TestGlobalTranslationsAreSharedByAddressSpaces() {
+	assert := assert.New(suite.T())
+	suite.setLeaf(leafFlags | PteRead | PteGlobal)
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+
+	suite.memory.words[secondTable+4*1] = pageTableEntry(0x7000, leafFlags|PteRead)
+	suite.control.satp += 1 << CsrManagers.SatpASIDShift
+	suite.mmu.Fence(0, asid, true, false)
+	assert.Equal(uint32(0x5234), suite.mmu.TranslateLoad(0x1234, 4))
+
+	suite.mmu.Fence(0, 0, true, true)
+	assert.Equal(uint32(0x7234), suite.mmu.TranslateLoad(0x1234, 4))
+}
+
+func (suite *Sv32MmuSuite) TestFenceIsIllegalInUserModeAndWhenTrappedByTvm() {
+	suite.control.privilege = CsrManagers.User
+	suite.assertFault(Traps.IllegalInstruction, 0, func() { suite.mmu.Fence(0, 0, true, true) })
+
+	suite.control.privilege = CsrManagers.Supervisor
+	suite.control.mstatus = CsrManagers.MstatusTVM
+	suite.assertFault(Traps.IllegalInstruction, 0, func() { suite.mmu.Fence(0, 0, true, true) })
+
+	suite.control.privilege = CsrManagers.Machine
+	suite.mmu.Fence(0, 0, true, true)
+}
@@ -0,0 +1,73 @@
+package virtualMemory
+
+/*tlbEntries is the number of translations that a Tlb holds*/
+const tlbEntries = 64
+
+/*TlbStatistics counts how the translations that a Tlb was asked for were found. A hit was found in
+the Tlb, and a miss had to walk the page table. Flushes counts the SFENCE.VMA instructions*/
+type TlbStatistics struct {
+	Hits    uint
+	Misses  uint
+	Flushes uint
+}
+
+/*tlbEntry is the leaf page table entry that translates the virtual page `vpn` for the address space `asid`.
+A superpage entry only translates the one 4 KiB page of the megapage that it was walked for*/
+type tlbEntry struct {
+	valid     bool
+	asid      uint32
+	vpn       uint32
+	pte       uint32
+	superpage bool
+}
+
+/*Tlb is a direct-mapped translation lookaside buffer, with one entry for each of the lowest bits of a virtual
+page number. It caches whole leaf page table entries, so their permissions are still checked on every access.
+Like in hardware, it is not kept coherent with the page table: software has to flush it with SFENCE.VMA*/
+type Tlb struct {
+	entries    [tlbEntries]tlbEntry
+	statistics TlbStatistics
+}
+
+/*MakeTlb is a constructor for an empty Tlb*/
+func MakeTlb() Tlb {
+	return Tlb{}
+}
+
+/*lookup returns the entry that translates the virtual page `vpn` for the address space `asid`, if there is one*/
+func (t *Tlb) lookup(asid uint32, vpn uint32) (tlbEntry, bool) {
+	entry := t.entries[vpn%tlbEntries]
+	if entry.valid && entry.vpn == vpn && (entry.asid == asid || isGlobal(entry.pte)) {
+		t.statistics.Hits++
+		return entry, true
+	}
+
+	t.statistics.Misses++
+	return tlbEntry{}, false
+}
+
+/*insert caches `entry`, replacing the entry that held its slot*/
+func (t *Tlb) insert(entry tlbEntry) {
+	entry.valid = true
+	t.entries[entry.vpn%tlbEntries] = entry
+}
+
+/*Flush invalidates entries, as SFENCE.VMA does. Unless `allAddresses` is set, only the entries that translate
+the page of `address` are invalidated. Unless `allAsids` is set, only those of the address space `asid` are,
+and global entries are kept*/
+func (t *Tlb) Flush(address uint32, asid uint32, allAddresses bool, allAsids bool) {
+	t.statistics.Flushes++
+	vpn := address >> PageShift
+	for i, entry := range t.entries {
+		sameAddress := entry.vpn == vpn || (entry.superpage && entry.vpn>>vpnBits == vpn>>vpnBits)
+		sameAsid := entry.asid == asid && !isGlobal(entry.pte)
+		if (allAddresses || sameAddress) && (allAsids || sameAsid) {
+			t.entries[i].valid = false
+		}
+	}
+}
+
+/*GetStatistics returns the statistics of the lookups and flushes of the Tlb*/
+func (t *Tlb) GetStatistics() TlbStatistics {
+	return t.statistics
+}